@@ -8,8 +8,12 @@ import (
 	"sentinel/packages/common/config"
 	"sentinel/packages/common/logger"
 	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/roles"
 	"sentinel/packages/infrastructure/cache"
 	"sentinel/packages/infrastructure/email"
+	locationprovider "sentinel/packages/infrastructure/location"
+	"sentinel/packages/infrastructure/revocation"
+	"sentinel/packages/infrastructure/scheduler"
 	"syscall"
 	"time"
 
@@ -23,6 +27,18 @@ func Start(Router *echo.Echo) {
 
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 
+	reload := make(chan os.Signal, 1)
+
+	signal.Notify(reload, syscall.SIGHUP)
+
+	go func() {
+		for range reload {
+			log.Info("SIGHUP signal received, reloading RBAC configuration...", nil)
+			// Result is logged by reloader
+			_, _ = roles.ReloadConfig()
+		}
+	}()
+
 	if err := email.Init(); err != nil {
 		log.Fatal("Failed to start mailer", err.Error(), nil)
 	}
@@ -49,6 +65,9 @@ func Start(Router *echo.Echo) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Otherwise opened revocation streams will prevent HTTP server from shutting down
+	revocation.Stop()
+
 	if err := Router.Shutdown(ctx); err != nil {
 		log.Error("Failed to stop HTTP server", err.Error(), nil)
 	} else {
@@ -61,6 +80,12 @@ func Start(Router *echo.Echo) {
 func Shutdown() {
 	log.Info("Shutting down...", nil)
 
+	roles.Stop()
+
+	scheduler.Stop()
+
+	locationprovider.Stop()
+
 	if err := DB.Database.Disconnect(); err != nil {
 		log.Error("Failed to disconnect from DB", err.Error(), nil)
 	}
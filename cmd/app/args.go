@@ -35,3 +35,65 @@ func (a *appArgs) Parse() {
 		fmt.Println(parser.Usage(err))
 	}
 }
+
+// Name of the subcommand which explains authorization decision using RBAC configuration file (see Explain)
+const ExplainCommand = "explain"
+
+type explainArgs struct {
+	RBACConfig *string
+	SelfID     *string
+	ServiceID  *string
+	Entity     *string
+	Action     *string
+	Resource   *string
+	Roles      *[]string
+}
+
+var ExplainArgs = new(explainArgs)
+
+// Returns true if application was started with explain subcommand.
+func IsExplainCommand() bool {
+	return len(os.Args) > 1 && os.Args[1] == ExplainCommand
+}
+
+func (a *explainArgs) Parse() {
+	parser := argparse.NewParser(
+		"Sentinel "+ExplainCommand,
+		"Explain authorization decision using RBAC configuration file, without starting the service. "+
+			"Exits with code 0 if authorization is allowed, 1 if it's denied and 2 if input is invalid.",
+	)
+
+	a.RBACConfig = parser.String("c", "rbac-config", &argparse.Options{
+		Required: true,
+		Help:     "Path to RBAC configuration file (RBAC.config.json)",
+	})
+	a.SelfID = parser.String("", "self-id", &argparse.Options{
+		Help: "ID of Sentinel schema in the configuration file, required to explain authorization of Sentinel's own operations",
+	})
+	a.ServiceID = parser.String("s", "service", &argparse.Options{
+		Required: true,
+		Help:     "ID of the service schema",
+	})
+	a.Entity = parser.String("e", "entity", &argparse.Options{
+		Required: true,
+		Help:     "Name of the entity",
+	})
+	a.Action = parser.String("a", "action", &argparse.Options{
+		Required: true,
+		Help:     "Name of the action",
+	})
+	a.Resource = parser.String("r", "resource", &argparse.Options{
+		Required: true,
+		Help:     "Name of the resource",
+	})
+	a.Roles = parser.StringList("R", "role", &argparse.Options{
+		Required: true,
+		Help:     "Role of the subject, can be specified multiple times",
+	})
+
+	// First argument is the subcommand itself, parser expects program name there
+	if err := parser.Parse(os.Args[1:]); err != nil {
+		fmt.Println(parser.Usage(err))
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sentinel/packages/infrastructure/auth/authz"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+// Explains authorization decision using RBAC configuration file specified in ExplainArgs
+// and prints explanation in stdout. Intended for review of policy changes before deployment.
+// Exits with code 0 if authorization is allowed, 1 if it's denied and 2 if input is invalid.
+func Explain() {
+	ExplainArgs.Parse()
+
+	host, err := rbac.LoadHost(*ExplainArgs.RBACConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load RBAC configuration file: "+err.Error())
+		os.Exit(2)
+	}
+
+	explanation, e := authz.DryRun(&host, *ExplainArgs.SelfID, &authz.DecisionRequest{
+		ServiceID: *ExplainArgs.ServiceID,
+		Entity:    *ExplainArgs.Entity,
+		Action:    *ExplainArgs.Action,
+		Resource:  *ExplainArgs.Resource,
+		Roles:     *ExplainArgs.Roles,
+	})
+	if e != nil {
+		fmt.Fprintln(os.Stderr, "Failed to explain authorization: "+e.Error())
+		os.Exit(2)
+	}
+
+	out, _ := json.MarshalIndent(explanation, "", "    ")
+	fmt.Println(string(out))
+
+	if !explanation.Allowed {
+		os.Exit(1)
+	}
+}
@@ -7,7 +7,11 @@ import (
 	"sentinel/packages/common/logger"
 	"sentinel/packages/infrastructure/DB"
 	"sentinel/packages/infrastructure/auth/authz"
+	"sentinel/packages/infrastructure/auth/roles"
 	"sentinel/packages/infrastructure/cache"
+	locationprovider "sentinel/packages/infrastructure/location"
+	"sentinel/packages/infrastructure/revocation"
+	"sentinel/packages/infrastructure/scheduler"
 	"sentinel/packages/infrastructure/token"
 	"sentinel/packages/presentation/api/http/router"
 
@@ -55,6 +59,20 @@ func InitConnections() {
 	cache.Client.Connect()
 	DB.Database.Connect()
 
+	authz.SetOrganizationsResolver(DB.Database.GetUserOrganizationIDs)
+
+	revocation.Start()
+
+	// Depends on cache
+	locationprovider.Init()
+
+	// Depends on authz, DB and cache
+	roles.Init()
+	roles.StartProcessing()
+
+	// Depends on DB and cache
+	scheduler.Start()
+
 	log.Info("Initializng connections: OK", nil)
 }
 
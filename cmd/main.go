@@ -36,6 +36,11 @@ var log = logger.NewSource("MAIN", logger.Default)
 // @name						oauth_session
 // @description 				OAuth state token in cookie. Must match with token in "state" query param.
 func main() {
+	if app.IsExplainCommand() {
+		app.Explain()
+		return
+	}
+
 	app.Args.Parse()
 
 	app.StartInit()
@@ -134,7 +134,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Create new access and refresh tokens and update current session info",
+                "description": "Create new access and refresh tokens and update current session info.\nSession which exceeded its idle timeout or max lifetime is revoked (491), user must log in again.\nSession used from the suspicious location (see risk config) is either revoked (491)\nor requires re-authentication before accessing sensitive endpoints.\nChanges of browser, OS, device, IP network or country are handled according to the session binding policy:\nsession is either refreshed, or alert is sent, or re-authentication is required, or session is revoked (491).",
                 "consumes": [
                     "application/json"
                 ],
@@ -157,7 +157,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when session looks suspicious and re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -172,7 +178,7 @@ const docTemplate = `{
                         }
                     },
                     "491": {
-                        "description": "Session revoked",
+                        "description": "Session revoked, expired or blocked due to suspicious location or binding policy",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Error"
                         },
@@ -200,7 +206,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Login endpoint",
+                "description": "Login endpoint.\nIf user reached max amount of active sessions, then depending on config either login is rejected (409)\nor least recently used sessions are revoked, in the last case they are listed in evictedSessions.\nSign-in from the suspicious location (see risk config) is either blocked (403)\nor succeeds, but requires re-authentication before accessing sensitive endpoints.",
                 "consumes": [
                     "application/json"
                 ],
@@ -228,6 +234,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Token"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when sign-in looks suspicious and re-authentication is required (see /v1/auth/reauth)"
+                            }
                         }
                     },
                     "400": {
@@ -242,6 +254,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -314,6 +338,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -482,6 +512,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -526,7 +562,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "RFC 7662 (https://datatracker.ietf.org/doc/html/rfc7662). Valid token types are: access, refresh and activate.",
+                "description": "RFC 7662 (https://datatracker.ietf.org/doc/html/rfc7662). Valid token types are: access, refresh and activate.\nIf token is invalid, expired or it's session was revoked, then only \"active\" field with false value will be returned.",
                 "consumes": [
                     "application/json"
                 ],
@@ -577,9 +613,12 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/auth/reset-password": {
+        "/v1/auth/organization": {
             "post": {
                 "security": [
+                    {
+                        "BearerAuth": []
+                    },
                     {
                         "CSRF_Header": []
                     },
@@ -587,7 +626,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Resets user password if password reset token is valid",
+                "description": "Issues new auth tokens scoped to the specified organization, such tokens will contain roles of the user in this organization.\nOrganization-scoped tokens can be used only on organization endpoints (/v1/organizations/{orgID}/...).\nIf organization ID isn't specified, then issued tokens won't be scoped to any organization.",
                 "consumes": [
                     "application/json"
                 ],
@@ -597,22 +636,25 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Resets user password",
-                "operationId": "reset-password",
+                "summary": "Switch organization",
+                "operationId": "switch-organization",
                 "parameters": [
                     {
-                        "description": "Password reset token and new user password",
-                        "name": "token-and-password",
+                        "description": "Organization ID",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requestbody.PasswordReset"
+                            "$ref": "#/definitions/requestbody.SwitchOrganization"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Token"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -632,6 +674,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -641,8 +707,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/auth/sessions/{uid}": {
-            "delete": {
+        "/v1/auth/reauth": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -654,7 +720,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Revoke all existing non-revoked sessions",
+                "description": "Confirms user identity by password and issues new auth tokens with updated authentication time.\nRequired by endpoints which responded with 401 and \"X-Step-Up-Required\" header.\nUsers without password (e.g. registered via OAuth) can re-authenticate via one-time code (see /v1/auth/reauth/code).",
                 "consumes": [
                     "application/json"
                 ],
@@ -664,20 +730,25 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Revokes all user sessions",
-                "operationId": "revoke-all-user-sessions",
+                "summary": "Re-authenticate user",
+                "operationId": "reauthenticate",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
+                        "description": "User password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.UserPassword"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Token"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -730,8 +801,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/auth/{sessionID}": {
-            "delete": {
+        "/v1/auth/reauth/code": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -743,7 +814,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Logout endpoint",
+                "description": "Sends one-time code to the login of the current user, which can be used for re-authentication\nvia /v1/auth/reauth/code/verify. Designed for users without password (e.g. registered via OAuth).\nCode is bound to the current session and expires in 5 minutes, new code replaces previous one.",
                 "consumes": [
                     "application/json"
                 ],
@@ -753,17 +824,8 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Revoke user session",
-                "operationId": "logout",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID of session that should be revoked",
-                        "name": "sessionID",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Send re-authentication code",
+                "operationId": "send-reauth-code",
                 "responses": {
                     "200": {
                         "description": "OK"
@@ -780,6 +842,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -792,6 +860,18 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -801,8 +881,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/cache": {
-            "delete": {
+        "/v1/auth/reauth/code/verify": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -814,7 +894,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Delete all cache. Only users with \"admin\" role can do that, even if they have enough permission to do that",
+                "description": "Same as /v1/auth/reauth, but confirms user identity by one-time code sent via /v1/auth/reauth/code.\nCode can be used only once and only within the session which requested it.\nCode is invalidated after the first attempt, so if it was wrong, then new code must be requested.",
                 "consumes": [
                     "application/json"
                 ],
@@ -822,13 +902,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cache"
+                    "auth"
+                ],
+                "summary": "Re-authenticate user via one-time code",
+                "operationId": "reauthenticate-with-code",
+                "parameters": [
+                    {
+                        "description": "One-time code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.OneTimeCode"
+                        }
+                    }
                 ],
-                "summary": "Flush cache",
-                "operationId": "drop-cache",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Token"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -881,9 +975,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/roles/{serviceID}": {
-            "get": {
-                "description": "Get list of all roles that exists in the specified service",
+        "/v1/auth/reset-password": {
+            "post": {
+                "security": [
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Resets user password if password reset token is valid",
                 "consumes": [
                     "application/json"
                 ],
@@ -891,28 +993,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "auth"
                 ],
-                "summary": "Get all service roles",
-                "operationId": "get-all-roles",
+                "summary": "Resets user password",
+                "operationId": "reset-password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID of the service which roles you want to get",
-                        "name": "serviceID",
-                        "in": "path",
-                        "required": true
+                        "description": "Password reset token and new user password",
+                        "name": "token-and-password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.PasswordReset"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -926,6 +1024,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -935,45 +1039,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user": {
-            "put": {
+        "/v1/auth/revocations": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
-                    },
-                    {
-                        "CSRF_Header": []
-                    },
-                    {
-                        "CSRF_Cookie": []
                     }
                 ],
-                "description": "Bulk restore soft deleted users",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Server-Sent Events stream of session revocations and user version changes. Designed for resource servers.\nStream will be closed once access token expires, after that client must reconnect with a new one.\nTo replay missed events specify ID of the last received event in Last-Event-ID header (only a limited amount of recent events can be replayed).",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "user"
+                    "auth"
                 ],
-                "summary": "Restore several soft deleted users",
-                "operationId": "bulk-restore-users",
+                "summary": "Stream of session revocations",
+                "operationId": "stream-revocations",
                 "parameters": [
                     {
-                        "description": "Users IDs",
-                        "name": "usersIDs",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requestbody.UsersIDs"
-                        }
+                        "type": "string",
+                        "description": "ID of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/revocation.Event"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1024,9 +1119,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Registration endpoint",
+            }
+        },
+        "/v1/auth/sessions": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Revokes all sessions of the current user except the current one",
                 "consumes": [
                     "application/json"
                 ],
@@ -1034,25 +1142,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "Create new user",
-                "operationId": "create-new-user",
-                "parameters": [
-                    {
-                        "description": "User credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requestbody.LoginAndPassword"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "Revoke all other sessions",
+                "operationId": "revoke-other-sessions",
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.RevokedSessions"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -1065,6 +1165,36 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1072,7 +1202,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/auth/sessions/{uid}": {
             "delete": {
                 "security": [
                     {
@@ -1085,7 +1217,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Bulk user soft delete. All sessions of soft deleted users will be revoked",
+                "description": "Revoke all existing non-revoked sessions",
                 "consumes": [
                     "application/json"
                 ],
@@ -1093,19 +1225,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "auth"
                 ],
-                "summary": "Soft delete several users",
-                "operationId": "bulk-soft-delete-users",
+                "summary": "Revokes all user sessions",
+                "operationId": "revoke-all-user-sessions",
                 "parameters": [
                     {
-                        "description": "Users IDs",
-                        "name": "usersIDs",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requestbody.UsersIDs"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1122,6 +1252,12 @@ const docTemplate = `{
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
                         }
                     },
                     "403": {
@@ -1163,9 +1299,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/activate/resend": {
-            "put": {
-                "description": "Create and send new activation token to user",
+        "/v1/auth/{sessionID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Logout endpoint",
                 "consumes": [
                     "application/json"
                 ],
@@ -1173,19 +1320,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "activation"
+                    "auth"
                 ],
-                "summary": "Resend activation token",
-                "operationId": "resend-activation-token",
+                "summary": "Revoke user session",
+                "operationId": "logout",
                 "parameters": [
                     {
-                        "description": "Login of not activated user to whom token should be sent",
-                        "name": "login",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requestbody.UserLogin"
-                        }
+                        "type": "string",
+                        "description": "ID of session that should be revoked",
+                        "name": "sessionID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1204,6 +1349,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1211,11 +1374,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/user/activate/{token}": {
-            "get": {
-                "description": "Activate user",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Sets friendly name of the device for the session of the current user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1223,17 +1395,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "activation"
+                    "auth"
                 ],
-                "summary": "Activate user",
-                "operationId": "activate",
+                "summary": "Rename session",
+                "operationId": "rename-session",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Activation token",
-                        "name": "token",
+                        "description": "Session ID",
+                        "name": "sessionID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New session name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.RenameSession"
+                        }
                     }
                 ],
                 "responses": {
@@ -1252,6 +1433,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1261,8 +1478,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/drop/all": {
-            "delete": {
+        "/v1/auth/{sessionID}/trusted": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1274,7 +1491,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Hard delete all soft deleted users",
+                "description": "Marks (or unmarks) session of the current user as trusted. Requires recent authentication.\nStep-up isn't required when trusted session is used from the suspicious location (security alert is still sent).",
                 "consumes": [
                     "application/json"
                 ],
@@ -1282,10 +1499,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "auth"
+                ],
+                "summary": "Mark session as trusted",
+                "operationId": "trust-session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trusted flag",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.TrustSession"
+                        }
+                    }
                 ],
-                "summary": "Drop all delete users",
-                "operationId": "drop-all-deleted-users",
                 "responses": {
                     "200": {
                         "description": "OK"
@@ -1300,6 +1535,12 @@ const docTemplate = `{
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
                         }
                     },
                     "403": {
@@ -1308,6 +1549,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -1341,9 +1588,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/login/available": {
-            "get": {
-                "description": "Check is login free to use",
+        "/v1/authz/check": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Evaluate authorization decision for the subject in the specified service.\nSubject can be specified either by its roles or by its access token (revocation of token's session isn't checked).\nIf authorization is denied, then reason will be specified: \"insufficient-permissions\" or \"denied-by-agp\".\nAttribute-based conditions of Sentinel's own contexts aren't evaluated: if roles are sufficient for such context,\nthen it isn't allowed, reason is \"conditional\" and condition is specified.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1351,24 +1603,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "authz"
                 ],
-                "summary": "Check login availability",
-                "operationId": "check-login",
+                "summary": "Check authorization",
+                "operationId": "check-authorization",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "The login you want to check",
-                        "name": "login",
-                        "in": "query",
-                        "required": true
+                        "description": "Authorization context and subject",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.AuthzCheck"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responsebody.IsLoginAvailable"
+                            "$ref": "#/definitions/authz.Decision"
                         }
                     },
                     "400": {
@@ -1383,6 +1637,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -1416,14 +1682,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/search": {
-            "get": {
+        "/v1/authz/check/batch": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Search users with pagination",
+                "description": "Same as /v1/authz/check, but for multiple checks at once (up to 100).\nDecisions are returned in the same order as checks. If any check is invalid, then whole request fails.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1431,31 +1697,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "authz"
                 ],
-                "summary": "Users search",
-                "operationId": "search-users",
+                "summary": "Check authorization (batch)",
+                "operationId": "check-authorization-batch",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search filter",
-                        "name": "filter",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Search page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Elements per page",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
+                        "description": "Checks",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.AuthzBatchCheck"
+                        }
                     }
                 ],
                 "responses": {
@@ -1464,7 +1718,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/userdto.Public"
+                                "$ref": "#/definitions/authz.Decision"
                             }
                         }
                     },
@@ -1486,6 +1740,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -1519,14 +1779,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/{uid}": {
-            "get": {
+        "/v1/authz/explain": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Search users with pagination",
+                "description": "Same as /v1/authz/check, but describes how decision was made: evaluated roles and their permissions,\npermissions required by the action, missing permissions and the Action Gate Policy rule for the context (if any).\nOnly users with \"admin\" role can do that.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1534,24 +1794,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "authz"
                 ],
-                "summary": "Users search",
-                "operationId": "get-user-by-id",
+                "summary": "Explain authorization",
+                "operationId": "explain-authorization",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
+                        "description": "Authorization context and subject",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.AuthzCheck"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/userdto.Public"
+                            "$ref": "#/definitions/authz.Explanation"
                         }
                     },
                     "400": {
@@ -1572,6 +1834,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -1603,7 +1871,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/cache": {
             "delete": {
                 "security": [
                     {
@@ -1616,7 +1886,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Soft delete user. All sessions of soft deleted user will be revoked",
+                "description": "Delete all cache. Only users with \"admin\" role can do that, even if they have enough permission to do that",
                 "consumes": [
                     "application/json"
                 ],
@@ -1624,19 +1894,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "Soft delete user",
-                "operationId": "soft-delete-user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
-                    }
+                    "cache"
                 ],
+                "summary": "Flush cache",
+                "operationId": "drop-cache",
                 "responses": {
                     "200": {
                         "description": "OK"
@@ -1651,6 +1912,12 @@ const docTemplate = `{
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
                         }
                     },
                     "403": {
@@ -1692,20 +1959,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/{uid}/drop": {
-            "delete": {
+        "/v1/groups": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
-                    },
-                    {
-                        "CSRF_Header": []
-                    },
-                    {
-                        "CSRF_Cookie": []
                     }
                 ],
-                "description": "Hard delete user. Only soft deleted users can be hard deleted",
+                "description": "Get all user groups with their roles",
                 "consumes": [
                     "application/json"
                 ],
@@ -1713,27 +1974,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "Hard delete user",
-                "operationId": "hard-delete-user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
-                    }
+                    "group"
                 ],
+                "summary": "Get all groups",
+                "operationId": "get-all-groups",
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responsebody.Error"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/groupdto.Full"
+                            }
                         }
                     },
                     "401": {
@@ -1779,10 +2031,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/user/{uid}/login": {
-            "patch": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1794,7 +2044,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Change user login",
+                "description": "Create new user group. Roles of the group are inherited by all its members.\nPrivileged roles can't be assigned to groups.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1802,39 +2052,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "group"
                 ],
-                "summary": "Change user login",
-                "operationId": "change-user-login",
+                "summary": "Create group",
+                "operationId": "create-group",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "New user login",
-                        "name": "newLogin",
+                        "description": "Group",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requestbody.UserLogin"
-                        }
-                    },
-                    {
-                        "description": "New user login and password (required if user tries to change his own login)",
-                        "name": "newLoginAndPassword",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/requestbody.LoginAndPassword"
+                            "$ref": "#/definitions/requestbody.CreateGroup"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/groupdto.Full"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1846,6 +2084,12 @@ const docTemplate = `{
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
                         }
                     },
                     "403": {
@@ -1854,6 +2098,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -1887,20 +2137,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/{uid}/password": {
-            "patch": {
+        "/v1/groups/{groupID}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
-                    },
-                    {
-                        "CSRF_Header": []
-                    },
-                    {
-                        "CSRF_Cookie": []
                     }
                 ],
-                "description": "Change user password",
+                "description": "Get user group by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1908,39 +2152,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "group"
                 ],
-                "summary": "Change user password",
-                "operationId": "change-user-password",
+                "summary": "Get group",
+                "operationId": "get-group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
+                        "description": "Group ID",
+                        "name": "groupID",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "New user password",
-                        "name": "newPassword",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requestbody.UserPassword"
-                        }
-                    },
-                    {
-                        "description": "Both new and current user passwords (required if user tries to change his own login)",
-                        "name": "newAndCurrentPassword",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/requestbody.ChangePassword"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/groupdto.Full"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1960,6 +2190,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -1991,10 +2227,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/user/{uid}/restore": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2006,7 +2240,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Restore soft delete user",
+                "description": "Delete user group. All its members will lose roles of the group.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2014,17 +2248,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "group"
                 ],
-                "summary": "Restore soft delete user",
-                "operationId": "restore-soft-delete-user",
+                "summary": "Delete group",
+                "operationId": "delete-group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
+                        "description": "Group ID",
+                        "name": "groupID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason of deletion",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ActionReason"
+                        }
                     }
                 ],
                 "responses": {
@@ -2041,6 +2283,12 @@ const docTemplate = `{
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
                         }
                     },
                     "403": {
@@ -2049,6 +2297,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -2082,14 +2336,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/{uid}/roles": {
+        "/v1/groups/{groupID}/members": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get user roles",
+                "description": "Get all members of the user group",
                 "consumes": [
                     "application/json"
                 ],
@@ -2097,15 +2351,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "group"
                 ],
-                "summary": "Get user roles",
-                "operationId": "get-user-roles",
+                "summary": "Get group members",
+                "operationId": "get-group-members",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
+                        "description": "Group ID",
+                        "name": "groupID",
                         "in": "path",
                         "required": true
                     }
@@ -2116,7 +2370,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "string"
+                                "$ref": "#/definitions/groupdto.Member"
                             }
                         }
                     },
@@ -2138,6 +2392,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -2169,8 +2429,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/groups/{groupID}/members/{uid}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2182,7 +2444,7 @@ const docTemplate = `{
                         "CSRF_Cookie": []
                     }
                 ],
-                "description": "Change user roles",
+                "description": "Add user to the group, user will inherit roles of the group after tokens refresh",
                 "consumes": [
                     "application/json"
                 ],
@@ -2190,33 +2452,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "group"
                 ],
-                "summary": "Change user roles",
-                "operationId": "change-user-roles",
+                "summary": "Add group member",
+                "operationId": "add-group-member",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
+                        "description": "Group ID",
+                        "name": "groupID",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New user roles",
-                        "name": "newRoles",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requestbody.UserRoles"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "New user roles and password (required if user tries to change his own login)",
-                        "name": "newRolesAndPassword",
+                        "description": "Reason",
+                        "name": "body",
                         "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/requestbody.ChangeRoles"
+                            "$ref": "#/definitions/requestbody.ActionReason"
                         }
                     }
                 ],
@@ -2234,6 +2494,12 @@ const docTemplate = `{
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
                         }
                     },
                     "403": {
@@ -2242,6 +2508,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -2273,16 +2551,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/user/{uid}/sessions": {
-            "get": {
-                "security": [
+            },
+            "delete": {
+                "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
                     }
                 ],
-                "description": "Get all active user sessions",
+                "description": "Remove user from the group, user will lose roles of the group after tokens refresh",
                 "consumes": [
                     "application/json"
                 ],
@@ -2290,28 +2572,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "group"
                 ],
-                "summary": "Get user sessions",
-                "operationId": "get-user-sessions",
+                "summary": "Remove group member",
+                "operationId": "remove-group-member",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "groupID",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "User ID",
                         "name": "uid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ActionReason"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/responsebody.UserSession"
-                            }
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2323,6 +2614,12 @@ const docTemplate = `{
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
                         }
                     },
                     "403": {
@@ -2331,6 +2628,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responsebody.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
                     "490": {
                         "description": "User data desynchronization",
                         "schema": {
@@ -2363,65 +2666,5916 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "requestbody.Auth": {
-            "type": "object",
-            "properties": {
-                "audience": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        },
+        "/v1/groups/{groupID}/roles": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Change roles of the user group. All members of the group will have to refresh their tokens.\nPrivileged roles can't be assigned to groups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group"
+                ],
+                "summary": "Change group roles",
+                "operationId": "change-group-roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "groupID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New roles of the group",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ChangeGroupRoles"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
                     }
-                },
-                "login": {
-                    "type": "string",
-                    "example": "admin@mail.com"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "your-password"
                 }
             }
         },
-        "requestbody.ChangePassword": {
-            "type": "object",
-            "properties": {
-                "newPassword": {
-                    "type": "string",
-                    "example": "your-new-password"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "your-password"
-                },
-                "reason": {
-                    "type": "string",
-                    "example": "Violation of terms of use"
+        "/v1/organizations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Create new organization. Can't be done with organization-scoped token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Create organization",
+                "operationId": "create-organization",
+                "parameters": [
+                    {
+                        "description": "Organization",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.CreateOrganization"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/organizationdto.Full"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
                 }
             }
         },
-        "requestbody.ChangeRoles": {
-            "type": "object",
-            "properties": {
-                "password": {
-                    "type": "string",
+        "/v1/organizations/{orgID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get organization by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get organization",
+                "operationId": "get-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizationdto.Full"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Delete organization with all its memberships. Can't be done with organization-scoped token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Delete organization",
+                "operationId": "delete-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason of deletion",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ActionReason"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/organizations/{orgID}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get members of the organization with their roles in it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get organization members",
+                "operationId": "get-organization-members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Elements per page",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/organizationdto.Member"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/organizations/{orgID}/members/{uid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get member of the organization with his roles in it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get organization member",
+                "operationId": "get-organization-member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizationdto.Member"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Add user to the organization or change his roles in it if he is already a member.\nMember must refresh his organization-scoped tokens to get new roles.\nNew members can be added only with global (not organization-scoped) token. Privileged roles can't be assigned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Set organization member",
+                "operationId": "set-organization-member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Roles of the user in the organization",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.SetOrganizationMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Remove user from the organization, his organization-scoped tokens will be invalidated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Remove organization member",
+                "operationId": "remove-organization-member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason of removal",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ActionReason"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/organizations/{orgID}/members/{uid}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all active sessions of the organization member",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get organization member sessions",
+                "operationId": "get-organization-member-sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responsebody.UserSession"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Revoke all existing non-revoked sessions of the organization member",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Revoke all organization member sessions",
+                "operationId": "revoke-all-organization-member-sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/organizations/{orgID}/users/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search users with pagination among members of the organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Organization users search",
+                "operationId": "search-organization-users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search filter",
+                        "name": "filter",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Search page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Elements per page",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/userdto.Public"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/rbac/agp": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all active Action Gate Policy rules (built-in and from config) with contexts they match",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get Action Gate Policy",
+                "operationId": "get-action-gate-policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/authz.ActionGateRule"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/rbac/reload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Reloads RBAC configuration file and atomically applies it along with Action Gate Policy.\nConfiguration is rejected if any role assigned to users or any default role doesn't exist in it.\nAffects only Sentinel instance which handled this request (to reload all instances send SIGHUP to each of them).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Reload RBAC configuration",
+                "operationId": "reload-rbac",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/roles.Diff"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/rbac/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all roles of all schemas stored in DB (including their permissions)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get all roles",
+                "operationId": "get-managed-roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/roledto.Public"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Create new role. Change will be propagated to all Sentinel instances.\nChange is rejected if resulting RBAC configuration is invalid (e.g. default role doesn't exist in schema of this service).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Create role",
+                "operationId": "create-role",
+                "parameters": [
+                    {
+                        "description": "Role",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.CreateRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/roledto.Public"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/rbac/roles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get role stored in DB by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get role",
+                "operationId": "get-managed-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/roledto.Public"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Update permissions of the role. Name and schema of the role can't be changed.\nChange will be propagated to all Sentinel instances.\nChange is rejected if resulting RBAC configuration is invalid (e.g. default role doesn't exist in schema of this service).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Update role",
+                "operationId": "update-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role permissions",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.UpdateRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Delete role. Role can't be deleted while it's assigned to any user\n(unless it's schema specific role and there are global role with the same name).\nChange will be propagated to all Sentinel instances.\nChange is rejected if resulting RBAC configuration is invalid (e.g. default role doesn't exist in schema of this service).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Delete role",
+                "operationId": "delete-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason of deletion",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ActionReason"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/roles/{serviceID}": {
+            "get": {
+                "description": "Get list of all roles that exists in the specified service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get all service roles",
+                "operationId": "get-all-roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the service which roles you want to get",
+                        "name": "serviceID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/scheduler/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get configured background jobs with their schedule and metrics.\nMetrics are collected by the Sentinel instance which handled this request since its start.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Get scheduler jobs",
+                "operationId": "get-scheduler-jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/scheduler.JobStatus"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/scheduler/jobs/{job}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get runs of the background job made by all Sentinel instances, most recent runs go first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Get job history",
+                "operationId": "get-scheduler-job-runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "job",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Elements per page",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/jobdto.Run"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Bulk restore soft deleted users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Restore several soft deleted users",
+                "operationId": "bulk-restore-users",
+                "parameters": [
+                    {
+                        "description": "Users IDs",
+                        "name": "usersIDs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.UsersIDs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registration endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create new user",
+                "operationId": "create-new-user",
+                "parameters": [
+                    {
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.LoginAndPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Bulk user soft delete. All sessions of soft deleted users will be revoked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Soft delete several users",
+                "operationId": "bulk-soft-delete-users",
+                "parameters": [
+                    {
+                        "description": "Users IDs",
+                        "name": "usersIDs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.UsersIDs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/activate/resend": {
+            "put": {
+                "description": "Create and send new activation token to user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "activation"
+                ],
+                "summary": "Resend activation token",
+                "operationId": "resend-activation-token",
+                "parameters": [
+                    {
+                        "description": "Login of not activated user to whom token should be sent",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.UserLogin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/activate/{token}": {
+            "get": {
+                "description": "Activate user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "activation"
+                ],
+                "summary": "Activate user",
+                "operationId": "activate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Activation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/drop/all": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Hard delete all soft deleted users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Drop all delete users",
+                "operationId": "drop-all-deleted-users",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/login/available": {
+            "get": {
+                "description": "Check is login free to use",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Check login availability",
+                "operationId": "check-login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The login you want to check",
+                        "name": "login",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.IsLoginAvailable"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/login/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Cancel login change via token sent to the old login. If change was already confirmed,\nbut its grace period isn't over yet, then old login is restored and all sessions of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Cancel login change",
+                "operationId": "cancel-login-change",
+                "parameters": [
+                    {
+                        "description": "Login change cancellation token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.LoginChangeToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/userdto.LoginChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/login/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Confirm login change via token sent to the new login. Login of the user is changed right away,\nbut change still can be reverted via cancellation link until the end of grace period (see \"login-change-grace-period\" in config).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Confirm login change",
+                "operationId": "confirm-login-change",
+                "parameters": [
+                    {
+                        "description": "Login change confirmation token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.LoginChangeToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/userdto.LoginChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/login/requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get login change requests of all users. Newest requests go first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get login change requests",
+                "operationId": "get-login-change-requests",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "confirmed",
+                            "cancelled",
+                            "reverted",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/userdto.LoginChangeRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/roles/requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get role change requests which were created due to addition of the privileged roles. Newest requests go first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get role change requests",
+                "operationId": "get-role-change-requests",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "cancelled",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/userdto.RoleChangeRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/roles/requests/{requestID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Cancel pending role change request, roles of the user won't be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Cancel role change request",
+                "operationId": "cancel-role-change-request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role change request ID",
+                        "name": "requestID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason of cancellation",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ActionReason"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/roles/requests/{requestID}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Approve pending role change request and apply its roles to the user.\nRequest can't be approved by its author or by the user whose roles it changes.\nIf reason isn't specified, then reason of the request will be used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Approve role change request",
+                "operationId": "approve-role-change-request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role change request ID",
+                        "name": "requestID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason of approval",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ActionReason"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/userdto.RoleChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search users with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Users search",
+                "operationId": "search-users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search filter",
+                        "name": "filter",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Search page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Elements per page",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/userdto.Public"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search users with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Users search",
+                "operationId": "get-user-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/userdto.Public"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Soft delete user. All sessions of soft deleted user will be revoked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Soft delete user",
+                "operationId": "soft-delete-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/devices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get devices from which user has logged in, most recently seen devices go first.\nDevice of the current request is marked as \"current\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user devices",
+                "operationId": "get-user-devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responsebody.UserDevice"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/devices/{deviceID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Removes device of the user and revokes all sessions which were created from it.\nRequires recent authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Remove user device",
+                "operationId": "remove-user-device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "deviceID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Sets friendly name of the device of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Rename device",
+                "operationId": "rename-user-device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "deviceID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New device name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.RenameDevice"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/devices/{deviceID}/trusted": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Marks (or unmarks) device of the current user as trusted.\nStep-up isn't required for suspicious logins from trusted devices (security alert is still sent).\nRequires recent authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Mark device as trusted",
+                "operationId": "trust-user-device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "deviceID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trusted flag",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.TrustDevice"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/drop": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Hard delete user. Only soft deleted users can be hard deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Hard delete user",
+                "operationId": "hard-delete-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/groups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get groups in which user is a member",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group"
+                ],
+                "summary": "Get user groups",
+                "operationId": "get-user-groups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/groupdto.Full"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/impersonate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Issues short-lived access token of the specified user with \"act\" claim naming the requester.\nImpersonation token can't be refreshed and can't be used for destructive operations.\nUsers with higher privileges than requester can't be impersonated.\nStart of impersonation (with its reason) is recorded in the audit, so token isn't issued if it can't be recorded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Impersonate user",
+                "operationId": "impersonate-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and audience of impersonation",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.Impersonate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Token"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/login": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Request change of user login. Login isn't changed right away, instead pending login change request is created and returned.\nConfirmation link is sent to the new login and cancellation link is sent to the old one.\nLogin will be changed only after confirmation (see /v1/user/login/confirm), earlier pending requests of the user are cancelled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change user login",
+                "operationId": "change-user-login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New user login",
+                        "name": "newLogin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.UserLogin"
+                        }
+                    },
+                    {
+                        "description": "New user login and password (required if user tries to change his own login)",
+                        "name": "newLoginAndPassword",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.LoginAndPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Login change request was created and awaits confirmation",
+                        "schema": {
+                            "$ref": "#/definitions/userdto.LoginChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/login/requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all login change requests of the user, including resolved ones. Newest requests go first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user login change requests",
+                "operationId": "get-user-login-change-requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/userdto.LoginChangeRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/logins": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get logins of the user (including revoked sessions) with their last known location, most recent logins go first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user login history",
+                "operationId": "get-user-login-history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Elements per page",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Logins made at or after this moment (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Logins made before this moment (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country code (ISO 3166-1 alpha-2)",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device type (desktop, mobile, tablet)",
+                        "name": "deviceType",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/sessiondto.Login"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/logins/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export logins of the user matching the filter as CSV or JSON file, most recent logins go first.\nUp to 10000 logins are exported. Requires recent authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Export user login history",
+                "operationId": "export-user-login-history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Export format: csv or json (default)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Logins made at or after this moment (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Logins made before this moment (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country code (ISO 3166-1 alpha-2)",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device type (desktop, mobile, tablet)",
+                        "name": "deviceType",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/sessiondto.Login"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/password": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Change user password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change user password",
+                "operationId": "change-user-password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New user password",
+                        "name": "newPassword",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.UserPassword"
+                        }
+                    },
+                    {
+                        "description": "Both new and current user passwords (required if user tries to change his own login)",
+                        "name": "newAndCurrentPassword",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ChangePassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/restore": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Restore soft delete user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Restore soft delete user",
+                "operationId": "restore-soft-delete-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get user roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user roles",
+                "operationId": "get-user-roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Change user roles. If new roles adds any of the privileged roles (see \"privileged-roles\" in config),\nthen roles won't be changed right away, instead pending role change request will be created and returned.\nSuch request must be approved by another user (see /v1/user/roles/requests), users who can approve it are notified via email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change user roles",
+                "operationId": "change-user-roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New user roles",
+                        "name": "newRoles",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.UserRoles"
+                        }
+                    },
+                    {
+                        "description": "New user roles and password (required if user tries to change his own login)",
+                        "name": "newRolesAndPassword",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.ChangeRoles"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "202": {
+                        "description": "Role change request was created and awaits approval",
+                        "schema": {
+                            "$ref": "#/definitions/userdto.RoleChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/roles/grants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all role grants of the user, including not yet active ones. Expired grants are removed automatically.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user role grants",
+                "operationId": "get-user-role-grants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/userdto.RoleGrant"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Assign role to user for a limited period of time and/or starting from the specified moment.\nGranted roles are added to the user's tokens while grant is active. Requires same permissions as changing user roles.\nPrivileged roles (see \"privileged-roles\" in config) can't be granted, since they require approval.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Grant role to user",
+                "operationId": "grant-user-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role grant",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requestbody.GrantRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/userdto.RoleGrant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/roles/grants/{grantID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "CSRF_Header": []
+                    },
+                    {
+                        "CSRF_Cookie": []
+                    }
+                ],
+                "description": "Delete role grant before its expiration. Requires same permissions as changing user roles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Revoke user role grant",
+                "operationId": "revoke-user-role-grant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grant ID",
+                        "name": "grantID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Step-Up-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{uid}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all active user sessions with their last known location (null if unknown).\nSession of the current request is marked as \"current\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user sessions",
+                "operationId": "get-user-sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responsebody.UserSession"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    },
+                    "490": {
+                        "description": "User data desynchronization",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Token-Refresh-Required": {
+                                "type": "string",
+                                "description": "Set to 'true' when token refresh is required"
+                            }
+                        }
+                    },
+                    "491": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        },
+                        "headers": {
+                            "X-Session-Revoked": {
+                                "type": "string",
+                                "description": "Set to 'true' if current user session was revoked"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responsebody.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "authz.ActionGateRule": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "description": "Authorization context which this rule matches: \"\u003centity\u003e:\u003caction\u003e:\u003cresource\u003e\"",
+                    "type": "string",
+                    "example": "user:drop:cache"
+                },
+                "effect": {
+                    "type": "string",
+                    "example": "require"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin"
+                    ]
+                },
+                "source": {
+                    "description": "Where rule was defined: \"builtin\", \"config\" or \"schema\"",
+                    "type": "string",
+                    "example": "builtin"
+                }
+            }
+        },
+        "authz.Decision": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "condition": {
+                    "description": "Attribute-based condition of the context, omitted if there is no such condition.\nRoles alone can't satisfy it, so if roles are sufficient, then decision isn't allowed,\ninstead its reason is \"conditional\": action is allowed only if request attributes satisfy this condition.",
+                    "type": "string",
+                    "example": "target.self || subject.organization in target.organizations"
+                },
+                "reason": {
+                    "description": "Why authorization was denied, empty if it was allowed.\nEither \"insufficient-permissions\", \"denied-by-agp\" or \"conditional\".",
+                    "type": "string",
+                    "example": "insufficient-permissions"
+                }
+            }
+        },
+        "authz.Explanation": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "condition": {
+                    "description": "Attribute-based condition of the context, omitted if there is no such condition.\nRoles alone can't satisfy it, so if roles are sufficient, then decision isn't allowed,\ninstead its reason is \"conditional\": action is allowed only if request attributes satisfy this condition.",
+                    "type": "string",
+                    "example": "target.self || subject.organization in target.organizations"
+                },
+                "context": {
+                    "description": "\"\u003centity\u003e:\u003caction\u003e:\u003cresource\u003e\"",
+                    "type": "string",
+                    "example": "user:drop:cache"
+                },
+                "granted-permissions": {
+                    "description": "Union of permissions of all roles",
+                    "type": "integer",
+                    "example": 84
+                },
+                "missing-permissions": {
+                    "description": "Required permissions which weren't granted by any role",
+                    "type": "integer",
+                    "example": 0
+                },
+                "missing-permissions-names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "description": "Why authorization was denied, empty if it was allowed.\nEither \"insufficient-permissions\", \"denied-by-agp\" or \"conditional\".",
+                    "type": "string",
+                    "example": "insufficient-permissions"
+                },
+                "required-permissions": {
+                    "description": "Permissions required by the action of the context",
+                    "type": "integer",
+                    "example": 64
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/authz.RoleExplanation"
+                    }
+                },
+                "rule": {
+                    "description": "Action Gate Policy rule for the context, omitted if there is no such rule",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/authz.ActionGateRule"
+                        }
+                    ]
+                },
+                "rule-fired": {
+                    "description": "Is rule affected the decision: denied it or authorized action bypassing permissions check",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "authz.RoleExplanation": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "moderator"
+                },
+                "overridden": {
+                    "description": "Is role permissions were overridden for the resource of the context",
+                    "type": "boolean",
+                    "example": false
+                },
+                "permissions": {
+                    "description": "Permissions which role grants for the resource of the context",
+                    "type": "integer",
+                    "example": 84
+                },
+                "permissions-names": {
+                    "description": "Names of these permissions",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "update",
+                        "delete"
+                    ]
+                }
+            }
+        },
+        "groupdto.Full": {
+            "type": "object",
+            "properties": {
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b6c1d2e-3f4a-4b5c-8d6e-7f8091a2b3c4"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "developers"
+                },
+                "roles": {
+                    "description": "Roles which are inherited by all members of the group",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user",
+                        "moderator"
+                    ]
+                },
+                "updated-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                }
+            }
+        },
+        "groupdto.Member": {
+            "type": "object",
+            "properties": {
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "group-id": {
+                    "type": "string",
+                    "example": "0b6c1d2e-3f4a-4b5c-8d6e-7f8091a2b3c4"
+                },
+                "login": {
+                    "type": "string",
+                    "example": "user@mail.com"
+                },
+                "user-id": {
+                    "type": "string",
+                    "example": "d529a8d2-1eb4-4bce-82aa-e62095dbc653"
+                }
+            }
+        },
+        "jobdto.Run": {
+            "type": "object",
+            "properties": {
+                "affected-rows": {
+                    "description": "Amount of purged (or archived) rows",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Internal Server Error"
+                },
+                "finished-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:15.012Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "instance-id": {
+                    "description": "ID of the Sentinel instance which has run the job",
+                    "type": "string",
+                    "example": "cb663674-803e-4b06-bfeb-87c5cc86383e"
+                },
+                "job": {
+                    "type": "string",
+                    "example": "expired-sessions"
+                },
+                "started-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "status": {
+                    "description": "\"succeeded\" or \"failed\"",
+                    "type": "string",
+                    "example": "succeeded"
+                }
+            }
+        },
+        "organizationdto.Full": {
+            "type": "object",
+            "properties": {
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5c0e1a7b-3f2d-4c8e-9b6a-1d2e3f4a5b6c"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Acme Corp"
+                }
+            }
+        },
+        "organizationdto.Member": {
+            "type": "object",
+            "properties": {
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "login": {
+                    "type": "string",
+                    "example": "user@mail.com"
+                },
+                "organization-id": {
+                    "type": "string",
+                    "example": "5c0e1a7b-3f2d-4c8e-9b6a-1d2e3f4a5b6c"
+                },
+                "roles": {
+                    "description": "Roles of the user in this organization",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user",
+                        "admin"
+                    ]
+                },
+                "updated-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "user-id": {
+                    "type": "string",
+                    "example": "d529a8d2-1eb4-4bce-82aa-e62095dbc653"
+                }
+            }
+        },
+        "requestbody.ActionReason": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
+                }
+            }
+        },
+        "requestbody.Auth": {
+            "type": "object",
+            "properties": {
+                "audience": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "login": {
+                    "type": "string",
+                    "example": "admin@mail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "your-password"
+                }
+            }
+        },
+        "requestbody.AuthzBatchCheck": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/requestbody.AuthzCheck"
+                    }
+                }
+            }
+        },
+        "requestbody.AuthzCheck": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "get_user"
+                },
+                "entity": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "resource": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "roles": {
+                    "description": "Roles of the subject. Ignored if token is specified.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user"
+                    ]
+                },
+                "service-id": {
+                    "type": "string",
+                    "example": "sentinel"
+                },
+                "token": {
+                    "description": "Access token of the subject, its roles will be used.",
+                    "type": "string",
+                    "example": "eyJhbGciOiJFZER..."
+                }
+            }
+        },
+        "requestbody.ChangeGroupRoles": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user",
+                        "moderator"
+                    ]
+                }
+            }
+        },
+        "requestbody.ChangePassword": {
+            "type": "object",
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "example": "your-new-password"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "your-password"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
+                }
+            }
+        },
+        "requestbody.ChangeRoles": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
                     "example": "your-password"
                 },
                 "reason": {
                     "type": "string",
                     "example": "Violation of terms of use"
                 },
-                "roles": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user",
+                        "moderator"
+                    ]
+                }
+            }
+        },
+        "requestbody.CreateGroup": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "developers"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user",
+                        "moderator"
+                    ]
+                }
+            }
+        },
+        "requestbody.CreateOrganization": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Acme Corp"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
+                }
+            }
+        },
+        "requestbody.CreateRole": {
+            "type": "object"
+        },
+        "requestbody.GrantRole": {
+            "type": "object",
+            "properties": {
+                "expires-at": {
+                    "description": "Optional, if not specified then grant will never expire",
+                    "type": "string",
+                    "example": "2025-07-21T03:54:14.503Z"
+                },
+                "not-before": {
+                    "description": "Optional, if not specified then grant will be active right away",
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "requestbody.Impersonate": {
+            "type": "object",
+            "properties": {
+                "audience": {
+                    "description": "Optional, by default token will be issued only for this service",
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
-                        "user",
-                        "moderator"
+                        "urn:api:auth"
                     ]
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
                 }
             }
         },
@@ -2451,256 +8605,760 @@ const docTemplate = `{
                 }
             }
         },
+        "requestbody.LoginChangeToken": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJFZER..."
+                }
+            }
+        },
+        "requestbody.OneTimeCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "042917"
+                }
+            }
+        },
         "requestbody.PasswordReset": {
             "type": "object",
             "properties": {
                 "password": {
                     "type": "string",
-                    "example": "your-password"
+                    "example": "your-password"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJFZER..."
+                }
+            }
+        },
+        "requestbody.RenameDevice": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Friendly name of the device, e.g. \"Work laptop\". Empty string removes the name.",
+                    "type": "string",
+                    "example": "Work laptop"
+                }
+            }
+        },
+        "requestbody.RenameSession": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Friendly name of the device, e.g. \"Work laptop\". Empty string removes the name.",
+                    "type": "string",
+                    "example": "Work laptop"
+                }
+            }
+        },
+        "requestbody.SetOrganizationMember": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user",
+                        "moderator"
+                    ]
+                }
+            }
+        },
+        "requestbody.SwitchOrganization": {
+            "type": "object",
+            "properties": {
+                "organization-id": {
+                    "description": "Optional, if empty then tokens won't be scoped to any organization",
+                    "type": "string",
+                    "example": "5c0e1a7b-3f2d-4c8e-9b6a-1d2e3f4a5b6c"
+                }
+            }
+        },
+        "requestbody.TrustDevice": {
+            "type": "object",
+            "properties": {
+                "trusted": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "requestbody.TrustSession": {
+            "type": "object",
+            "properties": {
+                "trusted": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "requestbody.UpdateRole": {
+            "type": "object"
+        },
+        "requestbody.UserLogin": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "example": "admin@mail.com"
+                }
+            }
+        },
+        "requestbody.UserPassword": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "your-password"
+                }
+            }
+        },
+        "requestbody.UserRoles": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user",
+                        "moderator"
+                    ]
+                }
+            }
+        },
+        "requestbody.UsersIDs": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "cef85e5a-5a5f-42d0-81bd-1650391c0e82",
+                        "9bc87af1-5f92-4d8c-bf41-7ade642c5a91"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Violation of terms of use"
+                }
+            }
+        },
+        "responsebody.CSRF": {
+            "type": "object",
+            "properties": {
+                "csrf-token": {
+                    "type": "string",
+                    "example": "h3PCI++3T0fEphsWoupOQyIQjlOx953bF0wlhMNu1jw="
+                }
+            }
+        },
+        "responsebody.Error": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Error"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Something went wrong"
+                }
+            }
+        },
+        "responsebody.Introspection": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "aud": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "urn:api:auth",
+                        "urn:api:billing"
+                    ]
+                },
+                "exp": {
+                    "type": "integer",
+                    "example": 1753707388
+                },
+                "iat": {
+                    "type": "integer",
+                    "example": 1753706788
+                },
+                "iss": {
+                    "type": "string",
+                    "example": "3c23ebbd-42af-47c6-9c50-7295b3ac3a62"
+                },
+                "jti": {
+                    "type": "string",
+                    "example": "ade1cdb0-309c-48c5-8251-c3f39ec0d606"
+                },
+                "scope": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "write"
+                    ]
+                },
+                "sub": {
+                    "type": "string",
+                    "example": "c9fcc8e3-f4f1-4b85-a65e-29bb889cbccb"
+                }
+            }
+        },
+        "responsebody.IsLoginAvailable": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "responsebody.JSONWebKey": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string",
+                    "example": "EdDSA"
+                },
+                "crv": {
+                    "type": "string",
+                    "example": "Ed25519"
+                },
+                "kid": {
+                    "type": "string",
+                    "example": "access-1"
+                },
+                "kty": {
+                    "type": "string",
+                    "example": "OKP"
                 },
-                "token": {
+                "use": {
                     "type": "string",
-                    "example": "eyJhbGciOiJFZER..."
+                    "example": "sig"
+                },
+                "x": {
+                    "type": "string",
+                    "example": "Vzu3AwphVg7zmlrmAojBswMl4xoEIzsc9BY5DHGgUzo"
                 }
             }
         },
-        "requestbody.UserLogin": {
+        "responsebody.JWKs": {
             "type": "object",
             "properties": {
-                "login": {
-                    "type": "string",
-                    "example": "admin@mail.com"
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responsebody.JSONWebKey"
+                    }
                 }
             }
         },
-        "requestbody.UserPassword": {
+        "responsebody.Location": {
             "type": "object",
             "properties": {
-                "password": {
+                "city": {
                     "type": "string",
-                    "example": "your-password"
+                    "example": "Ashburn"
+                },
+                "countryCode": {
+                    "description": "ISO 3166-1 alpha-2",
+                    "type": "string",
+                    "example": "US"
+                },
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-15T22:27:50.294Z"
+                },
+                "deleted-at": {
+                    "type": "string",
+                    "example": "2025-07-15T22:27:50.294Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0de6c6e9-5360-4cd8-a068-24ea035a0bd7"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "8.8.8.8"
+                },
+                "isp": {
+                    "type": "string",
+                    "example": "Google LLC"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 39.03
+                },
+                "lon": {
+                    "type": "number",
+                    "example": -77.5
+                },
+                "region": {
+                    "description": "ISO 3166-2 region code",
+                    "type": "string",
+                    "example": "VA"
                 }
             }
         },
-        "requestbody.UserRoles": {
+        "responsebody.RevokedSessions": {
             "type": "object",
             "properties": {
-                "roles": {
+                "sessions": {
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "user",
-                        "moderator"
-                    ]
+                        "$ref": "#/definitions/sessiondto.Public"
+                    }
                 }
             }
         },
-        "requestbody.UsersIDs": {
+        "responsebody.Token": {
             "type": "object",
             "properties": {
-                "id": {
+                "accessToken": {
+                    "type": "string",
+                    "example": "eyJhbGciOi..."
+                },
+                "evictedSessions": {
+                    "description": "Sessions which were revoked on login due to session limit",
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "cef85e5a-5a5f-42d0-81bd-1650391c0e82",
-                        "9bc87af1-5f92-4d8c-bf41-7ade642c5a91"
-                    ]
+                        "$ref": "#/definitions/sessiondto.Public"
+                    }
                 },
-                "reason": {
+                "expiresIn": {
+                    "type": "integer",
+                    "example": 600
+                },
+                "message": {
                     "type": "string",
-                    "example": "Violation of terms of use"
+                    "example": "hello"
                 }
             }
         },
-        "responsebody.CSRF": {
+        "responsebody.UserDevice": {
             "type": "object",
             "properties": {
-                "csrf-token": {
+                "browser": {
                     "type": "string",
-                    "example": "h3PCI++3T0fEphsWoupOQyIQjlOx953bF0wlhMNu1jw="
+                    "example": "Firefox"
+                },
+                "current": {
+                    "description": "True if this is the device of the current request",
+                    "type": "boolean",
+                    "example": true
+                },
+                "device-type": {
+                    "type": "string",
+                    "example": "desktop"
+                },
+                "first-seen-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f1c0f4e-8a51-4c43-9d7b-2c5d1e0b8f6a"
+                },
+                "last-seen-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "name": {
+                    "description": "Friendly device name given by the user",
+                    "type": "string",
+                    "example": "Work laptop"
+                },
+                "os": {
+                    "type": "string",
+                    "example": "Linux"
+                },
+                "trusted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "user-agent": {
+                    "description": "User agent of the last login from this device",
+                    "type": "string",
+                    "example": "Mozilla/5.0 (X11; Linux x86_64; rv:138.0) Gecko/20100101 Firefox/138.0"
+                },
+                "user-id": {
+                    "type": "string",
+                    "example": "7ee80427-b0c6-4120-b874-ba8567576b6d"
                 }
             }
         },
-        "responsebody.Error": {
+        "responsebody.UserSession": {
             "type": "object",
             "properties": {
-                "error": {
+                "browser": {
                     "type": "string",
-                    "example": "Error"
+                    "example": "Firefox"
                 },
-                "message": {
+                "browser-version": {
                     "type": "string",
-                    "example": "Something went wrong"
+                    "example": "138.0"
+                },
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "current": {
+                    "description": "True if this is the session of the current request",
+                    "type": "boolean",
+                    "example": true
+                },
+                "device-id": {
+                    "type": "string",
+                    "example": "Linux x86_64 Firefox/138.0"
+                },
+                "device-type": {
+                    "type": "string",
+                    "example": "desktop"
+                },
+                "expires-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "254be108-2a12-4b0f-b095-c10cd80ef91d"
+                },
+                "ip-address": {
+                    "type": "string",
+                    "example": "8.8.8.8"
+                },
+                "last-used-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "location": {
+                    "$ref": "#/definitions/responsebody.Location"
+                },
+                "name": {
+                    "description": "Friendly device name given by the user",
+                    "type": "string",
+                    "example": "Work laptop"
+                },
+                "os": {
+                    "type": "string",
+                    "example": "Linux"
+                },
+                "os-version": {
+                    "type": "string",
+                    "example": "Unknown"
+                },
+                "trusted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "user-agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (X11; Linux x86_64; rv:138.0) Gecko/20100101 Firefox/138.0"
                 }
             }
         },
-        "responsebody.Introspection": {
+        "revocation.Event": {
             "type": "object",
             "properties": {
-                "active": {
-                    "type": "boolean",
-                    "example": true
+                "id": {
+                    "description": "ID of the event in the revocation stream. Can be used as Last-Event-ID.",
+                    "type": "string"
                 },
-                "aud": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "urn:api:auth",
-                        "urn:api:billing"
-                    ]
+                "session_id": {
+                    "type": "string"
                 },
-                "exp": {
-                    "type": "integer",
-                    "example": 1753707388
+                "timestamp": {
+                    "description": "Unix timestamp (seconds)",
+                    "type": "integer"
                 },
-                "iat": {
-                    "type": "integer",
-                    "example": 1753706788
+                "type": {
+                    "$ref": "#/definitions/revocation.EventType"
                 },
-                "iss": {
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "revocation.EventType": {
+            "type": "string",
+            "enum": [
+                "session_revoked",
+                "user_version_changed"
+            ],
+            "x-enum-varnames": [
+                "SessionRevokedEvent",
+                "UserVersionChangedEvent"
+            ]
+        },
+        "roledto.Permissions": {
+            "type": "object",
+            "properties": {
+                "create": {
+                    "type": "boolean"
+                },
+                "delete": {
+                    "type": "boolean"
+                },
+                "read": {
+                    "type": "boolean"
+                },
+                "self-create": {
+                    "type": "boolean"
+                },
+                "self-delete": {
+                    "type": "boolean"
+                },
+                "self-read": {
+                    "type": "boolean"
+                },
+                "self-update": {
+                    "type": "boolean"
+                },
+                "update": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "roledto.Public": {
+            "type": "object",
+            "properties": {
+                "created-at": {
                     "type": "string",
-                    "example": "3c23ebbd-42af-47c6-9c50-7295b3ac3a62"
+                    "example": "2025-07-20T23:54:14.503Z"
                 },
-                "jti": {
+                "id": {
                     "type": "string",
-                    "example": "ade1cdb0-309c-48c5-8251-c3f39ec0d606"
+                    "example": "0b7c1f5e-4b0a-4d8e-9d43-0c9c6f2b6a11"
                 },
-                "scope": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "read",
-                        "write"
-                    ]
+                "is-default": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "moderator"
+                },
+                "permissions": {
+                    "$ref": "#/definitions/roledto.Permissions"
                 },
-                "sub": {
+                "resource-permissions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/roledto.Permissions"
+                    }
+                },
+                "schema-id": {
                     "type": "string",
-                    "example": "c9fcc8e3-f4f1-4b85-a65e-29bb889cbccb"
+                    "example": "cb663674-803e-4b06-bfeb-87c5cc86383e"
+                },
+                "updated-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
                 }
             }
         },
-        "responsebody.IsLoginAvailable": {
+        "roles.Diff": {
             "type": "object",
             "properties": {
-                "available": {
-                    "type": "boolean",
-                    "example": true
+                "added-default-roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "added-roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "changed-roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed-default-roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed-roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "responsebody.JSONWebKey": {
+        "scheduler.JobStatus": {
             "type": "object",
             "properties": {
-                "alg": {
-                    "type": "string",
-                    "example": "EdDSA"
+                "metrics": {
+                    "$ref": "#/definitions/scheduler.Metrics"
                 },
-                "crv": {
+                "mode": {
                     "type": "string",
-                    "example": "Ed25519"
+                    "example": "purge"
                 },
-                "kid": {
+                "name": {
                     "type": "string",
-                    "example": "access-1"
+                    "example": "expired-sessions"
                 },
-                "kty": {
+                "next-run-at": {
+                    "description": "Time of the next run on this instance, absent if scheduler is disabled",
                     "type": "string",
-                    "example": "OKP"
+                    "example": "2025-07-21T00:00:00Z"
                 },
-                "use": {
+                "retention": {
+                    "description": "How long records are kept before they are purged",
                     "type": "string",
-                    "example": "sig"
+                    "example": "720h"
                 },
-                "x": {
+                "schedule": {
                     "type": "string",
-                    "example": "Vzu3AwphVg7zmlrmAojBswMl4xoEIzsc9BY5DHGgUzo"
+                    "example": "*/30 * * * *"
                 }
             }
         },
-        "responsebody.JWKs": {
+        "scheduler.Metrics": {
             "type": "object",
             "properties": {
-                "keys": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/responsebody.JSONWebKey"
-                    }
+                "affected-rows": {
+                    "description": "Total amount of purged (or archived) rows",
+                    "type": "integer",
+                    "example": 4210
+                },
+                "failures": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "last-run-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "last-run-duration-ms": {
+                    "type": "integer",
+                    "example": 153
+                },
+                "last-run-status": {
+                    "type": "string",
+                    "example": "succeeded"
+                },
+                "runs": {
+                    "description": "Runs made by this instance",
+                    "type": "integer",
+                    "example": 12
+                },
+                "skipped": {
+                    "description": "Runs skipped cuz job was run by another instance",
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "responsebody.Location": {
+        "sessiondto.Login": {
             "type": "object",
             "properties": {
+                "browser": {
+                    "type": "string",
+                    "example": "Firefox"
+                },
                 "city": {
                     "type": "string",
-                    "example": "Ashburn"
+                    "example": "Mountain View"
                 },
-                "countryCode": {
-                    "description": "ISO 3166-1 alpha-2",
+                "country": {
                     "type": "string",
                     "example": "US"
                 },
-                "created-at": {
+                "device-type": {
                     "type": "string",
-                    "example": "2025-07-15T22:27:50.294Z"
+                    "example": "desktop"
                 },
-                "deleted-at": {
+                "ip-address": {
+                    "description": "Last known IP address of the session",
                     "type": "string",
-                    "example": "2025-07-15T22:27:50.294Z"
+                    "example": "8.8.4.4"
                 },
-                "id": {
+                "isp": {
                     "type": "string",
-                    "example": "0de6c6e9-5360-4cd8-a068-24ea035a0bd7"
+                    "example": "Google LLC"
                 },
-                "ip": {
+                "last-used-at": {
                     "type": "string",
-                    "example": "8.8.8.8"
+                    "example": "2025-07-20T23:54:14.503Z"
                 },
-                "isp": {
+                "logged-in-at": {
                     "type": "string",
-                    "example": "Google LLC"
+                    "example": "2025-07-20T23:54:14.503Z"
                 },
-                "lat": {
-                    "type": "number",
-                    "example": 39.03
+                "login-ip-address": {
+                    "description": "IP address from which user logged in",
+                    "type": "string",
+                    "example": "8.8.8.8"
                 },
-                "lon": {
-                    "type": "number",
-                    "example": -77.5
+                "os": {
+                    "type": "string",
+                    "example": "Linux"
                 },
                 "region": {
-                    "description": "ISO 3166-2 region code",
                     "type": "string",
-                    "example": "VA"
-                }
-            }
-        },
-        "responsebody.Token": {
-            "type": "object",
-            "properties": {
-                "accessToken": {
+                    "example": "CA"
+                },
+                "revoke-reason": {
                     "type": "string",
-                    "example": "eyJhbGciOi..."
+                    "example": "Logout"
                 },
-                "expiresIn": {
-                    "type": "integer",
-                    "example": 600
+                "revoked-at": {
+                    "description": "Nil if session wasn't revoked",
+                    "type": "string",
+                    "example": "2025-07-21T10:12:40.118Z"
                 },
-                "message": {
+                "revoked-by": {
+                    "description": "ID of the user who revoked the session",
                     "type": "string",
-                    "example": "hello"
+                    "example": "7ee80427-b0c6-4120-b874-ba8567576b6d"
+                },
+                "session-id": {
+                    "type": "string",
+                    "example": "254be108-2a12-4b0f-b095-c10cd80ef91d"
+                },
+                "user-agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (X11; Linux x86_64; rv:138.0) Gecko/20100101 Firefox/138.0"
                 }
             }
         },
-        "responsebody.UserSession": {
+        "sessiondto.Public": {
             "type": "object",
             "properties": {
                 "browser": {
@@ -2739,8 +9397,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2025-07-20T23:54:14.503Z"
                 },
-                "location": {
-                    "$ref": "#/definitions/responsebody.Location"
+                "name": {
+                    "description": "Friendly device name given by the user",
+                    "type": "string",
+                    "example": "Work laptop"
                 },
                 "os": {
                     "type": "string",
@@ -2750,15 +9410,84 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Unknown"
                 },
+                "trusted": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "user-agent": {
                     "type": "string",
                     "example": "Mozilla/5.0 (X11; Linux x86_64; rv:138.0) Gecko/20100101 Firefox/138.0"
                 }
             }
         },
+        "userdto.LoginChangeRequest": {
+            "type": "object",
+            "properties": {
+                "cancellable-until": {
+                    "description": "Confirmed change can be reverted via link sent to the old login until this time",
+                    "type": "string",
+                    "example": "2025-07-24T10:12:44.503Z"
+                },
+                "confirmed-at": {
+                    "type": "string",
+                    "example": "2025-07-21T10:12:44.503Z"
+                },
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "expires-at": {
+                    "description": "Deadline for confirmation of the new login",
+                    "type": "string",
+                    "example": "2025-07-21T23:54:14.503Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "new-login": {
+                    "type": "string",
+                    "example": "new@example.com"
+                },
+                "old-login": {
+                    "type": "string",
+                    "example": "old@example.com"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "User lost access to the old email"
+                },
+                "requested-by-user-id": {
+                    "type": "string",
+                    "example": "d529a8d2-1eb4-4bce-82aa-e62095dbc653"
+                },
+                "resolved-at": {
+                    "type": "string",
+                    "example": "2025-07-21T10:12:44.503Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "user-id": {
+                    "type": "string",
+                    "example": "d529a8d2-1eb4-4bce-82aa-e62095dbc653"
+                }
+            }
+        },
         "userdto.Payload": {
             "type": "object",
             "properties": {
+                "amr": {
+                    "description": "Authentication methods references (RFC 8176)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pwd"
+                    ]
+                },
                 "audience": {
                     "type": "array",
                     "items": {
@@ -2770,14 +9499,29 @@ const docTemplate = `{
                         "https://example.domain.com"
                     ]
                 },
+                "auth-time": {
+                    "description": "Unix time (in seconds) when user was authenticated last time (login or re-authentication)",
+                    "type": "integer",
+                    "example": 1735689600
+                },
                 "id": {
                     "type": "string",
                     "example": "d529a8d2-1eb4-4bce-82aa-e62095dbc653"
                 },
+                "impersonator-id": {
+                    "description": "ID of the user who impersonates this user (see \"act\" token claim).\nEmpty if token wasn't issued via impersonation.",
+                    "type": "string",
+                    "example": "4d9a3a6e-9d2a-4b1a-8f0e-4b3e5c2a1f7d"
+                },
                 "login": {
                     "type": "string",
                     "example": "admin@mail.com"
                 },
+                "organization-id": {
+                    "description": "ID of the organization to which token is scoped (see \"org\" token claim).\nEmpty if token isn't scoped to any organization.",
+                    "type": "string",
+                    "example": "5c0e1a7b-3f2d-4c8e-9b6a-1d2e3f4a5b6c"
+                },
                 "roles": {
                     "type": "array",
                     "items": {
@@ -2820,6 +9564,108 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "userdto.RoleChangeRequest": {
+            "type": "object",
+            "properties": {
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "expires-at": {
+                    "type": "string",
+                    "example": "2025-07-23T23:54:14.503Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b3f1e9a-6c2d-4e7f-8a1b-2c3d4e5f6a7b"
+                },
+                "old-roles": {
+                    "description": "User roles at the moment of request creation",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Promotion to the administrator"
+                },
+                "requested-by-user-id": {
+                    "type": "string",
+                    "example": "4d9a3a6e-9d2a-4b1a-8f0e-4b3e5c2a1f7d"
+                },
+                "resolved-at": {
+                    "type": "string",
+                    "example": "2025-07-21T10:12:44.503Z"
+                },
+                "resolved-by-user-id": {
+                    "description": "ID of the user who approved or cancelled this request, empty if request is pending or expired",
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user",
+                        "admin"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "user-id": {
+                    "type": "string",
+                    "example": "d529a8d2-1eb4-4bce-82aa-e62095dbc653"
+                }
+            }
+        },
+        "userdto.RoleGrant": {
+            "type": "object",
+            "properties": {
+                "activated-at": {
+                    "description": "Time when grant became active, nil if it isn't active yet",
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "created-at": {
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "expires-at": {
+                    "description": "If nil, then grant never expires",
+                    "type": "string",
+                    "example": "2025-07-21T03:54:14.503Z"
+                },
+                "granted-by-user-id": {
+                    "type": "string",
+                    "example": "4d9a3a6e-9d2a-4b1a-8f0e-4b3e5c2a1f7d"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6f1f2c7a-2b0e-4d6a-9a3c-7d1e8b2f4c5d"
+                },
+                "not-before": {
+                    "description": "If nil, then grant is active right after creation",
+                    "type": "string",
+                    "example": "2025-07-20T23:54:14.503Z"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "user-id": {
+                    "type": "string",
+                    "example": "d529a8d2-1eb4-4bce-82aa-e62095dbc653"
+                }
+            }
         }
     },
     "securityDefinitions": {
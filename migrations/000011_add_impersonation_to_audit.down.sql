@@ -0,0 +1,5 @@
+BEGIN;
+    ALTER TABLE "audit_user" DROP COLUMN impersonated_by_user_id;
+
+    ALTER TABLE "audit_user_session" DROP COLUMN impersonated_by_user_id;
+COMMIT;
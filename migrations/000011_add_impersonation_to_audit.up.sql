@@ -0,0 +1,6 @@
+BEGIN;
+    -- ID of the user who performed the change on behalf of changed_by_user_id (via impersonation)
+    ALTER TABLE IF EXISTS "audit_user" ADD COLUMN impersonated_by_user_id UUID;
+
+    ALTER TABLE IF EXISTS "audit_user_session" ADD COLUMN impersonated_by_user_id UUID;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+    DROP TABLE IF EXISTS "audit_rbac_role";
+
+    DROP TABLE IF EXISTS "rbac_role";
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+    CREATE TABLE IF NOT EXISTS "rbac_role" (
+        id                      UUID PRIMARY KEY,
+        -- Empty string means that role is global (shared by all schemas)
+        schema_id               VARCHAR(64) NOT NULL DEFAULT '',
+        name                    VARCHAR(32) NOT NULL,
+        permissions             SMALLINT NOT NULL,
+        -- Overrides of role permissions for specific resources: {"<resource>": <permissions>}
+        resource_permissions    JSONB NOT NULL DEFAULT '{}',
+        is_default              BOOLEAN NOT NULL DEFAULT FALSE,
+        created_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        updated_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        UNIQUE (schema_id, name)
+    );
+
+    CREATE TABLE IF NOT EXISTS "audit_rbac_role" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        changed_role_id         UUID NOT NULL,
+        changed_by_user_id      UUID,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        schema_id               VARCHAR(64) NOT NULL,
+        name                    VARCHAR(32) NOT NULL,
+        permissions             SMALLINT NOT NULL,
+        resource_permissions    JSONB NOT NULL,
+        is_default              BOOLEAN NOT NULL,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+COMMIT;
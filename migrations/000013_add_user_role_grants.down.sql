@@ -0,0 +1,5 @@
+BEGIN;
+    DROP TABLE IF EXISTS "audit_user_role_grant";
+
+    DROP TABLE IF EXISTS "user_role_grant";
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+    CREATE TABLE IF NOT EXISTS "user_role_grant" (
+        id                      UUID PRIMARY KEY,
+        user_id                 UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+        role                    VARCHAR(32) NOT NULL,
+        -- NULL means that grant is active right after creation
+        not_before              TIMESTAMP,
+        -- NULL means that grant never expires
+        expires_at              TIMESTAMP,
+        -- Time when grant became active (and user version was bumped), NULL if it isn't active yet
+        activated_at            TIMESTAMP,
+        granted_by_user_id      UUID NOT NULL,
+        created_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        CHECK (expires_at IS NULL OR not_before IS NULL OR expires_at > not_before)
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_user_role_grant_user_id ON "user_role_grant" (user_id);
+    CREATE INDEX IF NOT EXISTS idx_user_role_grant_expires_at ON "user_role_grant" (expires_at) WHERE expires_at IS NOT NULL;
+    CREATE INDEX IF NOT EXISTS idx_user_role_grant_pending ON "user_role_grant" (not_before) WHERE activated_at IS NULL;
+
+    CREATE TABLE IF NOT EXISTS "audit_user_role_grant" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        changed_grant_id        UUID NOT NULL,
+        user_id                 UUID NOT NULL,
+        -- NULL if change was made by Sentinel itself (e.g. grant has expired)
+        changed_by_user_id      UUID,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        role                    VARCHAR(32) NOT NULL,
+        not_before              TIMESTAMP,
+        expires_at              TIMESTAMP,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+    DROP TABLE IF EXISTS "audit_role_change_request";
+
+    DROP TABLE IF EXISTS "role_change_request";
+COMMIT;
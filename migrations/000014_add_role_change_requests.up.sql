@@ -0,0 +1,36 @@
+BEGIN;
+    CREATE TABLE IF NOT EXISTS "role_change_request" (
+        id                      UUID PRIMARY KEY,
+        user_id                 UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+        requested_by_user_id    UUID NOT NULL,
+        -- Roles which will be set to the user once request is approved
+        roles                   VARCHAR(32)[] NOT NULL,
+        -- Roles of the user at the moment of request creation.
+        -- Request can't be approved if user roles were changed since then.
+        old_roles               VARCHAR(32)[] NOT NULL,
+        status                  VARCHAR(16) NOT NULL DEFAULT 'pending',
+        reason                  TEXT,
+        created_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        expires_at              TIMESTAMP NOT NULL,
+        resolved_by_user_id     UUID,
+        resolved_at             TIMESTAMP,
+        CHECK (status IN ('pending', 'approved', 'cancelled', 'expired'))
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_role_change_request_user_id ON "role_change_request" (user_id);
+    CREATE INDEX IF NOT EXISTS idx_role_change_request_pending ON "role_change_request" (expires_at) WHERE status = 'pending';
+
+    CREATE TABLE IF NOT EXISTS "audit_role_change_request" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        changed_request_id      UUID NOT NULL,
+        user_id                 UUID NOT NULL,
+        -- NULL if change was made by Sentinel itself (e.g. request has expired)
+        changed_by_user_id      UUID,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        roles                   VARCHAR(32)[] NOT NULL,
+        status                  VARCHAR(16) NOT NULL,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+    DROP TABLE IF EXISTS "audit_organization_member";
+
+    DROP TABLE IF EXISTS "audit_organization";
+
+    DROP TABLE IF EXISTS "organization_member";
+
+    DROP TABLE IF EXISTS "organization";
+COMMIT;
@@ -0,0 +1,42 @@
+BEGIN;
+    CREATE TABLE IF NOT EXISTS "organization" (
+        id                      UUID PRIMARY KEY,
+        name                    VARCHAR(72) NOT NULL UNIQUE,
+        created_at              TIMESTAMP NOT NULL DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS "organization_member" (
+        organization_id         UUID NOT NULL REFERENCES "organization"(id) ON DELETE CASCADE,
+        user_id                 UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+        -- Roles of the user in this organization, they are used instead of user roles in organization-scoped tokens
+        roles                   VARCHAR(32)[] NOT NULL,
+        created_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        updated_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (organization_id, user_id)
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_organization_member_user_id ON "organization_member" (user_id);
+
+    CREATE TABLE IF NOT EXISTS "audit_organization" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        changed_organization_id UUID NOT NULL,
+        changed_by_user_id      UUID NOT NULL,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        name                    VARCHAR(72) NOT NULL,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+
+    CREATE TABLE IF NOT EXISTS "audit_organization_member" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        organization_id         UUID NOT NULL,
+        user_id                 UUID NOT NULL,
+        changed_by_user_id      UUID NOT NULL,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        roles                   VARCHAR(32)[] NOT NULL,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+    DROP TABLE IF EXISTS "audit_user_group_member";
+
+    DROP TABLE IF EXISTS "audit_user_group";
+
+    DROP TABLE IF EXISTS "user_group_member";
+
+    DROP TABLE IF EXISTS "user_group";
+COMMIT;
@@ -0,0 +1,42 @@
+BEGIN;
+    CREATE TABLE IF NOT EXISTS "user_group" (
+        id                      UUID PRIMARY KEY,
+        name                    VARCHAR(72) NOT NULL UNIQUE,
+        -- Roles which are inherited by all members of the group
+        roles                   VARCHAR(32)[] NOT NULL,
+        created_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        updated_at              TIMESTAMP NOT NULL DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS "user_group_member" (
+        group_id                UUID NOT NULL REFERENCES "user_group"(id) ON DELETE CASCADE,
+        user_id                 UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+        created_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (group_id, user_id)
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_user_group_member_user_id ON "user_group_member" (user_id);
+
+    CREATE TABLE IF NOT EXISTS "audit_user_group" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        changed_group_id        UUID NOT NULL,
+        changed_by_user_id      UUID NOT NULL,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        name                    VARCHAR(72) NOT NULL,
+        roles                   VARCHAR(32)[] NOT NULL,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+
+    CREATE TABLE IF NOT EXISTS "audit_user_group_member" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        group_id                UUID NOT NULL,
+        user_id                 UUID NOT NULL,
+        changed_by_user_id      UUID NOT NULL,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+COMMIT;
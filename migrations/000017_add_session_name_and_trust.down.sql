@@ -0,0 +1,9 @@
+BEGIN;
+    ALTER TABLE "audit_user_session" DROP COLUMN trusted;
+
+    ALTER TABLE "audit_user_session" DROP COLUMN name;
+
+    ALTER TABLE "user_session" DROP COLUMN trusted;
+
+    ALTER TABLE "user_session" DROP COLUMN name;
+COMMIT;
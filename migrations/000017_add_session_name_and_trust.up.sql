@@ -0,0 +1,10 @@
+BEGIN;
+    -- Friendly device name given by the user, NULL if session wasn't named
+    ALTER TABLE IF EXISTS "user_session" ADD COLUMN name VARCHAR(64);
+
+    ALTER TABLE IF EXISTS "user_session" ADD COLUMN trusted BOOLEAN NOT NULL DEFAULT FALSE;
+
+    ALTER TABLE IF EXISTS "audit_user_session" ADD COLUMN name VARCHAR(64);
+
+    ALTER TABLE IF EXISTS "audit_user_session" ADD COLUMN trusted BOOLEAN NOT NULL DEFAULT FALSE;
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+    -- Risk assessments of sessions, made on login and on each refresh from the new location
+    CREATE TABLE IF NOT EXISTS "session_risk" (
+        id          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        session_id  UUID NOT NULL,
+        user_id     UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+        ip          INET,
+        country     VARCHAR(2),
+        score       SMALLINT NOT NULL,
+        reasons     VARCHAR(32)[] NOT NULL,
+        -- none, alert, step_up, block
+        action      VARCHAR(16) NOT NULL,
+        created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_session_risk_user_id ON "session_risk" (user_id, created_at);
+    CREATE INDEX IF NOT EXISTS idx_session_risk_session_id ON "session_risk" (session_id);
+COMMIT;
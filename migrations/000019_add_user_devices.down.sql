@@ -0,0 +1,9 @@
+BEGIN;
+    DROP INDEX IF EXISTS idx_user_session_user_device_id;
+
+    ALTER TABLE "user_session" DROP COLUMN user_device_id;
+
+    DROP TABLE IF EXISTS "audit_user_device";
+
+    DROP TABLE IF EXISTS "user_device";
+COMMIT;
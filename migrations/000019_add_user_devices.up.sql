@@ -0,0 +1,43 @@
+BEGIN;
+    -- Devices from which user has logged in, identified by the signed device token (cookie)
+    CREATE TABLE IF NOT EXISTS "user_device" (
+        id                      UUID PRIMARY KEY,
+        user_id                 UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+        -- Friendly device name given by the user, NULL if device wasn't named
+        name                    VARCHAR(64),
+        -- User agent of the last login from this device
+        user_agent              TEXT NOT NULL,
+        device_type             TEXT NOT NULL,
+        os                      TEXT NOT NULL,
+        browser                 TEXT NOT NULL,
+        trusted                 BOOLEAN NOT NULL DEFAULT FALSE,
+        first_seen_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+        last_seen_at            TIMESTAMP NOT NULL DEFAULT NOW()
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_user_device_user_id ON "user_device" (user_id);
+
+    CREATE TABLE IF NOT EXISTS "audit_user_device" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        changed_device_id       UUID NOT NULL,
+        changed_by_user_id      UUID NOT NULL,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        user_id                 UUID NOT NULL,
+        name                    VARCHAR(64),
+        user_agent              TEXT NOT NULL,
+        device_type             TEXT NOT NULL,
+        os                      TEXT NOT NULL,
+        browser                 TEXT NOT NULL,
+        trusted                 BOOLEAN NOT NULL,
+        first_seen_at           TIMESTAMP NOT NULL,
+        last_seen_at            TIMESTAMP NOT NULL,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+
+    -- Device from which session was created, NULL for sessions created before devices were introduced
+    ALTER TABLE IF EXISTS "user_session" ADD COLUMN user_device_id UUID REFERENCES "user_device"(id) ON DELETE SET NULL;
+
+    CREATE INDEX IF NOT EXISTS idx_user_session_user_device_id ON "user_session" (user_device_id);
+COMMIT;
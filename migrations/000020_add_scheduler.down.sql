@@ -0,0 +1,9 @@
+BEGIN;
+    DROP INDEX IF EXISTS idx_user_deleted_at;
+
+    DROP INDEX IF EXISTS idx_user_session_expires_at;
+
+    DROP TABLE IF EXISTS "archived_record";
+
+    DROP TABLE IF EXISTS "scheduler_job_run";
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+    -- History of the background jobs runs
+    CREATE TABLE IF NOT EXISTS "scheduler_job_run" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        job                     VARCHAR(64) NOT NULL,
+        -- ID of the Sentinel instance which has run the job
+        instance_id             TEXT NOT NULL,
+        started_at              TIMESTAMP NOT NULL,
+        finished_at             TIMESTAMP NOT NULL,
+        status                  VARCHAR(16) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+        -- Amount of purged (or archived) rows
+        affected_rows           BIGINT NOT NULL DEFAULT 0,
+        error                   TEXT
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_scheduler_job_run_job ON "scheduler_job_run" (job, started_at DESC);
+
+    -- Rows purged by the jobs which are configured to archive them instead of just deleting
+    CREATE TABLE IF NOT EXISTS "archived_record" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        source_table            VARCHAR(64) NOT NULL,
+        record                  JSONB NOT NULL,
+        archived_at             TIMESTAMP NOT NULL DEFAULT NOW()
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_archived_record_source_table ON "archived_record" (source_table, archived_at);
+
+    -- Used to find rows which must be purged
+    CREATE INDEX IF NOT EXISTS idx_user_session_expires_at ON "user_session" (expires_at);
+    CREATE INDEX IF NOT EXISTS idx_user_deleted_at ON "user" (deleted_at) WHERE deleted_at IS NOT NULL;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+    DROP TABLE IF EXISTS "audit_login_change_request";
+
+    DROP TABLE IF EXISTS "login_change_request";
+COMMIT;
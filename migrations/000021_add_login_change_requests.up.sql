@@ -0,0 +1,41 @@
+BEGIN;
+    CREATE TABLE IF NOT EXISTS "login_change_request" (
+        id                      UUID PRIMARY KEY,
+        user_id                 UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+        requested_by_user_id    UUID NOT NULL,
+        -- Login of the user at the moment of request creation, confirmation and cancellation links are sent to it
+        old_login               VARCHAR(72) NOT NULL,
+        -- Login which will be set to the user once request is confirmed
+        new_login               VARCHAR(72) NOT NULL,
+        -- Requests are not marked as expired, instead pending request is considered expired once expires_at is reached
+        status                  VARCHAR(16) NOT NULL DEFAULT 'pending',
+        reason                  TEXT,
+        created_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        -- Deadline for confirmation of the new login
+        expires_at              TIMESTAMP NOT NULL,
+        confirmed_at            TIMESTAMP,
+        -- Confirmed request can be reverted via link sent to the old login until this time.
+        -- Old login stays reserved until then.
+        cancellable_until       TIMESTAMP,
+        resolved_at             TIMESTAMP,
+        CHECK (status IN ('pending', 'confirmed', 'cancelled', 'reverted'))
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_login_change_request_user_id ON "login_change_request" (user_id);
+    CREATE INDEX IF NOT EXISTS idx_login_change_request_confirmed ON "login_change_request" (old_login, cancellable_until) WHERE status = 'confirmed';
+
+    CREATE TABLE IF NOT EXISTS "audit_login_change_request" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        changed_request_id      UUID NOT NULL,
+        user_id                 UUID NOT NULL,
+        -- Same as user_id if change was made via link from the email (confirmation or cancellation)
+        changed_by_user_id      UUID NOT NULL,
+        impersonated_by_user_id UUID,
+        operation               CHAR(1) NOT NULL,
+        old_login               VARCHAR(72) NOT NULL,
+        new_login               VARCHAR(72) NOT NULL,
+        status                  VARCHAR(16) NOT NULL,
+        changed_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        reason                  TEXT
+    );
+COMMIT;
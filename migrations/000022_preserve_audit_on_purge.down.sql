@@ -0,0 +1,31 @@
+BEGIN;
+    DELETE FROM session_risk r WHERE NOT EXISTS (SELECT 1 FROM "user" u WHERE u.id = r.user_id);
+
+    ALTER TABLE session_risk
+        ADD CONSTRAINT session_risk_user_id_fkey
+            FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE;
+
+    UPDATE audit_location a SET session_id = NULL
+    WHERE NOT EXISTS (SELECT 1 FROM "user_session" s WHERE s.id = a.session_id);
+
+    ALTER TABLE audit_location
+        ADD CONSTRAINT audit_location_session_id_fkey
+            FOREIGN KEY (session_id) REFERENCES "user_session"(id) ON DELETE SET NULL;
+
+    UPDATE audit_user_session a SET changed_session_id = NULL
+    WHERE NOT EXISTS (SELECT 1 FROM "user_session" s WHERE s.id = a.changed_session_id);
+
+    UPDATE audit_user_session a SET changed_by_user_id = NULL
+    WHERE NOT EXISTS (SELECT 1 FROM "user" u WHERE u.id = a.changed_by_user_id);
+
+    UPDATE audit_user_session a SET user_id = NULL
+    WHERE NOT EXISTS (SELECT 1 FROM "user" u WHERE u.id = a.user_id);
+
+    ALTER TABLE audit_user_session
+        ADD CONSTRAINT audit_user_session_changed_session_id_fkey
+            FOREIGN KEY (changed_session_id) REFERENCES "user_session"(id) ON DELETE CASCADE,
+        ADD CONSTRAINT audit_user_session_changed_by_user_id_fkey
+            FOREIGN KEY (changed_by_user_id) REFERENCES "user"(id) ON DELETE SET NULL,
+        ADD CONSTRAINT audit_user_session_user_id_fkey
+            FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE SET NULL;
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+    -- Audit records must outlive rows they refer to, otherwise purge of sessions and users wipes their history.
+    -- Names of these constraints were wrong in 000010_fix_cascade_deletion, so they were never dropped.
+    ALTER TABLE audit_user_session
+        DROP CONSTRAINT IF EXISTS audit_user_session_changed_session_id_fkey,
+        DROP CONSTRAINT IF EXISTS audit_user_session_changed_by_user_id_fkey,
+        DROP CONSTRAINT IF EXISTS audit_user_session_user_id_fkey;
+
+    ALTER TABLE audit_location
+        DROP CONSTRAINT IF EXISTS audit_location_session_id_fkey;
+
+    -- Dropped by 000010_fix_cascade_deletion, but restored if it was rolled back
+    ALTER TABLE audit_user
+        DROP CONSTRAINT IF EXISTS audit_user_changed_user_id_fkey,
+        DROP CONSTRAINT IF EXISTS audit_user_changed_by_user_id_fkey;
+
+    -- Risk assessments are purged together with audit records
+    ALTER TABLE session_risk
+        DROP CONSTRAINT IF EXISTS session_risk_user_id_fkey;
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+    -- Started impersonations. Like other audit tables it has no foreign keys,
+    -- so records outlive both users and sessions and are removed only by audit purge.
+    CREATE TABLE IF NOT EXISTS "audit_impersonation" (
+        id                      BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+        impersonator_id         UUID NOT NULL,
+        impersonated_user_id    UUID NOT NULL,
+        -- Session of the impersonator to which impersonation token is bound
+        session_id              UUID NOT NULL,
+        audience                VARCHAR(128)[] NOT NULL,
+        reason                  TEXT,
+        ip_address              INET,
+        started_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+        expires_at              TIMESTAMP NOT NULL
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_audit_impersonation_impersonator_id ON "audit_impersonation" (impersonator_id, started_at);
+    CREATE INDEX IF NOT EXISTS idx_audit_impersonation_impersonated_user_id ON "audit_impersonation" (impersonated_user_id, started_at);
+COMMIT;
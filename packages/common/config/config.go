@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"io"
+	"maps"
 	"os"
 	"sentinel/packages/common/logger"
 	"slices"
@@ -47,6 +49,55 @@ type authConfing struct {
 	RawRefreshTokenTTL string   `yaml:"refresh-token-ttl" validate:"required"`
 	TokenAudience      []string `yaml:"token-audience" validate:"required,min=1"`
 	SelfAudience       string   `yaml:"self-audience" validate:"required"`
+	// Max amount of revocation events which can be replayed via Last-Event-ID
+	RevocationStreamMaxLen int64 `yaml:"revocation-stream-max-len" validate:"gt=0"`
+	// Max time since last user authentication after which re-authentication (step-up) is required.
+	// Zero value means that there are no limit for endpoints with such sensivity.
+	RawDefaultEndpointMaxAuthAge   string `yaml:"default-endpoint-max-auth-age" validate:"required"`
+	RawSensitiveEndpointMaxAuthAge string `yaml:"sensitive-endpoint-max-auth-age" validate:"required"`
+	RawImpersonationTokenTTL       string `yaml:"impersonation-token-ttl" validate:"required"`
+	// Max amount of active sessions of the user. Zero value means that there are no limit.
+	MaxSessions int `yaml:"max-sessions" validate:"min=0"`
+	// Role name -> max amount of active sessions, overrides MaxSessions for users with this role.
+	MaxSessionsPerRole map[string]int `yaml:"max-sessions-per-role" validate:"dive,min=0"`
+	// What to do on login when session limit is reached:
+	// "reject" - reject login, "evict" - revoke least recently used sessions.
+	SessionLimitPolicy string `yaml:"session-limit-policy" validate:"required,oneof=reject evict"`
+	// Max time since last use of the session after which it expires. Zero value means that there are no limit.
+	RawSessionIdleTimeout string `yaml:"session-idle-timeout" validate:"required"`
+	// Max time since creation of the session after which it expires regardless of its use.
+	// Zero value means that there are no limit.
+	RawSessionMaxLifetime string `yaml:"session-max-lifetime" validate:"required"`
+	// Role name -> session timeouts, overrides default timeouts for users with this role.
+	SessionTimeoutsPerRole map[string]SessionTimeouts `yaml:"session-timeouts-per-role" validate:"dive"`
+	// What to do when attributes of the session change on refresh.
+	SessionBinding SessionBindingPolicy `yaml:"session-binding"`
+	// Role name -> binding policy, overrides default policy for users with this role.
+	SessionBindingPerRole map[string]SessionBindingPolicy `yaml:"session-binding-per-role" validate:"dive"`
+	// Token audience -> binding policy, overrides default policy for sessions with this audience.
+	SessionBindingPerAudience map[string]SessionBindingPolicy `yaml:"session-binding-per-audience" validate:"dive"`
+	// TTL of the device token (cookie), which identifies device of the user across sessions.
+	// Each login or refresh from the device prolongs it.
+	RawDeviceTokenTTL string `yaml:"device-token-ttl" validate:"required"`
+}
+
+type SessionTimeouts struct {
+	RawIdleTimeout string `yaml:"idle-timeout" validate:"required"`
+	RawMaxLifetime string `yaml:"max-lifetime" validate:"required"`
+}
+
+// Action for each session attribute: "allow", "alert", "step-up" or "revoke".
+// Empty action means that action isn't specified (for overrides it's inherited from the default policy).
+type SessionBindingPolicy struct {
+	Browser string `yaml:"browser" validate:"omitempty,oneof=allow alert step-up revoke"`
+	OS      string `yaml:"os" validate:"omitempty,oneof=allow alert step-up revoke"`
+	Device  string `yaml:"device" validate:"omitempty,oneof=allow alert step-up revoke"`
+	IP      string `yaml:"ip" validate:"omitempty,oneof=allow alert step-up revoke"`
+	Country string `yaml:"country" validate:"omitempty,oneof=allow alert step-up revoke"`
+	// IP addresses within the same prefix (in bits) are treated as the same address.
+	// Zero value means that prefix isn't specified.
+	IPv4Prefix int `yaml:"ipv4-prefix" validate:"min=0,max=32"`
+	IPv6Prefix int `yaml:"ipv6-prefix" validate:"min=0,max=128"`
 }
 
 func (c *authConfing) AccessTokenTTL() time.Duration {
@@ -57,6 +108,121 @@ func (c *authConfing) RefreshTokenTTL() time.Duration {
 	return parseDuration(c.RawRefreshTokenTTL)
 }
 
+func (c *authConfing) ImpersonationTokenTTL() time.Duration {
+	return parseDuration(c.RawImpersonationTokenTTL)
+}
+
+func (c *authConfing) DeviceTokenTTL() time.Duration {
+	return parseDuration(c.RawDeviceTokenTTL)
+}
+
+const (
+	RejectSessionLimitPolicy = "reject"
+	EvictSessionLimitPolicy  = "evict"
+)
+
+// Returns max amount of active sessions for the user with the given roles, zero means that there are no limit.
+// If several roles have own limit, then the most permissive one is used.
+func (c *authConfing) MaxSessionsFor(roles []string) int {
+	limit, overridden := 0, false
+
+	for _, role := range roles {
+		roleLimit, ok := c.MaxSessionsPerRole[role]
+		if !ok {
+			continue
+		}
+		if roleLimit == 0 {
+			return 0
+		}
+		if !overridden || roleLimit > limit {
+			limit, overridden = roleLimit, true
+		}
+	}
+
+	if !overridden {
+		return c.MaxSessions
+	}
+
+	return limit
+}
+
+// Returns idle timeout and max lifetime of sessions of the user with the given roles, zero means that there are no limit.
+// If several roles have own timeouts, then the most restrictive ones are used.
+func (c *authConfing) SessionTimeoutsFor(roles []string) (idleTimeout time.Duration, maxLifetime time.Duration) {
+	// Zero means no limit, so it's less restrictive than any other value
+	restrictive := func(a, b time.Duration) time.Duration {
+		if a == 0 || (b != 0 && b < a) {
+			return b
+		}
+		return a
+	}
+
+	overridden := false
+
+	for _, role := range roles {
+		timeouts, ok := c.SessionTimeoutsPerRole[role]
+		if !ok {
+			continue
+		}
+
+		idle, lifetime := parseDuration(timeouts.RawIdleTimeout), parseDuration(timeouts.RawMaxLifetime)
+
+		if !overridden {
+			idleTimeout, maxLifetime, overridden = idle, lifetime, true
+			continue
+		}
+
+		idleTimeout, maxLifetime = restrictive(idleTimeout, idle), restrictive(maxLifetime, lifetime)
+	}
+
+	if !overridden {
+		return parseDuration(c.RawSessionIdleTimeout), parseDuration(c.RawSessionMaxLifetime)
+	}
+
+	return idleTimeout, maxLifetime
+}
+
+// Idle time is measured since the last refresh of the session, but it's checked on each request,
+// so idle timeout which isn't greater than access token TTL would revoke sessions of active users.
+func (c *authConfing) validateIdleTimeouts() error {
+	accessTokenTTL, err := time.ParseDuration(c.RawAccessTokenTTL)
+	if err != nil {
+		return fmt.Errorf("Invalid value of 'access-token-ttl': %w", err)
+	}
+
+	validate := func(name string, raw string) error {
+		idleTimeout, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("Invalid value of '%s': %w", name, err)
+		}
+		if idleTimeout != 0 && idleTimeout <= accessTokenTTL {
+			return fmt.Errorf("Value of '%s' must be either zero or greater than 'access-token-ttl'", name)
+		}
+		return nil
+	}
+
+	if err := validate("session-idle-timeout", c.RawSessionIdleTimeout); err != nil {
+		return err
+	}
+
+	for _, role := range slices.Sorted(maps.Keys(c.SessionTimeoutsPerRole)) {
+		name := "session-timeouts-per-role." + role + ".idle-timeout"
+		if err := validate(name, c.SessionTimeoutsPerRole[role].RawIdleTimeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *authConfing) DefaultEndpointMaxAuthAge() time.Duration {
+	return parseDuration(c.RawDefaultEndpointMaxAuthAge)
+}
+
+func (c *authConfing) SensitiveEndpointMaxAuthAge() time.Duration {
+	return parseDuration(c.RawSensitiveEndpointMaxAuthAge)
+}
+
 type cacheConfig struct {
 	RawPoolTimeout      string `yaml:"cache-pool-timeout" validate:"required"`
 	RawOperationTimeout string `yaml:"cache-operation-timeout" validate:"required"`
@@ -75,6 +241,71 @@ func (c *cacheConfig) TTL() time.Duration {
 	return parseDuration(c.RawTTL)
 }
 
+type locationConfig struct {
+	// "ip-api" - online lookup via ip-api.com (user IPs are sent to the third party),
+	// "mmdb" - offline lookup in MaxMind-format databases.
+	Provider string `yaml:"location-provider" validate:"required,oneof=ip-api mmdb"`
+	// Path to City database, required for "mmdb" provider
+	MMDBCityPath string `yaml:"location-mmdb-city-path" validate:"required_if=Provider mmdb"`
+	// Path to ASN database, optional. Used to resolve ISP.
+	MMDBASNPath string `yaml:"location-mmdb-asn-path"`
+	// How often databases are checked for changes
+	RawMMDBReloadInterval string `yaml:"location-mmdb-reload-interval" validate:"required"`
+	// For how long resolved locations are cached (per IP)
+	RawCacheTTL string `yaml:"location-cache-ttl" validate:"required"`
+}
+
+func (c *locationConfig) MMDBReloadInterval() time.Duration {
+	return parseDuration(c.RawMMDBReloadInterval)
+}
+
+func (c *locationConfig) CacheTTL() time.Duration {
+	return parseDuration(c.RawCacheTTL)
+}
+
+type riskConfig struct {
+	// If disabled, sessions aren't assessed at all
+	Enabled bool `yaml:"risk-enabled" validate:"exists"`
+	// Amount of recent user locations with which new location is compared
+	HistorySize int `yaml:"risk-history-size" validate:"gt=0"`
+	// Max plausible travel speed between two locations (km/h)
+	MaxTravelSpeed float64 `yaml:"risk-max-travel-speed" validate:"gt=0"`
+	// Distances less than this are ignored (km), since GeoIP isn't precise
+	MinTravelDistance float64 `yaml:"risk-min-travel-distance" validate:"min=0"`
+
+	ImpossibleTravelScore int `yaml:"risk-impossible-travel-score" validate:"min=0,max=100"`
+	NewCountryScore       int `yaml:"risk-new-country-score" validate:"min=0,max=100"`
+	NewASNScore           int `yaml:"risk-new-asn-score" validate:"min=0,max=100"`
+	NewDeviceScore        int `yaml:"risk-new-device-score" validate:"min=0,max=100"`
+
+	// Min score at which action is taken, 0 disables the action
+	AlertThreshold  int `yaml:"risk-alert-threshold" validate:"min=0,max=100"`
+	StepUpThreshold int `yaml:"risk-step-up-threshold" validate:"min=0,max=100"`
+	BlockThreshold  int `yaml:"risk-block-threshold" validate:"min=0,max=100"`
+}
+
+type schedulerConfig struct {
+	// If disabled, background jobs aren't run on this instance
+	Enabled bool `yaml:"scheduler-enabled" validate:"exists"`
+	// Max amount of rows purged by a single query. Jobs purge rows in batches until there is nothing left to purge.
+	BatchSize int `yaml:"scheduler-batch-size" validate:"gt=0"`
+	// Job name -> job settings, jobs which aren't specified here are disabled.
+	Jobs map[string]SchedulerJob `yaml:"scheduler-jobs" validate:"dive"`
+}
+
+type SchedulerJob struct {
+	// Cron expression: "<minute> <hour> <day of month> <month> <day of week>" (in UTC)
+	Schedule string `yaml:"schedule" validate:"required"`
+	// How long records are kept before they are purged
+	RawRetention string `yaml:"retention" validate:"required"`
+	// "purge" - delete records, "archive" - move records into "archived_record" table.
+	Mode string `yaml:"mode" validate:"required,oneof=purge archive"`
+}
+
+func (j *SchedulerJob) Retention() time.Duration {
+	return parseDuration(j.RawRetention)
+}
+
 type debugConfig struct {
 	Enabled           bool `yaml:"debug-mode" validate:"exists"`
 	SafeDatabaseScans bool `yaml:"debug-safe-db-scans" validate:"exists"`
@@ -91,6 +322,12 @@ type appConfig struct {
 	RawActivationTokenTTL    string `yaml:"user-activation-token-ttl" validate:"required"`
 	RawPasswordResetTokenTTL string `yaml:"password-reset-token-ttl" validate:"required"`
 	PasswordResetRedirectURL string `yaml:"password-reset-redirect-url" validate:"required"`
+	// Time during which new login must be confirmed, otherwise login change request expires
+	RawLoginChangeTokenTTL string `yaml:"login-change-token-ttl" validate:"required"`
+	// Time after confirmation during which login change still can be reverted via link sent to the old login
+	RawLoginChangeGracePeriod     string `yaml:"login-change-grace-period" validate:"required"`
+	LoginChangeConfirmRedirectURL string `yaml:"login-change-confirm-redirect-url" validate:"required"`
+	LoginChangeCancelRedirectURL  string `yaml:"login-change-cancel-redirect-url" validate:"required"`
 }
 
 type emailConfig struct {
@@ -106,6 +343,49 @@ func (c *appConfig) PasswordResetTokenTTL() time.Duration {
 	return parseDuration(c.RawPasswordResetTokenTTL)
 }
 
+func (c *appConfig) LoginChangeTokenTTL() time.Duration {
+	return parseDuration(c.RawLoginChangeTokenTTL)
+}
+
+func (c *appConfig) LoginChangeGracePeriod() time.Duration {
+	return parseDuration(c.RawLoginChangeGracePeriod)
+}
+
+type ActionGateRule struct {
+	// Authorization context in format "<entity>:<action>:<resource>", e.g. "user:drop:cache"
+	Context string   `yaml:"context" validate:"required"`
+	Effect  string   `yaml:"effect" validate:"required,oneof=deny require allow"`
+	Roles   []string `yaml:"roles" validate:"min=1,dive,required"`
+}
+
+type AuthzCondition struct {
+	// Authorization context in format "<entity>:<action>:<resource>", e.g. "user:change_login:user"
+	Context string `yaml:"context" validate:"required"`
+	// Policy expression which must be true for the action to be authorized
+	Expression string `yaml:"expression" validate:"required"`
+}
+
+type authzConfig struct {
+	// Where roles are stored: "file" - RBAC config file (changes requires restart),
+	// "database" - roles are managed via API and propagated to all instances.
+	RBACSource string `yaml:"rbac-source" validate:"required,oneof=file database"`
+	// Additional Action Gate Policy rules, rules for the same context as built-in rules replaces them.
+	ActionGatePolicy []ActionGateRule `yaml:"action-gate-policy" validate:"dive"`
+	// Roles which can't be added to the user by a single person. Change of user roles that adds
+	// any of these roles creates pending request, which must be approved by another user.
+	PrivilegedRoles []string `yaml:"privileged-roles" validate:"dive,required"`
+	// Attribute-based conditions, evaluated after action was authorized by roles.
+	Conditions []AuthzCondition `yaml:"authz-conditions" validate:"dive"`
+	// IANA time zone in which time attributes of conditions are calculated, UTC if empty.
+	ConditionsTimezone string `yaml:"authz-conditions-timezone"`
+	// Time after which not approved role change request expires.
+	RawRoleChangeRequestTTL string `yaml:"role-change-request-ttl" validate:"required"`
+}
+
+func (c *authzConfig) RoleChangeRequestTTL() time.Duration {
+	return parseDuration(c.RawRoleChangeRequestTTL)
+}
+
 type sentry struct {
 	TraceSampleRate float64 `yaml:"sentry-trace-sample-rate" validate:"required,min=0.0,max=1.0"`
 }
@@ -114,7 +394,11 @@ type configs struct {
 	dbConfig         `yaml:",inline"`
 	httpServerConfig `yaml:",inline"`
 	authConfing      `yaml:",inline"`
+	authzConfig      `yaml:",inline"`
 	cacheConfig      `yaml:",inline"`
+	locationConfig   `yaml:",inline"`
+	riskConfig       `yaml:",inline"`
+	schedulerConfig  `yaml:",inline"`
 	debugConfig      `yaml:",inline"`
 	appConfig        `yaml:",inline"`
 	emailConfig      `yaml:",inline"`
@@ -122,14 +406,18 @@ type configs struct {
 }
 
 var (
-	DB     *dbConfig
-	HTTP   *httpServerConfig
-	Auth   *authConfing
-	Cache  *cacheConfig
-	Debug  *debugConfig
-	App    *appConfig
-	Email  *emailConfig
-	Sentry *sentry
+	DB        *dbConfig
+	HTTP      *httpServerConfig
+	Auth      *authConfing
+	Authz     *authzConfig
+	Cache     *cacheConfig
+	Location  *locationConfig
+	Risk      *riskConfig
+	Scheduler *schedulerConfig
+	Debug     *debugConfig
+	App       *appConfig
+	Email     *emailConfig
+	Sentry    *sentry
 )
 
 var isInit bool = false
@@ -177,6 +465,11 @@ func loadConfig(path string, dest *configs) {
 		os.Exit(1)
 	}
 
+	if err := dest.authConfing.validateIdleTimeouts(); err != nil {
+		log.Fatal("Failed to validate config", err.Error(), nil)
+		os.Exit(1)
+	}
+
 	log.Info("Validating config: OK", nil)
 }
 
@@ -197,7 +490,11 @@ func Init() {
 	DB = &configs.dbConfig
 	HTTP = &configs.httpServerConfig
 	Auth = &configs.authConfing
+	Authz = &configs.authzConfig
 	Cache = &configs.cacheConfig
+	Location = &configs.locationConfig
+	Risk = &configs.riskConfig
+	Scheduler = &configs.schedulerConfig
 	Debug = &configs.debugConfig
 	App = &configs.appConfig
 	Email = &configs.emailConfig
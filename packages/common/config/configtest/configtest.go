@@ -0,0 +1,25 @@
+// Package configtest initializes configuration in tests, which can't load it from the config file.
+package configtest
+
+import "sentinel/packages/common/config"
+
+func alloc[T any](ptr **T) {
+	*ptr = new(T)
+}
+
+// Replaces all configuration sections with zero-valued ones.
+// Test must set all fields it depends on by itself, secrets aren't affected.
+func Init() {
+	alloc(&config.DB)
+	alloc(&config.HTTP)
+	alloc(&config.Auth)
+	alloc(&config.Authz)
+	alloc(&config.Cache)
+	alloc(&config.Location)
+	alloc(&config.Risk)
+	alloc(&config.Scheduler)
+	alloc(&config.Debug)
+	alloc(&config.App)
+	alloc(&config.Email)
+	alloc(&config.Sentry)
+}
@@ -30,6 +30,10 @@ type secrets struct {
 	ActivationTokenPublicKey     ed25519.PublicKey  `validate:"required"`
 	PasswordResetTokenPrivateKey ed25519.PrivateKey `validate:"required"`
 	PasswordResetTokenPublicKey  ed25519.PublicKey  `validate:"required"`
+	DeviceTokenPrivateKey        ed25519.PrivateKey `validate:"required"`
+	DeviceTokenPublicKey         ed25519.PublicKey  `validate:"required"`
+	LoginChangeTokenPrivateKey   ed25519.PrivateKey `validate:"required"`
+	LoginChangeTokenPublicKey    ed25519.PublicKey  `validate:"required"`
 
 	CacheURI      string `validate:"required"`
 	CachePassword string `validate:"required"`
@@ -78,6 +82,7 @@ func loadSecrets() {
 		"REFRESH_TOKEN_SECRET",
 		"ACTIVATION_TOKEN_SECRET",
 		"PASSWORD_RESET_TOKEN_SECRET",
+		"DEVICE_TOKEN_SECRET",
 
 		"CACHE_URI",
 		"CACHE_PASSWORD",
@@ -138,21 +143,29 @@ func loadSecrets() {
 	RefreshTokenSecret := []byte(getEnv("REFRESH_TOKEN_SECRET"))
 	ActivationTokenSecret := []byte(getEnv("ACTIVATION_TOKEN_SECRET"))
 	PasswordResetTokenSecret := []byte(getEnv("PASSWORD_RESET_TOKEN_SECRET"))
+	DeviceTokenSecret := []byte(getEnv("DEVICE_TOKEN_SECRET"))
+	LoginChangeTokenSecret := []byte(getEnv("LOGIN_CHANGE_TOKEN_SECRET"))
 
 	verifyTokenLength("access token", AccessTokenSecret)
 	verifyTokenLength("refresh token", RefreshTokenSecret)
 	verifyTokenLength("activation token", ActivationTokenSecret)
 	verifyTokenLength("password reset token", PasswordResetTokenSecret)
+	verifyTokenLength("device token", DeviceTokenSecret)
+	verifyTokenLength("login change token", LoginChangeTokenSecret)
 
 	Secret.AccessTokenPrivateKey = ed25519.NewKeyFromSeed(AccessTokenSecret)
 	Secret.RefreshTokenPrivateKey = ed25519.NewKeyFromSeed(RefreshTokenSecret)
 	Secret.ActivationTokenPrivateKey = ed25519.NewKeyFromSeed(ActivationTokenSecret)
 	Secret.PasswordResetTokenPrivateKey = ed25519.NewKeyFromSeed(RefreshTokenSecret)
+	Secret.DeviceTokenPrivateKey = ed25519.NewKeyFromSeed(DeviceTokenSecret)
+	Secret.LoginChangeTokenPrivateKey = ed25519.NewKeyFromSeed(LoginChangeTokenSecret)
 
 	Secret.AccessTokenPublicKey = Secret.AccessTokenPrivateKey.Public().(ed25519.PublicKey)
 	Secret.RefreshTokenPublicKey = Secret.RefreshTokenPrivateKey.Public().(ed25519.PublicKey)
 	Secret.ActivationTokenPublicKey = Secret.ActivationTokenPrivateKey.Public().(ed25519.PublicKey)
 	Secret.PasswordResetTokenPublicKey = Secret.PasswordResetTokenPrivateKey.Public().(ed25519.PublicKey)
+	Secret.DeviceTokenPublicKey = Secret.DeviceTokenPrivateKey.Public().(ed25519.PublicKey)
+	Secret.LoginChangeTokenPublicKey = Secret.LoginChangeTokenPrivateKey.Public().(ed25519.PublicKey)
 
 	log.Info("Loading environment vairables: OK", nil)
 
@@ -0,0 +1,65 @@
+package pbencoding
+
+import (
+	"net"
+	LocationDTO "sentinel/packages/core/location/DTO"
+	SessionDTO "sentinel/packages/core/session/DTO"
+	"testing"
+)
+
+var testIPs = []string{
+	"8.8.8.8",
+	"::ffff:8.8.4.4",
+	"2001:4860:4860::8888",
+	"::1",
+}
+
+func TestSessionIPRoundTrip(t *testing.T) {
+	for _, rawIP := range testIPs {
+		t.Run(rawIP, func(t *testing.T) {
+			ip := net.ParseIP(rawIP)
+
+			raw, err := MarshallFullSessionDTO(&SessionDTO.Full{ID: "id", IpAddress: ip})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dto, err := UnmarshallFullSessionDTO(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !dto.IpAddress.Equal(ip) {
+				t.Errorf("IP = %v, want %v", dto.IpAddress, ip)
+			}
+			if public := dto.MakePublic(); public.IpAddress != ip.String() {
+				t.Errorf("Public IP = %q, want %q", public.IpAddress, ip.String())
+			}
+		})
+	}
+}
+
+func TestLocationIPRoundTrip(t *testing.T) {
+	for _, rawIP := range testIPs {
+		t.Run(rawIP, func(t *testing.T) {
+			ip := net.ParseIP(rawIP)
+
+			raw, err := MarshallFullLocationDTO(&LocationDTO.Full{ID: "id", IP: ip})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dto, err := UnmarshallFullLocationDTO(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !dto.IP.Equal(ip) {
+				t.Errorf("IP = %v, want %v", dto.IP, ip)
+			}
+			if public := dto.MakePublic(); public.IP != ip.String() {
+				t.Errorf("Public IP = %q, want %q", public.IP, ip.String())
+			}
+		})
+	}
+}
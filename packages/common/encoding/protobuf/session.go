@@ -23,6 +23,8 @@ func MarshallFullSessionDTO(dto *SessionDTO.Full) ([]byte, error) {
 		LastUsedAt:     timestamppb.New(dto.LastUsedAt),
 		ExpiresAt:      timestamppb.New(dto.ExpiresAt),
 		RevokedAt:      timestamppb.New(dto.RevokedAt),
+		Name:           dto.Name,
+		Trusted:        dto.Trusted,
 	})
 }
 
@@ -47,5 +49,7 @@ func UnmarshallFullSessionDTO(rawDTO []byte) (*SessionDTO.Full, error) {
 		LastUsedAt:     dto.LastUsedAt.AsTime(),
 		ExpiresAt:      dto.ExpiresAt.AsTime(),
 		RevokedAt:      dto.RevokedAt.AsTime(),
+		Name:           dto.Name,
+		Trusted:        dto.Trusted,
 	}, nil
 }
@@ -38,6 +38,8 @@ type FullSessionDTO struct {
 	LastUsedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=LastUsedAt,proto3" json:"LastUsedAt,omitempty"`
 	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
 	RevokedAt      *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=RevokedAt,proto3" json:"RevokedAt,omitempty"`
+	Name           string                 `protobuf:"bytes,15,opt,name=Name,proto3" json:"Name,omitempty"`
+	Trusted        bool                   `protobuf:"varint,16,opt,name=Trusted,proto3" json:"Trusted,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -170,6 +172,20 @@ func (x *FullSessionDTO) GetRevokedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *FullSessionDTO) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FullSessionDTO) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
 type PublicSessionDTO struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ID             string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
@@ -184,6 +200,8 @@ type PublicSessionDTO struct {
 	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
 	LastUsedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=LastUsedAt,proto3" json:"LastUsedAt,omitempty"`
 	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
+	Name           string                 `protobuf:"bytes,13,opt,name=Name,proto3" json:"Name,omitempty"`
+	Trusted        bool                   `protobuf:"varint,14,opt,name=Trusted,proto3" json:"Trusted,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -302,12 +320,26 @@ func (x *PublicSessionDTO) GetExpiresAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *PublicSessionDTO) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PublicSessionDTO) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
 var File_packages_common_proto_session_proto protoreflect.FileDescriptor
 
 const file_packages_common_proto_session_proto_rawDesc = "" +
 	"\n" +
 	"#packages/common/proto/session.proto\x12\n" +
-	"user.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8a\x04\n" +
+	"user.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb8\x04\n" +
 	"\x0eFullSessionDTO\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x16\n" +
 	"\x06UserID\x18\x02 \x01(\tR\x06UserID\x12\x1c\n" +
@@ -327,7 +359,9 @@ const file_packages_common_proto_session_proto_rawDesc = "" +
 	"LastUsedAt\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
 	"LastUsedAt\x128\n" +
 	"\tExpiresAt\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tExpiresAt\x128\n" +
-	"\tRevokedAt\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tRevokedAt\"\xba\x03\n" +
+	"\tRevokedAt\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tRevokedAt\x12\x12\n" +
+	"\x04Name\x18\x0f \x01(\tR\x04Name\x12\x18\n" +
+	"\aTrusted\x18\x10 \x01(\bR\aTrusted\"\xe8\x03\n" +
 	"\x10PublicSessionDTO\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x1c\n" +
 	"\tUserAgent\x18\x02 \x01(\tR\tUserAgent\x12\x1c\n" +
@@ -345,7 +379,9 @@ const file_packages_common_proto_session_proto_rawDesc = "" +
 	"\n" +
 	"LastUsedAt\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\n" +
 	"LastUsedAt\x128\n" +
-	"\tExpiresAt\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tExpiresAtBBZ@github.com/StepanAnanin/Sentinel/packages/common/proto/generatedb\x06proto3"
+	"\tExpiresAt\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tExpiresAt\x12\x12\n" +
+	"\x04Name\x18\r \x01(\tR\x04Name\x12\x18\n" +
+	"\aTrusted\x18\x0e \x01(\bR\aTrustedBBZ@github.com/StepanAnanin/Sentinel/packages/common/proto/generatedb\x06proto3"
 
 var (
 	file_packages_common_proto_session_proto_rawDescOnce sync.Once
@@ -0,0 +1,48 @@
+package util
+
+import "net"
+
+// Returns canonical string form of the IP (both IPv4 and IPv6).
+// IPv4-mapped IPv6 addresses (::ffff:1.2.3.4) are rendered as IPv4.
+// Unlike net.IP.String() returns empty string instead of "<nil>" if ip is nil.
+func IPString(ip net.IP) string {
+	if len(ip) == 0 {
+		return ""
+	}
+	return ip.String()
+}
+
+// Reports whether both strings are the same IP address, regardless of its textual form
+// (e.g. "2001:db8::1" and "2001:0db8:0:0:0:0:0:1", "1.2.3.4" and "::ffff:1.2.3.4").
+// Strings which aren't valid IPs are compared as is.
+func SameIP(a string, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	return ipA.Equal(ipB)
+}
+
+// Size of the IPv6 network which usually belongs to a single subscriber
+const IPv6SubscriberPrefixLen = 64
+
+// Returns identifier of the network to which IP belongs:
+// IPv4 address itself or /64 prefix for IPv6, since single client usually owns whole /64 network
+// and can freely rotate addresses inside of it.
+// Strings which aren't valid IPs are returned as is.
+func IPNetwork(rawIP string) string {
+	ip := net.ParseIP(rawIP)
+	if ip == nil {
+		return rawIP
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.String()
+	}
+
+	prefix := net.IPNet{
+		IP:   ip.Mask(net.CIDRMask(IPv6SubscriberPrefixLen, 128)),
+		Mask: net.CIDRMask(IPv6SubscriberPrefixLen, 128),
+	}
+
+	return prefix.String()
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIPString(t *testing.T) {
+	tests := []struct {
+		ip       net.IP
+		expected string
+	}{
+		{nil, ""},
+		{net.ParseIP("8.8.8.8"), "8.8.8.8"},
+		{net.ParseIP("8.8.8.8").To4(), "8.8.8.8"},
+		{net.ParseIP("::ffff:8.8.8.8"), "8.8.8.8"},
+		{net.ParseIP("2001:4860:4860::8888"), "2001:4860:4860::8888"},
+		{net.ParseIP("2001:4860:4860:0000:0000:0000:0000:8888"), "2001:4860:4860::8888"},
+		{net.ParseIP("::1"), "::1"},
+	}
+
+	for _, tt := range tests {
+		if actual := IPString(tt.ip); actual != tt.expected {
+			t.Errorf("IPString(%#v) = %q, want %q", tt.ip, actual, tt.expected)
+		}
+	}
+}
+
+func TestSameIP(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"8.8.8.8", "8.8.8.8", true},
+		{"8.8.8.8", "::ffff:8.8.8.8", true},
+		{"8.8.8.8", "8.8.4.4", false},
+		{"2001:db8::1", "2001:0db8:0:0:0:0:0:1", true},
+		{"2001:db8::1", "2001:db8::2", false},
+		{"2001:db8::1", "8.8.8.8", false},
+		{"invalid", "invalid", true},
+		{"invalid", "8.8.8.8", false},
+	}
+
+	for _, tt := range tests {
+		if actual := SameIP(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("SameIP(%q, %q) = %v, want %v", tt.a, tt.b, actual, tt.expected)
+		}
+	}
+}
+
+func TestIPNetwork(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected string
+	}{
+		{"8.8.8.8", "8.8.8.8"},
+		{"::ffff:8.8.8.8", "8.8.8.8"},
+		{"2001:db8:1:2:3:4:5:6", "2001:db8:1:2::/64"},
+		{"2001:db8:1:2::ffff", "2001:db8:1:2::/64"},
+		{"2001:db8:1:3::1", "2001:db8:1:3::/64"},
+		{"invalid", "invalid"},
+	}
+
+	for _, tt := range tests {
+		if actual := IPNetwork(tt.ip); actual != tt.expected {
+			t.Errorf("IPNetwork(%q) = %q, want %q", tt.ip, actual, tt.expected)
+		}
+	}
+}
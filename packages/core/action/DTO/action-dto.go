@@ -13,6 +13,23 @@ type Basic struct {
 	RequesterUID   string
 	RequesterRoles []string
 	Reason         string
+	// ID of the user who impersonates requester.
+	// Empty if action isn't performed via impersonation.
+	ImpersonatorUID string
+	// ID of the organization to which action is scoped.
+	// Empty if action isn't scoped to any organization.
+	OrganizationID string
+	// IP address from which action was requested.
+	// Empty if action isn't performed via HTTP API.
+	RequesterIP string
+}
+
+func (dto *Basic) IsImpersonated() bool {
+	return dto.ImpersonatorUID != ""
+}
+
+func (dto *Basic) IsOrganizationScoped() bool {
+	return dto.OrganizationID != ""
 }
 
 func (dto *Basic) ValidateRequesterUID() *Error.Status {
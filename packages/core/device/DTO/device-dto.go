@@ -0,0 +1,30 @@
+package devicedto
+
+import "time"
+
+type Full struct {
+	ID     string `json:"id" example:"5f1c0f4e-8a51-4c43-9d7b-2c5d1e0b8f6a"`
+	UserID string `json:"user-id" example:"7ee80427-b0c6-4120-b874-ba8567576b6d"`
+	// Friendly device name given by the user
+	Name string `json:"name,omitempty" example:"Work laptop"`
+	// User agent of the last login from this device
+	UserAgent   string    `json:"user-agent" example:"Mozilla/5.0 (X11; Linux x86_64; rv:138.0) Gecko/20100101 Firefox/138.0"`
+	DeviceType  string    `json:"device-type" example:"desktop"`
+	OS          string    `json:"os" example:"Linux"`
+	Browser     string    `json:"browser" example:"Firefox"`
+	Trusted     bool      `json:"trusted" example:"false"`
+	FirstSeenAt time.Time `json:"first-seen-at" example:"2025-07-20T23:54:14.503Z"`
+	LastSeenAt  time.Time `json:"last-seen-at" example:"2025-07-20T23:54:14.503Z"`
+}
+
+type Audit struct {
+	ChangedDeviceID string    `json:"changed-device-id"`
+	ChangedByUserID string    `json:"changed-by-user-id"`
+	Operation       string    `json:"operation"`
+	ChangedAt       time.Time `json:"changed-at"`
+	Reason          string    `json:"reason,omitempty"`
+	// ID of the user who impersonated ChangedByUserID
+	ImpersonatedByUserID string `json:"impersonated-by-user-id,omitempty"`
+
+	*Full
+}
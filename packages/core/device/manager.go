@@ -0,0 +1,39 @@
+package device
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	DeviceDTO "sentinel/packages/core/device/DTO"
+)
+
+type Manager interface {
+	creator
+	seeker
+	updater
+	deleter
+}
+
+type creator interface {
+	// Registers new device of the requester
+	RegisterDevice(act *ActionDTO.Basic, dto *DeviceDTO.Full) *Error.Status
+}
+
+type seeker interface {
+	// Returns device of the user with specified ID. Works without authorization.
+	GetUserDevice(UID string, deviceID string) (*DeviceDTO.Full, *Error.Status)
+	// Returns devices of the user, most recently seen devices go first.
+	GetUserDevices(act *ActionDTO.UserTargeted) ([]*DeviceDTO.Full, *Error.Status)
+}
+
+type updater interface {
+	// Updates last seen time and user agent of the device. Works without authorization.
+	TouchDevice(deviceID string, userAgent string) *Error.Status
+	// Updates name and/or trusted flag of the device, nil values are left unchanged.
+	// Only owner of the device can do this.
+	UpdateDeviceLabel(act *ActionDTO.UserTargeted, deviceID string, name *string, trusted *bool) *Error.Status
+}
+
+type deleter interface {
+	// Removes device and revokes all its sessions
+	RemoveDevice(act *ActionDTO.UserTargeted, deviceID string) *Error.Status
+}
@@ -0,0 +1,19 @@
+package groupdto
+
+import "time"
+
+type Full struct {
+	ID   string `json:"id" example:"0b6c1d2e-3f4a-4b5c-8d6e-7f8091a2b3c4"`
+	Name string `json:"name" example:"developers"`
+	// Roles which are inherited by all members of the group
+	Roles     []string  `json:"roles" example:"user,moderator"`
+	CreatedAt time.Time `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+	UpdatedAt time.Time `json:"updated-at" example:"2025-07-20T23:54:14.503Z"`
+}
+
+type Member struct {
+	GroupID   string    `json:"group-id" example:"0b6c1d2e-3f4a-4b5c-8d6e-7f8091a2b3c4"`
+	UserID    string    `json:"user-id" example:"d529a8d2-1eb4-4bce-82aa-e62095dbc653"`
+	Login     string    `json:"login" example:"user@mail.com"`
+	CreatedAt time.Time `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+}
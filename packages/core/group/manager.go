@@ -0,0 +1,37 @@
+package group
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	GroupDTO "sentinel/packages/core/group/DTO"
+)
+
+type Manager interface {
+	creator
+	seeker
+	updater
+	deleter
+}
+
+type creator interface {
+	CreateGroup(act *ActionDTO.Basic, dto *GroupDTO.Full) (string, *Error.Status)
+}
+
+type seeker interface {
+	GetGroupByID(act *ActionDTO.Basic, id string) (*GroupDTO.Full, *Error.Status)
+	GetAllGroups(act *ActionDTO.Basic) ([]*GroupDTO.Full, *Error.Status)
+	GetGroupMembers(act *ActionDTO.Basic, id string) ([]*GroupDTO.Member, *Error.Status)
+	// Returns groups in which user is a member
+	GetUserGroups(act *ActionDTO.UserTargeted) ([]*GroupDTO.Full, *Error.Status)
+}
+
+type updater interface {
+	// Changes roles of the group, all members of the group will have to refresh their tokens.
+	ChangeGroupRoles(act *ActionDTO.Basic, id string, roles []string) *Error.Status
+	AddGroupMember(act *ActionDTO.UserTargeted, groupID string) *Error.Status
+	RemoveGroupMember(act *ActionDTO.UserTargeted, groupID string) *Error.Status
+}
+
+type deleter interface {
+	DeleteGroup(act *ActionDTO.Basic, id string) *Error.Status
+}
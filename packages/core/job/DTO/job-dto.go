@@ -0,0 +1,32 @@
+package jobdto
+
+import "time"
+
+const (
+	SucceededStatus = "succeeded"
+	FailedStatus    = "failed"
+)
+
+type Run struct {
+	ID  int64  `json:"id" example:"42"`
+	Job string `json:"job" example:"expired-sessions"`
+	// ID of the Sentinel instance which has run the job
+	InstanceID string    `json:"instance-id" example:"cb663674-803e-4b06-bfeb-87c5cc86383e"`
+	StartedAt  time.Time `json:"started-at" example:"2025-07-20T23:54:14.503Z"`
+	FinishedAt time.Time `json:"finished-at" example:"2025-07-20T23:54:15.012Z"`
+	// "succeeded" or "failed"
+	Status string `json:"status" example:"succeeded"`
+	// Amount of purged (or archived) rows
+	AffectedRows int64  `json:"affected-rows" example:"1000"`
+	Error        string `json:"error,omitempty" example:"Internal Server Error"`
+}
+
+// Parameters of the single purge batch
+type Purge struct {
+	// Records which became outdated before this moment are purged
+	Before time.Time
+	// If true, then records are moved into archive instead of just deletion
+	Archive bool
+	// Max amount of records purged at once
+	Limit int
+}
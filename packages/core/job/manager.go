@@ -0,0 +1,49 @@
+package job
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	JobDTO "sentinel/packages/core/job/DTO"
+	"time"
+)
+
+type Manager interface {
+	creator
+	seeker
+	purger
+	locker
+}
+
+type creator interface {
+	// Saves run of the job into the job history. Works without authorization.
+	SaveJobRun(run *JobDTO.Run) *Error.Status
+}
+
+type seeker interface {
+	// Returns history of the job runs, most recent runs go first.
+	GetJobRuns(act *ActionDTO.Basic, job string, page int, pageSize int) ([]*JobDTO.Run, *Error.Status)
+	// Returns true if job was started at or after the specified moment. Works without authorization.
+	HasJobRunSince(job string, since time.Time) (bool, *Error.Status)
+}
+
+// All methods work without authorization and purge a single batch of records,
+// amount of purged records is returned.
+type purger interface {
+	// Purges sessions which have expired before purge.Before
+	PurgeExpiredSessions(purge *JobDTO.Purge) (int64, *Error.Status)
+	// Purges locations which are deleted or not bound to any session and were created before purge.Before
+	PurgeOrphanedLocations(purge *JobDTO.Purge) (int64, *Error.Status)
+	// Purges users which were soft deleted before purge.Before
+	PurgeSoftDeletedUsers(purge *JobDTO.Purge) (int64, *Error.Status)
+	// Purges records of all audit tables (and session risk assessments) made before purge.Before
+	PurgeAuditRecords(purge *JobDTO.Purge) (int64, *Error.Status)
+	// Purges job runs started before purge.Before
+	PurgeJobRuns(purge *JobDTO.Purge) (int64, *Error.Status)
+}
+
+type locker interface {
+	// Tries to acquire lock of the job, which is shared between all Sentinel instances.
+	// Doesn't wait if lock is already held by someone else, just returns false.
+	// Returned unlock function must be called once job is done.
+	TryLockJob(job string) (unlock func(), acquired bool, err *Error.Status)
+}
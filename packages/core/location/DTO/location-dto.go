@@ -30,7 +30,7 @@ func (dto *Full) MakePublic() *Public {
 
 	return &Public{
 		ID:        dto.ID,
-		IP:        dto.IP.To4().String(),
+		IP:        util.IPString(dto.IP),
 		Country:   dto.Country,
 		Region:    dto.Region,
 		City:      dto.City,
@@ -45,10 +45,16 @@ func (dto *Full) MakePublic() *Public {
 func (dto *Full) String() string {
 	return fmt.Sprintf(
 		"%s: %s %s %s (Latitude: %f, Longitude: %f)",
-		dto.IP.To4().String(), dto.Country, dto.Region, dto.City, dto.Latitude, dto.Longitude,
+		util.IPString(dto.IP), dto.Country, dto.Region, dto.City, dto.Latitude, dto.Longitude,
 	)
 }
 
+// Location at which user was seen at some moment of time
+type Visit struct {
+	*Full
+	SeenAt time.Time
+}
+
 type Public struct {
 	ID string `json:"id" example:"0de6c6e9-5360-4cd8-a068-24ea035a0bd7"`
 	IP string `json:"ip" example:"8.8.8.8"`
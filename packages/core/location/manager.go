@@ -20,6 +20,9 @@ type creator interface {
 type seeker interface {
 	GetLocationByID(act *ActionDTO.UserTargeted, id string) (*LocationDTO.Full, *Error.Status)
 	GetLocationBySessionID(act *ActionDTO.UserTargeted, id string) (*LocationDTO.Full, *Error.Status)
+	// Returns locations of the user sessions (except specified one), most recently used sessions go first.
+	// Works without authorization.
+	GetRecentUserLocations(UID string, excludeSessionID string, limit int) ([]*LocationDTO.Visit, *Error.Status)
 }
 
 type updater interface {
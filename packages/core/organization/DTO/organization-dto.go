@@ -0,0 +1,19 @@
+package organizationdto
+
+import "time"
+
+type Full struct {
+	ID        string    `json:"id" example:"5c0e1a7b-3f2d-4c8e-9b6a-1d2e3f4a5b6c"`
+	Name      string    `json:"name" example:"Acme Corp"`
+	CreatedAt time.Time `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+}
+
+type Member struct {
+	OrganizationID string `json:"organization-id" example:"5c0e1a7b-3f2d-4c8e-9b6a-1d2e3f4a5b6c"`
+	UserID         string `json:"user-id" example:"d529a8d2-1eb4-4bce-82aa-e62095dbc653"`
+	Login          string `json:"login" example:"user@mail.com"`
+	// Roles of the user in this organization
+	Roles     []string  `json:"roles" example:"user,admin"`
+	CreatedAt time.Time `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+	UpdatedAt time.Time `json:"updated-at" example:"2025-07-20T23:54:14.503Z"`
+}
@@ -0,0 +1,41 @@
+package organization
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	OrganizationDTO "sentinel/packages/core/organization/DTO"
+)
+
+type Manager interface {
+	creator
+	seeker
+	updater
+	deleter
+}
+
+type creator interface {
+	CreateOrganization(act *ActionDTO.Basic, dto *OrganizationDTO.Full) (string, *Error.Status)
+}
+
+type seeker interface {
+	GetOrganizationByID(act *ActionDTO.Basic, id string) (*OrganizationDTO.Full, *Error.Status)
+	GetMembers(act *ActionDTO.Basic, orgID string, page int, pageSize int) ([]*OrganizationDTO.Member, *Error.Status)
+	GetMember(act *ActionDTO.UserTargeted, orgID string) (*OrganizationDTO.Member, *Error.Status)
+	IsMember(orgID string, UID string) (bool, *Error.Status)
+	// Returns roles of the user in the specified organization, works without authorization.
+	// These roles must be used when issuing organization-scoped tokens.
+	GetMemberRoles(orgID string, UID string) ([]string, *Error.Status)
+	// Returns IDs of all organizations in which user is a member, works without authorization.
+	GetUserOrganizationIDs(UID string) ([]string, *Error.Status)
+}
+
+type updater interface {
+	// Adds user to the organization or updates his roles in it if he is already a member.
+	SetMember(act *ActionDTO.UserTargeted, orgID string, roles []string) *Error.Status
+}
+
+type deleter interface {
+	// Deletes organization with all its memberships
+	DeleteOrganization(act *ActionDTO.Basic, id string) *Error.Status
+	RemoveMember(act *ActionDTO.UserTargeted, orgID string) *Error.Status
+}
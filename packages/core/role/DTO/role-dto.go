@@ -0,0 +1,56 @@
+package roledto
+
+import "time"
+
+type Full struct {
+	ID string `json:"id" example:"0b7c1f5e-4b0a-4d8e-9d43-0c9c6f2b6a11"`
+	// Empty if role is global (shared by all schemas)
+	SchemaID    string `json:"schema-id,omitempty" example:"cb663674-803e-4b06-bfeb-87c5cc86383e"`
+	Name        string `json:"name" example:"moderator"`
+	Permissions uint16 `json:"permissions" example:"255"`
+	// Overrides role permissions for the specific resources (resource name -> permissions)
+	ResourcePermissions map[string]uint16 `json:"resource-permissions"`
+	// Is role must be assigned to all new users
+	IsDefault bool      `json:"is-default"`
+	CreatedAt time.Time `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+	UpdatedAt time.Time `json:"updated-at" example:"2025-07-20T23:54:14.503Z"`
+}
+
+func (dto *Full) IsGlobal() bool {
+	return dto.SchemaID == ""
+}
+
+// Permissions in human-readable format
+type Permissions struct {
+	Create     bool `json:"create"`
+	SelfCreate bool `json:"self-create"`
+	Read       bool `json:"read"`
+	SelfRead   bool `json:"self-read"`
+	Update     bool `json:"update"`
+	SelfUpdate bool `json:"self-update"`
+	Delete     bool `json:"delete"`
+	SelfDelete bool `json:"self-delete"`
+}
+
+type Public struct {
+	ID                  string                 `json:"id" example:"0b7c1f5e-4b0a-4d8e-9d43-0c9c6f2b6a11"`
+	SchemaID            string                 `json:"schema-id,omitempty" example:"cb663674-803e-4b06-bfeb-87c5cc86383e"`
+	Name                string                 `json:"name" example:"moderator"`
+	Permissions         Permissions            `json:"permissions"`
+	ResourcePermissions map[string]Permissions `json:"resource-permissions,omitempty"`
+	IsDefault           bool                   `json:"is-default"`
+	CreatedAt           time.Time              `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+	UpdatedAt           time.Time              `json:"updated-at" example:"2025-07-20T23:54:14.503Z"`
+}
+
+type Audit struct {
+	ChangedRoleID   string    `json:"changed-role-id"`
+	ChangedByUserID string    `json:"changed-by-user-id"`
+	Operation       string    `json:"operation"`
+	ChangedAt       time.Time `json:"changed-at"`
+	Reason          string    `json:"reason,omitempty"`
+	// ID of the user who impersonated ChangedByUserID
+	ImpersonatedByUserID string `json:"impersonated-by-user-id,omitempty"`
+
+	*Full
+}
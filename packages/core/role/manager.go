@@ -0,0 +1,37 @@
+package role
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	RoleDTO "sentinel/packages/core/role/DTO"
+)
+
+type Manager interface {
+	creator
+	seeker
+	updater
+	deleter
+}
+
+type creator interface {
+	CreateRole(act *ActionDTO.Basic, dto *RoleDTO.Full) (string, *Error.Status)
+	// Inserts given roles only if there are no roles at all.
+	// Returns true if roles were inserted.
+	SeedRoles(dtos []*RoleDTO.Full) (bool, *Error.Status)
+}
+
+type seeker interface {
+	GetRoleByID(act *ActionDTO.Basic, id string) (*RoleDTO.Full, *Error.Status)
+	GetAllRoles(act *ActionDTO.Basic) ([]*RoleDTO.Full, *Error.Status)
+	// Works same as GetAllRoles, but without authorization.
+	// Designed for building RBAC schemas.
+	LoadAllRoles() ([]*RoleDTO.Full, *Error.Status)
+}
+
+type updater interface {
+	UpdateRole(act *ActionDTO.Basic, id string, dto *RoleDTO.Full) *Error.Status
+}
+
+type deleter interface {
+	DeleteRole(act *ActionDTO.Basic, id string) *Error.Status
+}
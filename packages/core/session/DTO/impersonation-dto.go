@@ -0,0 +1,19 @@
+package sessiondto
+
+import (
+	"net"
+	"time"
+)
+
+// Record about started impersonation
+type Impersonation struct {
+	ImpersonatorID     string `json:"impersonator-id" example:"c9fcc8e3-f4f1-4b85-a65e-29bb889cbccb"`
+	ImpersonatedUserID string `json:"impersonated-user-id" example:"d529a8d2-1eb4-4bce-82aa-e62095dbc653"`
+	// Session of the impersonator to which impersonation token is bound
+	SessionID string    `json:"session-id" example:"c27ee824-a78c-47c7-ae53-bf15f73734b3"`
+	Audience  []string  `json:"audience" example:"sentinel"`
+	Reason    string    `json:"reason,omitempty" example:"Investigating reported bug"`
+	IP        net.IP    `json:"ip" example:"8.8.8.8"`
+	StartedAt time.Time `json:"started-at" example:"2025-07-15T22:27:50.294Z"`
+	ExpiresAt time.Time `json:"expires-at" example:"2025-07-15T22:42:50.294Z"`
+}
@@ -0,0 +1,41 @@
+package sessiondto
+
+import (
+	"time"
+)
+
+// Entry of the user login history. Each login is a separate session,
+// location is the last known location of this session.
+type Login struct {
+	SessionID string `json:"session-id" example:"254be108-2a12-4b0f-b095-c10cd80ef91d"`
+	// IP address from which user logged in
+	LoginIpAddress string `json:"login-ip-address" example:"8.8.8.8"`
+	// Last known IP address of the session
+	IpAddress  string    `json:"ip-address" example:"8.8.4.4"`
+	UserAgent  string    `json:"user-agent" example:"Mozilla/5.0 (X11; Linux x86_64; rv:138.0) Gecko/20100101 Firefox/138.0"`
+	DeviceType string    `json:"device-type" example:"desktop"`
+	OS         string    `json:"os" example:"Linux"`
+	Browser    string    `json:"browser" example:"Firefox"`
+	Country    string    `json:"country,omitempty" example:"US"`
+	Region     string    `json:"region,omitempty" example:"CA"`
+	City       string    `json:"city,omitempty" example:"Mountain View"`
+	ISP        string    `json:"isp,omitempty" example:"Google LLC"`
+	LoggedInAt time.Time `json:"logged-in-at" example:"2025-07-20T23:54:14.503Z"`
+	LastUsedAt time.Time `json:"last-used-at" example:"2025-07-20T23:54:14.503Z"`
+	// Nil if session wasn't revoked
+	RevokedAt *time.Time `json:"revoked-at,omitempty" example:"2025-07-21T10:12:40.118Z"`
+	// ID of the user who revoked the session
+	RevokedBy    string `json:"revoked-by,omitempty" example:"7ee80427-b0c6-4120-b874-ba8567576b6d"`
+	RevokeReason string `json:"revoke-reason,omitempty" example:"Logout"`
+}
+
+// Filter of the user login history, zero values are ignored.
+type LoginHistoryFilter struct {
+	// Inclusive
+	From time.Time
+	// Exclusive
+	To time.Time
+	// ISO 3166-1 alpha-2 code
+	Country    string
+	DeviceType string
+}
@@ -0,0 +1,18 @@
+package sessiondto
+
+import (
+	"net"
+	"time"
+)
+
+// Result of the session risk assessment
+type Risk struct {
+	SessionID string    `json:"session-id" example:"c27ee824-a78c-47c7-ae53-bf15f73734b3"`
+	UserID    string    `json:"user-id" example:"c9fcc8e3-f4f1-4b85-a65e-29bb889cbccb"`
+	IP        net.IP    `json:"ip" example:"8.8.8.8"`
+	Country   string    `json:"country" example:"US"`
+	Score     int       `json:"score" example:"45"`
+	Reasons   []string  `json:"reasons" example:"new_country,new_asn"`
+	Action    string    `json:"action" example:"step_up"`
+	CreatedAt time.Time `json:"created-at" example:"2025-07-15T22:27:50.294Z"`
+}
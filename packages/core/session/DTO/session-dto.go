@@ -2,6 +2,7 @@ package sessiondto
 
 import (
 	"net"
+	"sentinel/packages/common/util"
 	"time"
 )
 
@@ -22,6 +23,9 @@ type Full struct {
 	LastUsedAt     time.Time `json:"last-used-at" example:"2025-07-20T23:54:14.503Z"`
 	ExpiresAt      time.Time `json:"expires-at" example:"2025-07-20T23:54:14.503Z"`
 	RevokedAt      time.Time `json:"revoked-at" example:"2025-07-20T23:54:14.503Z"`
+	// Friendly device name given by the user
+	Name    string `json:"name,omitempty" example:"Work laptop"`
+	Trusted bool   `json:"trusted" example:"false"`
 }
 
 // Creates new public session DTO based on current DTO
@@ -29,7 +33,7 @@ func (dto *Full) MakePublic() *Public {
 	return &Public{
 		ID:             dto.ID,
 		UserAgent:      dto.UserAgent,
-		IpAddress:      dto.IpAddress.To4().String(),
+		IpAddress:      util.IPString(dto.IpAddress),
 		DeviceID:       dto.DeviceID,
 		DeviceType:     dto.DeviceType,
 		OS:             dto.OS,
@@ -39,6 +43,8 @@ func (dto *Full) MakePublic() *Public {
 		CreatedAt:      dto.CreatedAt,
 		LastUsedAt:     dto.LastUsedAt,
 		ExpiresAt:      dto.ExpiresAt,
+		Name:           dto.Name,
+		Trusted:        dto.Trusted,
 	}
 }
 
@@ -55,6 +61,9 @@ type Public struct {
 	CreatedAt      time.Time `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
 	LastUsedAt     time.Time `json:"last-used-at" example:"2025-07-20T23:54:14.503Z"`
 	ExpiresAt      time.Time `json:"expires-at" example:"2025-07-20T23:54:14.503Z"`
+	// Friendly device name given by the user
+	Name    string `json:"name,omitempty" example:"Work laptop"`
+	Trusted bool   `json:"trusted" example:"false"`
 }
 
 type Audit struct {
@@ -63,6 +72,8 @@ type Audit struct {
 	Operation        string    `json:"operation"`
 	ChangedAt        time.Time `json:"changed-at"`
 	Reason           string    `json:"reason,omitempty"`
+	// ID of the user who impersonated ChangedByUserID
+	ImpersonatedByUserID string `json:"impersonated-by-user-id,omitempty"`
 
 	*Full
 }
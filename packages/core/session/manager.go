@@ -15,21 +15,42 @@ type Manager interface {
 
 type creator interface {
 	SaveSession(*SessionDTO.Full) *Error.Status
+	SaveSessionRisk(*SessionDTO.Risk) *Error.Status
+	SaveImpersonation(*SessionDTO.Impersonation) *Error.Status
 }
 
 type seeker interface {
 	GetSessionByID(act *ActionDTO.UserTargeted, sessionID string) (*SessionDTO.Full, *Error.Status)
 	GetRevokedSessionByID(act *ActionDTO.UserTargeted, sessionID string) (*SessionDTO.Full, *Error.Status)
+	// Same as GetSessionByID, but works without authorization.
+	// Must be used only for checks of the session of the current user.
+	GetActiveSessionByID(sessionID string) (*SessionDTO.Full, *Error.Status)
 	GetSessionByDeviceAndUserID(deviceID string, UID string) (*SessionDTO.Full, *Error.Status)
 	GetUserSessions(act *ActionDTO.UserTargeted) ([]*SessionDTO.Public, *Error.Status)
+	// Returns not revoked and not expired sessions of the user, least recently used sessions go first.
+	// Works without authorization.
+	GetActiveUserSessions(UID string) ([]*SessionDTO.Full, *Error.Status)
+	// Returns logins of the user (including revoked sessions), most recent logins go first.
+	GetLoginHistory(act *ActionDTO.UserTargeted, filter *SessionDTO.LoginHistoryFilter, page int, pageSize int) ([]*SessionDTO.Login, *Error.Status)
+	// Same as GetLoginHistory, but without pagination (amount of entries is limited though).
+	ExportLoginHistory(act *ActionDTO.UserTargeted, filter *SessionDTO.LoginHistoryFilter) ([]*SessionDTO.Login, *Error.Status)
 }
 
 type updater interface {
 	UpdateSession(act *ActionDTO.Basic, sessionID string, newSession *SessionDTO.Full) *Error.Status
+	// Updates name and/or trusted flag of the session, nil values are left unchanged.
+	// Only owner of the session can do this.
+	UpdateSessionLabel(act *ActionDTO.UserTargeted, sessionID string, name *string, trusted *bool) *Error.Status
+	// Works without authorization.
+	BindSessionToDevice(sessionID string, deviceID string) *Error.Status
 }
 
 type deleter interface {
 	RevokeSession(act *ActionDTO.UserTargeted, sessionID string) *Error.Status
 	RevokeAllUserSessions(act *ActionDTO.UserTargeted) *Error.Status
+	// Returns revoked sessions
+	RevokeAllUserSessionsExcept(act *ActionDTO.UserTargeted, sessionID string) ([]*SessionDTO.Public, *Error.Status)
+	// Revokes all active sessions of the target user bound to the device with the given ID.
+	RevokeDeviceSessions(act *ActionDTO.UserTargeted, deviceID string) *Error.Status
 	DeleteUserSessionsCache(UID string) *Error.Status
 }
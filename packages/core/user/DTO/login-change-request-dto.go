@@ -0,0 +1,50 @@
+package userdto
+
+import "time"
+
+const (
+	LoginChangeRequestPending   = "pending"
+	LoginChangeRequestConfirmed = "confirmed"
+	LoginChangeRequestCancelled = "cancelled"
+	// Request was confirmed, but then cancelled during grace period, so old login was restored
+	LoginChangeRequestReverted = "reverted"
+	LoginChangeRequestExpired  = "expired"
+)
+
+var LoginChangeRequestStatuses = []string{
+	LoginChangeRequestPending,
+	LoginChangeRequestConfirmed,
+	LoginChangeRequestCancelled,
+	LoginChangeRequestReverted,
+	LoginChangeRequestExpired,
+}
+
+// Change of user login which will be applied only after confirmation via link sent to the new login.
+// Until the end of grace period change can be cancelled (or reverted) via link sent to the old login.
+type LoginChangeRequest struct {
+	ID                string    `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	UserID            string    `json:"user-id" example:"d529a8d2-1eb4-4bce-82aa-e62095dbc653"`
+	RequestedByUserID string    `json:"requested-by-user-id" example:"d529a8d2-1eb4-4bce-82aa-e62095dbc653"`
+	OldLogin          string    `json:"old-login" example:"old@example.com"`
+	NewLogin          string    `json:"new-login" example:"new@example.com"`
+	Status            string    `json:"status" example:"pending"`
+	Reason            string    `json:"reason,omitempty" example:"User lost access to the old email"`
+	CreatedAt         time.Time `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+	// Deadline for confirmation of the new login
+	ExpiresAt   time.Time  `json:"expires-at" example:"2025-07-21T23:54:14.503Z"`
+	ConfirmedAt *time.Time `json:"confirmed-at,omitempty" example:"2025-07-21T10:12:44.503Z"`
+	// Confirmed change can be reverted via link sent to the old login until this time
+	CancellableUntil *time.Time `json:"cancellable-until,omitempty" example:"2025-07-24T10:12:44.503Z"`
+	ResolvedAt       *time.Time `json:"resolved-at,omitempty" example:"2025-07-21T10:12:44.503Z"`
+}
+
+func (dto *LoginChangeRequest) IsPending() bool {
+	return dto.Status == LoginChangeRequestPending
+}
+
+// Reports whether confirmed change still can be reverted
+func (dto *LoginChangeRequest) IsRevertible() bool {
+	return dto.Status == LoginChangeRequestConfirmed &&
+		dto.CancellableUntil != nil &&
+		time.Now().Before(*dto.CancellableUntil)
+}
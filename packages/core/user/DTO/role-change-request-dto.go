@@ -0,0 +1,39 @@
+package userdto
+
+import "time"
+
+const (
+	RoleChangeRequestPending   = "pending"
+	RoleChangeRequestApproved  = "approved"
+	RoleChangeRequestCancelled = "cancelled"
+	RoleChangeRequestExpired   = "expired"
+)
+
+var RoleChangeRequestStatuses = []string{
+	RoleChangeRequestPending,
+	RoleChangeRequestApproved,
+	RoleChangeRequestCancelled,
+	RoleChangeRequestExpired,
+}
+
+// Change of user roles that adds privileged roles and
+// therefore must be approved by another user before it will be applied.
+type RoleChangeRequest struct {
+	ID                string   `json:"id" example:"0b3f1e9a-6c2d-4e7f-8a1b-2c3d4e5f6a7b"`
+	UserID            string   `json:"user-id" example:"d529a8d2-1eb4-4bce-82aa-e62095dbc653"`
+	RequestedByUserID string   `json:"requested-by-user-id" example:"4d9a3a6e-9d2a-4b1a-8f0e-4b3e5c2a1f7d"`
+	Roles             []string `json:"roles" example:"user,admin"`
+	// User roles at the moment of request creation
+	OldRoles  []string  `json:"old-roles" example:"user"`
+	Status    string    `json:"status" example:"pending"`
+	Reason    string    `json:"reason,omitempty" example:"Promotion to the administrator"`
+	CreatedAt time.Time `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+	ExpiresAt time.Time `json:"expires-at" example:"2025-07-23T23:54:14.503Z"`
+	// ID of the user who approved or cancelled this request, empty if request is pending or expired
+	ResolvedByUserID string     `json:"resolved-by-user-id,omitempty" example:"a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"`
+	ResolvedAt       *time.Time `json:"resolved-at,omitempty" example:"2025-07-21T10:12:44.503Z"`
+}
+
+func (dto *RoleChangeRequest) IsPending() bool {
+	return dto.Status == RoleChangeRequestPending
+}
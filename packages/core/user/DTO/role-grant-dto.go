@@ -0,0 +1,43 @@
+package userdto
+
+import "time"
+
+// Time-bound assignment of the role to the user.
+// Unlike user roles, grants aren't cached, so they don't require protobuf models.
+type RoleGrant struct {
+	ID     string `json:"id" example:"6f1f2c7a-2b0e-4d6a-9a3c-7d1e8b2f4c5d"`
+	UserID string `json:"user-id" example:"d529a8d2-1eb4-4bce-82aa-e62095dbc653"`
+	Role   string `json:"role" example:"admin"`
+	// If nil, then grant is active right after creation
+	NotBefore *time.Time `json:"not-before,omitempty" example:"2025-07-20T23:54:14.503Z"`
+	// If nil, then grant never expires
+	ExpiresAt *time.Time `json:"expires-at,omitempty" example:"2025-07-21T03:54:14.503Z"`
+	// Time when grant became active, nil if it isn't active yet
+	ActivatedAt     *time.Time `json:"activated-at,omitempty" example:"2025-07-20T23:54:14.503Z"`
+	GrantedByUserID string     `json:"granted-by-user-id" example:"4d9a3a6e-9d2a-4b1a-8f0e-4b3e5c2a1f7d"`
+	CreatedAt       time.Time  `json:"created-at" example:"2025-07-20T23:54:14.503Z"`
+}
+
+// Reports whether grant is in effect at the given moment.
+func (dto *RoleGrant) IsActiveAt(t time.Time) bool {
+	if dto.NotBefore != nil && t.Before(*dto.NotBefore) {
+		return false
+	}
+	if dto.ExpiresAt != nil && !t.Before(*dto.ExpiresAt) {
+		return false
+	}
+	return true
+}
+
+type RoleGrantAudit struct {
+	ChangedGrantID string `json:"changed-grant-id"`
+	// Empty if change was made by Sentinel itself (e.g. grant has expired)
+	ChangedByUserID string    `json:"changed-by-user-id,omitempty"`
+	Operation       string    `json:"operation"`
+	ChangedAt       time.Time `json:"changed-at"`
+	Reason          string    `json:"reason,omitempty"`
+	// ID of the user who impersonated ChangedByUserID
+	ImpersonatedByUserID string `json:"impersonated-by-user-id,omitempty"`
+
+	*RoleGrant
+}
@@ -113,6 +113,8 @@ type Audit struct {
 	Operation       string    `json:"operation"`
 	ChangedAt       time.Time `json:"changedAt"`
 	Reason          string    `json:"reason,omitempty"`
+	// ID of the user who impersonated ChangedByUserID
+	ImpersonatedByUserID string `json:"impersonatedByUserID,omitempty"`
 
 	*Basic `json:",inline"`
 }
@@ -132,4 +134,22 @@ type Payload struct {
 	Version   uint32   `json:"version" example:"7"`
 	SessionID string   `json:"session-id" example:"35b92582-7694-4958-9751-1fef710cb94d"`
 	Audience  []string `json:"audience" example:"urn:api:auth,urn:api:billing,https://example.domain.com"`
+	// Unix time (in seconds) when user was authenticated last time (login or re-authentication)
+	AuthTime int64 `json:"auth-time" example:"1735689600"`
+	// Authentication methods references (RFC 8176)
+	AMR []string `json:"amr" example:"pwd"`
+	// ID of the user who impersonates this user (see "act" token claim).
+	// Empty if token wasn't issued via impersonation.
+	ImpersonatorID string `json:"impersonator-id,omitempty" example:"4d9a3a6e-9d2a-4b1a-8f0e-4b3e5c2a1f7d"`
+	// ID of the organization to which token is scoped (see "org" token claim).
+	// Empty if token isn't scoped to any organization.
+	OrganizationID string `json:"organization-id,omitempty" example:"5c0e1a7b-3f2d-4c8e-9b6a-1d2e3f4a5b6c"`
+}
+
+func (p *Payload) IsImpersonated() bool {
+	return p.ImpersonatorID != ""
+}
+
+func (p *Payload) IsOrganizationScoped() bool {
+	return p.OrganizationID != ""
 }
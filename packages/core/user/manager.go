@@ -11,6 +11,9 @@ type Manager interface {
 	seeker
 	updater
 	deleter
+	grantor
+	roleChangeApprover
+	loginChanger
 }
 
 type creator interface {
@@ -33,12 +36,17 @@ type seeker interface {
 
 	GetRoles(act *ActionDTO.UserTargeted) ([]string, *Error.Status)
 
+	// Returns names of all roles that assigned to at least one user (including soft deleted ones and role grants)
+	GetAssignedRoles() ([]string, *Error.Status)
+
 	GetUserVersion(UID string) (uint32, *Error.Status)
+
+	// Returns logins of not deleted users which have at least one of the specified roles.
+	// Only roles assigned directly to the user are taken into account (grants and groups are ignored).
+	GetLoginsByRoles(roles []string) ([]string, *Error.Status)
 }
 
 type updater interface {
-	ChangeLogin(act *ActionDTO.UserTargeted, newLogin string) *Error.Status
-
 	ChangePassword(act *ActionDTO.UserTargeted, newPassword string) *Error.Status
 
 	ChangeRoles(act *ActionDTO.UserTargeted, newRoles []string) *Error.Status
@@ -59,3 +67,65 @@ type deleter interface {
 
 	BulkRestore(act *ActionDTO.Basic, UIDs []string) *Error.Status
 }
+
+type grantor interface {
+	// Returns grant ID if error is nil, otherwise returns empty string and error
+	GrantRole(act *ActionDTO.UserTargeted, grant *UserDTO.RoleGrant) (string, *Error.Status)
+
+	RevokeRoleGrant(act *ActionDTO.UserTargeted, grantID string) *Error.Status
+
+	// Returns all grants of the user, including not yet active ones
+	GetRoleGrants(act *ActionDTO.UserTargeted) ([]*UserDTO.RoleGrant, *Error.Status)
+
+	// Returns roles of the user merged with roles from its active grants and its groups.
+	// These roles must be used when issuing tokens.
+	GetEffectiveRoles(user *UserDTO.Full) ([]string, *Error.Status)
+
+	// Activates grants which reached their not_before and deletes expired ones,
+	// bumps version of affected users. Returns amounts of activated and expired grants.
+	ProcessRoleGrants() (activated int, expired int, err *Error.Status)
+}
+
+type roleChangeApprover interface {
+	// Changes user roles right away if new roles don't add any of the privileged roles,
+	// in this case returns nil request. Otherwise creates and returns pending role change request,
+	// which will be applied only after approval by another user.
+	RequestRolesChange(act *ActionDTO.UserTargeted, newRoles []string) (*UserDTO.RoleChangeRequest, *Error.Status)
+
+	// Returns role change requests with the specified status, or all requests if status is empty
+	GetRoleChangeRequests(act *ActionDTO.Basic, status string) ([]*UserDTO.RoleChangeRequest, *Error.Status)
+
+	// Applies pending role change request. Request can't be approved by its author or by the user whose roles it changes.
+	ApproveRoleChangeRequest(act *ActionDTO.Basic, requestID string) (*UserDTO.RoleChangeRequest, *Error.Status)
+
+	CancelRoleChangeRequest(act *ActionDTO.Basic, requestID string) *Error.Status
+
+	// Marks outdated pending requests as expired. Returns amount of expired requests.
+	ExpireRoleChangeRequests() (int, *Error.Status)
+}
+
+type loginChanger interface {
+	// Creates pending login change request, earlier pending requests of the user are cancelled.
+	// Login will be changed only after confirmation via token sent to the new login.
+	// notify is called before request is saved, if it fails then request isn't created
+	// and earlier pending requests stay untouched.
+	RequestLoginChange(
+		act *ActionDTO.UserTargeted,
+		newLogin string,
+		notify func(req *UserDTO.LoginChangeRequest) *Error.Status,
+	) (*UserDTO.LoginChangeRequest, *Error.Status)
+
+	// Returns all login change requests of the user, newest first
+	GetUserLoginChangeRequests(act *ActionDTO.UserTargeted) ([]*UserDTO.LoginChangeRequest, *Error.Status)
+
+	// Returns login change requests with the specified status, or all requests if status is empty
+	GetLoginChangeRequests(act *ActionDTO.Basic, status string) ([]*UserDTO.LoginChangeRequest, *Error.Status)
+
+	// Applies login change request which confirmation token belongs to
+	ConfirmLoginChange(token string) (*UserDTO.LoginChangeRequest, *Error.Status)
+
+	// Cancels login change request which cancellation token belongs to.
+	// If request was already confirmed, but its grace period isn't over yet,
+	// then old login is restored and all sessions of the user are revoked.
+	CancelLoginChange(token string) (*UserDTO.LoginChangeRequest, *Error.Status)
+}
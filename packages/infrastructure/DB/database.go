@@ -1,7 +1,12 @@
 package DB
 
 import (
+	"sentinel/packages/core/device"
+	"sentinel/packages/core/group"
+	"sentinel/packages/core/job"
 	"sentinel/packages/core/location"
+	"sentinel/packages/core/organization"
+	"sentinel/packages/core/role"
 	"sentinel/packages/core/session"
 	"sentinel/packages/core/user"
 	"sentinel/packages/infrastructure/DB/postgres"
@@ -12,6 +17,11 @@ type database interface {
 	user.Manager
 	session.Manager
 	location.Manager
+	role.Manager
+	organization.Manager
+	group.Manager
+	device.Manager
+	job.Manager
 }
 
 type connector interface {
@@ -3,7 +3,16 @@ package audit
 type Operation string
 
 const (
+	CreateOperation  Operation = "C"
 	DeleteOperation  Operation = "D"
 	UpdatedOperation Operation = "U"
 	RestoreOperation Operation = "R"
+	// Time-bound entity became active (e.g. role grant with not_before)
+	ActivateOperation Operation = "A"
+	// Time-bound entity has expired (e.g. role grant with expires_at)
+	ExpireOperation Operation = "E"
+	// Pending request was approved (e.g. role change request)
+	ApproveOperation Operation = "P"
+	// Pending request was cancelled (e.g. role change request)
+	CancelOperation Operation = "X"
 )
@@ -3,17 +3,27 @@ package postgres
 import (
 	"sentinel/packages/infrastructure/DB/postgres/connection"
 	"sentinel/packages/infrastructure/DB/postgres/executor"
+	DeviceTable "sentinel/packages/infrastructure/DB/postgres/table/device"
+	GroupTable "sentinel/packages/infrastructure/DB/postgres/table/group"
+	JobTable "sentinel/packages/infrastructure/DB/postgres/table/job"
 	LocationTable "sentinel/packages/infrastructure/DB/postgres/table/location"
+	OrganizationTable "sentinel/packages/infrastructure/DB/postgres/table/organization"
+	RoleTable "sentinel/packages/infrastructure/DB/postgres/table/role"
 	SessionTable "sentinel/packages/infrastructure/DB/postgres/table/session"
 	UserTable "sentinel/packages/infrastructure/DB/postgres/table/user"
 	"sentinel/packages/infrastructure/DB/postgres/transaction"
 )
 
 type (
-	ConnectionManager = *connection.Manager
-	UserManager       = *UserTable.Manager
-	SessionManager    = *SessionTable.Manager
-	LocationManager   = *LocationTable.Manager
+	ConnectionManager   = *connection.Manager
+	UserManager         = *UserTable.Manager
+	SessionManager      = *SessionTable.Manager
+	LocationManager     = *LocationTable.Manager
+	RoleManager         = *RoleTable.Manager
+	OrganizationManager = *OrganizationTable.Manager
+	GroupManager        = *GroupTable.Manager
+	DeviceManager       = *DeviceTable.Manager
+	JobManager          = *JobTable.Manager
 )
 
 type postgers struct {
@@ -21,6 +31,11 @@ type postgers struct {
 	UserManager
 	SessionManager
 	LocationManager
+	RoleManager
+	OrganizationManager
+	GroupManager
+	DeviceManager
+	JobManager
 }
 
 var driver *postgers
@@ -28,15 +43,25 @@ var driver *postgers
 func InitDriver() *postgers {
 	session := new(SessionTable.Manager)
 	location := new(LocationTable.Manager)
+	role := new(RoleTable.Manager)
+	organization := new(OrganizationTable.Manager)
+	group := new(GroupTable.Manager)
+	job := new(JobTable.Manager)
 	connection := new(connection.Manager)
 
 	user := UserTable.NewManager(session)
+	device := DeviceTable.NewManager(session)
 
 	driver = &postgers{
-		ConnectionManager: ConnectionManager(connection),
-		UserManager:       UserManager(user),
-		SessionManager:    SessionManager(session),
-		LocationManager:   LocationManager(location),
+		ConnectionManager:   ConnectionManager(connection),
+		UserManager:         UserManager(user),
+		SessionManager:      SessionManager(session),
+		LocationManager:     LocationManager(location),
+		RoleManager:         RoleManager(role),
+		OrganizationManager: OrganizationManager(organization),
+		GroupManager:        GroupManager(group),
+		DeviceManager:       DeviceManager(device),
+		JobManager:          JobManager(job),
 	}
 
 	executor.Init(connection)
@@ -5,7 +5,13 @@ import (
 	"net"
 	pbencoding "sentinel/packages/common/encoding/protobuf"
 	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/util"
+	DeviceDTO "sentinel/packages/core/device/DTO"
+	GroupDTO "sentinel/packages/core/group/DTO"
+	JobDTO "sentinel/packages/core/job/DTO"
 	LocationDTO "sentinel/packages/core/location/DTO"
+	OrganizationDTO "sentinel/packages/core/organization/DTO"
+	RoleDTO "sentinel/packages/core/role/DTO"
 	SessionDTO "sentinel/packages/core/session/DTO"
 	UserDTO "sentinel/packages/core/user/DTO"
 	"sentinel/packages/infrastructure/DB/postgres/connection"
@@ -23,6 +29,23 @@ func collect[T any](
 	conType connection.Type,
 	q *query.Query,
 	collectFunc func(pgx.CollectableRow) (T, error),
+) ([]T, *Error.Status) {
+	dtos, err := collectAll(conType, q, collectFunc)
+	if err != nil {
+		return nil, err
+	}
+	if len(dtos) == 0 {
+		return nil, q.ConvertAndLogError(Error.StatusNotFound)
+	}
+
+	return dtos, nil
+}
+
+// Same as collect, but returns empty slice instead of error if there are no rows.
+func collectAll[T any](
+	conType connection.Type,
+	q *query.Query,
+	collectFunc func(pgx.CollectableRow) (T, error),
 ) ([]T, *Error.Status) {
 	dblog.Logger.Trace("Collecting rows...", nil)
 
@@ -36,9 +59,6 @@ func collect[T any](
 		dblog.Logger.Error("Failed to collect rows", e.Error(), nil)
 		return nil, q.ConvertAndLogError(e)
 	}
-	if len(dtos) == 0 {
-		return nil, q.ConvertAndLogError(Error.StatusNotFound)
-	}
 
 	dblog.Logger.Trace("Collecting rows: OK", nil)
 
@@ -192,6 +212,8 @@ func FullSessionDTO(conType connection.Type, q *query.Query, cacheKey string) (*
 		&lastUsedAt,
 		&expiresAt,
 		&revokedAt,
+		&dto.Name,
+		&dto.Trusted,
 	)
 	if err != nil {
 		return nil, err
@@ -242,6 +264,8 @@ func CollectFullSessionDTO(conType connection.Type, query *query.Query) ([]*Sess
 			&lastUsedAt,
 			&expiresAt,
 			&revokedAt,
+			&dto.Name,
+			&dto.Trusted,
 		)
 		if err != nil {
 			return nil, err
@@ -320,3 +344,447 @@ func FullLocationDTO(conType connection.Type, q *query.Query, cacheKey string) (
 
 	return dto, nil
 }
+
+// Last column of the query must be the time when location was seen.
+func CollectLocationVisitDTO(conType connection.Type, q *query.Query) ([]*LocationDTO.Visit, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*LocationDTO.Visit, error) {
+		dto := &LocationDTO.Visit{Full: new(LocationDTO.Full)}
+
+		var createdAt sql.NullTime
+		var deletedAt sql.NullTime
+		var addr net.IP
+
+		err := row.Scan(
+			&dto.ID,
+			&addr,
+			&dto.SessionID,
+			&dto.Country,
+			&dto.Region,
+			&dto.City,
+			&dto.Latitude,
+			&dto.Longitude,
+			&dto.ISP,
+			&deletedAt,
+			&createdAt,
+			&dto.SeenAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if createdAt.Valid {
+			dto.CreatedAt = createdAt.Time
+		}
+		if deletedAt.Valid {
+			dto.DeletedAt = deletedAt.Time
+		}
+		dto.IP = addr
+
+		return dto, nil
+	})
+}
+
+func CollectLoginDTO(conType connection.Type, q *query.Query) ([]*SessionDTO.Login, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*SessionDTO.Login, error) {
+		dto := new(SessionDTO.Login)
+
+		var loginAddr net.IP
+		var addr net.IP
+		var revokedAt sql.NullTime
+
+		err := row.Scan(
+			&dto.SessionID,
+			&loginAddr,
+			&addr,
+			&dto.UserAgent,
+			&dto.DeviceType,
+			&dto.OS,
+			&dto.Browser,
+			&dto.Country,
+			&dto.Region,
+			&dto.City,
+			&dto.ISP,
+			&dto.LoggedInAt,
+			&dto.LastUsedAt,
+			&revokedAt,
+			&dto.RevokedBy,
+			&dto.RevokeReason,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		dto.LoginIpAddress = util.IPString(loginAddr)
+		dto.IpAddress = util.IPString(addr)
+		if revokedAt.Valid {
+			dto.RevokedAt = &revokedAt.Time
+		}
+
+		return dto, nil
+	})
+}
+
+// Roles are rarely requested (mostly on RBAC schema rebuilding), so they aren't cached.
+func FullRoleDTO(conType connection.Type, q *query.Query) (*RoleDTO.Full, *Error.Status) {
+	scan, err := Row(conType, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := new(RoleDTO.Full)
+
+	if err := scan(
+		&dto.ID,
+		&dto.SchemaID,
+		&dto.Name,
+		&dto.Permissions,
+		&dto.ResourcePermissions,
+		&dto.IsDefault,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func CollectFullRoleDTO(conType connection.Type, q *query.Query) ([]*RoleDTO.Full, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*RoleDTO.Full, error) {
+		dto := new(RoleDTO.Full)
+
+		if err := row.Scan(
+			&dto.ID,
+			&dto.SchemaID,
+			&dto.Name,
+			&dto.Permissions,
+			&dto.ResourcePermissions,
+			&dto.IsDefault,
+			&dto.CreatedAt,
+			&dto.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		return dto, nil
+	})
+}
+
+func RoleGrantDTO(conType connection.Type, q *query.Query) (*UserDTO.RoleGrant, *Error.Status) {
+	scan, err := Row(conType, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := new(UserDTO.RoleGrant)
+
+	if err := scan(
+		&dto.ID,
+		&dto.UserID,
+		&dto.Role,
+		&dto.NotBefore,
+		&dto.ExpiresAt,
+		&dto.ActivatedAt,
+		&dto.GrantedByUserID,
+		&dto.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func CollectRoleGrantDTO(conType connection.Type, q *query.Query) ([]*UserDTO.RoleGrant, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*UserDTO.RoleGrant, error) {
+		dto := new(UserDTO.RoleGrant)
+
+		if err := row.Scan(
+			&dto.ID,
+			&dto.UserID,
+			&dto.Role,
+			&dto.NotBefore,
+			&dto.ExpiresAt,
+			&dto.ActivatedAt,
+			&dto.GrantedByUserID,
+			&dto.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		return dto, nil
+	})
+}
+
+func RoleChangeRequestDTO(conType connection.Type, q *query.Query) (*UserDTO.RoleChangeRequest, *Error.Status) {
+	scan, err := Row(conType, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := new(UserDTO.RoleChangeRequest)
+
+	if err := scan(
+		&dto.ID,
+		&dto.UserID,
+		&dto.RequestedByUserID,
+		&dto.Roles,
+		&dto.OldRoles,
+		&dto.Status,
+		&dto.Reason,
+		&dto.CreatedAt,
+		&dto.ExpiresAt,
+		&dto.ResolvedByUserID,
+		&dto.ResolvedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func CollectRoleChangeRequestDTO(conType connection.Type, q *query.Query) ([]*UserDTO.RoleChangeRequest, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*UserDTO.RoleChangeRequest, error) {
+		dto := new(UserDTO.RoleChangeRequest)
+
+		if err := row.Scan(
+			&dto.ID,
+			&dto.UserID,
+			&dto.RequestedByUserID,
+			&dto.Roles,
+			&dto.OldRoles,
+			&dto.Status,
+			&dto.Reason,
+			&dto.CreatedAt,
+			&dto.ExpiresAt,
+			&dto.ResolvedByUserID,
+			&dto.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		return dto, nil
+	})
+}
+
+func LoginChangeRequestDTO(conType connection.Type, q *query.Query) (*UserDTO.LoginChangeRequest, *Error.Status) {
+	scan, err := Row(conType, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := new(UserDTO.LoginChangeRequest)
+
+	if err := scan(
+		&dto.ID,
+		&dto.UserID,
+		&dto.RequestedByUserID,
+		&dto.OldLogin,
+		&dto.NewLogin,
+		&dto.Status,
+		&dto.Reason,
+		&dto.CreatedAt,
+		&dto.ExpiresAt,
+		&dto.ConfirmedAt,
+		&dto.CancellableUntil,
+		&dto.ResolvedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func CollectLoginChangeRequestDTO(conType connection.Type, q *query.Query) ([]*UserDTO.LoginChangeRequest, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*UserDTO.LoginChangeRequest, error) {
+		dto := new(UserDTO.LoginChangeRequest)
+
+		if err := row.Scan(
+			&dto.ID,
+			&dto.UserID,
+			&dto.RequestedByUserID,
+			&dto.OldLogin,
+			&dto.NewLogin,
+			&dto.Status,
+			&dto.Reason,
+			&dto.CreatedAt,
+			&dto.ExpiresAt,
+			&dto.ConfirmedAt,
+			&dto.CancellableUntil,
+			&dto.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		return dto, nil
+	})
+}
+
+func FullOrganizationDTO(conType connection.Type, q *query.Query) (*OrganizationDTO.Full, *Error.Status) {
+	scan, err := Row(conType, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := new(OrganizationDTO.Full)
+
+	if err := scan(&dto.ID, &dto.Name, &dto.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func MemberDTO(conType connection.Type, q *query.Query) (*OrganizationDTO.Member, *Error.Status) {
+	scan, err := Row(conType, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := new(OrganizationDTO.Member)
+
+	if err := scan(
+		&dto.OrganizationID,
+		&dto.UserID,
+		&dto.Login,
+		&dto.Roles,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func CollectMemberDTO(conType connection.Type, q *query.Query) ([]*OrganizationDTO.Member, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*OrganizationDTO.Member, error) {
+		dto := new(OrganizationDTO.Member)
+
+		if err := row.Scan(
+			&dto.OrganizationID,
+			&dto.UserID,
+			&dto.Login,
+			&dto.Roles,
+			&dto.CreatedAt,
+			&dto.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		return dto, nil
+	})
+}
+
+func FullGroupDTO(conType connection.Type, q *query.Query) (*GroupDTO.Full, *Error.Status) {
+	scan, err := Row(conType, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := new(GroupDTO.Full)
+
+	if err := scan(&dto.ID, &dto.Name, &dto.Roles, &dto.CreatedAt, &dto.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func CollectFullGroupDTO(conType connection.Type, q *query.Query) ([]*GroupDTO.Full, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*GroupDTO.Full, error) {
+		dto := new(GroupDTO.Full)
+
+		if err := row.Scan(&dto.ID, &dto.Name, &dto.Roles, &dto.CreatedAt, &dto.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		return dto, nil
+	})
+}
+
+func CollectGroupMemberDTO(conType connection.Type, q *query.Query) ([]*GroupDTO.Member, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*GroupDTO.Member, error) {
+		dto := new(GroupDTO.Member)
+
+		if err := row.Scan(&dto.GroupID, &dto.UserID, &dto.Login, &dto.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		return dto, nil
+	})
+}
+
+func FullDeviceDTO(conType connection.Type, q *query.Query) (*DeviceDTO.Full, *Error.Status) {
+	scan, err := Row(conType, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := new(DeviceDTO.Full)
+
+	if err := scan(
+		&dto.ID,
+		&dto.UserID,
+		&dto.Name,
+		&dto.UserAgent,
+		&dto.DeviceType,
+		&dto.OS,
+		&dto.Browser,
+		&dto.Trusted,
+		&dto.FirstSeenAt,
+		&dto.LastSeenAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func CollectFullDeviceDTO(conType connection.Type, q *query.Query) ([]*DeviceDTO.Full, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*DeviceDTO.Full, error) {
+		dto := new(DeviceDTO.Full)
+
+		if err := row.Scan(
+			&dto.ID,
+			&dto.UserID,
+			&dto.Name,
+			&dto.UserAgent,
+			&dto.DeviceType,
+			&dto.OS,
+			&dto.Browser,
+			&dto.Trusted,
+			&dto.FirstSeenAt,
+			&dto.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+
+		return dto, nil
+	})
+}
+
+func CollectJobRunDTO(conType connection.Type, q *query.Query) ([]*JobDTO.Run, *Error.Status) {
+	return collectAll(conType, q, func(row pgx.CollectableRow) (*JobDTO.Run, error) {
+		dto := new(JobDTO.Run)
+
+		var errMsg sql.NullString
+
+		if err := row.Scan(
+			&dto.ID,
+			&dto.Job,
+			&dto.InstanceID,
+			&dto.StartedAt,
+			&dto.FinishedAt,
+			&dto.Status,
+			&dto.AffectedRows,
+			&errMsg,
+		); err != nil {
+			return nil, err
+		}
+		if errMsg.Valid {
+			dto.Error = errMsg.String
+		}
+
+		return dto, nil
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sentinel/packages/common/config"
 	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/util"
 	"sentinel/packages/infrastructure/DB/postgres/connection"
 	"sentinel/packages/infrastructure/DB/postgres/dblog"
 	"sentinel/packages/infrastructure/DB/postgres/query"
@@ -64,7 +65,7 @@ func initExecutionContext(conType connection.Type, q *query.Query) (*executionCo
 			case bool:
 				args[i] = strconv.FormatBool(a)
 			case net.IP:
-				args[i] = a.To4().String()
+				args[i] = util.IPString(a)
 			}
 		}
 
@@ -146,9 +147,10 @@ func Exec(conType connection.Type, query *query.Query) *Error.Status {
 	}
 	defer cancel()
 
-	if _, err := ctx.Connection.Exec(ctx, query.SQL, query.Args...); err != nil {
-		return query.ConvertAndLogError(err)
+	tag, e := ctx.Connection.Exec(ctx, query.SQL, query.Args...)
+	if e != nil {
+		return query.ConvertAndLogError(e)
 	}
 
-	return nil
+	return query.CheckAffectedRows(tag.RowsAffected())
 }
@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"context"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"strconv"
+	"time"
+)
+
+// Tries to acquire session-level advisory lock with the given key in Primary DB, doesn't wait if it's already held.
+// Lock is bound to the connection, so it's held out of the pool until unlock is called.
+// If instance crashes, connection is closed and lock is released by Postgres.
+func TryAdvisoryLock(key int64) (unlock func(), acquired bool, err *Error.Status) {
+	lockName := strconv.FormatInt(key, 10)
+
+	dblog.Logger.Trace("Acquiring advisory lock "+lockName+"...", nil)
+
+	con, err := conManager.AcquireConnection(connection.Primary)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if e := con.QueryRow(ctx, "SELECT pg_try_advisory_lock($1);", key).Scan(&acquired); e != nil {
+		con.Release()
+		dblog.Logger.Error("Failed to acquire advisory lock "+lockName, e.Error(), nil)
+		return nil, false, Error.StatusInternalError
+	}
+
+	if !acquired {
+		con.Release()
+		dblog.Logger.Trace("Advisory lock "+lockName+" is already held", nil)
+		return nil, false, nil
+	}
+
+	dblog.Logger.Trace("Acquiring advisory lock "+lockName+": OK", nil)
+
+	unlock = func() {
+		dblog.Logger.Trace("Releasing advisory lock "+lockName+"...", nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		var unlocked bool
+
+		if e := con.QueryRow(ctx, "SELECT pg_advisory_unlock($1);", key).Scan(&unlocked); e != nil || !unlocked {
+			errMsg := "Lock wasn't held"
+			if e != nil {
+				errMsg = e.Error()
+			}
+			dblog.Logger.Error("Failed to release advisory lock "+lockName+", closing its connection", errMsg, nil)
+
+			// Lock can't outlive its connection
+			if e := con.Hijack().Close(context.Background()); e != nil {
+				dblog.Logger.Error("Failed to close connection of advisory lock "+lockName, e.Error(), nil)
+			}
+			return
+		}
+
+		con.Release()
+
+		dblog.Logger.Trace("Releasing advisory lock "+lockName+": OK", nil)
+	}
+
+	return unlock, true, nil
+}
@@ -0,0 +1,50 @@
+package invalidation
+
+import (
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/cache"
+	"sentinel/packages/infrastructure/revocation"
+)
+
+// Used when change of one entity affects tokens of many users (e.g. organization or group).
+// Must be executed in the same transaction as the change itself.
+func NewBumpVersionsQuery(UIDs []string) *query.Query {
+	return query.New(`UPDATE "user" SET version = version + 1 WHERE id = ANY($1);`, UIDs)
+}
+
+// Must be called after versions of the users were bumped (see NewBumpVersionsQuery).
+// Publishes version change events and invalidates cache of the specified users.
+// Failures are only logged, since change itself was already committed.
+func Users(UIDs []string) {
+	if len(UIDs) == 0 {
+		return
+	}
+
+	scan, err := executor.Row(connection.Primary, query.New(
+		`SELECT COALESCE(array_agg(id::text), '{}'), COALESCE(array_agg(login), '{}'), COALESCE(array_agg(version::bigint), '{}')
+        FROM "user" WHERE id = ANY($1);`,
+		UIDs,
+	))
+	if err != nil {
+		dblog.Logger.Error("Failed to invalidate users", err.Error(), nil)
+		return
+	}
+
+	ids, logins, versions := []string{}, []string{}, []int64{}
+
+	if err := scan(&ids, &logins, &versions); err != nil {
+		dblog.Logger.Error("Failed to invalidate users", err.Error(), nil)
+		return
+	}
+
+	for i, uid := range ids {
+		revocation.TryPublish(revocation.NewUserVersionChangedEvent(uid, uint32(versions[i])))
+	}
+
+	if err := cache.BulkInvalidateBasicUserDTO(ids, logins); err != nil {
+		dblog.Logger.Error("Failed to invalidate cache", err.Error(), nil)
+	}
+}
@@ -11,6 +11,8 @@ var queryLogger = logger.NewSource("QUERY", logger.Default)
 type Query struct {
 	SQL  string
 	Args []any
+	// Returned if query hasn't affected any rows, nil if query may affect no rows.
+	noRowsErr *Error.Status
 }
 
 func New(sql string, args ...any) *Query {
@@ -20,6 +22,23 @@ func New(sql string, args ...any) *Query {
 	}
 }
 
+// Makes query fail with specified error if it hasn't affected any rows.
+// Within transaction this leads to the rollback, so it can be used as a guard
+// against concurrent modifications (e.g. "UPDATE ... WHERE status = 'pending'").
+func (q *Query) RequireAffectedRows(err *Error.Status) *Query {
+	q.noRowsErr = err
+	return q
+}
+
+// Returns error if query requires affected rows, but none of them were affected
+func (q *Query) CheckAffectedRows(n int64) *Error.Status {
+	if q.noRowsErr != nil && n == 0 {
+		queryLogger.Error("Query failed", "No rows were affected", nil)
+		return q.noRowsErr
+	}
+	return nil
+}
+
 // Converts err into *Error.Status
 func (q *Query) ConvertAndLogError(err error) *Error.Status {
 	defer queryLogger.Debug("Failed query: "+q.SQL, nil)
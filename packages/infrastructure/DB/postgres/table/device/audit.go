@@ -0,0 +1,63 @@
+package devicetable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	DeviceDTO "sentinel/packages/core/device/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"time"
+)
+
+// Converts empty string into NULL
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func newAuditDTO(op audit.Operation, act *ActionDTO.Basic, device *DeviceDTO.Full) DeviceDTO.Audit {
+	return DeviceDTO.Audit{
+		ChangedDeviceID: device.ID,
+		ChangedByUserID: act.RequesterUID,
+		Operation:       string(op),
+		ChangedAt:       time.Now(),
+		Reason:          act.Reason,
+		Full:            device,
+
+		ImpersonatedByUserID: act.ImpersonatorUID,
+	}
+}
+
+func newAuditQuery(dto *DeviceDTO.Audit) *query.Query {
+	return query.New(
+		`INSERT INTO "audit_user_device"
+        (changed_device_id, changed_by_user_id, impersonated_by_user_id, operation, user_id, name, user_agent, device_type, os, browser, trusted, first_seen_at, last_seen_at, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		dto.ChangedDeviceID,
+		dto.ChangedByUserID,
+		nullable(dto.ImpersonatedByUserID),
+		dto.Operation,
+		dto.UserID,
+		nullable(dto.Name),
+		dto.UserAgent,
+		dto.DeviceType,
+		dto.OS,
+		dto.Browser,
+		dto.Trusted,
+		dto.FirstSeenAt,
+		dto.LastSeenAt,
+		dto.ChangedAt,
+		nullable(dto.Reason),
+	)
+}
+
+func execTxWithAudit(dto *DeviceDTO.Audit, queries ...*query.Query) *Error.Status {
+	queries = append(queries, newAuditQuery(dto))
+
+	return transaction.New(queries...).Exec(connection.Primary)
+}
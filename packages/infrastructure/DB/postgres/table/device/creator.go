@@ -0,0 +1,48 @@
+package devicetable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	DeviceDTO "sentinel/packages/core/device/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"unicode/utf8"
+)
+
+func (m *Manager) RegisterDevice(act *ActionDTO.Basic, dto *DeviceDTO.Full) *Error.Status {
+	dblog.Logger.Trace("Registering device "+dto.ID+" of user "+dto.UserID+"...", nil)
+
+	if err := validateDeviceID(dto.ID); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(dto.Name) > maxDeviceNameLength {
+		return deviceNameIsTooLong
+	}
+
+	insertQuery := query.New(
+		`INSERT INTO "user_device" (id, user_id, name, user_agent, device_type, os, browser, trusted, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`,
+		dto.ID,
+		dto.UserID,
+		nullable(dto.Name),
+		dto.UserAgent,
+		dto.DeviceType,
+		dto.OS,
+		dto.Browser,
+		dto.Trusted,
+		dto.FirstSeenAt,
+		dto.LastSeenAt,
+	)
+
+	audit := newAuditDTO(audit.CreateOperation, act, dto)
+
+	if err := execTxWithAudit(&audit, insertQuery); err != nil {
+		dblog.Logger.Error("Failed to register device "+dto.ID+" of user "+dto.UserID, err.Error(), nil)
+		return err
+	}
+
+	dblog.Logger.Trace("Registering device "+dto.ID+" of user "+dto.UserID+": OK", nil)
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package devicetable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+)
+
+func (m *Manager) RemoveDevice(act *ActionDTO.UserTargeted, deviceID string) *Error.Status {
+	dblog.Logger.Info("Removing device "+deviceID+" of user "+act.TargetUID+"...", nil)
+
+	if err := act.ValidateTargetUID(); err != nil {
+		return err
+	}
+
+	if act.RequesterUID != act.TargetUID {
+		if err := authz.User.For(act).Logout(act.RequesterRoles); err != nil {
+			return err
+		}
+	}
+
+	device, err := m.GetUserDevice(act.TargetUID, deviceID)
+	if err != nil {
+		dblog.Logger.Error("Failed to remove device "+deviceID+" of user "+act.TargetUID, err.Error(), nil)
+		return err
+	}
+
+	// Sessions must be revoked before deletion, since they are unbound from the device on deletion
+	if err := m.session.RevokeDeviceSessions(act, deviceID); err != nil {
+		dblog.Logger.Error("Failed to remove device "+deviceID+" of user "+act.TargetUID, err.Error(), nil)
+		return err
+	}
+
+	deleteQuery := query.New(`DELETE FROM "user_device" WHERE id = $1;`, deviceID)
+
+	audit := newAuditDTO(audit.DeleteOperation, &act.Basic, device)
+
+	if err := execTxWithAudit(&audit, deleteQuery); err != nil {
+		dblog.Logger.Error("Failed to remove device "+deviceID+" of user "+act.TargetUID, err.Error(), nil)
+		return err
+	}
+
+	dblog.Logger.Info("Removing device "+deviceID+" of user "+act.TargetUID+": OK", nil)
+
+	return nil
+}
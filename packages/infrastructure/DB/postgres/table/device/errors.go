@@ -0,0 +1,25 @@
+package devicetable
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+)
+
+// Max length of the device name (in characters)
+const maxDeviceNameLength = 64
+
+var deviceNameIsTooLong = Error.NewStatusError(
+	"Device name is too long (max 64 characters)",
+	http.StatusBadRequest,
+)
+
+func validateDeviceID(id string) *Error.Status {
+	if err := validation.UUID(id); err != nil {
+		return err.ToStatus(
+			"Device ID is not specified",
+			"Device ID has invalid format (UUID expected)",
+		)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package devicetable
+
+import (
+	SessionTable "sentinel/packages/infrastructure/DB/postgres/table/session"
+)
+
+type Manager struct {
+	session *SessionTable.Manager
+}
+
+func NewManager(session *SessionTable.Manager) *Manager {
+	return &Manager{
+		session: session,
+	}
+}
+
+const selectDeviceSQL = `SELECT id, user_id, COALESCE(name, ''), user_agent, device_type, os, browser, trusted, first_seen_at, last_seen_at FROM "user_device"`
@@ -0,0 +1,52 @@
+package devicetable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	DeviceDTO "sentinel/packages/core/device/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+)
+
+func (m *Manager) GetUserDevice(UID string, deviceID string) (*DeviceDTO.Full, *Error.Status) {
+	if err := validateDeviceID(deviceID); err != nil {
+		return nil, err
+	}
+
+	// Primary is used cuz device is requested on login, right after its possible registration
+	return executor.FullDeviceDTO(
+		connection.Primary,
+		query.New(selectDeviceSQL+` WHERE id = $1 AND user_id = $2;`, deviceID, UID),
+	)
+}
+
+func (m *Manager) GetUserDevices(act *ActionDTO.UserTargeted) ([]*DeviceDTO.Full, *Error.Status) {
+	dblog.Logger.Info("Getting devices of user "+act.TargetUID+"...", nil)
+
+	if err := act.ValidateTargetUID(); err != nil {
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetUserSession(
+		act.TargetUID == act.RequesterUID,
+		act.RequesterRoles,
+	); err != nil {
+		return nil, err
+	}
+
+	devices, err := executor.CollectFullDeviceDTO(
+		connection.Replica,
+		query.New(selectDeviceSQL+` WHERE user_id = $1 ORDER BY last_seen_at DESC;`, act.TargetUID),
+	)
+	if err != nil {
+		dblog.Logger.Error("Failed to get devices of user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting devices of user "+act.TargetUID+": OK", nil)
+
+	return devices, nil
+}
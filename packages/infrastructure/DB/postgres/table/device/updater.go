@@ -0,0 +1,79 @@
+package devicetable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"unicode/utf8"
+)
+
+// Last seen time changes on each login and refresh, so it's not audited.
+func (m *Manager) TouchDevice(deviceID string, userAgent string) *Error.Status {
+	dblog.Logger.Trace("Touching device "+deviceID+"...", nil)
+
+	updateQuery := query.New(
+		`UPDATE "user_device" SET user_agent = $1, last_seen_at = NOW() WHERE id = $2;`,
+		userAgent,
+		deviceID,
+	)
+
+	if err := executor.Exec(connection.Primary, updateQuery); err != nil {
+		dblog.Logger.Error("Failed to touch device "+deviceID, err.Error(), nil)
+		return err
+	}
+
+	dblog.Logger.Trace("Touching device "+deviceID+": OK", nil)
+
+	return nil
+}
+
+// Updates name and/or trusted flag of the device owned by the target user.
+// Nil values are left unchanged.
+func (m *Manager) UpdateDeviceLabel(act *ActionDTO.UserTargeted, deviceID string, name *string, trusted *bool) *Error.Status {
+	dblog.Logger.Info("Updating label of device "+deviceID+"...", nil)
+
+	// Device of another user must look like non-existing one
+	if act.RequesterUID != act.TargetUID {
+		dblog.Logger.Error("Failed to update label of device "+deviceID, "Device doesn't belong to user "+act.RequesterUID, nil)
+		return Error.StatusNotFound
+	}
+
+	device, err := m.GetUserDevice(act.TargetUID, deviceID)
+	if err != nil {
+		dblog.Logger.Error("Failed to update label of device "+deviceID, err.Error(), nil)
+		return err
+	}
+
+	updated := *device
+	if name != nil {
+		if utf8.RuneCountInString(*name) > maxDeviceNameLength {
+			return deviceNameIsTooLong
+		}
+		updated.Name = *name
+	}
+	if trusted != nil {
+		updated.Trusted = *trusted
+	}
+
+	updateQuery := query.New(
+		`UPDATE "user_device" SET name = $1, trusted = $2 WHERE id = $3;`,
+		nullable(updated.Name),
+		updated.Trusted,
+		deviceID,
+	)
+
+	audit := newAuditDTO(audit.UpdatedOperation, &act.Basic, &updated)
+
+	if err := execTxWithAudit(&audit, updateQuery); err != nil {
+		dblog.Logger.Error("Failed to update label of device "+deviceID, err.Error(), nil)
+		return err
+	}
+
+	dblog.Logger.Info("Updating label of device "+deviceID+": OK", nil)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package grouptable
+
+import (
+	ActionDTO "sentinel/packages/core/action/DTO"
+	GroupDTO "sentinel/packages/core/group/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"time"
+)
+
+// Converts empty string into NULL
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func newGroupAuditQuery(op audit.Operation, act *ActionDTO.Basic, group *GroupDTO.Full) *query.Query {
+	return query.New(
+		`INSERT INTO "audit_user_group"
+        (changed_group_id, changed_by_user_id, impersonated_by_user_id, operation, name, roles, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		group.ID,
+		act.RequesterUID,
+		nullable(act.ImpersonatorUID),
+		string(op),
+		group.Name,
+		group.Roles,
+		time.Now(),
+		nullable(act.Reason),
+	)
+}
+
+func newMemberAuditQuery(op audit.Operation, act *ActionDTO.UserTargeted, groupID string) *query.Query {
+	return query.New(
+		`INSERT INTO "audit_user_group_member"
+        (group_id, user_id, changed_by_user_id, impersonated_by_user_id, operation, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7)`,
+		groupID,
+		act.TargetUID,
+		act.RequesterUID,
+		nullable(act.ImpersonatorUID),
+		string(op),
+		time.Now(),
+		nullable(act.Reason),
+	)
+}
@@ -0,0 +1,75 @@
+package grouptable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	GroupDTO "sentinel/packages/core/group/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+func (m *Manager) CreateGroup(act *ActionDTO.Basic, dto *GroupDTO.Full) (string, *Error.Status) {
+	dto.Name = strings.TrimSpace(dto.Name)
+
+	dblog.Logger.Info("Creating group "+dto.Name+"...", nil)
+
+	if dto.Name == "" {
+		return "", groupNameIsMissing
+	}
+	if utf8.RuneCountInString(dto.Name) > 72 {
+		return "", groupNameIsTooLong
+	}
+
+	if err := validateGroupRoles(dto.Roles); err != nil {
+		dblog.Logger.Error("Failed to create group "+dto.Name, err.Error(), nil)
+		return "", err
+	}
+
+	if err := authz.User.For(act).CreateGroup(act.RequesterRoles); err != nil {
+		return "", err
+	}
+
+	_, err := executor.FullGroupDTO(
+		connection.Primary,
+		query.New(selectGroupSQL+` WHERE name = $1;`, dto.Name),
+	)
+	if err == nil {
+		dblog.Logger.Error("Failed to create group "+dto.Name, groupAlreadyExists.Error(), nil)
+		return "", groupAlreadyExists
+	}
+	if err != Error.StatusNotFound {
+		return "", err
+	}
+
+	now := time.Now()
+
+	dto.ID = uuid.NewString()
+	dto.CreatedAt = now
+	dto.UpdatedAt = now
+
+	err = transaction.New(
+		query.New(
+			`INSERT INTO "user_group" (id, name, roles, created_at, updated_at) VALUES ($1, $2, $3, $4, $5);`,
+			dto.ID, dto.Name, dto.Roles, dto.CreatedAt, dto.UpdatedAt,
+		),
+		newGroupAuditQuery(audit.CreateOperation, act, dto),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to create group "+dto.Name, err.Error(), nil)
+		return "", err
+	}
+
+	dblog.Logger.Info("Creating group "+dto.Name+": OK", nil)
+
+	return dto.ID, nil
+}
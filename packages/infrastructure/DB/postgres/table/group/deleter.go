@@ -0,0 +1,49 @@
+package grouptable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/invalidation"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+)
+
+func (m *Manager) DeleteGroup(act *ActionDTO.Basic, id string) *Error.Status {
+	dblog.Logger.Info("Deleting group "+id+"...", nil)
+
+	if err := authz.User.For(act).DeleteGroup(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	group, err := getGroupByID(id)
+	if err != nil {
+		dblog.Logger.Error("Failed to delete group "+id, err.Error(), nil)
+		return err
+	}
+
+	// Members must be collected before deletion, since memberships are deleted with group
+	UIDs, err := getMembersIDs(id)
+	if err != nil {
+		return err
+	}
+
+	err = transaction.New(
+		invalidation.NewBumpVersionsQuery(UIDs),
+		query.New(`DELETE FROM "user_group" WHERE id = $1;`, id),
+		newGroupAuditQuery(audit.DeleteOperation, act, group),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to delete group "+id, err.Error(), nil)
+		return err
+	}
+
+	invalidation.Users(UIDs)
+
+	dblog.Logger.Info("Deleting group "+id+": OK", nil)
+
+	return nil
+}
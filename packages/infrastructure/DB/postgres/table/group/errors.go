@@ -0,0 +1,42 @@
+package grouptable
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+)
+
+var groupAlreadyExists = Error.NewStatusError(
+	"Group with this name already exists",
+	http.StatusConflict,
+)
+
+var groupNameIsMissing = Error.NewStatusError(
+	"Group name is missing",
+	http.StatusBadRequest,
+)
+
+var groupNameIsTooLong = Error.NewStatusError(
+	"Group name is too long (max 72 characters)",
+	http.StatusBadRequest,
+)
+
+var groupRolesAreMissing = Error.NewStatusError(
+	"Group roles are missing",
+	http.StatusBadRequest,
+)
+
+// Otherwise privileged roles could be obtained bypassing role change requests approval
+var groupHasPrivilegedRoles = Error.NewStatusError(
+	"Privileged roles can't be assigned to groups",
+	http.StatusBadRequest,
+)
+
+var userIsAlreadyMember = Error.NewStatusError(
+	"User is already a member of this group",
+	http.StatusConflict,
+)
+
+var ownMembershipChange = Error.NewStatusError(
+	"Can't change own group membership",
+	http.StatusForbidden,
+)
@@ -0,0 +1,7 @@
+package grouptable
+
+type Manager struct {
+	//
+}
+
+const selectGroupSQL = `SELECT id, name, roles, created_at, updated_at FROM "user_group"`
@@ -0,0 +1,130 @@
+package grouptable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	GroupDTO "sentinel/packages/core/group/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+)
+
+func getGroupByID(id string) (*GroupDTO.Full, *Error.Status) {
+	if err := validateGroupID(id); err != nil {
+		return nil, err
+	}
+
+	return executor.FullGroupDTO(
+		connection.Primary,
+		query.New(selectGroupSQL+` WHERE id = $1;`, id),
+	)
+}
+
+// Returns IDs of all members of the group
+func getMembersIDs(groupID string) ([]string, *Error.Status) {
+	scan, err := executor.Row(connection.Primary, query.New(
+		`SELECT COALESCE(array_agg(user_id::text), '{}') FROM "user_group_member" WHERE group_id = $1;`,
+		groupID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	UIDs := []string{}
+
+	if err := scan(&UIDs); err != nil {
+		return nil, err
+	}
+
+	return UIDs, nil
+}
+
+func (m *Manager) GetGroupByID(act *ActionDTO.Basic, id string) (*GroupDTO.Full, *Error.Status) {
+	if err := authz.User.For(act).GetGroup(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	return getGroupByID(id)
+}
+
+func (m *Manager) GetAllGroups(act *ActionDTO.Basic) ([]*GroupDTO.Full, *Error.Status) {
+	dblog.Logger.Info("Getting all groups...", nil)
+
+	if err := authz.User.For(act).GetGroup(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	groups, err := executor.CollectFullGroupDTO(
+		connection.Replica,
+		query.New(selectGroupSQL+` ORDER BY name;`),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting all groups: OK", nil)
+
+	return groups, nil
+}
+
+func (m *Manager) GetGroupMembers(act *ActionDTO.Basic, id string) ([]*GroupDTO.Member, *Error.Status) {
+	dblog.Logger.Info("Getting members of group "+id+"...", nil)
+
+	if err := authz.User.For(act).GetGroup(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	if _, err := getGroupByID(id); err != nil {
+		dblog.Logger.Error("Failed to get members of group "+id, err.Error(), nil)
+		return nil, err
+	}
+
+	members, err := executor.CollectGroupMemberDTO(
+		connection.Replica,
+		query.New(
+			`SELECT m.group_id, m.user_id, u.login, m.created_at
+            FROM "user_group_member" AS m JOIN "user" AS u ON u.id = m.user_id
+            WHERE m.group_id = $1 ORDER BY m.created_at, m.user_id;`,
+			id,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting members of group "+id+": OK", nil)
+
+	return members, nil
+}
+
+func (m *Manager) GetUserGroups(act *ActionDTO.UserTargeted) ([]*GroupDTO.Full, *Error.Status) {
+	dblog.Logger.Info("Getting groups of user "+act.TargetUID+"...", nil)
+
+	if err := act.ValidateTargetUID(); err != nil {
+		dblog.Logger.Error("Failed to get groups of user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetGroup(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	groups, err := executor.CollectFullGroupDTO(
+		connection.Replica,
+		query.New(
+			`SELECT g.id, g.name, g.roles, g.created_at, g.updated_at
+            FROM "user_group" AS g JOIN "user_group_member" AS m ON m.group_id = g.id
+            WHERE m.user_id = $1 ORDER BY g.name;`,
+			act.TargetUID,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting groups of user "+act.TargetUID+": OK", nil)
+
+	return groups, nil
+}
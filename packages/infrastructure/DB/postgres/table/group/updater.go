@@ -0,0 +1,185 @@
+package grouptable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/invalidation"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+	"time"
+)
+
+func (m *Manager) ChangeGroupRoles(act *ActionDTO.Basic, id string, roles []string) *Error.Status {
+	dblog.Logger.Info("Changing roles of group "+id+"...", nil)
+
+	if err := validateGroupRoles(roles); err != nil {
+		dblog.Logger.Error("Failed to change roles of group "+id, err.Error(), nil)
+		return err
+	}
+
+	if err := authz.User.For(act).UpdateGroup(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	group, err := getGroupByID(id)
+	if err != nil {
+		dblog.Logger.Error("Failed to change roles of group "+id, err.Error(), nil)
+		return err
+	}
+
+	UIDs, err := getMembersIDs(id)
+	if err != nil {
+		return err
+	}
+
+	group.Roles = roles
+	group.UpdatedAt = time.Now()
+
+	// Roles of all members are changed, so all of them must refresh their tokens
+	err = transaction.New(
+		query.New(
+			`UPDATE "user_group" SET roles = $1, updated_at = $2 WHERE id = $3;`,
+			group.Roles, group.UpdatedAt, group.ID,
+		),
+		newGroupAuditQuery(audit.UpdatedOperation, act, group),
+		invalidation.NewBumpVersionsQuery(UIDs),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to change roles of group "+id, err.Error(), nil)
+		return err
+	}
+
+	invalidation.Users(UIDs)
+
+	dblog.Logger.Info("Changing roles of group "+id+": OK", nil)
+
+	return nil
+}
+
+func isMember(groupID string, UID string) (bool, *Error.Status) {
+	scan, err := executor.Row(connection.Primary, query.New(
+		`SELECT EXISTS(SELECT 1 FROM "user_group_member" WHERE group_id = $1 AND user_id = $2);`,
+		groupID, UID,
+	))
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// Validates membership change and returns whether target user is a member of the group
+func validateMembershipChange(act *ActionDTO.UserTargeted, groupID string) (bool, *Error.Status) {
+	if err := validateGroupID(groupID); err != nil {
+		return false, err
+	}
+	if err := act.ValidateUIDs(); err != nil {
+		return false, err
+	}
+
+	// Membership gives roles, so it's the same as changing own roles
+	if act.RequesterUID == act.TargetUID {
+		return false, ownMembershipChange
+	}
+
+	if err := authz.User.For(act).UpdateGroup(act.RequesterRoles); err != nil {
+		return false, err
+	}
+
+	if _, err := getGroupByID(groupID); err != nil {
+		return false, err
+	}
+
+	return isMember(groupID, act.TargetUID)
+}
+
+func (m *Manager) AddGroupMember(act *ActionDTO.UserTargeted, groupID string) *Error.Status {
+	dblog.Logger.Info("Adding user "+act.TargetUID+" to group "+groupID+"...", nil)
+
+	member, err := validateMembershipChange(act, groupID)
+	if err != nil {
+		dblog.Logger.Error("Failed to add user "+act.TargetUID+" to group "+groupID, err.Error(), nil)
+		return err
+	}
+	if member {
+		dblog.Logger.Error("Failed to add user "+act.TargetUID+" to group "+groupID, userIsAlreadyMember.Error(), nil)
+		return userIsAlreadyMember
+	}
+
+	scan, err := executor.Row(connection.Primary, query.New(
+		`SELECT EXISTS(SELECT 1 FROM "user" WHERE id = $1 AND deleted_at IS NULL);`,
+		act.TargetUID,
+	))
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	if err := scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return Error.StatusNotFound
+	}
+
+	err = transaction.New(
+		query.New(
+			`INSERT INTO "user_group_member" (group_id, user_id, created_at) VALUES ($1, $2, $3);`,
+			groupID, act.TargetUID, time.Now(),
+		),
+		newMemberAuditQuery(audit.CreateOperation, act, groupID),
+		invalidation.NewBumpVersionsQuery([]string{act.TargetUID}),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to add user "+act.TargetUID+" to group "+groupID, err.Error(), nil)
+		return err
+	}
+
+	invalidation.Users([]string{act.TargetUID})
+
+	dblog.Logger.Info("Adding user "+act.TargetUID+" to group "+groupID+": OK", nil)
+
+	return nil
+}
+
+func (m *Manager) RemoveGroupMember(act *ActionDTO.UserTargeted, groupID string) *Error.Status {
+	dblog.Logger.Info("Removing user "+act.TargetUID+" from group "+groupID+"...", nil)
+
+	member, err := validateMembershipChange(act, groupID)
+	if err != nil {
+		dblog.Logger.Error("Failed to remove user "+act.TargetUID+" from group "+groupID, err.Error(), nil)
+		return err
+	}
+	if !member {
+		return Error.StatusNotFound
+	}
+
+	err = transaction.New(
+		query.New(
+			`DELETE FROM "user_group_member" WHERE group_id = $1 AND user_id = $2;`,
+			groupID, act.TargetUID,
+		),
+		newMemberAuditQuery(audit.DeleteOperation, act, groupID),
+		invalidation.NewBumpVersionsQuery([]string{act.TargetUID}),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to remove user "+act.TargetUID+" from group "+groupID, err.Error(), nil)
+		return err
+	}
+
+	invalidation.Users([]string{act.TargetUID})
+
+	dblog.Logger.Info("Removing user "+act.TargetUID+" from group "+groupID+": OK", nil)
+
+	return nil
+}
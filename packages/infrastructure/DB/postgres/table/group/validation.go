@@ -0,0 +1,41 @@
+package grouptable
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	"sentinel/packages/infrastructure/auth/authz"
+	"slices"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+func validateGroupID(id string) *Error.Status {
+	if err := validation.UUID(id); err != nil {
+		return err.ToStatus(
+			"Group ID is not specified",
+			"Group ID has invalid format (UUID expected)",
+		)
+	}
+	return nil
+}
+
+func validateGroupRoles(roles []string) *Error.Status {
+	if len(roles) == 0 {
+		return groupRolesAreMissing
+	}
+
+	schemaRoles := authz.GetSchema().Roles
+
+	for _, role := range roles {
+		if !slices.ContainsFunc(schemaRoles, func(r rbac.Role) bool { return r.Name == role }) {
+			return Error.NewStatusError("Role '"+role+"' doesn't exists", http.StatusBadRequest)
+		}
+		if slices.Contains(config.Authz.PrivilegedRoles, role) {
+			return groupHasPrivilegedRoles
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package jobtable
+
+import (
+	Error "sentinel/packages/common/errors"
+	JobDTO "sentinel/packages/core/job/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+)
+
+func (_ *Manager) SaveJobRun(run *JobDTO.Run) *Error.Status {
+	dblog.Logger.Trace("Saving run of job "+run.Job+"...", nil)
+
+	var errMsg *string
+	if run.Error != "" {
+		errMsg = &run.Error
+	}
+
+	insertQuery := query.New(
+		`INSERT INTO "scheduler_job_run" (job, instance_id, started_at, finished_at, status, affected_rows, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+		run.Job,
+		run.InstanceID,
+		run.StartedAt,
+		run.FinishedAt,
+		run.Status,
+		run.AffectedRows,
+		errMsg,
+	)
+
+	if err := executor.Exec(connection.Primary, insertQuery); err != nil {
+		dblog.Logger.Error("Failed to save run of job "+run.Job, err.Error(), nil)
+		return err
+	}
+
+	dblog.Logger.Trace("Saving run of job "+run.Job+": OK", nil)
+
+	return nil
+}
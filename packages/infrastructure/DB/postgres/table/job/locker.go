@@ -0,0 +1,21 @@
+package jobtable
+
+import (
+	"hash/fnv"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+)
+
+// Advisory locks share the same key space with all other applications which use the DB,
+// so prefix is added to reduce chance of collision.
+const lockKeyPrefix = "sentinel:job:"
+
+func lockKey(job string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(lockKeyPrefix + job))
+	return int64(h.Sum64())
+}
+
+func (_ *Manager) TryLockJob(job string) (unlock func(), acquired bool, err *Error.Status) {
+	return executor.TryAdvisoryLock(lockKey(job))
+}
@@ -0,0 +1,5 @@
+package jobtable
+
+type Manager struct{}
+
+const selectJobRunSQL = `SELECT id, job, instance_id, started_at, finished_at, status, affected_rows, error FROM "scheduler_job_run"`
@@ -0,0 +1,172 @@
+package jobtable
+
+import (
+	Error "sentinel/packages/common/errors"
+	JobDTO "sentinel/packages/core/job/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/cache"
+	"strconv"
+)
+
+type purgeTarget struct {
+	table string
+	// Condition of the rows which must be purged, $1 - purge.Before
+	cond string
+	// Expression which is stored in archive, must be based on "purged" row
+	record string
+}
+
+const defaultArchivedRecord = "to_jsonb(purged)"
+
+var (
+	expiredSessionsTarget = purgeTarget{
+		table:  "user_session",
+		cond:   "expires_at < $1",
+		record: defaultArchivedRecord,
+	}
+	orphanedLocationsTarget = purgeTarget{
+		table:  "location",
+		cond:   "(session_id IS NULL OR deleted_at IS NOT NULL) AND COALESCE(deleted_at, created_at) < $1",
+		record: defaultArchivedRecord,
+	}
+	// Sessions of the user are deleted as well, but audit records and risk assessments are kept,
+	// since they don't reference other tables and are purged only after audit retention.
+	softDeletedUsersTarget = purgeTarget{
+		table: "user",
+		cond:  "deleted_at < $1",
+		// There are no reasons to keep password hashes of the deleted users
+		record: defaultArchivedRecord + " - 'password'",
+	}
+	jobRunsTarget = purgeTarget{
+		table:  "scheduler_job_run",
+		cond:   "started_at < $1",
+		record: defaultArchivedRecord,
+	}
+)
+
+var auditTargets = func() []purgeTarget {
+	tables := []string{
+		"audit_user",
+		"audit_user_session",
+		"audit_location",
+		"audit_rbac_role",
+		"audit_user_role_grant",
+		"audit_role_change_request",
+		"audit_login_change_request",
+		"audit_organization",
+		"audit_organization_member",
+		"audit_user_group",
+		"audit_user_group_member",
+		"audit_user_device",
+	}
+
+	targets := make([]purgeTarget, 0, len(tables)+2)
+
+	for _, table := range tables {
+		targets = append(targets, purgeTarget{
+			table:  table,
+			cond:   "changed_at < $1",
+			record: defaultArchivedRecord,
+		})
+	}
+
+	// Not an audit table, but it's append-only log as well
+	targets = append(targets, purgeTarget{
+		table:  "session_risk",
+		cond:   "created_at < $1",
+		record: defaultArchivedRecord,
+	})
+
+	// Audit table, but impersonations are started rather than changed
+	targets = append(targets, purgeTarget{
+		table:  "audit_impersonation",
+		cond:   "started_at < $1",
+		record: defaultArchivedRecord,
+	})
+
+	return targets
+}()
+
+// Deletion and archiving are made by a single statement, so they are atomic.
+// $1 - purge.Before, $2 - purge.Limit, $3 - purge.Archive.
+func newPurgeQuery(target purgeTarget, purge *JobDTO.Purge) *query.Query {
+	return query.New(
+		`WITH purged AS (
+			DELETE FROM "`+target.table+`" WHERE id IN (
+				SELECT id FROM "`+target.table+`" WHERE `+target.cond+` LIMIT $2
+			)
+			RETURNING *
+		), archived AS (
+			INSERT INTO "archived_record" (source_table, record, archived_at)
+			SELECT '`+target.table+`', `+target.record+`, NOW() FROM purged WHERE $3::boolean
+		)
+		SELECT COUNT(*) FROM purged;`,
+		purge.Before,
+		purge.Limit,
+		purge.Archive,
+	)
+}
+
+func purgeTable(target purgeTarget, purge *JobDTO.Purge) (int64, *Error.Status) {
+	dblog.Logger.Trace("Purging outdated rows of "+target.table+"...", nil)
+
+	scan, err := executor.Row(connection.Primary, newPurgeQuery(target, purge))
+	if err != nil {
+		dblog.Logger.Error("Failed to purge outdated rows of "+target.table, err.Error(), nil)
+		return 0, err
+	}
+
+	var count int64
+
+	if err := scan(&count); err != nil {
+		dblog.Logger.Error("Failed to purge outdated rows of "+target.table, err.Error(), nil)
+		return 0, err
+	}
+
+	dblog.Logger.Trace("Purging outdated rows of "+target.table+": OK (purged: "+strconv.FormatInt(count, 10)+")", nil)
+
+	return count, nil
+}
+
+func (_ *Manager) PurgeExpiredSessions(purge *JobDTO.Purge) (int64, *Error.Status) {
+	return purgeTable(expiredSessionsTarget, purge)
+}
+
+func (_ *Manager) PurgeOrphanedLocations(purge *JobDTO.Purge) (int64, *Error.Status) {
+	return purgeTable(orphanedLocationsTarget, purge)
+}
+
+func (_ *Manager) PurgeSoftDeletedUsers(purge *JobDTO.Purge) (int64, *Error.Status) {
+	count, err := purgeTable(softDeletedUsersTarget, purge)
+	if err != nil {
+		return 0, err
+	}
+
+	// Same as in DropAllSoftDeleted
+	if count != 0 {
+		cache.Client.ProgressiveDeletePattern(cache.DeletedUserKeyPrefix + "*")
+	}
+
+	return count, nil
+}
+
+func (_ *Manager) PurgeAuditRecords(purge *JobDTO.Purge) (int64, *Error.Status) {
+	var total int64
+
+	for _, target := range auditTargets {
+		count, err := purgeTable(target, purge)
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
+func (_ *Manager) PurgeJobRuns(purge *JobDTO.Purge) (int64, *Error.Status) {
+	return purgeTable(jobRunsTarget, purge)
+}
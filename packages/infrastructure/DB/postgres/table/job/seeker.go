@@ -0,0 +1,75 @@
+package jobtable
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	JobDTO "sentinel/packages/core/job/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+	"strconv"
+	"time"
+)
+
+func (_ *Manager) GetJobRuns(act *ActionDTO.Basic, job string, page int, pageSize int) ([]*JobDTO.Run, *Error.Status) {
+	dblog.Logger.Info("Getting runs of job "+job+"...", nil)
+
+	if err := act.ValidateRequesterUID(); err != nil {
+		dblog.Logger.Error("Failed to get runs of job "+job, err.Error(), nil)
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetSchedulerJobs(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		errMsg := "Invalid page: " + strconv.Itoa(page) + ". It must be greater than 0."
+		dblog.Logger.Error("Failed to get runs of job "+job, errMsg, nil)
+		return nil, Error.NewStatusError(errMsg, http.StatusBadRequest)
+	}
+	if pageSize < 1 || pageSize > config.DB.MaxSearchPageSize {
+		errMsg := "Invalid page size: " + strconv.Itoa(pageSize) + ". It must be between 1 and " + strconv.Itoa(config.DB.MaxSearchPageSize)
+		dblog.Logger.Error("Failed to get runs of job "+job, errMsg, nil)
+		return nil, Error.NewStatusError(errMsg, http.StatusBadRequest)
+	}
+
+	runs, err := executor.CollectJobRunDTO(
+		connection.Replica,
+		query.New(
+			selectJobRunSQL+` WHERE job = $1 ORDER BY started_at DESC, id DESC LIMIT $2 OFFSET $3;`,
+			job, pageSize, (page-1)*pageSize,
+		),
+	)
+	if err != nil {
+		dblog.Logger.Error("Failed to get runs of job "+job, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting runs of job "+job+": OK", nil)
+
+	return runs, nil
+}
+
+func (_ *Manager) HasJobRunSince(job string, since time.Time) (bool, *Error.Status) {
+	// Primary is used cuz run may be saved by another instance right before this check
+	scan, err := executor.Row(
+		connection.Primary,
+		query.New(`SELECT EXISTS (SELECT 1 FROM "scheduler_job_run" WHERE job = $1 AND started_at >= $2);`, job, since),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+
+	if err := scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
@@ -18,7 +18,7 @@ func (l *Manager) deleteLocation(id string, act *ActionDTO.UserTargeted, drop bo
 	dblog.Logger.Info(logPrefix+"deleting location "+id+"...", nil)
 
 	if act.TargetUID != act.RequesterUID {
-		if err := authz.User.DeleteLocation(act.RequesterRoles); err != nil {
+		if err := authz.User.For(act).DeleteLocation(act.RequesterRoles); err != nil {
 			return err
 		}
 	}
@@ -37,7 +37,7 @@ func (_ *Manager) getLocationByID(id string) (*LocationDTO.Full, *Error.Status)
 
 func (l *Manager) GetLocationByID(act *ActionDTO.UserTargeted, id string) (*LocationDTO.Full, *Error.Status) {
 	if act.TargetUID != act.RequesterUID {
-		if err := authz.User.GetSessionLocation(act.RequesterRoles); err != nil {
+		if err := authz.User.For(act).GetSessionLocation(act.RequesterRoles); err != nil {
 			return nil, err
 		}
 	}
@@ -49,7 +49,7 @@ func (l *Manager) GetLocationBySessionID(act *ActionDTO.UserTargeted, sessionID
 	dblog.Logger.Trace("Getting location for session "+sessionID+"...", nil)
 
 	if act.TargetUID != act.RequesterUID {
-		if err := authz.User.GetSessionLocation(act.RequesterRoles); err != nil {
+		if err := authz.User.For(act).GetSessionLocation(act.RequesterRoles); err != nil {
 			return nil, err
 		}
 	}
@@ -73,3 +73,29 @@ func (l *Manager) GetLocationBySessionID(act *ActionDTO.UserTargeted, sessionID
 
 	return dto, nil
 }
+
+// Locations of the sessions which were blocked due to high risk are skipped,
+// otherwise they would be treated as known locations on the next login attempt.
+func (_ *Manager) GetRecentUserLocations(UID string, excludeSessionID string, limit int) ([]*LocationDTO.Visit, *Error.Status) {
+	dblog.Logger.Trace("Getting recent locations of user "+UID+"...", nil)
+
+	selectQuery := query.New(
+		`SELECT l.id, l.ip, l.session_id, l.country, l.region, l.city, l.latitude, l.longitude, l.isp, l.deleted_at, l.created_at, s.last_used_at
+		FROM "location" l
+		JOIN "user_session" s ON s.id = l.session_id
+		WHERE s.user_id = $1 AND l.session_id <> $2 AND l.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM "session_risk" r WHERE r.session_id = s.id AND r.action = 'block')
+		ORDER BY s.last_used_at DESC
+		LIMIT $3`,
+		UID, excludeSessionID, limit,
+	)
+
+	visits, err := executor.CollectLocationVisitDTO(connection.Replica, selectQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Trace("Getting recent locations of user "+UID+": OK", nil)
+
+	return visits, nil
+}
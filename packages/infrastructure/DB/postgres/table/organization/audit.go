@@ -0,0 +1,50 @@
+package organizationtable
+
+import (
+	ActionDTO "sentinel/packages/core/action/DTO"
+	OrganizationDTO "sentinel/packages/core/organization/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"time"
+)
+
+// Converts empty string into NULL
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func newOrganizationAuditQuery(op audit.Operation, act *ActionDTO.Basic, org *OrganizationDTO.Full) *query.Query {
+	return query.New(
+		`INSERT INTO "audit_organization"
+        (changed_organization_id, changed_by_user_id, impersonated_by_user_id, operation, name, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7)`,
+		org.ID,
+		act.RequesterUID,
+		nullable(act.ImpersonatorUID),
+		string(op),
+		org.Name,
+		time.Now(),
+		nullable(act.Reason),
+	)
+}
+
+func newMemberAuditQuery(op audit.Operation, act *ActionDTO.UserTargeted, orgID string, roles []string) *query.Query {
+	return query.New(
+		`INSERT INTO "audit_organization_member"
+        (organization_id, user_id, changed_by_user_id, impersonated_by_user_id, operation, roles, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		orgID,
+		act.TargetUID,
+		act.RequesterUID,
+		nullable(act.ImpersonatorUID),
+		string(op),
+		roles,
+		time.Now(),
+		nullable(act.Reason),
+	)
+}
@@ -0,0 +1,71 @@
+package organizationtable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	OrganizationDTO "sentinel/packages/core/organization/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+func (m *Manager) CreateOrganization(act *ActionDTO.Basic, dto *OrganizationDTO.Full) (string, *Error.Status) {
+	dto.Name = strings.TrimSpace(dto.Name)
+
+	dblog.Logger.Info("Creating organization "+dto.Name+"...", nil)
+
+	if act.IsOrganizationScoped() {
+		return "", organizationScopedAction
+	}
+
+	if dto.Name == "" {
+		return "", organizationNameIsMissing
+	}
+	if utf8.RuneCountInString(dto.Name) > 72 {
+		return "", organizationNameIsTooLong
+	}
+
+	if err := authz.User.For(act).CreateOrganization(act.RequesterRoles); err != nil {
+		return "", err
+	}
+
+	_, err := executor.FullOrganizationDTO(
+		connection.Primary,
+		query.New(selectOrganizationSQL+` WHERE name = $1;`, dto.Name),
+	)
+	if err == nil {
+		dblog.Logger.Error("Failed to create organization "+dto.Name, organizationAlreadyExists.Error(), nil)
+		return "", organizationAlreadyExists
+	}
+	if err != Error.StatusNotFound {
+		return "", err
+	}
+
+	dto.ID = uuid.NewString()
+	dto.CreatedAt = time.Now()
+
+	err = transaction.New(
+		query.New(
+			`INSERT INTO "organization" (id, name, created_at) VALUES ($1, $2, $3);`,
+			dto.ID, dto.Name, dto.CreatedAt,
+		),
+		newOrganizationAuditQuery(audit.CreateOperation, act, dto),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to create organization "+dto.Name, err.Error(), nil)
+		return "", err
+	}
+
+	dblog.Logger.Info("Creating organization "+dto.Name+": OK", nil)
+
+	return dto.ID, nil
+}
@@ -0,0 +1,112 @@
+package organizationtable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/invalidation"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+)
+
+func (m *Manager) DeleteOrganization(act *ActionDTO.Basic, id string) *Error.Status {
+	dblog.Logger.Info("Deleting organization "+id+"...", nil)
+
+	if act.IsOrganizationScoped() {
+		return organizationScopedAction
+	}
+
+	if err := authz.User.For(act).DeleteOrganization(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	org, err := getOrganizationByID(id)
+	if err != nil {
+		dblog.Logger.Error("Failed to delete organization "+id, err.Error(), nil)
+		return err
+	}
+
+	// Members must be collected before deletion, since memberships are deleted with organization
+	scan, err := executor.Row(connection.Primary, query.New(
+		`SELECT COALESCE(array_agg(user_id::text), '{}') FROM "organization_member" WHERE organization_id = $1;`,
+		id,
+	))
+	if err != nil {
+		return err
+	}
+
+	UIDs := []string{}
+
+	if err := scan(&UIDs); err != nil {
+		return err
+	}
+
+	err = transaction.New(
+		invalidation.NewBumpVersionsQuery(UIDs),
+		query.New(`DELETE FROM "organization" WHERE id = $1;`, id),
+		newOrganizationAuditQuery(audit.DeleteOperation, act, org),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to delete organization "+id, err.Error(), nil)
+		return err
+	}
+
+	invalidation.Users(UIDs)
+
+	dblog.Logger.Info("Deleting organization "+id+": OK", nil)
+
+	return nil
+}
+
+func (m *Manager) RemoveMember(act *ActionDTO.UserTargeted, orgID string) *Error.Status {
+	dblog.Logger.Info("Removing member "+act.TargetUID+" from organization "+orgID+"...", nil)
+
+	if err := validateOrganizationID(orgID); err != nil {
+		return err
+	}
+	if err := act.ValidateUIDs(); err != nil {
+		dblog.Logger.Error("Failed to remove member "+act.TargetUID+" from organization "+orgID, err.Error(), nil)
+		return err
+	}
+
+	if err := checkScope(&act.Basic, orgID); err != nil {
+		return err
+	}
+
+	if act.RequesterUID == act.TargetUID {
+		return ownMembershipChange
+	}
+
+	if err := authz.User.For(act).ChangeOrganizationMembers(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	member, err := getMember(orgID, act.TargetUID)
+	if err != nil {
+		dblog.Logger.Error("Failed to remove member "+act.TargetUID+" from organization "+orgID, err.Error(), nil)
+		return err
+	}
+
+	err = transaction.New(
+		query.New(
+			`DELETE FROM "organization_member" WHERE organization_id = $1 AND user_id = $2;`,
+			orgID, act.TargetUID,
+		),
+		newMemberAuditQuery(audit.DeleteOperation, act, orgID, member.Roles),
+		invalidation.NewBumpVersionsQuery([]string{act.TargetUID}),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to remove member "+act.TargetUID+" from organization "+orgID, err.Error(), nil)
+		return err
+	}
+
+	invalidation.Users([]string{act.TargetUID})
+
+	dblog.Logger.Info("Removing member "+act.TargetUID+" from organization "+orgID+": OK", nil)
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package organizationtable
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+)
+
+var organizationAlreadyExists = Error.NewStatusError(
+	"Organization with this name already exists",
+	http.StatusConflict,
+)
+
+var organizationNameIsMissing = Error.NewStatusError(
+	"Organization name is missing",
+	http.StatusBadRequest,
+)
+
+var organizationNameIsTooLong = Error.NewStatusError(
+	"Organization name is too long (max 72 characters)",
+	http.StatusBadRequest,
+)
+
+var outsideOfOrganization = Error.NewStatusError(
+	"Action is outside of the organization to which the token is scoped",
+	http.StatusForbidden,
+)
+
+var organizationScopedAction = Error.NewStatusError(
+	"This action can't be performed with organization-scoped token",
+	http.StatusForbidden,
+)
+
+var ownMembershipChange = Error.NewStatusError(
+	"Can't change own organization membership",
+	http.StatusForbidden,
+)
+
+var memberRolesAreMissing = Error.NewStatusError(
+	"Member roles are missing",
+	http.StatusBadRequest,
+)
+
+var memberHasPrivilegedRoles = Error.NewStatusError(
+	"Privileged roles can't be assigned to organization members",
+	http.StatusBadRequest,
+)
+
+var scopedMemberAddition = Error.NewStatusError(
+	"New members can't be added with organization-scoped token, only roles of existing members can be changed",
+	http.StatusForbidden,
+)
@@ -0,0 +1,10 @@
+package organizationtable
+
+type Manager struct {
+	//
+}
+
+const selectOrganizationSQL = `SELECT id, name, created_at FROM "organization"`
+
+const selectMemberSQL = `SELECT m.organization_id, m.user_id, u.login, m.roles, m.created_at, m.updated_at
+FROM "organization_member" AS m JOIN "user" AS u ON u.id = m.user_id`
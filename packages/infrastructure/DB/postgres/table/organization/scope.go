@@ -0,0 +1,26 @@
+package organizationtable
+
+import (
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	ActionDTO "sentinel/packages/core/action/DTO"
+)
+
+func validateOrganizationID(orgID string) *Error.Status {
+	if err := validation.UUID(orgID); err != nil {
+		return err.ToStatus(
+			"Organization ID is not specified",
+			"Organization ID has invalid format (UUID expected)",
+		)
+	}
+	return nil
+}
+
+// Organization-scoped actions can only affect organization to which they are scoped,
+// so admin of one organization can't touch anything outside of it.
+func checkScope(act *ActionDTO.Basic, orgID string) *Error.Status {
+	if act.IsOrganizationScoped() && act.OrganizationID != orgID {
+		return outsideOfOrganization
+	}
+	return nil
+}
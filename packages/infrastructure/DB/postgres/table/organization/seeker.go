@@ -0,0 +1,194 @@
+package organizationtable
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	OrganizationDTO "sentinel/packages/core/organization/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+	"strconv"
+)
+
+func getOrganizationByID(id string) (*OrganizationDTO.Full, *Error.Status) {
+	if err := validateOrganizationID(id); err != nil {
+		return nil, err
+	}
+
+	return executor.FullOrganizationDTO(
+		connection.Primary,
+		query.New(selectOrganizationSQL+` WHERE id = $1;`, id),
+	)
+}
+
+func (m *Manager) GetOrganizationByID(act *ActionDTO.Basic, id string) (*OrganizationDTO.Full, *Error.Status) {
+	dblog.Logger.Info("Getting organization "+id+"...", nil)
+
+	if err := checkScope(act, id); err != nil {
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetOrganization(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	org, err := getOrganizationByID(id)
+	if err != nil {
+		dblog.Logger.Error("Failed to get organization "+id, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting organization "+id+": OK", nil)
+
+	return org, nil
+}
+
+func (m *Manager) GetMembers(act *ActionDTO.Basic, orgID string, page int, pageSize int) ([]*OrganizationDTO.Member, *Error.Status) {
+	dblog.Logger.Info("Getting members of organization "+orgID+"...", nil)
+
+	if err := checkScope(act, orgID); err != nil {
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetOrganizationMembers(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		errMsg := "Invalid page: " + strconv.Itoa(page) + ". It must be greater than 0."
+		dblog.Logger.Error("Failed to get members of organization "+orgID, errMsg, nil)
+		return nil, Error.NewStatusError(errMsg, http.StatusBadRequest)
+	}
+	if pageSize < 1 || pageSize > config.DB.MaxSearchPageSize {
+		errMsg := "Invalid page size: " + strconv.Itoa(pageSize) + ". It must be between 1 and " + strconv.Itoa(config.DB.MaxSearchPageSize)
+		dblog.Logger.Error("Failed to get members of organization "+orgID, errMsg, nil)
+		return nil, Error.NewStatusError(errMsg, http.StatusBadRequest)
+	}
+
+	if _, err := getOrganizationByID(orgID); err != nil {
+		dblog.Logger.Error("Failed to get members of organization "+orgID, err.Error(), nil)
+		return nil, err
+	}
+
+	members, err := executor.CollectMemberDTO(
+		connection.Replica,
+		query.New(
+			selectMemberSQL+` WHERE m.organization_id = $1 ORDER BY m.created_at, m.user_id LIMIT $2 OFFSET $3;`,
+			orgID, pageSize, (page-1)*pageSize,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting members of organization "+orgID+": OK", nil)
+
+	return members, nil
+}
+
+func getMember(orgID string, UID string) (*OrganizationDTO.Member, *Error.Status) {
+	return executor.MemberDTO(
+		connection.Primary,
+		query.New(selectMemberSQL+` WHERE m.organization_id = $1 AND m.user_id = $2;`, orgID, UID),
+	)
+}
+
+func (m *Manager) GetMember(act *ActionDTO.UserTargeted, orgID string) (*OrganizationDTO.Member, *Error.Status) {
+	dblog.Logger.Info("Getting member "+act.TargetUID+" of organization "+orgID+"...", nil)
+
+	if err := validateOrganizationID(orgID); err != nil {
+		return nil, err
+	}
+	if err := act.ValidateTargetUID(); err != nil {
+		return nil, err
+	}
+
+	if err := checkScope(&act.Basic, orgID); err != nil {
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetOrganizationMembers(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	member, err := getMember(orgID, act.TargetUID)
+	if err != nil {
+		dblog.Logger.Error("Failed to get member "+act.TargetUID+" of organization "+orgID, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting member "+act.TargetUID+" of organization "+orgID+": OK", nil)
+
+	return member, nil
+}
+
+func (m *Manager) IsMember(orgID string, UID string) (bool, *Error.Status) {
+	if _, err := m.GetMemberRoles(orgID, UID); err != nil {
+		if err == Error.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *Manager) GetMemberRoles(orgID string, UID string) ([]string, *Error.Status) {
+	dblog.Logger.Trace("Getting roles of member "+UID+" of organization "+orgID+"...", nil)
+
+	if err := validateOrganizationID(orgID); err != nil {
+		return nil, err
+	}
+	if err := validation.UUID(UID); err != nil {
+		return nil, err.ToStatus("User ID is not specified", "User ID has invalid format (UUID expected)")
+	}
+
+	// Primary is used cuz roles are usually requested when token is issued
+	scan, err := executor.Row(connection.Primary, query.New(
+		`SELECT roles FROM "organization_member" WHERE organization_id = $1 AND user_id = $2;`,
+		orgID, UID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{}
+
+	if err := scan(&roles); err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Trace("Getting roles of member "+UID+" of organization "+orgID+": OK", nil)
+
+	return roles, nil
+}
+
+func (m *Manager) GetUserOrganizationIDs(UID string) ([]string, *Error.Status) {
+	dblog.Logger.Trace("Getting organizations of user "+UID+"...", nil)
+
+	if err := validation.UUID(UID); err != nil {
+		return nil, err.ToStatus("User ID is not specified", "User ID has invalid format (UUID expected)")
+	}
+
+	scan, err := executor.Row(connection.Replica, query.New(
+		`SELECT COALESCE(array_agg(organization_id::text), '{}') FROM "organization_member" WHERE user_id = $1;`,
+		UID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	IDs := []string{}
+
+	if err := scan(&IDs); err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Trace("Getting organizations of user "+UID+": OK", nil)
+
+	return IDs, nil
+}
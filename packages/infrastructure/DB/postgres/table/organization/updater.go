@@ -0,0 +1,121 @@
+package organizationtable
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/invalidation"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+	"slices"
+	"time"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+func (m *Manager) SetMember(act *ActionDTO.UserTargeted, orgID string, roles []string) *Error.Status {
+	dblog.Logger.Info("Setting member "+act.TargetUID+" of organization "+orgID+"...", nil)
+
+	if err := validateOrganizationID(orgID); err != nil {
+		return err
+	}
+	if err := act.ValidateUIDs(); err != nil {
+		dblog.Logger.Error("Failed to set member "+act.TargetUID+" of organization "+orgID, err.Error(), nil)
+		return err
+	}
+
+	if err := checkScope(&act.Basic, orgID); err != nil {
+		return err
+	}
+
+	if len(roles) == 0 {
+		return memberRolesAreMissing
+	}
+
+	schemaRoles := authz.GetSchema().Roles
+	for _, role := range roles {
+		if !slices.ContainsFunc(schemaRoles, func(r rbac.Role) bool { return r.Name == role }) {
+			errMsg := "Role '" + role + "' doesn't exists"
+			dblog.Logger.Error("Failed to set member "+act.TargetUID+" of organization "+orgID, errMsg, nil)
+			return Error.NewStatusError(errMsg, http.StatusBadRequest)
+		}
+		// Otherwise privileged roles could be obtained bypassing approval of the role change request
+		if slices.Contains(config.Authz.PrivilegedRoles, role) {
+			dblog.Logger.Error("Failed to set member "+act.TargetUID+" of organization "+orgID, memberHasPrivilegedRoles.Error(), nil)
+			return memberHasPrivilegedRoles
+		}
+	}
+
+	if act.RequesterUID == act.TargetUID {
+		return ownMembershipChange
+	}
+
+	if err := authz.User.For(act).ChangeOrganizationMembers(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	if _, err := getOrganizationByID(orgID); err != nil {
+		dblog.Logger.Error("Failed to set member "+act.TargetUID+" of organization "+orgID, err.Error(), nil)
+		return err
+	}
+
+	scan, err := executor.Row(connection.Primary, query.New(
+		`SELECT EXISTS(SELECT 1 FROM "user" WHERE id = $1 AND deleted_at IS NULL);`,
+		act.TargetUID,
+	))
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	if err := scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return Error.StatusNotFound
+	}
+
+	op := audit.UpdatedOperation
+	if _, err := getMember(orgID, act.TargetUID); err == Error.StatusNotFound {
+		op = audit.CreateOperation
+	} else if err != nil {
+		return err
+	}
+
+	// Membership gives access to the sessions of the user and makes it searchable within organization,
+	// so organization admin mustn't be able to pull in arbitrary users.
+	if op == audit.CreateOperation && act.IsOrganizationScoped() {
+		dblog.Logger.Error("Failed to set member "+act.TargetUID+" of organization "+orgID, scopedMemberAddition.Error(), nil)
+		return scopedMemberAddition
+	}
+
+	now := time.Now()
+
+	err = transaction.New(
+		query.New(
+			`INSERT INTO "organization_member" (organization_id, user_id, roles, created_at, updated_at)
+            VALUES ($1, $2, $3, $4, $4)
+            ON CONFLICT (organization_id, user_id) DO UPDATE SET roles = EXCLUDED.roles, updated_at = EXCLUDED.updated_at;`,
+			orgID, act.TargetUID, roles, now,
+		),
+		newMemberAuditQuery(op, act, orgID, roles),
+		// Organization-scoped tokens of the member must be refreshed to get new roles
+		invalidation.NewBumpVersionsQuery([]string{act.TargetUID}),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to set member "+act.TargetUID+" of organization "+orgID, err.Error(), nil)
+		return err
+	}
+
+	invalidation.Users([]string{act.TargetUID})
+
+	dblog.Logger.Info("Setting member "+act.TargetUID+" of organization "+orgID+": OK", nil)
+
+	return nil
+}
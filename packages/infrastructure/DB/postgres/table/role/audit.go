@@ -0,0 +1,67 @@
+package roletable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	RoleDTO "sentinel/packages/core/role/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"time"
+)
+
+func newAuditDTO(op audit.Operation, act *ActionDTO.Basic, role *RoleDTO.Full) RoleDTO.Audit {
+	return RoleDTO.Audit{
+		ChangedRoleID:   role.ID,
+		ChangedByUserID: act.RequesterUID,
+		Operation:       string(op),
+		ChangedAt:       time.Now(),
+		Reason:          act.Reason,
+		Full:            role,
+
+		ImpersonatedByUserID: act.ImpersonatorUID,
+	}
+}
+
+// Converts empty string into NULL
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func newAuditQuery(dto *RoleDTO.Audit) *query.Query {
+	return query.New(
+		`INSERT INTO "audit_rbac_role"
+        (changed_role_id, changed_by_user_id, impersonated_by_user_id, operation, schema_id, name, permissions, resource_permissions, is_default, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		dto.ChangedRoleID,
+		nullable(dto.ChangedByUserID),
+		nullable(dto.ImpersonatedByUserID),
+		dto.Operation,
+		dto.SchemaID,
+		dto.Name,
+		dto.Permissions,
+		resourcePermissions(dto.Full),
+		dto.IsDefault,
+		dto.ChangedAt,
+		nullable(dto.Reason),
+	)
+}
+
+func execTxWithAudit(dto *RoleDTO.Audit, queries ...*query.Query) *Error.Status {
+	queries = append(queries, newAuditQuery(dto))
+
+	return transaction.New(queries...).Exec(connection.Primary)
+}
+
+// Returns resource permissions of the role, which are never nil (column is NOT NULL)
+func resourcePermissions(role *RoleDTO.Full) map[string]uint16 {
+	if role.ResourcePermissions == nil {
+		return map[string]uint16{}
+	}
+	return role.ResourcePermissions
+}
@@ -0,0 +1,124 @@
+package roletable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	RoleDTO "sentinel/packages/core/role/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const insertRoleSQL = `INSERT INTO "rbac_role" (id, schema_id, name, permissions, resource_permissions, is_default, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+func newInsertQuery(sqlSuffix string, dto *RoleDTO.Full) *query.Query {
+	return query.New(
+		insertRoleSQL+sqlSuffix,
+		dto.ID,
+		dto.SchemaID,
+		dto.Name,
+		dto.Permissions,
+		resourcePermissions(dto),
+		dto.IsDefault,
+		dto.CreatedAt,
+		dto.UpdatedAt,
+	)
+}
+
+func (m *Manager) CreateRole(act *ActionDTO.Basic, dto *RoleDTO.Full) (string, *Error.Status) {
+	dblog.Logger.Info("Creating role "+dto.Name+"...", nil)
+
+	if err := authz.User.For(act).CreateRole(act.RequesterRoles); err != nil {
+		return "", err
+	}
+
+	if _, err := m.getRoleByName(dto.SchemaID, dto.Name); err == nil {
+		dblog.Logger.Error("Failed to create role "+dto.Name, roleAlreadyExists.Error(), nil)
+		return "", roleAlreadyExists
+	} else if err != Error.StatusNotFound {
+		return "", err
+	}
+
+	now := time.Now()
+
+	dto.ID = uuid.NewString()
+	dto.CreatedAt = now
+	dto.UpdatedAt = now
+
+	audit := newAuditDTO(audit.CreateOperation, act, dto)
+
+	if err := execTxWithAudit(&audit, newInsertQuery(";", dto)); err != nil {
+		return "", err
+	}
+
+	dblog.Logger.Info("Creating role "+dto.Name+": OK", nil)
+
+	return dto.ID, nil
+}
+
+func (m *Manager) SeedRoles(dtos []*RoleDTO.Full) (bool, *Error.Status) {
+	dblog.Logger.Info("Seeding roles...", nil)
+
+	scan, err := executor.Row(connection.Primary, query.New(`SELECT EXISTS(SELECT 1 FROM "rbac_role");`))
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := scan(&exists); err != nil {
+		return false, err
+	}
+	if exists {
+		dblog.Logger.Info("Seeding roles: SKIPPED (roles already exist)", nil)
+		return false, nil
+	}
+
+	now := time.Now()
+	queries := make([]*query.Query, 0, len(dtos)*2)
+
+	for _, dto := range dtos {
+		dto.ID = uuid.NewString()
+		dto.CreatedAt = now
+		dto.UpdatedAt = now
+
+		// Several instances may try to seed roles at the same time
+		queries = append(queries, newInsertQuery(" ON CONFLICT (schema_id, name) DO NOTHING;", dto))
+		queries = append(queries, newSeedAuditQuery(dto, now))
+	}
+
+	if err := transaction.New(queries...).Exec(connection.Primary); err != nil {
+		return false, err
+	}
+
+	dblog.Logger.Info("Seeding roles: OK", nil)
+
+	return true, nil
+}
+
+// Audit record is inserted only if role was actually inserted (see SeedRoles).
+// Seeding isn't performed by any user, so changed_by_user_id is NULL.
+func newSeedAuditQuery(dto *RoleDTO.Full, changedAt time.Time) *query.Query {
+	return query.New(
+		`INSERT INTO "audit_rbac_role"
+        (changed_role_id, operation, schema_id, name, permissions, resource_permissions, is_default, changed_at, reason)
+        SELECT $1::uuid, $2::char(1), $3::varchar, $4::varchar, $5::smallint, $6::jsonb, $7::boolean, $8::timestamp, $9::text
+        WHERE EXISTS(SELECT 1 FROM "rbac_role" WHERE id = $1::uuid);`,
+		dto.ID,
+		string(audit.CreateOperation),
+		dto.SchemaID,
+		dto.Name,
+		dto.Permissions,
+		resourcePermissions(dto),
+		dto.IsDefault,
+		changedAt,
+		"Seeded from RBAC configuration file",
+	)
+}
@@ -0,0 +1,80 @@
+package roletable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+)
+
+func (m *Manager) DeleteRole(act *ActionDTO.Basic, id string) *Error.Status {
+	dblog.Logger.Info("Deleting role "+id+"...", nil)
+
+	if err := authz.User.For(act).DeleteRole(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	role, err := m.getRoleByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Users reference roles by name, so role can be safely deleted only if there are
+	// no users with it (including grants, groups and organization memberships) or if global role with the same name will still exist.
+	globalRoleRemains := false
+	if !role.IsGlobal() {
+		_, err := m.getRoleByName("", role.Name)
+		if err != nil && err != Error.StatusNotFound {
+			return err
+		}
+		globalRoleRemains = err == nil
+	}
+	if !globalRoleRemains {
+		if err := m.checkRoleIsUnused(role.Name); err != nil {
+			return err
+		}
+	}
+
+	audit := newAuditDTO(audit.DeleteOperation, act, role)
+
+	deleteQuery := query.New(`DELETE FROM "rbac_role" WHERE id = $1;`, id)
+
+	if err := execTxWithAudit(&audit, deleteQuery); err != nil {
+		return err
+	}
+
+	dblog.Logger.Info("Deleting role "+id+": OK", nil)
+
+	return nil
+}
+
+func (m *Manager) checkRoleIsUnused(name string) *Error.Status {
+	scan, err := executor.Row(
+		connection.Primary,
+		query.New(
+			`SELECT EXISTS(SELECT 1 FROM "user" WHERE $1 = ANY(roles))
+            OR EXISTS(SELECT 1 FROM "user_role_grant" WHERE role = $1)
+            OR EXISTS(SELECT 1 FROM "user_group" WHERE $1 = ANY(roles))
+            OR EXISTS(SELECT 1 FROM "organization_member" WHERE $1 = ANY(roles));`,
+			name,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	var inUse bool
+	if err := scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		dblog.Logger.Error("Failed to delete role "+name, roleIsInUse.Error(), nil)
+		return roleIsInUse
+	}
+
+	return nil
+}
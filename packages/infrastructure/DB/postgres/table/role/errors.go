@@ -0,0 +1,16 @@
+package roletable
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+)
+
+var roleAlreadyExists = Error.NewStatusError(
+	"Role with this name already exists in this schema",
+	http.StatusConflict,
+)
+
+var roleIsInUse = Error.NewStatusError(
+	"Role is assigned to users, remove it from all users (including role grants, groups and organization members) before deleting",
+	http.StatusConflict,
+)
@@ -0,0 +1,7 @@
+package roletable
+
+type Manager struct {
+	//
+}
+
+const selectRoleSQL = `SELECT id, schema_id, name, permissions, resource_permissions, is_default, created_at, updated_at FROM "rbac_role"`
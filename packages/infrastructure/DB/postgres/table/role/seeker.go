@@ -0,0 +1,81 @@
+package roletable
+
+import (
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	RoleDTO "sentinel/packages/core/role/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+)
+
+func (m *Manager) getRoleByID(id string) (*RoleDTO.Full, *Error.Status) {
+	dblog.Logger.Trace("Getting role "+id+"...", nil)
+
+	if err := validation.UUID(id); err != nil {
+		e := err.ToStatus("Role ID is not specified", "Role ID has invalid format (UUID expected)")
+		dblog.Logger.Error("Failed to get role "+id, e.Error(), nil)
+		return nil, e
+	}
+
+	selectQuery := query.New(selectRoleSQL+` WHERE id = $1;`, id)
+
+	dto, err := executor.FullRoleDTO(connection.Primary, selectQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Trace("Getting role "+id+": OK", nil)
+
+	return dto, nil
+}
+
+func (m *Manager) getRoleByName(schemaID string, name string) (*RoleDTO.Full, *Error.Status) {
+	dblog.Logger.Trace("Getting role "+name+" of schema '"+schemaID+"'...", nil)
+
+	selectQuery := query.New(selectRoleSQL+` WHERE schema_id = $1 AND name = $2;`, schemaID, name)
+
+	dto, err := executor.FullRoleDTO(connection.Primary, selectQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Trace("Getting role "+name+" of schema '"+schemaID+"': OK", nil)
+
+	return dto, nil
+}
+
+func (m *Manager) GetRoleByID(act *ActionDTO.Basic, id string) (*RoleDTO.Full, *Error.Status) {
+	if err := authz.User.For(act).GetRole(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	return m.getRoleByID(id)
+}
+
+func (m *Manager) GetAllRoles(act *ActionDTO.Basic) ([]*RoleDTO.Full, *Error.Status) {
+	if err := authz.User.For(act).GetRole(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	return m.LoadAllRoles()
+}
+
+func (m *Manager) LoadAllRoles() ([]*RoleDTO.Full, *Error.Status) {
+	dblog.Logger.Trace("Getting all roles...", nil)
+
+	// Primary is used to avoid building RBAC schema from stale replica
+	selectQuery := query.New(selectRoleSQL + ` ORDER BY schema_id, name;`)
+
+	dtos, err := executor.CollectFullRoleDTO(connection.Primary, selectQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Trace("Getting all roles: OK", nil)
+
+	return dtos, nil
+}
@@ -0,0 +1,53 @@
+package roletable
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	RoleDTO "sentinel/packages/core/role/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+)
+
+// Updates permissions, resource permissions and default state of the role.
+// Name and schema of the role can't be changed, since users reference roles by name.
+func (m *Manager) UpdateRole(act *ActionDTO.Basic, id string, dto *RoleDTO.Full) *Error.Status {
+	dblog.Logger.Info("Updating role "+id+"...", nil)
+
+	if err := authz.User.For(act).UpdateRole(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	role, err := m.getRoleByID(id)
+	if err != nil {
+		return err
+	}
+
+	updatedRole := *role
+	updatedRole.Permissions = dto.Permissions
+	updatedRole.ResourcePermissions = dto.ResourcePermissions
+	updatedRole.IsDefault = dto.IsDefault
+
+	audit := newAuditDTO(audit.UpdatedOperation, act, &updatedRole)
+
+	updatedRole.UpdatedAt = audit.ChangedAt
+
+	updateQuery := query.New(
+		`UPDATE "rbac_role" SET permissions = $1, resource_permissions = $2, is_default = $3, updated_at = $4
+        WHERE id = $5;`,
+		updatedRole.Permissions,
+		resourcePermissions(&updatedRole),
+		updatedRole.IsDefault,
+		updatedRole.UpdatedAt,
+		id,
+	)
+
+	if err := execTxWithAudit(&audit, updateQuery); err != nil {
+		return err
+	}
+
+	dblog.Logger.Info("Updating role "+id+": OK", nil)
+
+	return nil
+}
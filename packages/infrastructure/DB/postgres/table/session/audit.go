@@ -20,6 +20,8 @@ func newAuditDTO(op audit.Operation, act *ActionDTO.Basic, session *SessionDTO.F
 		ChangedAt:        time.Now(),
 		Reason:           act.Reason,
 		Full:             session,
+
+		ImpersonatedByUserID: act.ImpersonatorUID,
 	}
 }
 
@@ -31,11 +33,23 @@ func newAuditQuery(dto *SessionDTO.Audit) *query.Query {
 		reason = nil
 	}
 
+	var name any = dto.Name
+
+	if dto.Name == "" {
+		name = nil
+	}
+
+	var impersonatedBy any = dto.ImpersonatedByUserID
+
+	if dto.ImpersonatedByUserID == "" {
+		impersonatedBy = nil
+	}
+
 	return query.New(
 		`INSERT INTO "audit_user_session"
-        (changed_session_id, changed_by_user_id, operation, user_id, user_agent, ip_address, device_id, device_type, os, os_version, browser, browser_version, created_at, last_used_at, expires_at, revoked_at, changed_at, reason)
+        (changed_session_id, changed_by_user_id, operation, user_id, user_agent, ip_address, device_id, device_type, os, os_version, browser, browser_version, created_at, last_used_at, expires_at, revoked_at, changed_at, reason, impersonated_by_user_id, name, trusted)
         VALUES
-        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`,
 		dto.ChangedSessionID,
 		dto.ChangedByUserID,
 		dto.Operation,
@@ -54,6 +68,9 @@ func newAuditQuery(dto *SessionDTO.Audit) *query.Query {
 		revokedAt,
 		dto.ChangedAt,
 		reason,
+		impersonatedBy,
+		name,
+		dto.Trusted,
 	)
 }
 
@@ -38,3 +38,59 @@ func (m *Manager) SaveSession(session *SessionDTO.Full) *Error.Status {
 
 	return nil
 }
+
+func (m *Manager) SaveSessionRisk(risk *SessionDTO.Risk) *Error.Status {
+	dblog.Logger.Trace("Saving risk assessment of session "+risk.SessionID+"...", nil)
+
+	insertQuery := query.New(
+		`INSERT INTO "session_risk" (session_id, user_id, ip, country, score, reasons, action, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`,
+		risk.SessionID,
+		risk.UserID,
+		risk.IP,
+		risk.Country,
+		risk.Score,
+		risk.Reasons,
+		risk.Action,
+		risk.CreatedAt,
+	)
+
+	if err := executor.Exec(connection.Primary, insertQuery); err != nil {
+		return err
+	}
+
+	dblog.Logger.Trace("Saving risk assessment of session "+risk.SessionID+": OK", nil)
+
+	return nil
+}
+
+func (m *Manager) SaveImpersonation(imp *SessionDTO.Impersonation) *Error.Status {
+	dblog.Logger.Trace("Saving impersonation of user "+imp.ImpersonatedUserID+" by user "+imp.ImpersonatorID+"...", nil)
+
+	var reason any = imp.Reason
+
+	if imp.Reason == "" {
+		reason = nil
+	}
+
+	insertQuery := query.New(
+		`INSERT INTO "audit_impersonation" (impersonator_id, impersonated_user_id, session_id, audience, reason, ip_address, started_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`,
+		imp.ImpersonatorID,
+		imp.ImpersonatedUserID,
+		imp.SessionID,
+		imp.Audience,
+		reason,
+		imp.IP,
+		imp.StartedAt,
+		imp.ExpiresAt,
+	)
+
+	if err := executor.Exec(connection.Primary, insertQuery); err != nil {
+		return err
+	}
+
+	dblog.Logger.Trace("Saving impersonation of user "+imp.ImpersonatedUserID+" by user "+imp.ImpersonatorID+": OK", nil)
+
+	return nil
+}
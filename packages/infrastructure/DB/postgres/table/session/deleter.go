@@ -9,10 +9,13 @@ import (
 	"sentinel/packages/infrastructure/DB/postgres/audit"
 	"sentinel/packages/infrastructure/DB/postgres/connection"
 	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
 	"sentinel/packages/infrastructure/DB/postgres/query"
 	"sentinel/packages/infrastructure/DB/postgres/transaction"
 	"sentinel/packages/infrastructure/auth/authz"
 	"sentinel/packages/infrastructure/cache"
+	"sentinel/packages/infrastructure/revocation"
+	"slices"
 )
 
 func NewRevokeAllUserSessionsQuery(act *ActionDTO.UserTargeted) *query.Query {
@@ -23,7 +26,7 @@ func (m *Manager) RevokeSession(act *ActionDTO.UserTargeted, sessionID string) *
 	dblog.Logger.Trace("Revoking user session...", nil)
 
 	if act.RequesterUID != act.TargetUID {
-		if err := authz.User.Logout(act.RequesterRoles); err != nil {
+		if err := authz.User.For(act).Logout(act.RequesterRoles); err != nil {
 			return err
 		}
 	}
@@ -49,6 +52,8 @@ func (m *Manager) RevokeSession(act *ActionDTO.UserTargeted, sessionID string) *
 		cache.KeyBase[cache.UserBySessionID]+sessionID,
 	)
 
+	revocation.TryPublish(revocation.NewSessionRevokedEvent(session.UserID, sessionID))
+
 	dblog.Logger.Trace("Revoking user session: OK", nil)
 
 	return nil
@@ -67,11 +72,35 @@ func (m *Manager) deleteSessionsCache(sessions []*SessionDTO.Full) *Error.Status
 
 const revokeAllUserSessionsSQL = `UPDATE "user_session" SET revoked_at = NOW() WHERE user_id = $1;`
 
+// Revokes given sessions in one transaction (with audit for each of them),
+// then invalidates their cache and publishes revocation events.
+func (m *Manager) revokeSessions(act *ActionDTO.UserTargeted, sessions []*SessionDTO.Full, revokeQuery *query.Query) *Error.Status {
+	queries := make([]*query.Query, 0, len(sessions)+1)
+	queries = append(queries, revokeQuery)
+
+	for _, session := range sessions {
+		auditDTO := newAuditDTO(audit.DeleteOperation, &act.Basic, session)
+		queries = append(queries, newAuditQuery(&auditDTO))
+	}
+
+	if err := transaction.New(queries...).Exec(connection.Primary); err != nil {
+		return err
+	}
+
+	m.deleteSessionsCache(sessions)
+
+	for _, session := range sessions {
+		revocation.TryPublish(revocation.NewSessionRevokedEvent(session.UserID, session.ID))
+	}
+
+	return nil
+}
+
 func (m *Manager) RevokeAllUserSessions(act *ActionDTO.UserTargeted) *Error.Status {
 	dblog.Logger.Trace("Revoking all user sessions...", nil)
 
 	if act.RequesterUID != act.TargetUID {
-		if err := authz.User.Logout(act.RequesterRoles); err != nil {
+		if err := authz.User.For(act).Logout(act.RequesterRoles); err != nil {
 			return err
 		}
 	}
@@ -81,25 +110,62 @@ func (m *Manager) RevokeAllUserSessions(act *ActionDTO.UserTargeted) *Error.Stat
 		return err
 	}
 
-	revokeQuery := query.New(revokeAllUserSessionsSQL, act.TargetUID)
+	if err := m.revokeSessions(act, sessions, query.New(revokeAllUserSessionsSQL, act.TargetUID)); err != nil {
+		return err
+	}
 
-	queries := make([]*query.Query, 0, len(sessions)+1)
-	queries = append(queries, revokeQuery)
+	dblog.Logger.Trace("Revoking all user sessions: OK", nil)
 
-	for i := range queries {
-		auditDTO := newAuditDTO(audit.DeleteOperation, &act.Basic, sessions[i])
-		queries = append(queries, newAuditQuery(&auditDTO))
+	return nil
+}
+
+// Revokes all sessions of the target user except the one with the given ID.
+// Returns revoked sessions.
+func (m *Manager) RevokeAllUserSessionsExcept(act *ActionDTO.UserTargeted, sessionID string) ([]*SessionDTO.Public, *Error.Status) {
+	dblog.Logger.Trace("Revoking all user sessions except "+sessionID+"...", nil)
+
+	if act.RequesterUID != act.TargetUID {
+		if err := authz.User.For(act).Logout(act.RequesterRoles); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := transaction.New(queries...).Exec(connection.Primary); err != nil {
-		return err
+	sessions, err := m.getUserSessions(act.TargetUID)
+	if err != nil {
+		if err == Error.StatusNotFound {
+			dblog.Logger.Trace("Revoking all user sessions except "+sessionID+": OK (nothing to revoke)", nil)
+			return []*SessionDTO.Public{}, nil
+		}
+		return nil, err
 	}
 
-	m.deleteSessionsCache(sessions)
+	sessions = slices.DeleteFunc(sessions, func(session *SessionDTO.Full) bool {
+		return session.ID == sessionID
+	})
 
-	dblog.Logger.Trace("Revoking all user sessions: OK", nil)
+	revoked := make([]*SessionDTO.Public, len(sessions))
+	for i, session := range sessions {
+		revoked[i] = session.MakePublic()
+	}
 
-	return nil
+	if len(sessions) == 0 {
+		dblog.Logger.Trace("Revoking all user sessions except "+sessionID+": OK (nothing to revoke)", nil)
+		return revoked, nil
+	}
+
+	revokeQuery := query.New(
+		`UPDATE "user_session" SET revoked_at = NOW() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;`,
+		act.TargetUID,
+		sessionID,
+	)
+
+	if err := m.revokeSessions(act, sessions, revokeQuery); err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Trace("Revoking all user sessions except "+sessionID+": OK", nil)
+
+	return revoked, nil
 }
 
 // Invalidates sessions cache of user with specified ID
@@ -131,3 +197,41 @@ func (m *Manager) DeleteUserSessionsCache(UID string) *Error.Status {
 
 	return nil
 }
+
+// Revokes all active sessions of the target user which were bound to the device with the given ID.
+func (m *Manager) RevokeDeviceSessions(act *ActionDTO.UserTargeted, deviceID string) *Error.Status {
+	dblog.Logger.Trace("Revoking sessions of device "+deviceID+"...", nil)
+
+	if act.RequesterUID != act.TargetUID {
+		if err := authz.User.For(act).Logout(act.RequesterRoles); err != nil {
+			return err
+		}
+	}
+
+	sessions, err := executor.CollectFullSessionDTO(connection.Primary, query.New(
+		selectSessionSQL+` WHERE user_id = $1 AND user_device_id = $2 AND revoked_at IS NULL;`,
+		act.TargetUID,
+		deviceID,
+	))
+	if err != nil {
+		if err == Error.StatusNotFound {
+			dblog.Logger.Trace("Revoking sessions of device "+deviceID+": OK (nothing to revoke)", nil)
+			return nil
+		}
+		return err
+	}
+
+	revokeQuery := query.New(
+		`UPDATE "user_session" SET revoked_at = NOW() WHERE user_id = $1 AND user_device_id = $2 AND revoked_at IS NULL;`,
+		act.TargetUID,
+		deviceID,
+	)
+
+	if err := m.revokeSessions(act, sessions, revokeQuery); err != nil {
+		return err
+	}
+
+	dblog.Logger.Trace("Revoking sessions of device "+deviceID+": OK", nil)
+
+	return nil
+}
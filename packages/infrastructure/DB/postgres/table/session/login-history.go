@@ -0,0 +1,172 @@
+package sessiontable
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	SessionDTO "sentinel/packages/core/session/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/auth/authz"
+	"strconv"
+	"strings"
+)
+
+// Max amount of entries in the exported login history
+const maxLoginHistoryExportSize = 10000
+
+// IP address of the login is taken from the earliest audit record of the session (if there are any),
+// since audit stores state of the session before each change.
+// Revocation info is taken from the latest audit record of the deletion.
+const selectLoginHistorySQL = `SELECT
+	s.id,
+	COALESCE(first_audit.ip_address, s.ip_address),
+	s.ip_address,
+	s.user_agent,
+	s.device_type,
+	s.os,
+	s.browser,
+	COALESCE(l.country, ''),
+	COALESCE(l.region, ''),
+	COALESCE(l.city, ''),
+	COALESCE(l.isp, ''),
+	s.created_at,
+	s.last_used_at,
+	s.revoked_at,
+	COALESCE(revocation.changed_by_user_id::text, ''),
+	COALESCE(revocation.reason, '')
+FROM "user_session" s
+LEFT JOIN "location" l ON l.session_id = s.id AND l.deleted_at IS NULL
+LEFT JOIN LATERAL (
+	SELECT a.ip_address FROM "audit_user_session" a
+	WHERE a.changed_session_id = s.id
+	ORDER BY a.changed_at, a.id
+	LIMIT 1
+) first_audit ON TRUE
+LEFT JOIN LATERAL (
+	SELECT a.changed_by_user_id, a.reason FROM "audit_user_session" a
+	WHERE a.changed_session_id = s.id AND a.operation = '` + string(audit.DeleteOperation) + `'
+	ORDER BY a.changed_at DESC, a.id DESC
+	LIMIT 1
+) revocation ON TRUE`
+
+func newLoginHistoryQuery(UID string, filter *SessionDTO.LoginHistoryFilter, limit int, offset int) *query.Query {
+	conds := []string{"s.user_id = $1"}
+	args := []any{UID}
+
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, cond+" $"+strconv.Itoa(len(args)))
+	}
+
+	if !filter.From.IsZero() {
+		addCond("s.created_at >=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCond("s.created_at <", filter.To)
+	}
+	if filter.Country != "" {
+		addCond("l.country =", strings.ToUpper(filter.Country))
+	}
+	if filter.DeviceType != "" {
+		addCond("s.device_type =", filter.DeviceType)
+	}
+
+	args = append(args, limit, offset)
+
+	return query.New(
+		selectLoginHistorySQL+
+			" WHERE "+strings.Join(conds, " AND ")+
+			" ORDER BY s.created_at DESC, s.id DESC"+
+			" LIMIT $"+strconv.Itoa(len(args)-1)+" OFFSET $"+strconv.Itoa(len(args))+";",
+		args...,
+	)
+}
+
+func validateLoginHistoryFilter(filter *SessionDTO.LoginHistoryFilter) *Error.Status {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return Error.NewStatusError("Invalid time range: 'from' must be before 'to'", http.StatusBadRequest)
+	}
+	if filter.Country != "" && len(filter.Country) != 2 {
+		return Error.NewStatusError("Invalid country: ISO 3166-1 alpha-2 code expected", http.StatusBadRequest)
+	}
+	return nil
+}
+
+func getLoginHistory(
+	act *ActionDTO.UserTargeted,
+	filter *SessionDTO.LoginHistoryFilter,
+	limit int,
+	offset int,
+) ([]*SessionDTO.Login, *Error.Status) {
+	if err := act.ValidateTargetUID(); err != nil {
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetLoginHistory(
+		act.TargetUID == act.RequesterUID,
+		act.RequesterRoles,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := validateLoginHistoryFilter(filter); err != nil {
+		return nil, err
+	}
+
+	return executor.CollectLoginDTO(
+		connection.Replica,
+		newLoginHistoryQuery(act.TargetUID, filter, limit, offset),
+	)
+}
+
+func (m *Manager) GetLoginHistory(
+	act *ActionDTO.UserTargeted,
+	filter *SessionDTO.LoginHistoryFilter,
+	page int,
+	pageSize int,
+) ([]*SessionDTO.Login, *Error.Status) {
+	dblog.Logger.Info("Getting login history of user "+act.TargetUID+"...", nil)
+
+	if page < 1 {
+		errMsg := "Invalid page: " + strconv.Itoa(page) + ". It must be greater than 0."
+		dblog.Logger.Error("Failed to get login history of user "+act.TargetUID, errMsg, nil)
+		return nil, Error.NewStatusError(errMsg, http.StatusBadRequest)
+	}
+	if pageSize < 1 || pageSize > config.DB.MaxSearchPageSize {
+		errMsg := "Invalid page size: " + strconv.Itoa(pageSize) + ". It must be between 1 and " + strconv.Itoa(config.DB.MaxSearchPageSize)
+		dblog.Logger.Error("Failed to get login history of user "+act.TargetUID, errMsg, nil)
+		return nil, Error.NewStatusError(errMsg, http.StatusBadRequest)
+	}
+
+	logins, err := getLoginHistory(act, filter, pageSize, (page-1)*pageSize)
+	if err != nil {
+		dblog.Logger.Error("Failed to get login history of user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting login history of user "+act.TargetUID+": OK", nil)
+
+	return logins, nil
+}
+
+func (m *Manager) ExportLoginHistory(
+	act *ActionDTO.UserTargeted,
+	filter *SessionDTO.LoginHistoryFilter,
+) ([]*SessionDTO.Login, *Error.Status) {
+	dblog.Logger.Info("Exporting login history of user "+act.TargetUID+"...", nil)
+
+	logins, err := getLoginHistory(act, filter, maxLoginHistoryExportSize, 0)
+	if err != nil {
+		dblog.Logger.Error("Failed to export login history of user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Exporting login history of user "+act.TargetUID+": OK", nil)
+
+	return logins, nil
+}
@@ -19,7 +19,7 @@ func (m *Manager) getSessionByID(sessionID string, revoked bool) (*SessionDTO.Fu
 	cond := util.Ternary(revoked, "IS NOT", "IS")
 
 	selectQuery := query.New(
-		`SELECT id, user_id, user_agent, ip_address, device_id, device_type, os, os_version, browser, browser_version, created_at, last_used_at, expires_at, revoked_at FROM "user_session" WHERE id = $1 AND revoked_at `+cond+" NULL;",
+		selectSessionSQL+` WHERE id = $1 AND revoked_at `+cond+" NULL;",
 		sessionID,
 	)
 
@@ -41,7 +41,7 @@ func (m *Manager) getSessionByID(sessionID string, revoked bool) (*SessionDTO.Fu
 }
 
 func (m *Manager) GetSessionByID(act *ActionDTO.UserTargeted, sessionID string) (*SessionDTO.Full, *Error.Status) {
-	if err := authz.User.GetUserSession(
+	if err := authz.User.For(act).GetUserSession(
 		act.TargetUID == act.RequesterUID,
 		act.RequesterRoles,
 	); err != nil {
@@ -51,8 +51,12 @@ func (m *Manager) GetSessionByID(act *ActionDTO.UserTargeted, sessionID string)
 	return m.getSessionByID(sessionID, false)
 }
 
+func (m *Manager) GetActiveSessionByID(sessionID string) (*SessionDTO.Full, *Error.Status) {
+	return m.getSessionByID(sessionID, false)
+}
+
 func (m *Manager) GetRevokedSessionByID(act *ActionDTO.UserTargeted, sessionID string) (*SessionDTO.Full, *Error.Status) {
-	if err := authz.User.GetUserSession(
+	if err := authz.User.For(act).GetUserSession(
 		act.TargetUID == act.RequesterUID,
 		act.RequesterRoles,
 	); err != nil {
@@ -66,7 +70,7 @@ func (m *Manager) getUserSessions(UID string) ([]*SessionDTO.Full, *Error.Status
 	dblog.Logger.Trace("Getting all sessions of user "+UID+"...", nil)
 
 	selectQuery := query.New(
-		`SELECT id, user_id, user_agent, ip_address, device_id, device_type, os, os_version, browser, browser_version, created_at, last_used_at, expires_at, revoked_at FROM "user_session" WHERE user_id = $1 AND revoked_at IS NULL;`,
+		selectSessionSQL+` WHERE user_id = $1 AND revoked_at IS NULL;`,
 		UID,
 	)
 
@@ -80,8 +84,27 @@ func (m *Manager) getUserSessions(UID string) ([]*SessionDTO.Full, *Error.Status
 	return sessions, nil
 }
 
+func (m *Manager) GetActiveUserSessions(UID string) ([]*SessionDTO.Full, *Error.Status) {
+	dblog.Logger.Trace("Getting active sessions of user "+UID+"...", nil)
+
+	selectQuery := query.New(
+		selectSessionSQL+` WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW() ORDER BY last_used_at ASC;`,
+		UID,
+	)
+
+	// Primary is used cuz sessions are requested on login, right before creation of the new one
+	sessions, err := executor.CollectFullSessionDTO(connection.Primary, selectQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Trace("Getting active sessions of user "+UID+": OK", nil)
+
+	return sessions, nil
+}
+
 func (m *Manager) GetUserSessions(act *ActionDTO.UserTargeted) ([]*SessionDTO.Public, *Error.Status) {
-	if err := authz.User.GetUserSession(
+	if err := authz.User.For(act).GetUserSession(
 		act.TargetUID == act.RequesterUID,
 		act.RequesterRoles,
 	); err != nil {
@@ -107,7 +130,7 @@ func (m *Manager) GetSessionByDeviceAndUserID(deviceID string, UID string) (*Ses
 	dblog.Logger.Trace("Getting session with "+deviceID+" device and "+UID+" user...", nil)
 
 	selectQuery := query.New(
-		`SELECT id, user_id, user_agent, ip_address, device_id, device_type, os, os_version, browser, browser_version, created_at, last_used_at, expires_at, revoked_at FROM "user_session" WHERE device_id = $1 AND user_id = $2 AND revoked_at IS NULL;`,
+		selectSessionSQL+` WHERE device_id = $1 AND user_id = $2 AND revoked_at IS NULL;`,
 		deviceID,
 		UID,
 	)
@@ -1,5 +1,7 @@
 package sessiontable
 
+const selectSessionSQL = `SELECT id, user_id, user_agent, ip_address, device_id, device_type, os, os_version, browser, browser_version, created_at, last_used_at, expires_at, revoked_at, COALESCE(name, ''), trusted FROM "user_session"`
+
 type Manager struct {
 	//
 }
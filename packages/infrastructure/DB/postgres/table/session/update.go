@@ -2,10 +2,13 @@ package sessiontable
 
 import (
 	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/util"
 	actiondto "sentinel/packages/core/action/DTO"
 	SessionDTO "sentinel/packages/core/session/DTO"
 	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
 	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
 	"sentinel/packages/infrastructure/DB/postgres/query"
 	"sentinel/packages/infrastructure/cache"
 )
@@ -51,3 +54,71 @@ func (m *Manager) UpdateSession(act *actiondto.Basic, sessionID string, newSessi
 
 	return nil
 }
+
+// Updates name and/or trusted flag of the session owned by the target user.
+// Nil values are left unchanged.
+func (m *Manager) UpdateSessionLabel(act *actiondto.UserTargeted, sessionID string, name *string, trusted *bool) *Error.Status {
+	dblog.Logger.Trace("Updating label of session "+sessionID+"...", nil)
+
+	session, err := m.getSessionByID(sessionID, false)
+	if err != nil {
+		return err
+	}
+
+	// Session of another user must look like non-existing one
+	if session.UserID != act.TargetUID || act.RequesterUID != act.TargetUID {
+		dblog.Logger.Error("Failed to update label of session "+sessionID, "Session doesn't belong to user "+act.RequesterUID, nil)
+		return Error.StatusNotFound
+	}
+
+	updated := *session
+	if name != nil {
+		updated.Name = *name
+	}
+	if trusted != nil {
+		updated.Trusted = *trusted
+	}
+
+	updateQuery := query.New(
+		`UPDATE "user_session" SET name = $1, trusted = $2 WHERE id = $3 AND revoked_at IS NULL;`,
+		util.Ternary[any](updated.Name == "", nil, updated.Name),
+		updated.Trusted,
+		sessionID,
+	)
+
+	audit := newAuditDTO(audit.UpdatedOperation, &act.Basic, &updated)
+
+	if err := execTxWithAudit(&audit, updateQuery); err != nil {
+		return err
+	}
+
+	cache.Client.Delete(
+		cache.KeyBase[cache.SessionByID]+sessionID,
+		cache.KeyBase[cache.UserBySessionID]+sessionID,
+	)
+
+	dblog.Logger.Trace("Updating label of session "+sessionID+": OK", nil)
+
+	return nil
+}
+
+// Links session to the device from which it was created or refreshed.
+// Works without authorization.
+func (m *Manager) BindSessionToDevice(sessionID string, deviceID string) *Error.Status {
+	dblog.Logger.Trace("Binding session "+sessionID+" to device "+deviceID+"...", nil)
+
+	updateQuery := query.New(
+		`UPDATE "user_session" SET user_device_id = $1 WHERE id = $2 AND revoked_at IS NULL;`,
+		deviceID,
+		sessionID,
+	)
+
+	if err := executor.Exec(connection.Primary, updateQuery); err != nil {
+		dblog.Logger.Error("Failed to bind session "+sessionID+" to device "+deviceID, err.Error(), nil)
+		return err
+	}
+
+	dblog.Logger.Trace("Binding session "+sessionID+" to device "+deviceID+": OK", nil)
+
+	return nil
+}
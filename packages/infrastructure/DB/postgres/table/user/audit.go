@@ -20,6 +20,8 @@ func newAuditDTO(op audit.Operation, act *ActionDTO.UserTargeted, user *UserDTO.
 		ChangedAt:       time.Now(),
 		Reason:          act.Reason,
 		Basic:           &user.Basic,
+
+		ImpersonatedByUserID: act.ImpersonatorUID,
 	}
 }
 
@@ -32,11 +34,17 @@ func newAuditQuery(dto *UserDTO.Audit) *query.Query {
 		reason = nil
 	}
 
+	var impersonatedBy any = dto.ImpersonatedByUserID
+
+	if dto.ImpersonatedByUserID == "" {
+		impersonatedBy = nil
+	}
+
 	return query.New(
 		`INSERT INTO "audit_user"
-        (changed_user_id, changed_by_user_id, operation, login, password, roles, deleted_at, changed_at, version, reason)
+        (changed_user_id, changed_by_user_id, operation, login, password, roles, deleted_at, changed_at, version, reason, impersonated_by_user_id)
         VALUES
-        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
 		dto.ChangedUserID,
 		dto.ChangedByUserID,
 		dto.Operation,
@@ -47,6 +55,7 @@ func newAuditQuery(dto *UserDTO.Audit) *query.Query {
 		dto.ChangedAt,
 		dto.Version,
 		reason,
+		impersonatedBy,
 	)
 }
 
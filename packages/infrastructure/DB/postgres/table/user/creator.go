@@ -37,7 +37,7 @@ func (m *Manager) Create(login string, password string) (string, *Error.Status)
 	insertQuery := query.New(
 		`INSERT INTO "user" (id, login, password, roles) VALUES
         ($1, $2, $3, $4);`,
-		uid, login, hashedPassword, rbac.GetRolesNames(authz.Host.DefaultRoles),
+		uid, login, hashedPassword, rbac.GetRolesNames(authz.GetHost().DefaultRoles),
 	)
 
 	if err := executor.Exec(connection.Primary, insertQuery); err != nil {
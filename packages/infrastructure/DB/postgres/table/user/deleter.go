@@ -16,6 +16,7 @@ import (
 	"sentinel/packages/infrastructure/DB/postgres/transaction"
 	"sentinel/packages/infrastructure/auth/authz"
 	"sentinel/packages/infrastructure/cache"
+	"sentinel/packages/infrastructure/revocation"
 	"slices"
 	"strings"
 	"time"
@@ -29,7 +30,7 @@ func (m *Manager) SoftDelete(act *ActionDTO.UserTargeted) *Error.Status {
 		return err
 	}
 
-	if err := authz.User.SoftDeleteUser(
+	if err := authz.User.For(act).SoftDeleteUser(
 		act.RequesterUID == act.TargetUID,
 		act.RequesterRoles,
 	); err != nil {
@@ -76,7 +77,7 @@ func (m *Manager) Restore(act *ActionDTO.UserTargeted, newLogin string) *Error.S
 		return err
 	}
 
-	if err := authz.User.RestoreUser(act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).RestoreUser(act.RequesterRoles); err != nil {
 		return err
 	}
 
@@ -218,6 +219,7 @@ func (m *Manager) bulkStateUpdate(newState user.State, act *ActionDTO.Basic, UID
 	for i, deletedUser := range deletedUsers {
 		UIDs[i] = deletedUser.ID
 		logins[i] = deletedUser.Login
+		revocation.TryPublish(revocation.NewUserVersionChangedEvent(deletedUser.ID, deletedUser.Version+1))
 		if newState == user.DeletedState {
 			dblog.Logger.Trace("Revoking sessions of user "+deletedUser.ID+"...", nil)
 			// TODO find more optimal solution, cuz this one cause a lot of DB queries
@@ -239,7 +241,7 @@ func (m *Manager) BulkSoftDelete(act *ActionDTO.Basic, UIDs []string) *Error.Sta
 
 	dblog.Logger.Info("Bulk soft deleting users "+uidsStr+"...", nil)
 
-	if err := authz.User.SoftDeleteUser(false, act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).SoftDeleteUser(false, act.RequesterRoles); err != nil {
 		return err
 	}
 
@@ -257,7 +259,7 @@ func (m *Manager) BulkRestore(act *ActionDTO.Basic, UIDs []string) *Error.Status
 
 	dblog.Logger.Info("Bulk soft deleting users "+uidsStr+"...", nil)
 
-	if err := authz.User.RestoreUser(act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).RestoreUser(act.RequesterRoles); err != nil {
 		return err
 	}
 
@@ -279,7 +281,7 @@ func (m *Manager) Drop(act *ActionDTO.UserTargeted) *Error.Status {
 		return err
 	}
 
-	if err := authz.User.DropUser(act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).DropUser(act.RequesterRoles); err != nil {
 		return err
 	}
 
@@ -326,7 +328,7 @@ func (m *Manager) DropAllSoftDeleted(act *ActionDTO.Basic) *Error.Status {
 		return err
 	}
 
-	if err := authz.User.DropAllSoftDeletedUsers(act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).DropAllSoftDeletedUsers(act.RequesterRoles); err != nil {
 		return err
 	}
 
@@ -0,0 +1,564 @@
+package usertable
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/core/user"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+	"sentinel/packages/infrastructure/token"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pending requests aren't marked as expired in DB, so status is computed on select.
+// $1 - current time.
+const loginChangeRequestStatusSQL = `CASE WHEN status = '` + UserDTO.LoginChangeRequestPending + `' AND expires_at <= $1::timestamp THEN '` + UserDTO.LoginChangeRequestExpired + `' ELSE status END`
+
+const selectLoginChangeRequestSQL = `SELECT id, user_id, requested_by_user_id, old_login, new_login, ` + loginChangeRequestStatusSQL + `, COALESCE(reason, ''), created_at, expires_at, confirmed_at, cancellable_until, resolved_at FROM "login_change_request"`
+
+// Single statement, so status change and audit are atomic.
+// $1 - user ID, $2 - current time, $3 - ID of the user who made the change,
+// $4 - ID of the impersonator, $5 - audit operation, $6 - audit reason.
+const cancelPendingLoginChangeRequestsSQL = `WITH cancelled AS (
+    UPDATE "login_change_request" SET status = '` + UserDTO.LoginChangeRequestCancelled + `', resolved_at = $2::timestamp
+    WHERE user_id = $1 AND status = '` + UserDTO.LoginChangeRequestPending + `' AND expires_at > $2::timestamp
+    RETURNING id, user_id, old_login, new_login, status
+)
+INSERT INTO "audit_login_change_request" (changed_request_id, user_id, changed_by_user_id, impersonated_by_user_id, operation, old_login, new_login, status, changed_at, reason)
+SELECT id, user_id, $3::uuid, $4::uuid, $5::char(1), old_login, new_login, status, $2::timestamp, $6::text FROM cancelled;`
+
+// $1 - current time, $2 - user ID or login (see usages).
+const revertibleLoginChangeExistsSQL = `SELECT EXISTS (
+    SELECT 1 FROM "login_change_request"
+    WHERE status = '` + UserDTO.LoginChangeRequestConfirmed + `' AND cancellable_until > $1::timestamp AND `
+
+var loginChangeRequestIsResolved = Error.NewStatusError(
+	"Login change request is already resolved",
+	http.StatusConflict,
+)
+
+var loginChangeRequestHasExpired = Error.NewStatusError(
+	"Login change request has expired",
+	http.StatusConflict,
+)
+
+var loginChangeGracePeriodIsOver = Error.NewStatusError(
+	"Login change can't be reverted, since its grace period is over",
+	http.StatusConflict,
+)
+
+var loginChangeIsRevertible = Error.NewStatusError(
+	"Previous login change still can be reverted, new one can't be requested until its grace period is over",
+	http.StatusConflict,
+)
+
+var userLoginWasChanged = Error.NewStatusError(
+	"User login was changed since login change request creation",
+	http.StatusConflict,
+)
+
+var loginIsReserved = Error.NewStatusError(
+	"This login is reserved, since its recent change still can be reverted",
+	http.StatusConflict,
+)
+
+var invalidLoginChangeRequestStatus = Error.NewStatusError(
+	"Invalid login change request status",
+	http.StatusBadRequest,
+)
+
+func newLoginChangeRequestAuditQuery(op audit.Operation, act *ActionDTO.Basic, req *UserDTO.LoginChangeRequest) *query.Query {
+	var reason any = act.Reason
+
+	if act.Reason == "" {
+		reason = nil
+	}
+
+	var impersonatedBy any = act.ImpersonatorUID
+
+	if act.ImpersonatorUID == "" {
+		impersonatedBy = nil
+	}
+
+	return query.New(
+		`INSERT INTO "audit_login_change_request"
+        (changed_request_id, user_id, changed_by_user_id, impersonated_by_user_id, operation, old_login, new_login, status, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		req.ID,
+		req.UserID,
+		act.RequesterUID,
+		impersonatedBy,
+		string(op),
+		req.OldLogin,
+		req.NewLogin,
+		req.Status,
+		time.Now(),
+		reason,
+	)
+}
+
+func newCancelPendingLoginChangeRequestsQuery(act *ActionDTO.UserTargeted, reason string) *query.Query {
+	var impersonatedBy any = act.ImpersonatorUID
+
+	if act.ImpersonatorUID == "" {
+		impersonatedBy = nil
+	}
+
+	return query.New(
+		cancelPendingLoginChangeRequestsSQL,
+		act.TargetUID,
+		time.Now(),
+		act.RequesterUID,
+		impersonatedBy,
+		string(audit.CancelOperation),
+		reason,
+	)
+}
+
+// Updates request only if its status in DB is still equal to prevStatus.
+func newResolveLoginChangeRequestQuery(req *UserDTO.LoginChangeRequest, prevStatus string) *query.Query {
+	return query.New(
+		`UPDATE "login_change_request" SET status = $1, confirmed_at = $2, cancellable_until = $3, resolved_at = $4
+        WHERE id = $5 AND status = $6;`,
+		req.Status, req.ConfirmedAt, req.CancellableUntil, req.ResolvedAt, req.ID, prevStatus,
+	).RequireAffectedRows(loginChangeRequestIsResolved) // Request could be resolved concurrently
+}
+
+func newRevertibleLoginChangeExistsQuery(column string, value string) *query.Query {
+	return query.New(revertibleLoginChangeExistsSQL+column+` = $2);`, time.Now(), value)
+}
+
+func isRevertibleLoginChangeExists(column string, value string) (bool, *Error.Status) {
+	scan, err := executor.Row(connection.Primary, newRevertibleLoginChangeExistsQuery(column, value))
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+
+	if err := scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// Old login stays reserved until grace period of the confirmed login change is over,
+// otherwise it could be taken by another user and change couldn't be reverted.
+func isLoginReserved(login string) (bool, *Error.Status) {
+	return isRevertibleLoginChangeExists("old_login", login)
+}
+
+func getLoginChangeRequest(requestID string) (*UserDTO.LoginChangeRequest, *Error.Status) {
+	if err := validation.UUID(requestID); err != nil {
+		return nil, err.ToStatus(
+			"Login change request ID is not specified",
+			"Login change request ID has invalid format (UUID expected)",
+		)
+	}
+
+	return executor.LoginChangeRequestDTO(
+		connection.Primary,
+		query.New(selectLoginChangeRequestSQL+` WHERE id = $2;`, time.Now(), requestID),
+	)
+}
+
+// Parses login change token and checks that it has the specified type
+func parseLoginChangeToken(tk string, tokenType string) (*token.Claims, *Error.Status) {
+	t, err := token.ParseSingedToken(tk, config.Secret.LoginChangeTokenPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Header["typ"] != tokenType {
+		dblog.Logger.Error("Failed to parse login change token", "Unexpected token type", nil)
+		return nil, token.InvalidToken
+	}
+
+	return t.Claims.(*token.Claims), nil
+}
+
+// Parses login change token of the specified type and returns request which it belongs to
+func getLoginChangeRequestByToken(tk string, tokenType string) (*UserDTO.LoginChangeRequest, *Error.Status) {
+	claims, err := parseLoginChangeToken(tk, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := getLoginChangeRequest(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Should be impossible, since token is signed, but additional check won't be redundant
+	if req.UserID != claims.Subject {
+		dblog.Logger.Error("Failed to get login change request", "Token subject doesn't match request user", nil)
+		return nil, token.InvalidToken
+	}
+
+	return req, nil
+}
+
+// Returns error if request can't be confirmed
+func checkLoginChangeConfirmable(req *UserDTO.LoginChangeRequest) *Error.Status {
+	if req.Status == UserDTO.LoginChangeRequestExpired {
+		return loginChangeRequestHasExpired
+	}
+	if !req.IsPending() {
+		return loginChangeRequestIsResolved
+	}
+	return nil
+}
+
+type loginChangeCancellation uint8
+
+const (
+	// Pending request is just marked as cancelled
+	pendingLoginChangeCancellation loginChangeCancellation = iota
+	// Confirmed change is reverted, so old login of the user is restored
+	revertLoginChangeCancellation
+)
+
+// Determines how request must be cancelled, returns error if it can't be cancelled
+func getLoginChangeCancellation(req *UserDTO.LoginChangeRequest) (loginChangeCancellation, *Error.Status) {
+	switch {
+	case req.IsPending():
+		return pendingLoginChangeCancellation, nil
+	case req.IsRevertible():
+		return revertLoginChangeCancellation, nil
+	case req.Status == UserDTO.LoginChangeRequestExpired:
+		return 0, loginChangeRequestHasExpired
+	case req.Status == UserDTO.LoginChangeRequestConfirmed:
+		return 0, loginChangeGracePeriodIsOver
+	default:
+		return 0, loginChangeRequestIsResolved
+	}
+}
+
+func (m *Manager) RequestLoginChange(
+	act *ActionDTO.UserTargeted,
+	newLogin string,
+	notify func(req *UserDTO.LoginChangeRequest) *Error.Status,
+) (*UserDTO.LoginChangeRequest, *Error.Status) {
+	dblog.Logger.Info("Creating login change request for user "+act.TargetUID+"...", nil)
+
+	if err := act.ValidateUIDs(); err != nil {
+		dblog.Logger.Error("Failed to create login change request for user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	if err := user.ValidateLogin(newLogin); err != nil {
+		dblog.Logger.Error("Failed to create login change request for user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	if err := authz.User.For(act).ChangeUserLogin(
+		act.RequesterUID == act.TargetUID,
+		act.RequesterRoles,
+	); err != nil {
+		return nil, err
+	}
+
+	targetUser, err := m.GetUserByID(act.TargetUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetUser.Login == newLogin {
+		errMsg := "Login not changed: Current login and new login are the same"
+		dblog.Logger.Error("Failed to create login change request for user "+act.TargetUID, errMsg, nil)
+		return nil, Error.NewStatusError(errMsg, http.StatusConflict)
+	}
+
+	if err := m.checkLoginAvailability(newLogin); err != nil {
+		return nil, err
+	}
+
+	// Otherwise link for reverting of the previous change would revert this one as well
+	revertible, err := isRevertibleLoginChangeExists("user_id", act.TargetUID)
+	if err != nil {
+		dblog.Logger.Error("Failed to create login change request for user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+	if revertible {
+		dblog.Logger.Error("Failed to create login change request for user "+act.TargetUID, loginChangeIsRevertible.Error(), nil)
+		return nil, loginChangeIsRevertible
+	}
+
+	now := time.Now()
+
+	req := &UserDTO.LoginChangeRequest{
+		ID:                uuid.NewString(),
+		UserID:            act.TargetUID,
+		RequestedByUserID: act.RequesterUID,
+		OldLogin:          targetUser.Login,
+		NewLogin:          newLogin,
+		Status:            UserDTO.LoginChangeRequestPending,
+		Reason:            act.Reason,
+		CreatedAt:         now,
+		ExpiresAt:         now.Add(config.App.LoginChangeTokenTTL()),
+	}
+
+	var reason any = req.Reason
+
+	if req.Reason == "" {
+		reason = nil
+	}
+
+	// Without notification request can be neither confirmed nor cancelled, so it mustn't be created.
+	// If transaction fails after this, links from notification just won't work, since request doesn't exist.
+	if err := notify(req); err != nil {
+		dblog.Logger.Error("Failed to create login change request for user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	err = transaction.New(
+		// Only the latest request can be confirmed
+		newCancelPendingLoginChangeRequestsQuery(act, "Superseded by login change request "+req.ID),
+		query.New(
+			`INSERT INTO "login_change_request" (id, user_id, requested_by_user_id, old_login, new_login, status, reason, created_at, expires_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+			req.ID,
+			req.UserID,
+			req.RequestedByUserID,
+			req.OldLogin,
+			req.NewLogin,
+			req.Status,
+			reason,
+			req.CreatedAt,
+			req.ExpiresAt,
+		),
+		newLoginChangeRequestAuditQuery(audit.CreateOperation, &act.Basic, req),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to create login change request for user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Creating login change request for user "+act.TargetUID+": OK", nil)
+
+	return req, nil
+}
+
+func (m *Manager) GetUserLoginChangeRequests(act *ActionDTO.UserTargeted) ([]*UserDTO.LoginChangeRequest, *Error.Status) {
+	dblog.Logger.Info("Getting login change requests of user "+act.TargetUID+"...", nil)
+
+	if err := act.ValidateTargetUID(); err != nil {
+		dblog.Logger.Error("Failed to get login change requests of user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetLoginChangeRequests(
+		act.RequesterUID == act.TargetUID,
+		act.RequesterRoles,
+	); err != nil {
+		return nil, err
+	}
+
+	requests, err := executor.CollectLoginChangeRequestDTO(
+		connection.Replica,
+		query.New(selectLoginChangeRequestSQL+` WHERE user_id = $2 ORDER BY created_at DESC;`, time.Now(), act.TargetUID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting login change requests of user "+act.TargetUID+": OK", nil)
+
+	return requests, nil
+}
+
+func (m *Manager) GetLoginChangeRequests(act *ActionDTO.Basic, status string) ([]*UserDTO.LoginChangeRequest, *Error.Status) {
+	dblog.Logger.Info("Getting login change requests...", nil)
+
+	if status != "" && !slices.Contains(UserDTO.LoginChangeRequestStatuses, status) {
+		dblog.Logger.Error("Failed to get login change requests", invalidLoginChangeRequestStatus.Error(), nil)
+		return nil, invalidLoginChangeRequestStatus
+	}
+
+	if err := authz.User.For(act).GetLoginChangeRequests(false, act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	var q *query.Query
+	if status == "" {
+		q = query.New(selectLoginChangeRequestSQL+` ORDER BY created_at DESC;`, time.Now())
+	} else {
+		q = query.New(
+			selectLoginChangeRequestSQL+` WHERE `+loginChangeRequestStatusSQL+` = $2 ORDER BY created_at DESC;`,
+			time.Now(), status,
+		)
+	}
+
+	requests, err := executor.CollectLoginChangeRequestDTO(connection.Replica, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting login change requests: OK", nil)
+
+	return requests, nil
+}
+
+func (m *Manager) ConfirmLoginChange(tk string) (*UserDTO.LoginChangeRequest, *Error.Status) {
+	dblog.Logger.Info("Confirming login change...", nil)
+
+	req, err := getLoginChangeRequestByToken(tk, token.LoginChangeConfirmationTokenType)
+	if err != nil {
+		dblog.Logger.Error("Failed to confirm login change", err.Error(), nil)
+		return nil, err
+	}
+
+	if err := checkLoginChangeConfirmable(req); err != nil {
+		dblog.Logger.Error("Failed to confirm login change request "+req.ID, err.Error(), nil)
+		return nil, err
+	}
+
+	targetUser, err := m.GetUserByID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetUser.Login != req.OldLogin {
+		dblog.Logger.Error("Failed to confirm login change request "+req.ID, userLoginWasChanged.Error(), nil)
+		return nil, userLoginWasChanged
+	}
+
+	// New login could be taken since request creation
+	if err := m.checkLoginAvailability(req.NewLogin); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cancellableUntil := now.Add(config.App.LoginChangeGracePeriod())
+
+	req.Status = UserDTO.LoginChangeRequestConfirmed
+	req.ConfirmedAt = &now
+	req.CancellableUntil = &cancellableUntil
+
+	// Confirmation is made by the owner of the new login, who is considered to be the user itself
+	act := ActionDTO.NewUserTargeted(targetUser.ID, targetUser.ID, targetUser.Roles)
+	act.Reason = req.Reason
+
+	queries := []*query.Query{
+		newResolveLoginChangeRequestQuery(req, UserDTO.LoginChangeRequestPending),
+		newLoginChangeRequestAuditQuery(audit.ApproveOperation, &act.Basic, req),
+	}
+
+	if err := setLogin(act, targetUser, req.NewLogin, queries...); err != nil {
+		dblog.Logger.Error("Failed to confirm login change request "+req.ID, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Confirming login change: OK", nil)
+
+	return req, nil
+}
+
+func (m *Manager) CancelLoginChange(tk string) (*UserDTO.LoginChangeRequest, *Error.Status) {
+	dblog.Logger.Info("Cancelling login change...", nil)
+
+	req, err := getLoginChangeRequestByToken(tk, token.LoginChangeCancellationTokenType)
+	if err != nil {
+		dblog.Logger.Error("Failed to cancel login change", err.Error(), nil)
+		return nil, err
+	}
+
+	cancellation, err := getLoginChangeCancellation(req)
+	if err != nil {
+		dblog.Logger.Error("Failed to cancel login change request "+req.ID, err.Error(), nil)
+		return nil, err
+	}
+
+	if cancellation == revertLoginChangeCancellation {
+		err = m.revertLoginChange(req)
+	} else {
+		err = cancelLoginChangeRequest(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Cancelling login change: OK", nil)
+
+	return req, nil
+}
+
+func cancelLoginChangeRequest(req *UserDTO.LoginChangeRequest) *Error.Status {
+	now := time.Now()
+
+	req.Status = UserDTO.LoginChangeRequestCancelled
+	req.ResolvedAt = &now
+
+	// Cancellation is made by the owner of the old login, who is considered to be the user itself
+	act := ActionDTO.Basic{RequesterUID: req.UserID}
+
+	err := transaction.New(
+		newResolveLoginChangeRequestQuery(req, UserDTO.LoginChangeRequestPending),
+		newLoginChangeRequestAuditQuery(audit.CancelOperation, &act, req),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to cancel login change request "+req.ID, err.Error(), nil)
+		return err
+	}
+
+	return nil
+}
+
+// Restores old login of the user and revokes all its sessions,
+// since confirmed change most likely was made from the hijacked session.
+func (m *Manager) revertLoginChange(req *UserDTO.LoginChangeRequest) *Error.Status {
+	dblog.Logger.Info("Reverting login change request "+req.ID+"...", nil)
+
+	targetUser, err := m.GetUserByID(req.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Should be impossible, since new login change can't be requested during grace period
+	if targetUser.Login != req.NewLogin {
+		dblog.Logger.Error("Failed to revert login change request "+req.ID, userLoginWasChanged.Error(), nil)
+		return userLoginWasChanged
+	}
+
+	now := time.Now()
+
+	req.Status = UserDTO.LoginChangeRequestReverted
+	req.ResolvedAt = &now
+
+	act := ActionDTO.NewUserTargeted(targetUser.ID, targetUser.ID, targetUser.Roles)
+	act.Reason = "Login change was reverted via cancellation link"
+
+	queries := []*query.Query{
+		newResolveLoginChangeRequestQuery(req, UserDTO.LoginChangeRequestConfirmed),
+		newLoginChangeRequestAuditQuery(audit.CancelOperation, &act.Basic, req),
+		newCancelPendingLoginChangeRequestsQuery(act, act.Reason),
+	}
+
+	// Availability of the old login isn't checked, since it's reserved until the end of grace period
+	if err := setLogin(act, targetUser, req.OldLogin, queries...); err != nil {
+		dblog.Logger.Error("Failed to revert login change request "+req.ID, err.Error(), nil)
+		return err
+	}
+
+	if err := m.session.RevokeAllUserSessions(act); err != nil && err != Error.StatusNotFound {
+		dblog.Logger.Error("Failed to revoke sessions of user "+targetUser.ID, err.Error(), nil)
+	}
+
+	dblog.Logger.Info("Reverting login change request "+req.ID+": OK", nil)
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package usertable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"sentinel/packages/common/config"
+	"sentinel/packages/common/config/configtest"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/token"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUID       = "d529a8d2-1eb4-4bce-82aa-e62095dbc653"
+	testRequestID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+)
+
+func TestMain(m *testing.M) {
+	configtest.Init()
+
+	config.App.ServiceID = "sentinel"
+	config.Auth.SelfAudience = "sentinel"
+	config.Auth.TokenAudience = []string{"sentinel"}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	config.Secret.LoginChangeTokenPrivateKey = privateKey
+	config.Secret.LoginChangeTokenPublicKey = privateKey.Public().(ed25519.PublicKey)
+
+	token.Init()
+
+	os.Exit(m.Run())
+}
+
+func newTestLoginChangeRequest(status string) *UserDTO.LoginChangeRequest {
+	now := time.Now()
+
+	return &UserDTO.LoginChangeRequest{
+		ID:        testRequestID,
+		UserID:    testUID,
+		OldLogin:  "old@example.com",
+		NewLogin:  "new@example.com",
+		Status:    status,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+}
+
+func newConfirmedLoginChangeRequest(cancellableUntil time.Time) *UserDTO.LoginChangeRequest {
+	req := newTestLoginChangeRequest(UserDTO.LoginChangeRequestConfirmed)
+	confirmedAt := time.Now()
+	req.ConfirmedAt = &confirmedAt
+	req.CancellableUntil = &cancellableUntil
+	return req
+}
+
+func TestCheckLoginChangeConfirmable(t *testing.T) {
+	t.Run("pending request", func(t *testing.T) {
+		assert.Nil(t, checkLoginChangeConfirmable(newTestLoginChangeRequest(UserDTO.LoginChangeRequestPending)))
+	})
+
+	t.Run("expired request", func(t *testing.T) {
+		err := checkLoginChangeConfirmable(newTestLoginChangeRequest(UserDTO.LoginChangeRequestExpired))
+		assert.Equal(t, loginChangeRequestHasExpired, err)
+	})
+
+	for _, status := range []string{
+		UserDTO.LoginChangeRequestConfirmed,
+		UserDTO.LoginChangeRequestCancelled,
+		UserDTO.LoginChangeRequestReverted,
+	} {
+		t.Run(status+" request", func(t *testing.T) {
+			err := checkLoginChangeConfirmable(newTestLoginChangeRequest(status))
+			assert.Equal(t, loginChangeRequestIsResolved, err)
+		})
+	}
+}
+
+func TestGetLoginChangeCancellation(t *testing.T) {
+	t.Run("pending request is cancelled", func(t *testing.T) {
+		cancellation, err := getLoginChangeCancellation(newTestLoginChangeRequest(UserDTO.LoginChangeRequestPending))
+		assert.Nil(t, err)
+		assert.Equal(t, pendingLoginChangeCancellation, cancellation)
+	})
+
+	t.Run("confirmed request is reverted during grace period", func(t *testing.T) {
+		cancellation, err := getLoginChangeCancellation(newConfirmedLoginChangeRequest(time.Now().Add(time.Hour)))
+		assert.Nil(t, err)
+		assert.Equal(t, revertLoginChangeCancellation, cancellation)
+	})
+
+	t.Run("confirmed request can't be reverted after grace period", func(t *testing.T) {
+		_, err := getLoginChangeCancellation(newConfirmedLoginChangeRequest(time.Now().Add(-time.Second)))
+		assert.Equal(t, loginChangeGracePeriodIsOver, err)
+	})
+
+	t.Run("confirmed request without grace period can't be reverted", func(t *testing.T) {
+		_, err := getLoginChangeCancellation(newTestLoginChangeRequest(UserDTO.LoginChangeRequestConfirmed))
+		assert.Equal(t, loginChangeGracePeriodIsOver, err)
+	})
+
+	t.Run("expired request", func(t *testing.T) {
+		_, err := getLoginChangeCancellation(newTestLoginChangeRequest(UserDTO.LoginChangeRequestExpired))
+		assert.Equal(t, loginChangeRequestHasExpired, err)
+	})
+
+	for _, status := range []string{UserDTO.LoginChangeRequestCancelled, UserDTO.LoginChangeRequestReverted} {
+		t.Run(status+" request", func(t *testing.T) {
+			_, err := getLoginChangeCancellation(newTestLoginChangeRequest(status))
+			assert.Equal(t, loginChangeRequestIsResolved, err)
+		})
+	}
+}
+
+func TestParseLoginChangeToken(t *testing.T) {
+	newToken := func(tokenType string) string {
+		tk, err := token.NewLoginChangeToken(testUID, "new@example.com", testRequestID, tokenType, time.Hour)
+		require.Nil(t, err)
+		return tk.String()
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		claims, err := parseLoginChangeToken(
+			newToken(token.LoginChangeConfirmationTokenType),
+			token.LoginChangeConfirmationTokenType,
+		)
+		require.Nil(t, err)
+		assert.Equal(t, testRequestID, claims.ID)
+		assert.Equal(t, testUID, claims.Subject)
+	})
+
+	t.Run("cancellation token can't be used for confirmation", func(t *testing.T) {
+		_, err := parseLoginChangeToken(
+			newToken(token.LoginChangeCancellationTokenType),
+			token.LoginChangeConfirmationTokenType,
+		)
+		assert.Equal(t, token.InvalidToken, err)
+	})
+
+	t.Run("confirmation token can't be used for cancellation", func(t *testing.T) {
+		_, err := parseLoginChangeToken(
+			newToken(token.LoginChangeConfirmationTokenType),
+			token.LoginChangeCancellationTokenType,
+		)
+		assert.Equal(t, token.InvalidToken, err)
+	})
+
+	t.Run("token signed with other key", func(t *testing.T) {
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		originalKey := config.Secret.LoginChangeTokenPrivateKey
+		config.Secret.LoginChangeTokenPrivateKey = otherKey
+		tk := newToken(token.LoginChangeConfirmationTokenType)
+		config.Secret.LoginChangeTokenPrivateKey = originalKey
+
+		_, e := parseLoginChangeToken(tk, token.LoginChangeConfirmationTokenType)
+		assert.Equal(t, token.TokenInvalidSignature, e)
+	})
+}
+
+func TestResolveLoginChangeRequestQuery(t *testing.T) {
+	req := newConfirmedLoginChangeRequest(time.Now().Add(time.Hour))
+
+	q := newResolveLoginChangeRequestQuery(req, UserDTO.LoginChangeRequestPending)
+
+	assert.Equal(t, UserDTO.LoginChangeRequestConfirmed, q.Args[0])
+	assert.Equal(t, req.ID, q.Args[4])
+	assert.Equal(t, UserDTO.LoginChangeRequestPending, q.Args[5])
+
+	t.Run("request resolved concurrently", func(t *testing.T) {
+		assert.Equal(t, loginChangeRequestIsResolved, q.CheckAffectedRows(0))
+	})
+
+	t.Run("request resolved", func(t *testing.T) {
+		assert.Nil(t, q.CheckAffectedRows(1))
+	})
+}
+
+func TestLoginReservationQuery(t *testing.T) {
+	q := newRevertibleLoginChangeExistsQuery("old_login", "old@example.com")
+
+	// Login is reserved only by confirmed changes which still can be reverted
+	assert.True(t, strings.Contains(q.SQL, "status = '"+UserDTO.LoginChangeRequestConfirmed+"'"))
+	assert.True(t, strings.Contains(q.SQL, "cancellable_until > $1::timestamp"))
+	assert.True(t, strings.HasSuffix(q.SQL, "old_login = $2);"))
+	require.Len(t, q.Args, 2)
+	assert.Equal(t, "old@example.com", q.Args[1])
+}
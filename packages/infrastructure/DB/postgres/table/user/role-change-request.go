@@ -0,0 +1,357 @@
+package usertable
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const selectRoleChangeRequestSQL = `SELECT id, user_id, requested_by_user_id, roles, old_roles, status, COALESCE(reason, ''), created_at, expires_at, COALESCE(resolved_by_user_id::text, ''), resolved_at FROM "role_change_request"`
+
+// Single statement, so status change and audit are atomic.
+// $1 - current time, $2 - audit operation, $3 - audit reason.
+const expireRoleChangeRequestsSQL = `WITH expired AS (
+    UPDATE "role_change_request" SET status = '` + UserDTO.RoleChangeRequestExpired + `', resolved_at = $1::timestamp
+    WHERE status = '` + UserDTO.RoleChangeRequestPending + `' AND expires_at <= $1::timestamp
+    RETURNING id, user_id, roles, status
+), audited AS (
+    INSERT INTO "audit_role_change_request" (changed_request_id, user_id, operation, roles, status, changed_at, reason)
+    SELECT id, user_id, $2::char(1), roles, status, $1::timestamp, $3::text FROM expired
+)
+SELECT COUNT(*) FROM expired;`
+
+var roleChangeRequestIsResolved = Error.NewStatusError(
+	"Role change request is already resolved",
+	http.StatusConflict,
+)
+
+var roleChangeRequestHasExpired = Error.NewStatusError(
+	"Role change request has expired",
+	http.StatusConflict,
+)
+
+var roleChangeRequestSelfApproval = Error.NewStatusError(
+	"Role change request can't be approved by its author",
+	http.StatusForbidden,
+)
+
+var roleChangeRequestTargetApproval = Error.NewStatusError(
+	"Role change request can't be approved by the user whose roles it changes",
+	http.StatusForbidden,
+)
+
+var userRolesWereChanged = Error.NewStatusError(
+	"User roles were changed since role change request creation",
+	http.StatusConflict,
+)
+
+var invalidRoleChangeRequestStatus = Error.NewStatusError(
+	"Invalid role change request status",
+	http.StatusBadRequest,
+)
+
+func newRoleChangeRequestAuditQuery(op audit.Operation, act *ActionDTO.Basic, req *UserDTO.RoleChangeRequest) *query.Query {
+	var reason any = act.Reason
+
+	if act.Reason == "" {
+		reason = nil
+	}
+
+	var impersonatedBy any = act.ImpersonatorUID
+
+	if act.ImpersonatorUID == "" {
+		impersonatedBy = nil
+	}
+
+	return query.New(
+		`INSERT INTO "audit_role_change_request"
+        (changed_request_id, user_id, changed_by_user_id, impersonated_by_user_id, operation, roles, status, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		req.ID,
+		req.UserID,
+		act.RequesterUID,
+		impersonatedBy,
+		string(op),
+		req.Roles,
+		req.Status,
+		time.Now(),
+		reason,
+	)
+}
+
+func newResolveRoleChangeRequestQuery(req *UserDTO.RoleChangeRequest) *query.Query {
+	return query.New(
+		`UPDATE "role_change_request" SET status = $1, resolved_by_user_id = $2, resolved_at = $3
+        WHERE id = $4 AND status = '`+UserDTO.RoleChangeRequestPending+`';`,
+		req.Status, req.ResolvedByUserID, req.ResolvedAt, req.ID,
+	).RequireAffectedRows(roleChangeRequestIsResolved) // Request could be resolved concurrently
+}
+
+// Returns privileged roles which are present in newRoles, but missing in oldRoles
+func addedPrivilegedRoles(oldRoles []string, newRoles []string) []string {
+	added := []string{}
+
+	for _, role := range newRoles {
+		if !slices.Contains(oldRoles, role) && slices.Contains(config.Authz.PrivilegedRoles, role) {
+			added = append(added, role)
+		}
+	}
+
+	return added
+}
+
+func getRoleChangeRequest(requestID string) (*UserDTO.RoleChangeRequest, *Error.Status) {
+	if err := validation.UUID(requestID); err != nil {
+		return nil, err.ToStatus(
+			"Role change request ID is not specified",
+			"Role change request ID has invalid format (UUID expected)",
+		)
+	}
+
+	return executor.RoleChangeRequestDTO(
+		connection.Primary,
+		query.New(selectRoleChangeRequestSQL+` WHERE id = $1;`, requestID),
+	)
+}
+
+func (m *Manager) RequestRolesChange(act *ActionDTO.UserTargeted, newRoles []string) (*UserDTO.RoleChangeRequest, *Error.Status) {
+	user, err := m.validateRolesChange(act, newRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addedPrivilegedRoles(user.Roles, newRoles)) == 0 {
+		dblog.Logger.Info("Changing roles of user "+act.TargetUID+"...", nil)
+
+		if err := setRoles(act, user, newRoles); err != nil {
+			return nil, err
+		}
+
+		dblog.Logger.Info("Changing roles of user "+act.TargetUID+": OK", nil)
+
+		return nil, nil
+	}
+
+	dblog.Logger.Info("Creating role change request for user "+act.TargetUID+"...", nil)
+
+	now := time.Now()
+
+	req := &UserDTO.RoleChangeRequest{
+		ID:                uuid.NewString(),
+		UserID:            act.TargetUID,
+		RequestedByUserID: act.RequesterUID,
+		Roles:             newRoles,
+		OldRoles:          user.Roles,
+		Status:            UserDTO.RoleChangeRequestPending,
+		Reason:            act.Reason,
+		CreatedAt:         now,
+		ExpiresAt:         now.Add(config.Authz.RoleChangeRequestTTL()),
+	}
+
+	var reason any = req.Reason
+
+	if req.Reason == "" {
+		reason = nil
+	}
+
+	err = transaction.New(
+		query.New(
+			`INSERT INTO "role_change_request" (id, user_id, requested_by_user_id, roles, old_roles, status, reason, created_at, expires_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+			req.ID,
+			req.UserID,
+			req.RequestedByUserID,
+			req.Roles,
+			req.OldRoles,
+			req.Status,
+			reason,
+			req.CreatedAt,
+			req.ExpiresAt,
+		),
+		newRoleChangeRequestAuditQuery(audit.CreateOperation, &act.Basic, req),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to create role change request for user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Creating role change request for user "+act.TargetUID+": OK", nil)
+
+	return req, nil
+}
+
+func (m *Manager) GetRoleChangeRequests(act *ActionDTO.Basic, status string) ([]*UserDTO.RoleChangeRequest, *Error.Status) {
+	dblog.Logger.Info("Getting role change requests...", nil)
+
+	if status != "" && !slices.Contains(UserDTO.RoleChangeRequestStatuses, status) {
+		dblog.Logger.Error("Failed to get role change requests", invalidRoleChangeRequestStatus.Error(), nil)
+		return nil, invalidRoleChangeRequestStatus
+	}
+
+	if err := authz.User.For(act).GetUserRoles(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	var q *query.Query
+	if status == "" {
+		q = query.New(selectRoleChangeRequestSQL + ` ORDER BY created_at DESC;`)
+	} else {
+		q = query.New(selectRoleChangeRequestSQL+` WHERE status = $1 ORDER BY created_at DESC;`, status)
+	}
+
+	requests, err := executor.CollectRoleChangeRequestDTO(connection.Replica, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting role change requests: OK", nil)
+
+	return requests, nil
+}
+
+func (m *Manager) ApproveRoleChangeRequest(act *ActionDTO.Basic, requestID string) (*UserDTO.RoleChangeRequest, *Error.Status) {
+	dblog.Logger.Info("Approving role change request "+requestID+"...", nil)
+
+	req, err := getRoleChangeRequest(requestID)
+	if err != nil {
+		dblog.Logger.Error("Failed to approve role change request "+requestID, err.Error(), nil)
+		return nil, err
+	}
+
+	if !req.IsPending() {
+		return nil, roleChangeRequestIsResolved
+	}
+	if !time.Now().Before(req.ExpiresAt) {
+		return nil, roleChangeRequestHasExpired
+	}
+	if act.RequesterUID == req.RequestedByUserID {
+		return nil, roleChangeRequestSelfApproval
+	}
+	if act.RequesterUID == req.UserID {
+		return nil, roleChangeRequestTargetApproval
+	}
+
+	targetedAct := act.ToUserTargeted(req.UserID)
+
+	// Roles and permissions must be checked again, since they could be changed since request creation
+	user, err := m.validateRolesChange(targetedAct, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	currentRoles, oldRoles := slices.Clone(user.Roles), slices.Clone(req.OldRoles)
+	slices.Sort(currentRoles)
+	slices.Sort(oldRoles)
+
+	if !slices.Equal(currentRoles, oldRoles) {
+		dblog.Logger.Error("Failed to approve role change request "+requestID, userRolesWereChanged.Error(), nil)
+		return nil, userRolesWereChanged
+	}
+
+	now := time.Now()
+
+	req.Status = UserDTO.RoleChangeRequestApproved
+	req.ResolvedByUserID = act.RequesterUID
+	req.ResolvedAt = &now
+
+	queries := []*query.Query{
+		newResolveRoleChangeRequestQuery(req),
+		newRoleChangeRequestAuditQuery(audit.ApproveOperation, act, req),
+	}
+
+	// Reason of the role change is the reason of the request, unless approver specified another one
+	if targetedAct.Reason == "" {
+		targetedAct.Reason = req.Reason
+	}
+
+	if err := setRoles(targetedAct, user, req.Roles, queries...); err != nil {
+		dblog.Logger.Error("Failed to approve role change request "+requestID, err.Error(), nil)
+		return nil, err
+	}
+
+	dblog.Logger.Info("Approving role change request "+requestID+": OK", nil)
+
+	return req, nil
+}
+
+func (m *Manager) CancelRoleChangeRequest(act *ActionDTO.Basic, requestID string) *Error.Status {
+	dblog.Logger.Info("Cancelling role change request "+requestID+"...", nil)
+
+	req, err := getRoleChangeRequest(requestID)
+	if err != nil {
+		dblog.Logger.Error("Failed to cancel role change request "+requestID, err.Error(), nil)
+		return err
+	}
+
+	if !req.IsPending() {
+		return roleChangeRequestIsResolved
+	}
+
+	if err := authz.User.For(act).ChangeUserRoles(
+		act.RequesterUID == req.UserID,
+		act.RequesterRoles,
+	); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	req.Status = UserDTO.RoleChangeRequestCancelled
+	req.ResolvedByUserID = act.RequesterUID
+	req.ResolvedAt = &now
+
+	err = transaction.New(
+		newResolveRoleChangeRequestQuery(req),
+		newRoleChangeRequestAuditQuery(audit.CancelOperation, act, req),
+	).Exec(connection.Primary)
+	if err != nil {
+		dblog.Logger.Error("Failed to cancel role change request "+requestID, err.Error(), nil)
+		return err
+	}
+
+	dblog.Logger.Info("Cancelling role change request "+requestID+": OK", nil)
+
+	return nil
+}
+
+func (_ *Manager) ExpireRoleChangeRequests() (int, *Error.Status) {
+	dblog.Logger.Trace("Expiring role change requests...", nil)
+
+	scan, err := executor.Row(connection.Primary, query.New(
+		expireRoleChangeRequestsSQL,
+		time.Now(),
+		string(audit.ExpireOperation),
+		"Request has expired",
+	))
+	if err != nil {
+		dblog.Logger.Error("Failed to expire role change requests", err.Error(), nil)
+		return 0, err
+	}
+
+	var count int64
+
+	if err := scan(&count); err != nil {
+		dblog.Logger.Error("Failed to expire role change requests", err.Error(), nil)
+		return 0, err
+	}
+
+	dblog.Logger.Trace("Expiring role change requests: OK (expired: "+strconv.FormatInt(count, 10)+")", nil)
+
+	return int(count), nil
+}
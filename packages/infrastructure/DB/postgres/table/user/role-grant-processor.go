@@ -0,0 +1,99 @@
+package usertable
+
+import (
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/cache"
+	"sentinel/packages/infrastructure/revocation"
+	"strconv"
+	"time"
+)
+
+// Both queries are single statements, so grants change, audit and version bump are atomic.
+// Several instances may process grants at the same time, but row locks guarantee
+// that each grant will be processed only once.
+// $1 - current time, $2 - audit operation, $3 - audit reason.
+
+const activateRoleGrantsSQL = `WITH processed AS (
+    UPDATE "user_role_grant" SET activated_at = $1::timestamp
+    WHERE activated_at IS NULL AND not_before <= $1::timestamp AND (expires_at IS NULL OR expires_at > $1::timestamp)
+    RETURNING id, user_id, role, not_before, expires_at
+), affected AS (
+    SELECT user_id FROM processed
+),` + processRoleGrantsSQLEnd
+
+// Version is bumped only if expired grant was active, otherwise it didn't affect user tokens
+const expireRoleGrantsSQL = `WITH processed AS (
+    DELETE FROM "user_role_grant" WHERE expires_at <= $1::timestamp
+    RETURNING id, user_id, role, not_before, expires_at, activated_at
+), affected AS (
+    SELECT user_id FROM processed WHERE activated_at IS NOT NULL
+),` + processRoleGrantsSQLEnd
+
+const processRoleGrantsSQLEnd = ` audited AS (
+    INSERT INTO "audit_user_role_grant" (changed_grant_id, user_id, operation, role, not_before, expires_at, changed_at, reason)
+    SELECT id, user_id, $2::char(1), role, not_before, expires_at, $1::timestamp, $3::text FROM processed
+), bumped AS (
+    UPDATE "user" SET version = version + 1
+    WHERE id IN (SELECT user_id FROM affected)
+    RETURNING id, login, version
+)
+SELECT
+    (SELECT COUNT(*) FROM processed),
+    COALESCE((SELECT array_agg(id::text) FROM bumped), '{}'),
+    COALESCE((SELECT array_agg(login) FROM bumped), '{}'),
+    COALESCE((SELECT array_agg(version::bigint) FROM bumped), '{}');`
+
+// Returns amount of processed grants
+func processRoleGrants(sql string, op audit.Operation, reason string) (int, *Error.Status) {
+	scan, err := executor.Row(connection.Primary, query.New(sql, time.Now(), string(op), reason))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	UIDs, logins, versions := []string{}, []string{}, []int64{}
+
+	if err := scan(&count, &UIDs, &logins, &versions); err != nil {
+		return 0, err
+	}
+
+	for i, uid := range UIDs {
+		revocation.TryPublish(revocation.NewUserVersionChangedEvent(uid, uint32(versions[i])))
+	}
+
+	if len(UIDs) != 0 {
+		if err := cache.BulkInvalidateBasicUserDTO(UIDs, logins); err != nil {
+			dblog.Logger.Error("Failed to invalidate cache", err.Error(), nil)
+		}
+	}
+
+	return int(count), nil
+}
+
+func (_ *Manager) ProcessRoleGrants() (activated int, expired int, err *Error.Status) {
+	dblog.Logger.Trace("Processing role grants...", nil)
+
+	activated, err = processRoleGrants(activateRoleGrantsSQL, audit.ActivateOperation, "Grant became active")
+	if err != nil {
+		dblog.Logger.Error("Failed to activate role grants", err.Error(), nil)
+		return 0, 0, err
+	}
+
+	expired, err = processRoleGrants(expireRoleGrantsSQL, audit.ExpireOperation, "Grant has expired")
+	if err != nil {
+		dblog.Logger.Error("Failed to expire role grants", err.Error(), nil)
+		return activated, 0, err
+	}
+
+	dblog.Logger.Trace(
+		"Processing role grants: OK (activated: "+strconv.Itoa(activated)+", expired: "+strconv.Itoa(expired)+")",
+		nil,
+	)
+
+	return activated, expired, nil
+}
@@ -0,0 +1,307 @@
+package usertable
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB/postgres/audit"
+	"sentinel/packages/infrastructure/DB/postgres/connection"
+	"sentinel/packages/infrastructure/DB/postgres/dblog"
+	"sentinel/packages/infrastructure/DB/postgres/executor"
+	"sentinel/packages/infrastructure/DB/postgres/query"
+	"sentinel/packages/infrastructure/DB/postgres/transaction"
+	"sentinel/packages/infrastructure/auth/authz"
+	"slices"
+	"time"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+	"github.com/google/uuid"
+)
+
+const selectRoleGrantSQL = `SELECT id, user_id, role, not_before, expires_at, activated_at, granted_by_user_id, created_at FROM "user_role_grant"`
+
+var roleGrantExpiresInPast = Error.NewStatusError(
+	"Grant expiration time must be in the future",
+	http.StatusBadRequest,
+)
+
+var roleGrantExpiresBeforeStart = Error.NewStatusError(
+	"Grant expiration time must be after its start time",
+	http.StatusBadRequest,
+)
+
+var privilegedRoleGrant = Error.NewStatusError(
+	"Privileged roles can't be granted, change roles of the user instead (such change requires approval)",
+	http.StatusBadRequest,
+)
+
+var roleIsAlreadyAssigned = Error.NewStatusError(
+	"User already has this role",
+	http.StatusConflict,
+)
+
+func newRoleGrantAuditQuery(op audit.Operation, act *ActionDTO.UserTargeted, grant *UserDTO.RoleGrant) *query.Query {
+	dto := UserDTO.RoleGrantAudit{
+		ChangedGrantID:       grant.ID,
+		ChangedByUserID:      act.RequesterUID,
+		Operation:            string(op),
+		ChangedAt:            time.Now(),
+		Reason:               act.Reason,
+		ImpersonatedByUserID: act.ImpersonatorUID,
+		RoleGrant:            grant,
+	}
+
+	var reason any = dto.Reason
+
+	if dto.Reason == "" {
+		reason = nil
+	}
+
+	var impersonatedBy any = dto.ImpersonatedByUserID
+
+	if dto.ImpersonatedByUserID == "" {
+		impersonatedBy = nil
+	}
+
+	return query.New(
+		`INSERT INTO "audit_user_role_grant"
+        (changed_grant_id, user_id, changed_by_user_id, impersonated_by_user_id, operation, role, not_before, expires_at, changed_at, reason)
+        VALUES
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		dto.ChangedGrantID,
+		dto.UserID,
+		dto.ChangedByUserID,
+		impersonatedBy,
+		dto.Operation,
+		dto.Role,
+		dto.NotBefore,
+		dto.ExpiresAt,
+		dto.ChangedAt,
+		reason,
+	)
+}
+
+func newBumpVersionQuery(uid string) *query.Query {
+	return query.New(`UPDATE "user" SET version = version + 1 WHERE id = $1;`, uid)
+}
+
+func (m *Manager) GrantRole(act *ActionDTO.UserTargeted, grant *UserDTO.RoleGrant) (string, *Error.Status) {
+	dblog.Logger.Info("Granting role "+grant.Role+" to user "+act.TargetUID+"...", nil)
+
+	if err := act.ValidateUIDs(); err != nil {
+		dblog.Logger.Error("Failed to grant role "+grant.Role+" to user "+act.TargetUID, err.Error(), nil)
+		return "", err
+	}
+
+	if !slices.ContainsFunc(authz.GetSchema().Roles, func(role rbac.Role) bool { return role.Name == grant.Role }) {
+		errMsg := "Role '" + grant.Role + "' doesn't exists"
+		dblog.Logger.Error("Failed to grant role "+grant.Role+" to user "+act.TargetUID, errMsg, nil)
+		return "", Error.NewStatusError(errMsg, http.StatusBadRequest)
+	}
+
+	// Otherwise grant could be used to bypass approval of the role change request
+	if slices.Contains(config.Authz.PrivilegedRoles, grant.Role) {
+		dblog.Logger.Error("Failed to grant role "+grant.Role+" to user "+act.TargetUID, privilegedRoleGrant.Error(), nil)
+		return "", privilegedRoleGrant
+	}
+
+	now := time.Now()
+
+	if grant.ExpiresAt != nil {
+		if !grant.ExpiresAt.After(now) {
+			return "", roleGrantExpiresInPast
+		}
+		if grant.NotBefore != nil && !grant.ExpiresAt.After(*grant.NotBefore) {
+			return "", roleGrantExpiresBeforeStart
+		}
+	}
+
+	if err := authz.User.For(act).ChangeUserRoles(
+		act.RequesterUID == act.TargetUID,
+		act.RequesterRoles,
+	); err != nil {
+		return "", err
+	}
+
+	user, err := m.GetUserByID(act.TargetUID)
+	if err != nil {
+		return "", err
+	}
+
+	if slices.Contains(user.Roles, grant.Role) {
+		dblog.Logger.Error("Failed to grant role "+grant.Role+" to user "+act.TargetUID, roleIsAlreadyAssigned.Error(), nil)
+		return "", roleIsAlreadyAssigned
+	}
+
+	grant.ID = uuid.NewString()
+	grant.UserID = act.TargetUID
+	grant.GrantedByUserID = act.RequesterUID
+	grant.CreatedAt = now
+	grant.ActivatedAt = nil
+
+	isActive := grant.IsActiveAt(now)
+	if isActive {
+		grant.ActivatedAt = &now
+	}
+
+	queries := []*query.Query{
+		query.New(
+			`INSERT INTO "user_role_grant" (id, user_id, role, not_before, expires_at, activated_at, granted_by_user_id, created_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`,
+			grant.ID,
+			grant.UserID,
+			grant.Role,
+			grant.NotBefore,
+			grant.ExpiresAt,
+			grant.ActivatedAt,
+			grant.GrantedByUserID,
+			grant.CreatedAt,
+		),
+		newRoleGrantAuditQuery(audit.CreateOperation, act, grant),
+	}
+
+	// Scheduled grants will bump user version once they become active (see ProcessRoleGrants)
+	if isActive {
+		queries = append(queries, newBumpVersionQuery(act.TargetUID))
+	}
+
+	if err := transaction.New(queries...).Exec(connection.Primary); err != nil {
+		return "", err
+	}
+
+	if isActive {
+		updatedUser := user.Copy()
+		updatedUser.Version = user.Version + 1
+		invalidateBasicUserDtoCache(user, updatedUser)
+	}
+
+	dblog.Logger.Info("Granting role "+grant.Role+" to user "+act.TargetUID+": OK", nil)
+
+	return grant.ID, nil
+}
+
+func (m *Manager) RevokeRoleGrant(act *ActionDTO.UserTargeted, grantID string) *Error.Status {
+	dblog.Logger.Info("Revoking role grant "+grantID+" of user "+act.TargetUID+"...", nil)
+
+	if err := act.ValidateUIDs(); err != nil {
+		dblog.Logger.Error("Failed to revoke role grant "+grantID+" of user "+act.TargetUID, err.Error(), nil)
+		return err
+	}
+
+	if err := validation.UUID(grantID); err != nil {
+		e := err.ToStatus("Grant ID is not specified", "Grant ID has invalid format (UUID expected)")
+		dblog.Logger.Error("Failed to revoke role grant "+grantID+" of user "+act.TargetUID, e.Error(), nil)
+		return e
+	}
+
+	if err := authz.User.For(act).ChangeUserRoles(
+		act.RequesterUID == act.TargetUID,
+		act.RequesterRoles,
+	); err != nil {
+		return err
+	}
+
+	grant, err := executor.RoleGrantDTO(
+		connection.Primary,
+		query.New(selectRoleGrantSQL+` WHERE id = $1 AND user_id = $2;`, grantID, act.TargetUID),
+	)
+	if err != nil {
+		return err
+	}
+
+	user, err := m.GetUserByID(act.TargetUID)
+	if err != nil {
+		return err
+	}
+
+	queries := []*query.Query{
+		query.New(`DELETE FROM "user_role_grant" WHERE id = $1;`, grantID),
+		newRoleGrantAuditQuery(audit.DeleteOperation, act, grant),
+	}
+
+	// If grant isn't active yet, then it doesn't affect user's tokens
+	isActive := grant.ActivatedAt != nil
+	if isActive {
+		queries = append(queries, newBumpVersionQuery(act.TargetUID))
+	}
+
+	if err := transaction.New(queries...).Exec(connection.Primary); err != nil {
+		return err
+	}
+
+	if isActive {
+		updatedUser := user.Copy()
+		updatedUser.Version = user.Version + 1
+		invalidateBasicUserDtoCache(user, updatedUser)
+	}
+
+	dblog.Logger.Info("Revoking role grant "+grantID+" of user "+act.TargetUID+": OK", nil)
+
+	return nil
+}
+
+func (m *Manager) GetRoleGrants(act *ActionDTO.UserTargeted) ([]*UserDTO.RoleGrant, *Error.Status) {
+	dblog.Logger.Info("Getting role grants of user "+act.TargetUID+"...", nil)
+
+	if err := act.ValidateTargetUID(); err != nil {
+		dblog.Logger.Error("Failed to get role grants of user "+act.TargetUID, err.Error(), nil)
+		return nil, err
+	}
+
+	if err := authz.User.For(act).GetUserRoles(act.RequesterRoles); err != nil {
+		return nil, err
+	}
+
+	grants, err := executor.CollectRoleGrantDTO(
+		connection.Replica,
+		query.New(selectRoleGrantSQL+` WHERE user_id = $1 ORDER BY created_at;`, act.TargetUID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dblog.Logger.Info("Getting role grants of user "+act.TargetUID+": OK", nil)
+
+	return grants, nil
+}
+
+func (m *Manager) GetEffectiveRoles(user *UserDTO.Full) ([]string, *Error.Status) {
+	dblog.Logger.Trace("Getting effective roles of user "+user.ID+"...", nil)
+
+	// Activity is checked by time instead of activated_at, since grant may
+	// be already in effect, but not yet processed by ProcessRoleGrants.
+	// Primary is used cuz tokens are usually refreshed right after grant or group was changed.
+	scan, err := executor.Row(connection.Primary, query.New(
+		`SELECT COALESCE(array_agg(DISTINCT role), '{}') FROM (
+            SELECT role FROM "user_role_grant"
+            WHERE user_id = $1 AND (not_before IS NULL OR not_before <= $2) AND (expires_at IS NULL OR expires_at > $2)
+            UNION
+            SELECT unnest(g.roles) FROM "user_group" AS g JOIN "user_group_member" AS m ON m.group_id = g.id
+            WHERE m.user_id = $1
+        ) AS inherited;`,
+		user.ID, time.Now(),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	inheritedRoles := []string{}
+
+	if err := scan(&inheritedRoles); err != nil {
+		return nil, err
+	}
+
+	roles := slices.Clone(user.Roles)
+	for _, role := range inheritedRoles {
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	dblog.Logger.Trace("Getting effective roles of user "+user.ID+": OK", nil)
+
+	return roles, nil
+}
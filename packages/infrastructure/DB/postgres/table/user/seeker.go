@@ -24,6 +24,10 @@ const (
 	searchUsersSqlSelect = `SELECT id, login, roles, deleted_at, version FROM numbered_users WHERE row_num BETWEEN `
 )
 
+func organizationMemberCond(n int) string {
+	return `id IN (SELECT user_id FROM "organization_member" WHERE organization_id = $` + strconv.Itoa(n) + `)`
+}
+
 func searchUsersSqlEnd(page, pageSize int) string {
 	start := ((page - 1) * pageSize) + 1
 	end := page * pageSize
@@ -40,7 +44,7 @@ func (m *Manager) SearchUsers(
 
 	dblog.Logger.Info("Searching users matching "+filtersStr+"...", nil)
 
-	if err := authz.User.SearchUsers(act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).SearchUsers(act.RequesterRoles); err != nil {
 		return nil, err
 	}
 
@@ -63,7 +67,15 @@ func (m *Manager) SearchUsers(
 
 	// If filter is "null"
 	if len(rawFilters) == 1 && rawFilters[0] == "null" {
-		searchQuery := query.New(searchUsersSqlStart + searchUsersSqlEnd(page, pageSize))
+		var searchQuery *query.Query
+		if act.IsOrganizationScoped() {
+			searchQuery = query.New(
+				searchUsersSqlStart+" WHERE "+organizationMemberCond(1)+searchUsersSqlEnd(page, pageSize),
+				act.OrganizationID,
+			)
+		} else {
+			searchQuery = query.New(searchUsersSqlStart + searchUsersSqlEnd(page, pageSize))
+		}
 		dtos, err := executor.CollectPublicUserDTO(connection.Replica, searchQuery)
 		if err != nil {
 			return nil, err
@@ -109,6 +121,12 @@ func (m *Manager) SearchUsers(
 		}
 	}
 
+	// Organization-scoped search must never return users outside of the organization
+	if act.IsOrganizationScoped() {
+		conds = append(conds, organizationMemberCond(valuesCount))
+		values = append(values, act.OrganizationID)
+	}
+
 	searchQuery := query.New(
 		searchUsersSqlStart+" WHERE "+strings.Join(conds, " AND ")+searchUsersSqlEnd(page, pageSize), values...,
 	)
@@ -241,6 +259,15 @@ func (m *Manager) checkLoginAvailability(login string) *Error.Status {
 	_, err := m.GetUserByLogin(login)
 	if err != nil {
 		if err == Error.StatusNotFound {
+			reserved, err := isLoginReserved(login)
+			if err != nil {
+				dblog.Logger.Error("Failed to check availability of login \""+login+"\"", err.Error(), nil)
+				return err
+			}
+			if reserved {
+				dblog.Logger.Info("Checking availability of login \""+login+"\": Reserved by recent login change", nil)
+				return loginIsReserved
+			}
 			dblog.Logger.Info("Checking availability of login \""+login+"\": OK", nil)
 			return nil
 		}
@@ -269,7 +296,7 @@ func (_ *Manager) GetRoles(act *ActionDTO.UserTargeted) ([]string, *Error.Status
 		return nil, err
 	}
 
-	if err := authz.User.GetUserRoles(act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).GetUserRoles(act.RequesterRoles); err != nil {
 		return nil, err
 	}
 
@@ -301,6 +328,60 @@ func (_ *Manager) GetRoles(act *ActionDTO.UserTargeted) ([]string, *Error.Status
 	return roles, nil
 }
 
+func (_ *Manager) GetAssignedRoles() ([]string, *Error.Status) {
+	dblog.Logger.Info("Getting assigned roles...", nil)
+
+	selectQuery := query.New(
+		`SELECT COALESCE(array_agg(DISTINCT role), '{}') FROM (
+            SELECT unnest(roles) AS role FROM "user"
+            UNION
+            SELECT role FROM "user_role_grant"
+            UNION
+            SELECT unnest(roles) FROM "user_group"
+            UNION
+            SELECT unnest(roles) FROM "organization_member"
+        ) AS assigned;`,
+	)
+
+	scan, err := executor.Row(connection.Primary, selectQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{}
+
+	if e := scan(&roles); e != nil {
+		return nil, e
+	}
+
+	dblog.Logger.Info("Getting assigned roles: OK", nil)
+
+	return roles, nil
+}
+
+func (_ *Manager) GetLoginsByRoles(roles []string) ([]string, *Error.Status) {
+	dblog.Logger.Info("Getting logins of users with roles "+strings.Join(roles, ",")+"...", nil)
+
+	scan, err := executor.Row(connection.Replica, query.New(
+		`SELECT COALESCE(array_agg(login), '{}') FROM "user"
+        WHERE deleted_at IS NULL AND roles && $1::varchar[];`,
+		roles,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	logins := []string{}
+
+	if e := scan(&logins); e != nil {
+		return nil, e
+	}
+
+	dblog.Logger.Info("Getting logins of users with roles "+strings.Join(roles, ",")+": OK", nil)
+
+	return logins, nil
+}
+
 func (_ *Manager) GetUserVersion(UID string) (uint32, *Error.Status) {
 	dblog.Logger.Info("Getting version of user "+UID+"...", nil)
 
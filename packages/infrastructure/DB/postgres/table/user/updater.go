@@ -15,64 +15,37 @@ import (
 	"sentinel/packages/infrastructure/auth/authz"
 	"sentinel/packages/infrastructure/cache"
 	UserMapper "sentinel/packages/infrastructure/mappers/user"
+	"sentinel/packages/infrastructure/revocation"
 	"sentinel/packages/infrastructure/token"
 	"slices"
 	"strings"
 )
 
+// Also notifies revocation stream subscribers if user version was changed
 func invalidateBasicUserDtoCache(old, current *UserDTO.Full) {
 	invalidator := cache.NewBasicUserDtoInvalidator(old, current)
 	if err := invalidator.Invalidate(); err != nil {
 		dblog.Logger.Error("Failed to invalidate cache", err.Error(), nil)
 	}
+	if old.Version != current.Version {
+		revocation.TryPublish(revocation.NewUserVersionChangedEvent(current.ID, current.Version))
+	}
 }
 
 // TODO make this change methods also return old user?
 
-func (m *Manager) ChangeLogin(act *ActionDTO.UserTargeted, newLogin string) *Error.Status {
-	dblog.Logger.Info("Changing login of user "+act.TargetUID+"...", nil)
-
-	if err := act.ValidateUIDs(); err != nil {
-		dblog.Logger.Error("Failed to change login of user "+act.TargetUID, err.Error(), nil)
-		return err
-	}
-
-	if err := user.ValidateLogin(newLogin); err != nil {
-		dblog.Logger.Error("Failed to change login of user "+act.TargetUID, err.Error(), nil)
-		return err
-	}
-
-	if err := authz.User.ChangeUserLogin(
-		act.RequesterUID == act.TargetUID,
-		act.RequesterRoles,
-	); err != nil {
-		return err
-	}
-
-	user, err := m.GetUserByID(act.TargetUID)
-	if err != nil {
-		return err
-	}
-
-	if user.Login == newLogin {
-		errMsg := "Login not changed: Current login and new login are the same"
-		dblog.Logger.Error("Failed to change login of user "+act.TargetUID, err.Error(), nil)
-		return Error.NewStatusError(errMsg, http.StatusConflict)
-	}
-
-	if err := m.checkLoginAvailability(newLogin); err != nil {
-		return err
-	}
-
+// Sets new login of the user, additional queries are executed in the same transaction.
+// Login must be validated before calling this function.
+func setLogin(act *ActionDTO.UserTargeted, user *UserDTO.Full, newLogin string, queries ...*query.Query) *Error.Status {
 	audit := newAuditDTO(audit.UpdatedOperation, act, user)
 
-	query := query.New(
+	queries = append(queries, query.New(
 		`UPDATE "user" SET login = $1, version = version + 1
         WHERE id = $2;`,
 		newLogin, act.TargetUID,
-	)
+	))
 
-	if err := execTxWithAudit(&audit, query); err != nil {
+	if err := execTxWithAudit(&audit, queries...); err != nil {
 		return err
 	}
 
@@ -81,8 +54,6 @@ func (m *Manager) ChangeLogin(act *ActionDTO.UserTargeted, newLogin string) *Err
 	updatedUser.Version++
 	invalidateBasicUserDtoCache(user, updatedUser)
 
-	dblog.Logger.Info("Changing login of user "+act.TargetUID+": OK", nil)
-
 	return nil
 }
 
@@ -99,7 +70,7 @@ func (m *Manager) ChangePassword(act *ActionDTO.UserTargeted, newPassword string
 		return err
 	}
 
-	if err := authz.User.ChangeUserPassword(
+	if err := authz.User.For(act).ChangeUserPassword(
 		act.RequesterUID == act.TargetUID,
 		act.RequesterRoles,
 	); err != nil {
@@ -139,12 +110,12 @@ func (m *Manager) ChangePassword(act *ActionDTO.UserTargeted, newPassword string
 	return nil
 }
 
-func (m *Manager) ChangeRoles(act *ActionDTO.UserTargeted, newRoles []string) *Error.Status {
-	dblog.Logger.Info("Changing roles of user "+act.TargetUID+"...", nil)
-
+// Validates new roles and checks that requester can change roles of the target user.
+// Returns target user.
+func (m *Manager) validateRolesChange(act *ActionDTO.UserTargeted, newRoles []string) (*UserDTO.Full, *Error.Status) {
 main_loop:
 	for _, newRole := range newRoles {
-		for _, role := range authz.Schema.Roles {
+		for _, role := range authz.GetSchema().Roles {
 			if role.Name == newRole {
 				continue main_loop
 			}
@@ -152,12 +123,12 @@ main_loop:
 
 		errMsg := "Role '" + newRole + "' doesn't exists"
 		dblog.Logger.Error("Failed to change roles of user "+act.TargetUID, errMsg, nil)
-		return Error.NewStatusError(errMsg, http.StatusBadRequest)
+		return nil, Error.NewStatusError(errMsg, http.StatusBadRequest)
 	}
 
 	if err := act.ValidateUIDs(); err != nil {
 		dblog.Logger.Error("Failed to change roles of user "+act.TargetUID, err.Error(), nil)
-		return err
+		return nil, err
 	}
 
 	isRequesterAdmin := slices.Contains(act.RequesterRoles, "admin")
@@ -166,38 +137,58 @@ main_loop:
 	if act.TargetUID == act.RequesterUID && isRequesterAdmin && !isAdminInNewRoles {
 		errMsg := "Нельзя снять роль администратора с самого себя"
 		dblog.Logger.Error("Failed to change roles of user "+act.TargetUID, errMsg, nil)
-		return Error.NewStatusError(errMsg, http.StatusForbidden)
+		return nil, Error.NewStatusError(errMsg, http.StatusForbidden)
 	}
 
 	user, err := m.GetUserByID(act.TargetUID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := authz.User.ChangeUserRoles(
+	if err := authz.User.For(act).ChangeUserRoles(
 		act.RequesterUID == act.TargetUID,
 		act.RequesterRoles,
 	); err != nil {
-		return err
+		return nil, err
 	}
 
+	return user, nil
+}
+
+// Sets new roles of the user, additional queries are executed in the same transaction.
+func setRoles(act *ActionDTO.UserTargeted, user *UserDTO.Full, newRoles []string, queries ...*query.Query) *Error.Status {
 	audit := newAuditDTO(audit.UpdatedOperation, act, user)
 
-	query := query.New(
+	queries = append(queries, query.New(
 		`UPDATE "user" SET roles = $1, version = version + 1
         WHERE id = $2;`,
 		newRoles, act.TargetUID,
-	)
+	))
 
-	if err := execTxWithAudit(&audit, query); err != nil {
+	if err := execTxWithAudit(&audit, queries...); err != nil {
 		return err
 	}
 
 	updatedUser := user.Copy()
 	updatedUser.Roles = newRoles
-	updatedUser.Version++
+	updatedUser.Version = user.Version + 1
 	invalidateBasicUserDtoCache(user, updatedUser)
 
+	return nil
+}
+
+func (m *Manager) ChangeRoles(act *ActionDTO.UserTargeted, newRoles []string) *Error.Status {
+	dblog.Logger.Info("Changing roles of user "+act.TargetUID+"...", nil)
+
+	user, err := m.validateRolesChange(act, newRoles)
+	if err != nil {
+		return err
+	}
+
+	if err := setRoles(act, user, newRoles); err != nil {
+		return err
+	}
+
 	dblog.Logger.Info("Changing roles of user "+act.TargetUID+": OK", nil)
 
 	return nil
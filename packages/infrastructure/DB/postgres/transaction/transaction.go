@@ -78,10 +78,15 @@ func (t *Transaction) Exec(conType connection.Type) *Error.Status {
 	}()
 
 	for _, query := range t.queries {
-		if _, err := tx.Exec(ctx, query.SQL, query.Args...); err != nil {
+		tag, err := tx.Exec(ctx, query.SQL, query.Args...)
+		if err != nil {
 			dblog.Logger.Error("Transaction failed", err.Error(), nil)
 			return query.ConvertAndLogError(err)
 		}
+		if err := query.CheckAffectedRows(tag.RowsAffected()); err != nil {
+			dblog.Logger.Error("Transaction failed", err.Error(), nil)
+			return err
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -172,3 +172,35 @@ func BenchmarkCompareHashAndPasswordInvalid(b *testing.B) {
 		CompareHashAndPassword(string(hash), "wrongPassword")
 	}
 }
+
+func TestOneTimeCode(t *testing.T) {
+	code, hash, err := NewOneTimeCode()
+	if err != nil {
+		t.Fatalf("Failed to generate one-time code: %v", err)
+	}
+
+	if len(code) != oneTimeCodeLength {
+		t.Errorf("Expected code of length %d, got: %q", oneTimeCodeLength, code)
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			t.Fatalf("Code must consist only of digits, got: %q", code)
+		}
+	}
+
+	if err := CompareHashAndOneTimeCode(hash, code); err != nil {
+		t.Errorf("Valid code was rejected: %v", err)
+	}
+
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "000001"
+	}
+	if err := CompareHashAndOneTimeCode(hash, wrongCode); err != InvalidOneTimeCode {
+		t.Errorf("Expected InvalidOneTimeCode for wrong code, got: %v", err)
+	}
+
+	if err := CompareHashAndOneTimeCode("", code); err != InvalidOneTimeCode {
+		t.Errorf("Expected InvalidOneTimeCode for empty hash, got: %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+package authn
+
+// Authentication methods references, used as values of "amr" token claim.
+// (https://datatracker.ietf.org/doc/html/rfc8176)
+const (
+	// Password-based authentication
+	PasswordMethod = "pwd"
+	// Authentication via external identity provider (OAuth).
+	// Not registered in RFC 8176, but widely used for federated authentication.
+	FederatedMethod = "fed"
+	// Authentication via one-time code sent to the user login
+	OneTimeCodeMethod = "otp"
+)
@@ -0,0 +1,51 @@
+package authn
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	"strings"
+)
+
+const oneTimeCodeLength = 6
+
+var InvalidOneTimeCode = Error.NewStatusError(
+	"Invalid or expired one-time code",
+	http.StatusBadRequest,
+)
+
+var oneTimeCodeMax = big.NewInt(1_000_000)
+
+// Generates random numeric code which can be sent to the user to confirm its identity.
+// Returns code itself and its hash, only hash must be stored.
+func NewOneTimeCode() (code string, hash string, err *Error.Status) {
+	n, e := rand.Int(rand.Reader, oneTimeCodeMax)
+	if e != nil {
+		return "", "", Error.StatusInternalError
+	}
+
+	code = n.String()
+	code = strings.Repeat("0", oneTimeCodeLength-len(code)) + code
+
+	return code, HashOneTimeCode(code), nil
+}
+
+// One-time codes are short-lived and have high entropy relative to their lifetime,
+// so there is no need in expensive hashing (like bcrypt) here.
+func HashOneTimeCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// Compares hash of the one-time code with it's possible plaintext equivalent in constant time.
+// Returns nil on success, otherwise returns InvalidOneTimeCode error.
+func CompareHashAndOneTimeCode(hash string, code string) *Error.Status {
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(HashOneTimeCode(code))) != 1 {
+		return InvalidOneTimeCode
+	}
+	return nil
+}
@@ -1,24 +1,150 @@
 package authz
 
-import rbac "github.com/abaxoth0/SentinelRBAC"
+import (
+	"errors"
+	"fmt"
+	"sentinel/packages/common/config"
 
-func initAGP() {
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+const (
+	BuiltinRuleSource = "builtin"
+	ConfigRuleSource  = "config"
+	// Rule is defined in the schema of other service (in RBAC configuration file)
+	SchemaRuleSource = "schema"
+)
+
+// Action Gate Policy rule in human-readable format.
+type ActionGateRule struct {
+	// Authorization context which this rule matches: "<entity>:<action>:<resource>"
+	Context string   `json:"context" example:"user:drop:cache"`
+	Effect  string   `json:"effect" example:"require"`
+	Roles   []string `json:"roles" example:"admin"`
+	// Where rule was defined: "builtin", "config" or "schema"
+	Source string `json:"source" example:"builtin"`
+}
+
+func newBuiltinRule(ctx *rbac.AuthorizationContext, effect rbac.ActionGateEffect, roles ...string) ActionGateRule {
+	return ActionGateRule{
+		Context: stringFromContext(ctx),
+		Effect:  string(effect),
+		Roles:   roles,
+		Source:  BuiltinRuleSource,
+	}
+}
+
+func builtinRules() []ActionGateRule {
+	return []ActionGateRule{
+		newBuiltinRule(&userDropCacheContext, rbac.RequireActionGateEffect, "admin"),
+		// Impersonation gives access to everything that target user can access,
+		// so it must be available only for the staff.
+		newBuiltinRule(&userImpersonateUserContext, rbac.RequireActionGateEffect, "admin", "support"),
+		// Roles changes affects authorization of all users in all services
+		newBuiltinRule(&userCreateRoleContext, rbac.RequireActionGateEffect, "admin"),
+		newBuiltinRule(&userUpdateRoleContext, rbac.RequireActionGateEffect, "admin"),
+		newBuiltinRule(&userDeleteRoleContext, rbac.RequireActionGateEffect, "admin"),
+		newBuiltinRule(&userReloadRBACContext, rbac.RequireActionGateEffect, "admin"),
+		// Explanation reveals permissions of roles and Action Gate Policy rules
+		newBuiltinRule(&userExplainAuthorizationContext, rbac.RequireActionGateEffect, "admin"),
+		// Organizations are created and deleted only by the staff, not by tenant admins
+		// (organization-scoped tokens are rejected by these endpoints anyway).
+		newBuiltinRule(&userCreateOrganizationContext, rbac.RequireActionGateEffect, "admin"),
+		newBuiltinRule(&userDeleteOrganizationContext, rbac.RequireActionGateEffect, "admin"),
+		// Groups changes affects roles of all their members
+		newBuiltinRule(&userCreateGroupContext, rbac.RequireActionGateEffect, "admin"),
+		newBuiltinRule(&userUpdateGroupContext, rbac.RequireActionGateEffect, "admin"),
+		newBuiltinRule(&userDeleteGroupContext, rbac.RequireActionGateEffect, "admin"),
+		// Jobs reveal internal state of the service
+		newBuiltinRule(&userGetSchedulerJobsContext, rbac.RequireActionGateEffect, "admin"),
+	}
+}
+
+// Returns built-in rules merged with the rules from config.
+// Config rule replaces built-in rule for the same context.
+func mergeRules(builtin []ActionGateRule, configRules []config.ActionGateRule) ([]ActionGateRule, error) {
+	rules := make([]ActionGateRule, len(builtin), len(builtin)+len(configRules))
+	copy(rules, builtin)
+
+	configContexts := make(map[string]bool, len(configRules))
+
+outer:
+	for _, configRule := range configRules {
+		if configContexts[configRule.Context] {
+			return nil, fmt.Errorf("rule for context %s is defined more than once in config", configRule.Context)
+		}
+		configContexts[configRule.Context] = true
+
+		rule := ActionGateRule{
+			Context: configRule.Context,
+			Effect:  configRule.Effect,
+			Roles:   configRule.Roles,
+			Source:  ConfigRuleSource,
+		}
+
+		for i, builtinRule := range rules {
+			if builtinRule.Context == rule.Context {
+				log.Warning("Built-in Action Gate Policy rule for "+rule.Context+" is replaced by rule from config", nil)
+				rules[i] = rule
+				continue outer
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Builds Action Gate Policy from built-in rules and rules from config.
+// Each rule must refer to the registered context (see initContexts) and to the roles of the given schema.
+func newAGP(schema *rbac.Schema) (*rbac.ActionGatePolicy, []ActionGateRule, error) {
 	log.Info("Initializing Action Gate Policy...", nil)
 
+	var configRules []config.ActionGateRule
+	if config.Authz != nil {
+		configRules = config.Authz.ActionGatePolicy
+	}
+
+	rules, err := mergeRules(builtinRules(), configRules)
+	if err != nil {
+		log.Error("Failed to initialize Action Gate Policy", err.Error(), nil)
+		return nil, nil, err
+	}
+
 	agp := rbac.NewActionGatePolicy()
 
-	requireAdminRoleToDropCache := rbac.NewActionGateRule(
-		&userDropCacheContext,
-		rbac.RequireActionGateEffect,
-		// Only role name matters
-		[]rbac.Role{rbac.NewRole("admin", 0)},
-	)
+	for _, rule := range rules {
+		ctx, ok := registeredContexts[rule.Context]
+		if !ok {
+			err := errors.New("rule refers to unknown context " + rule.Context)
+			log.Error("Failed to initialize Action Gate Policy", err.Error(), nil)
+			return nil, nil, err
+		}
 
-	if err := agp.AddRule(requireAdminRoleToDropCache); err != nil {
-		log.Panic("Failed to initialize Action Gate Policy", err.Error(), nil)
-	}
+		roles := make([]rbac.Role, len(rule.Roles))
+		for i, roleName := range rule.Roles {
+			role, e := schema.ParseRole(roleName)
+			// Built-in rules must not prevent roles from being removed
+			// (only role name matters for Action Gate Policy)
+			if e != nil && rule.Source == BuiltinRuleSource {
+				role, e = rbac.NewRole(roleName, 0), nil
+			}
+			if e != nil {
+				err := errors.New("rule for context " + rule.Context + " refers to unknown role: " + e.Error())
+				log.Error("Failed to initialize Action Gate Policy", err.Error(), nil)
+				return nil, nil, err
+			}
+			roles[i] = role
+		}
 
-	Schema.ActionGatePolicy = agp
+		if e := agp.AddRule(rbac.NewActionGateRule(&ctx, rbac.ActionGateEffect(rule.Effect), roles)); e != nil {
+			log.Error("Failed to initialize Action Gate Policy", e.Error(), nil)
+			return nil, nil, e
+		}
+	}
 
 	log.Info("Initializing Action Gate Policy: OK", nil)
+
+	return &agp, rules, nil
 }
@@ -6,7 +6,11 @@ import (
 	"sentinel/packages/common/config"
 	Error "sentinel/packages/common/errors"
 	"sentinel/packages/common/logger"
+	"sentinel/packages/infrastructure/auth/authz/policy"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	rbac "github.com/abaxoth0/SentinelRBAC"
 )
@@ -14,39 +18,82 @@ import (
 var log = logger.NewSource("AUTHZ", logger.Default)
 
 var (
-	userResource       *rbac.Resource
-	cacheResource      *rbac.Resource
-	docsResource       *rbac.Resource
-	sessionResource    *rbac.Resource
-	locationResource   *rbac.Resource
-	oauthTokenResource *rbac.Resource
+	userResource         *rbac.Resource
+	cacheResource        *rbac.Resource
+	docsResource         *rbac.Resource
+	sessionResource      *rbac.Resource
+	locationResource     *rbac.Resource
+	oauthTokenResource   *rbac.Resource
+	roleResource         *rbac.Resource
+	authzResource        *rbac.Resource
+	organizationResource *rbac.Resource
+	groupResource        *rbac.Resource
+	schedulerResource    *rbac.Resource
 )
 
 var userEntity = rbac.NewEntity("user")
 
-var Schema *rbac.Schema
-var Host *rbac.Host
+// Role name -> resource name -> permissions.
+// Overrides permissions of the role for the specific resources.
+type ResourcePermissions map[string]map[string]rbac.Permissions
 
-func Init(configPath string) {
-	log.Info("Loading configuration file...", nil)
+type snapshot struct {
+	host   *rbac.Host
+	schema *rbac.Schema
+	agp    *rbac.ActionGatePolicy
+	// Rules from which agp was built
+	agpRules []ActionGateRule
+	// Role name -> resource name -> permissions
+	resourcePermissions ResourcePermissions
+	// Context string -> condition, which is evaluated after action was authorized by roles
+	conditions map[string]*policy.Expression
+	// Location in which time attributes of conditions are calculated
+	location *time.Location
+	// Cached results of Check. Belongs to the snapshot,
+	// so it's dropped each time new RBAC state is applied.
+	decisions decisionCache
+}
 
-	host, e := rbac.LoadHost(configPath)
-	if e != nil {
-		log.Fatal("Failed to load configuration file", e.Error(), nil)
-	}
-	Host = &host
+// Current RBAC state. Can be replaced at runtime (see Apply),
+// so must be always accessed via GetHost and GetSchema.
+var current atomic.Pointer[snapshot]
 
-	log.Info("Loading configuration file: OK", nil)
-	log.Info("Getting schema for this service...", nil)
+// Returns rules of the Action Gate Policy which is currently in use.
+func GetActionGatePolicy() []ActionGateRule {
+	return current.Load().agpRules
+}
+
+// Path to the RBAC configuration file, set in Init
+var configPath string
+
+// Returns RBAC host which is currently in use.
+func GetHost() *rbac.Host {
+	return current.Load().host
+}
 
-	schema, err := Host.GetSchema(config.App.ServiceID)
+// Returns RBAC schema of this service which is currently in use.
+func GetSchema() *rbac.Schema {
+	return current.Load().schema
+}
+
+func Init(path string) {
+	configPath = path
+
+	host, err := LoadConfig()
 	if err != nil {
-		log.Fatal("Failed to get schema for this service", err.Error(), nil)
+		log.Fatal("Failed to load configuration file", err.Error(), nil)
 	}
 
-	Schema = schema
+	initResources()
 
-	log.Info("Getting schema for this service: OK", nil)
+	initContexts()
+
+	if err := Apply(host, nil); err != nil {
+		log.Fatal("Failed to apply RBAC configuration", err.Error(), nil)
+	}
+}
+
+func initResources() {
 	log.Info("Initializing resources...", nil)
 
 	userResource = rbac.NewResource("user")
@@ -55,11 +102,70 @@ func Init(configPath string) {
 	locationResource = rbac.NewResource("location")
 	oauthTokenResource = rbac.NewResource("oauth_token")
 	docsResource = rbac.NewResource("docs")
+	roleResource = rbac.NewResource("role")
+	authzResource = rbac.NewResource("authz")
+	organizationResource = rbac.NewResource("organization")
+	groupResource = rbac.NewResource("group")
+	schedulerResource = rbac.NewResource("scheduler")
 
 	log.Info("Initializing resources: OK", nil)
+}
 
-	initContexts()
-	initAGP()
+// Reads and validates RBAC configuration file.
+// Doesn't apply it, to do so use Apply.
+func LoadConfig() (*rbac.Host, error) {
+	log.Info("Loading configuration file...", nil)
+
+	host, err := rbac.LoadHost(configPath)
+	if err != nil {
+		log.Error("Failed to load configuration file", err.Error(), nil)
+		return nil, err
+	}
+
+	log.Info("Loading configuration file: OK", nil)
+
+	return &host, nil
+}
+
+// Atomically replaces RBAC host, schema of this service and Action Gate Policy which are used for authorization.
+// Authorizations which are already in progress will be completed using previous state.
+// Overrides can be nil.
+func Apply(host *rbac.Host, overrides ResourcePermissions) error {
+	log.Info("Getting schema for this service...", nil)
+
+	schema, err := host.GetSchema(config.App.ServiceID)
+	if err != nil {
+		log.Error("Failed to get schema for this service", err.Error(), nil)
+		return err
+	}
+
+	log.Info("Getting schema for this service: OK", nil)
+
+	agp, rules, e := newAGP(schema)
+	if e != nil {
+		return e
+	}
+
+	conditions, location, e := newConditions()
+	if e != nil {
+		return e
+	}
+
+	if overrides == nil {
+		overrides = ResourcePermissions{}
+	}
+
+	current.Store(&snapshot{
+		host:                host,
+		schema:              schema,
+		agp:                 agp,
+		agpRules:            rules,
+		resourcePermissions: overrides,
+		conditions:          conditions,
+		location:            location,
+	})
+
+	return nil
 }
 
 func stringFromContext(ctx *rbac.AuthorizationContext) string {
@@ -76,28 +182,86 @@ var DeniedByActionGatePolicy = Error.NewStatusError(
 	http.StatusForbidden,
 )
 
-// Can authorize operations only for the schema of this service.
-// Operations on other services must be authorized by themselves!
-func authorize(ctx *rbac.AuthorizationContext, rolesNames []string) *Error.Status {
-	ctxString := stringFromContext(ctx)
-
-	log.Trace("Authorizing "+ctxString+"...", nil)
+// Returns roles of this service schema with specified names.
+func (s *snapshot) getRoles(rolesNames []string) ([]rbac.Role, *Error.Status) {
+	return findRoles(s.schema, rolesNames)
+}
 
+// Returns roles of the given schema with specified names.
+func findRoles(schema *rbac.Schema, rolesNames []string) ([]rbac.Role, *Error.Status) {
 	roles := make([]rbac.Role, 0, len(rolesNames))
 main_loop:
 	for _, roleName := range rolesNames {
-		for _, role := range Schema.Roles {
+		for _, role := range schema.Roles {
 			if roleName == role.Name {
 				roles = append(roles, role)
 				continue main_loop
 			}
 		}
-		errMsg := "Role " + roleName + " doesn't exist"
-		log.Error("Authorization failed", errMsg, nil)
-		return Error.NewStatusError(errMsg, http.StatusBadRequest)
+		return nil, Error.NewStatusError("Role "+roleName+" doesn't exist", http.StatusBadRequest)
+	}
+	return roles, nil
+}
+
+// Replaces permissions of the given roles (of this service schema) with their permissions for the specified resource.
+func (s *snapshot) applyResourcePermissions(roles []rbac.Role, resource *rbac.Resource) {
+	for i, role := range roles {
+		if permissions, ok := s.resourcePermissions[role.Name][resource.Name()]; ok {
+			roles[i].Permissions = permissions
+		}
+	}
+}
+
+// Returns union of permissions of all specified roles for the resource with specified name.
+// Resource permissions overrides are taken into account, empty resource name means base permissions of roles.
+func (s *snapshot) mergePermissions(roles []rbac.Role, resourceName string) rbac.Permissions {
+	var permissions rbac.Permissions
+	for _, role := range roles {
+		if resourcePermissions, ok := s.resourcePermissions[role.Name][resourceName]; resourceName != "" && ok {
+			permissions |= resourcePermissions
+			continue
+		}
+		permissions |= role.Permissions
+	}
+	return permissions
+}
+
+// Returns names of all resources for which permissions of any of the given roles are overridden.
+func (s *snapshot) overriddenResources(roles ...[]rbac.Role) []string {
+	resources := []string{}
+	for _, r := range roles {
+		for _, role := range r {
+			for resourceName := range s.resourcePermissions[role.Name] {
+				if !slices.Contains(resources, resourceName) {
+					resources = append(resources, resourceName)
+				}
+			}
+		}
+	}
+	return resources
+}
+
+// Can authorize operations only for the schema of this service.
+// Operations on other services must be authorized by themselves!
+//
+// If action is authorized by roles, then condition of the context (if any) is evaluated with the given attributes.
+func authorize(ctx *rbac.AuthorizationContext, rolesNames []string, attributes policy.Attributes) *Error.Status {
+	ctxString := stringFromContext(ctx)
+
+	log.Trace("Authorizing "+ctxString+"...", nil)
+
+	// Snapshot may be replaced concurrently, so it must be loaded only once
+	s := current.Load()
+
+	roles, e := s.getRoles(rolesNames)
+	if e != nil {
+		log.Error("Authorization failed", e.Error(), nil)
+		return e
 	}
 
-	err := rbac.Authorize(ctx, roles, &Schema.ActionGatePolicy)
+	s.applyResourcePermissions(roles, ctx.Resource)
+
+	err := rbac.Authorize(ctx, roles, s.agp)
 
 	if err != nil {
 		log.Error("Failed to authorize "+ctxString, err.Error(), nil)
@@ -121,6 +285,10 @@ main_loop:
 		}
 	}
 
+	if e := s.checkCondition(ctxString, attributes); e != nil {
+		return e
+	}
+
 	log.Trace("Authorizing "+ctxString+": OK", nil)
 
 	return nil
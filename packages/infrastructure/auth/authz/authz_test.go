@@ -2,10 +2,14 @@ package authz
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 	"testing"
 
+	"sentinel/packages/common/config"
+	"sentinel/packages/common/config/configtest"
 	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
 
 	rbac "github.com/abaxoth0/SentinelRBAC"
 )
@@ -125,7 +129,7 @@ func TestAuthorizeFunction(t *testing.T) {
 		}()
 
 		// This should panic due to nil context dereference
-		result := authorize(nil, []string{"any-role"})
+		result := authorize(nil, []string{"any-role"}, nil)
 		t.Errorf("Expected panic with nil context, got result: %v", result)
 	})
 
@@ -157,6 +161,10 @@ func TestAuthorizationContexts(t *testing.T) {
 		&userLogoutUserContext,
 		&userGetSessionContext,
 		&userGetSelfSessionContext,
+		&userGetLoginHistoryContext,
+		&userGetSelfLoginHistoryContext,
+		&userGetLoginChangeRequestsContext,
+		&userGetSelfLoginChangeRequestsContext,
 		&userAccessAPIDocsContext,
 		&userGetSessionLocationContext,
 		&userDeleteLocationContext,
@@ -164,6 +172,14 @@ func TestAuthorizationContexts(t *testing.T) {
 		&userGetSelfContext,
 		&userIntrospectOAuthTokenContext,
 		&userDropCacheContext,
+		&userImpersonateUserContext,
+		&userCreateRoleContext,
+		&userGetRoleContext,
+		&userUpdateRoleContext,
+		&userDeleteRoleContext,
+		&userReloadRBACContext,
+		&userGetActionGatePolicyContext,
+		&userGetSchedulerJobsContext,
 	}
 
 	for i, ctx := range contexts {
@@ -184,11 +200,13 @@ func TestUserSelfVsOtherPermissions(t *testing.T) {
 
 		t.Run("self parameter methods exist", func(t *testing.T) {
 			// Test that the global User variable exists and has the expected methods
-			_ = User.SoftDeleteUser     // takes (self bool, roles []string)
-			_ = User.ChangeUserLogin    // takes (self bool, roles []string)
-			_ = User.GetUserSession     // takes (self bool, roles []string)
-			_ = User.ChangeUserPassword // takes (self bool, roles []string)
-			_ = User.ChangeUserRoles    // takes (self bool, roles []string)
+			_ = User.SoftDeleteUser         // takes (self bool, roles []string)
+			_ = User.ChangeUserLogin        // takes (self bool, roles []string)
+			_ = User.GetUserSession         // takes (self bool, roles []string)
+			_ = User.GetLoginHistory        // takes (self bool, roles []string)
+			_ = User.GetLoginChangeRequests // takes (self bool, roles []string)
+			_ = User.ChangeUserPassword     // takes (self bool, roles []string)
+			_ = User.ChangeUserRoles        // takes (self bool, roles []string)
 			t.Log("User methods with boolean self parameters are accessible")
 		})
 
@@ -238,6 +256,8 @@ func TestUserSelfVsOtherPermissions(t *testing.T) {
 				&userChangeUserPasswordContext, &userChangeSelfPasswordContext,
 				&userChangeUserRolesContext, &userChangeSelfRolesContext,
 				&userGetSessionContext, &userGetSelfSessionContext,
+				&userGetLoginHistoryContext, &userGetSelfLoginHistoryContext,
+				&userGetLoginChangeRequestsContext, &userGetSelfLoginChangeRequestsContext,
 			}
 
 			for i, ctx := range contexts {
@@ -250,3 +270,476 @@ func TestUserSelfVsOtherPermissions(t *testing.T) {
 		})
 	})
 }
+
+var testRoles = []rbac.Role{
+	rbac.NewRole("user", rbac.SelfReadPermission|rbac.SelfUpdatePermission),
+	rbac.NewRole("support", rbac.ReadPermission|rbac.SelfReadPermission),
+	rbac.NewRole("moderator", rbac.ReadPermission|rbac.SelfReadPermission|rbac.UpdatePermission|rbac.DeletePermission),
+	rbac.NewRole("admin", rbac.ReadPermission|rbac.SelfReadPermission|rbac.UpdatePermission|rbac.DeletePermission),
+}
+
+func initTestSnapshot(overrides ResourcePermissions) {
+	initResources()
+	initContexts()
+
+	schema := &rbac.Schema{Roles: testRoles}
+
+	agp, rules, err := newAGP(schema)
+	if err != nil {
+		panic(err)
+	}
+
+	conditions, location, err := newConditions()
+	if err != nil {
+		panic(err)
+	}
+
+	current.Store(&snapshot{
+		schema:              schema,
+		agp:                 agp,
+		agpRules:            rules,
+		resourcePermissions: overrides,
+		conditions:          conditions,
+		location:            location,
+	})
+}
+
+func TestImpersonateUser(t *testing.T) {
+	initTestSnapshot(nil)
+
+	tests := []struct {
+		name        string
+		roles       []string
+		targetRoles []string
+		expected    *Error.Status
+	}{
+		{"admin impersonates user", []string{"admin"}, []string{"user"}, nil},
+		{"admin impersonates moderator", []string{"admin"}, []string{"moderator"}, nil},
+		{"support impersonates user", []string{"support"}, []string{"user"}, nil},
+		{"support impersonates moderator", []string{"support"}, []string{"moderator"}, ImpersonationOfHigherPrivilegedUser},
+		{"moderator isn't allowed to impersonate", []string{"moderator"}, []string{"user"}, DeniedByActionGatePolicy},
+		{"user isn't allowed to impersonate", []string{"user"}, []string{"user"}, DeniedByActionGatePolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := User.ImpersonateUser(tt.roles, tt.targetRoles); err != tt.expected {
+				t.Errorf("ImpersonateUser() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImpersonateUserWithResourcePermissionsOverrides(t *testing.T) {
+	initTestSnapshot(ResourcePermissions{
+		// Moderator can change roles, while support can only read them
+		"moderator": {"role": rbac.ReadPermission | rbac.UpdatePermission},
+		"user":      {"role": rbac.ReadPermission},
+	})
+
+	tests := []struct {
+		name        string
+		roles       []string
+		targetRoles []string
+		expected    *Error.Status
+	}{
+		{"override of target is taken into account", []string{"support"}, []string{"user"}, nil},
+		{"admin has higher base permissions than overridden ones", []string{"admin"}, []string{"moderator"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := User.ImpersonateUser(tt.roles, tt.targetRoles); err != tt.expected {
+				t.Errorf("ImpersonateUser() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+
+	initTestSnapshot(ResourcePermissions{
+		// User can delete roles, while support can't
+		"user": {"role": rbac.DeletePermission},
+	})
+
+	if err := User.ImpersonateUser([]string{"support"}, []string{"user"}); err != ImpersonationOfHigherPrivilegedUser {
+		t.Errorf("ImpersonateUser() = %v, want %v", err, ImpersonationOfHigherPrivilegedUser)
+	}
+
+	initTestSnapshot(ResourcePermissions{
+		// Support can't read roles, while user can
+		"support": {"role": rbac.SelfReadPermission},
+		"user":    {"role": rbac.ReadPermission},
+	})
+
+	if err := User.ImpersonateUser([]string{"support"}, []string{"user"}); err != ImpersonationOfHigherPrivilegedUser {
+		t.Errorf("ImpersonateUser() = %v, want %v", err, ImpersonationOfHigherPrivilegedUser)
+	}
+}
+
+func TestResourcePermissionsOverrides(t *testing.T) {
+	initTestSnapshot(ResourcePermissions{
+		// Moderator can't read roles
+		"moderator": {"role": rbac.SelfReadPermission},
+		// Support can read users and roles
+		"support": {"role": rbac.ReadPermission},
+	})
+
+	tests := []struct {
+		name     string
+		authz    func([]string) *Error.Status
+		roles    []string
+		expected *Error.Status
+	}{
+		{"override revokes permissions", User.GetRole, []string{"moderator"}, InsufficientPermissions},
+		{"override grants permissions", User.GetRole, []string{"support"}, nil},
+		{"override doesn't affect other resources", User.SearchUsers, []string{"moderator"}, nil},
+		{"role without override", User.GetRole, []string{"admin"}, nil},
+		{"AGP is applied on top of overrides", User.DeleteRole, []string{"moderator"}, DeniedByActionGatePolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.authz(tt.roles); err != tt.expected {
+				t.Errorf("authorize() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRoleChangeApprovers(t *testing.T) {
+	initTestSnapshot(nil)
+
+	if approvers := RoleChangeApprovers(); !slices.Equal(approvers, []string{"moderator", "admin"}) {
+		t.Errorf("RoleChangeApprovers() = %v, want [moderator admin]", approvers)
+	}
+
+	initTestSnapshot(ResourcePermissions{
+		// Moderator can't change users
+		"moderator": {"user": rbac.ReadPermission},
+	})
+
+	if approvers := RoleChangeApprovers(); !slices.Equal(approvers, []string{"admin"}) {
+		t.Errorf("RoleChangeApprovers() = %v, want [admin]", approvers)
+	}
+}
+
+func TestConfigActionGatePolicyRules(t *testing.T) {
+	configtest.Init()
+
+	t.Run("invalid rules are rejected", func(t *testing.T) {
+		for _, rule := range []config.ActionGateRule{
+			{Context: "user:unknown:user", Effect: "deny", Roles: []string{"admin"}},
+			{Context: "user:search_users:user", Effect: "deny", Roles: []string{"unknown"}},
+		} {
+			config.Authz.ActionGatePolicy = []config.ActionGateRule{rule}
+			if _, _, err := newAGP(&rbac.Schema{Roles: testRoles}); err == nil {
+				t.Errorf("Rule %v must be rejected", rule)
+			}
+		}
+	})
+
+	config.Authz.ActionGatePolicy = []config.ActionGateRule{
+		{Context: "user:search_users:user", Effect: "deny", Roles: []string{"moderator"}},
+		// Replaces built-in rule
+		{Context: "user:impersonate:user", Effect: "require", Roles: []string{"admin"}},
+	}
+	initTestSnapshot(nil)
+
+	tests := []struct {
+		name     string
+		authz    func([]string) *Error.Status
+		roles    []string
+		expected *Error.Status
+	}{
+		{"config rule is applied", User.SearchUsers, []string{"moderator"}, DeniedByActionGatePolicy},
+		{"config rule doesn't affect other roles", User.SearchUsers, []string{"admin"}, nil},
+		{"built-in rule is replaced", func(roles []string) *Error.Status {
+			return User.ImpersonateUser(roles, []string{"user"})
+		}, []string{"support"}, DeniedByActionGatePolicy},
+		{"other built-in rules are kept", User.DropCache, []string{"moderator"}, DeniedByActionGatePolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.authz(tt.roles); err != tt.expected {
+				t.Errorf("authorize() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+
+	rules := GetActionGatePolicy()
+	if len(rules) != len(builtinRules())+1 {
+		t.Errorf("Expected %d rules, got %d", len(builtinRules())+1, len(rules))
+	}
+}
+
+func TestConditions(t *testing.T) {
+	configtest.Init()
+
+	t.Run("invalid conditions are rejected", func(t *testing.T) {
+		for _, condition := range []config.AuthzCondition{
+			{Context: "user:unknown:user", Expression: "true"},
+			{Context: "user:search_users:user", Expression: "subject.id =="},
+			{Context: "user:search_users:user", Expression: "subject.unknown == ''"},
+		} {
+			config.Authz.Conditions = []config.AuthzCondition{condition}
+			if _, _, err := newConditions(); err == nil {
+				t.Errorf("Condition %v must be rejected", condition)
+			}
+		}
+
+		config.Authz.Conditions = nil
+		config.Authz.ConditionsTimezone = "Unknown/Timezone"
+		if _, _, err := newConditions(); err == nil {
+			t.Errorf("Unknown timezone must be rejected")
+		}
+		config.Authz.ConditionsTimezone = ""
+	})
+
+	config.Authz.Conditions = []config.AuthzCondition{
+		{Context: "user:change_login:user", Expression: "subject.organization == '' || subject.organization in target.organizations"},
+		{Context: "user:search_users:user", Expression: "in_cidr(request.ip, '10.0.0.0/8')"},
+	}
+	initTestSnapshot(nil)
+
+	SetOrganizationsResolver(func(UID string) ([]string, *Error.Status) {
+		return []string{"org-1"}, nil
+	})
+	defer SetOrganizationsResolver(nil)
+
+	newAct := func(orgID string, ip string) *ActionDTO.UserTargeted {
+		act := ActionDTO.NewUserTargeted("target", "requester", []string{"admin"})
+		act.OrganizationID = orgID
+		act.RequesterIP = ip
+		return act
+	}
+
+	tests := []struct {
+		name     string
+		authz    func() *Error.Status
+		expected *Error.Status
+	}{
+		{"condition is satisfied", func() *Error.Status {
+			return User.For(newAct("org-1", "")).ChangeUserLogin(false, []string{"admin"})
+		}, nil},
+		{"condition isn't satisfied", func() *Error.Status {
+			return User.For(newAct("org-2", "")).ChangeUserLogin(false, []string{"admin"})
+		}, DeniedByCondition},
+		{"condition isn't evaluated if roles denied action", func() *Error.Status {
+			return User.For(newAct("org-2", "")).ChangeUserLogin(false, []string{"user"})
+		}, InsufficientPermissions},
+		{"condition is evaluated only for its context", func() *Error.Status {
+			return User.For(newAct("org-2", "")).ChangeUserLogin(true, []string{"user"})
+		}, nil},
+		{"request attributes", func() *Error.Status {
+			return User.For(newAct("", "10.1.2.3")).SearchUsers([]string{"admin"})
+		}, nil},
+		{"missing attribute denies action", func() *Error.Status {
+			return User.For(newAct("", "")).SearchUsers([]string{"admin"})
+		}, DeniedByCondition},
+		{"action attributes aren't specified", func() *Error.Status {
+			return User.SearchUsers([]string{"admin"})
+		}, DeniedByCondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.authz(); err != tt.expected {
+				t.Errorf("authorize() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+
+	config.Authz.Conditions = nil
+	initTestSnapshot(nil)
+}
+
+func TestCheck(t *testing.T) {
+	configtest.Init()
+	config.App.ServiceID = "sentinel"
+
+	initTestSnapshot(nil)
+
+	post := rbac.NewEntity("post")
+	edit, err := post.NewAction("edit", rbac.UpdatePermission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	postResource := rbac.NewResource("post")
+
+	editor := rbac.NewRole("editor", rbac.ReadPermission|rbac.UpdatePermission)
+	banned := rbac.NewRole("banned", rbac.ReadPermission|rbac.UpdatePermission)
+
+	otherSchema := rbac.Schema{
+		ID:               "blog",
+		Roles:            []rbac.Role{editor, banned, rbac.NewRole("reader", rbac.ReadPermission)},
+		Entities:         []rbac.Entity{post},
+		Resources:        []rbac.Resource{*postResource},
+		ActionGatePolicy: rbac.NewActionGatePolicy(),
+	}
+	ctx := rbac.NewAuthorizationContext(&post, edit, postResource)
+	if err := otherSchema.ActionGatePolicy.AddRule(
+		rbac.NewActionGateRule(&ctx, rbac.DenyActionGateEffect, []rbac.Role{banned}),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	s := current.Load()
+	s.schema.ID = "sentinel"
+	s.host = &rbac.Host{Schemas: []rbac.Schema{*s.schema, otherSchema}}
+
+	tests := []struct {
+		name     string
+		req      DecisionRequest
+		expected *Decision
+		invalid  bool
+	}{
+		{"own schema allowed", DecisionRequest{"sentinel", "user", "search_users", "user", []string{"moderator"}}, allowed, false},
+		{"own schema insufficient permissions", DecisionRequest{"sentinel", "user", "search_users", "user", []string{"user"}}, deniedByPermissions, false},
+		{"own schema denied by AGP", DecisionRequest{"sentinel", "user", "drop", "cache", []string{"moderator"}}, deniedByActionGatePolicy, false},
+		{"own schema unknown context", DecisionRequest{"sentinel", "user", "unknown", "user", []string{"admin"}}, nil, true},
+		{"other schema allowed", DecisionRequest{"blog", "post", "edit", "post", []string{"editor"}}, allowed, false},
+		{"other schema insufficient permissions", DecisionRequest{"blog", "post", "edit", "post", []string{"reader"}}, deniedByPermissions, false},
+		{"other schema denied by AGP", DecisionRequest{"blog", "post", "edit", "post", []string{"editor", "banned"}}, deniedByActionGatePolicy, false},
+		{"other schema unknown action", DecisionRequest{"blog", "post", "delete", "post", []string{"editor"}}, nil, true},
+		{"other schema unknown resource", DecisionRequest{"blog", "post", "edit", "comment", []string{"editor"}}, nil, true},
+		{"other schema unknown role", DecisionRequest{"blog", "post", "edit", "post", []string{"admin"}}, nil, true},
+		{"unknown schema", DecisionRequest{"unknown", "post", "edit", "post", []string{"editor"}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := Check(&tt.req)
+			if tt.invalid {
+				if err == nil || err.Status() != http.StatusBadRequest {
+					t.Errorf("Check() error = %v, want bad request", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() unexpected error: %v", err)
+			}
+			if decision != tt.expected {
+				t.Errorf("Check() = %+v, want %+v", decision, tt.expected)
+			}
+		})
+	}
+
+	t.Run("decisions are cached regardless of roles order", func(t *testing.T) {
+		if _, ok := s.decisions.get("blog|post:edit:post|banned,editor"); !ok {
+			t.Error("Decision wasn't cached")
+		}
+		req := DecisionRequest{"blog", "post", "edit", "post", []string{"banned", "editor", "banned"}}
+		if decision, _ := Check(&req); decision != deniedByActionGatePolicy {
+			t.Errorf("Check() = %+v, want %+v", decision, deniedByActionGatePolicy)
+		}
+		if n := s.decisions.size.Load(); n != 6 {
+			t.Errorf("Expected 6 cached decisions, got %d", n)
+		}
+	})
+
+	t.Run("conditioned context isn't allowed", func(t *testing.T) {
+		const expression = "in_cidr(request.ip, '10.0.0.0/8')"
+
+		config.Authz.Conditions = []config.AuthzCondition{{Context: "user:search_users:user", Expression: expression}}
+		defer func() { config.Authz.Conditions = nil }()
+
+		initTestSnapshot(nil)
+		current.Load().schema.ID = "sentinel"
+
+		req := DecisionRequest{"sentinel", "user", "search_users", "user", []string{"moderator"}}
+
+		// Second check is served from the cache
+		for range 2 {
+			decision, err := Check(&req)
+			if err != nil {
+				t.Fatalf("Check() unexpected error: %v", err)
+			}
+			if decision.Allowed || decision.Reason != ReasonConditional || decision.Condition != expression {
+				t.Errorf("Check() = %+v, want conditional decision", decision)
+			}
+		}
+
+		req.Roles = []string{"user"}
+		if decision, _ := Check(&req); decision != deniedByPermissions {
+			t.Errorf("Check() = %+v, want %+v", decision, deniedByPermissions)
+		}
+	})
+
+	t.Run("cache is dropped when snapshot is replaced", func(t *testing.T) {
+		initTestSnapshot(nil)
+		if n := current.Load().decisions.size.Load(); n != 0 {
+			t.Errorf("Expected empty cache, got %d decisions", n)
+		}
+	})
+}
+
+func TestExplain(t *testing.T) {
+	initTestSnapshot(ResourcePermissions{
+		"support": {"role": rbac.ReadPermission | rbac.UpdatePermission},
+	})
+	current.Load().schema.ID = "sentinel"
+
+	t.Run("missing permissions", func(t *testing.T) {
+		e, err := Explain(&DecisionRequest{"sentinel", "user", "change_roles", "user", []string{"user", "support"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Allowed || e.Reason != ReasonInsufficientPermissions {
+			t.Errorf("Unexpected decision: %+v", e.Decision)
+		}
+		if e.RequiredPermissions != rbac.UpdatePermission || e.MissingPermissions != rbac.UpdatePermission {
+			t.Errorf("Required = %d, missing = %d, want both %d", e.RequiredPermissions, e.MissingPermissions, rbac.UpdatePermission)
+		}
+		if len(e.MissingPermissionsNames) != 1 || e.MissingPermissionsNames[0] != "update" {
+			t.Errorf("Missing permissions names = %v, want [update]", e.MissingPermissionsNames)
+		}
+		if e.Rule != nil || e.RuleFired {
+			t.Errorf("Unexpected rule: %+v", e.Rule)
+		}
+	})
+
+	t.Run("overridden permissions and AGP", func(t *testing.T) {
+		e, err := Explain(&DecisionRequest{"sentinel", "user", "update_role", "role", []string{"support"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Allowed || e.Reason != ReasonDeniedByAGP {
+			t.Errorf("Unexpected decision: %+v", e.Decision)
+		}
+		if !e.Roles[0].Overridden || e.MissingPermissions != 0 {
+			t.Errorf("Unexpected roles explanation: %+v, missing = %d", e.Roles, e.MissingPermissions)
+		}
+		if e.Rule == nil || !e.RuleFired || e.Rule.Source != BuiltinRuleSource {
+			t.Errorf("Unexpected rule: %+v, fired = %v", e.Rule, e.RuleFired)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		post := rbac.NewEntity("post")
+		edit, _ := post.NewAction("edit", rbac.UpdatePermission)
+		postResource := rbac.NewResource("post")
+		editor := rbac.NewRole("editor", rbac.UpdatePermission)
+
+		schema := rbac.Schema{
+			ID:               "blog",
+			Roles:            []rbac.Role{editor},
+			Entities:         []rbac.Entity{post},
+			Resources:        []rbac.Resource{*postResource},
+			ActionGatePolicy: rbac.NewActionGatePolicy(),
+		}
+		ctx := rbac.NewAuthorizationContext(&post, edit, postResource)
+		schema.ActionGatePolicy.AddRule(rbac.NewActionGateRule(&ctx, rbac.AllowActionGateEffect, []rbac.Role{editor}))
+
+		e, err := DryRun(&rbac.Host{Schemas: []rbac.Schema{schema}}, "", &DecisionRequest{"blog", "post", "edit", "post", []string{"editor"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !e.Allowed || !e.RuleFired || e.Rule.Source != SchemaRuleSource {
+			t.Errorf("Unexpected explanation: %+v", e)
+		}
+		// Current state must not be affected
+		if GetSchema().ID != "sentinel" {
+			t.Error("Dry run has changed current schema")
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package authz
+
+import (
+	"errors"
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/auth/authz/policy"
+	"slices"
+	"time"
+)
+
+// Attributes which can be used in conditions.
+// Attributes of the target are available only for actions targeted at some user,
+// request IP is available only for actions performed via HTTP API.
+const (
+	SubjectIDAttribute           = "subject.id"
+	SubjectRolesAttribute        = "subject.roles"
+	SubjectOrganizationAttribute = "subject.organization"
+	SubjectImpersonatedAttribute = "subject.impersonated"
+	TargetIDAttribute            = "target.id"
+	TargetSelfAttribute          = "target.self"
+	TargetOrganizationsAttribute = "target.organizations"
+	RequestIPAttribute           = "request.ip"
+	// 0-23
+	RequestHourAttribute = "request.hour"
+	// 1 (Monday) - 7 (Sunday)
+	RequestWeekdayAttribute = "request.weekday"
+)
+
+var knownAttributes = []string{
+	SubjectIDAttribute,
+	SubjectRolesAttribute,
+	SubjectOrganizationAttribute,
+	SubjectImpersonatedAttribute,
+	TargetIDAttribute,
+	TargetSelfAttribute,
+	TargetOrganizationsAttribute,
+	RequestIPAttribute,
+	RequestHourAttribute,
+	RequestWeekdayAttribute,
+}
+
+var DeniedByCondition = Error.NewStatusError(
+	"Authorization has been denied by policy condition",
+	http.StatusForbidden,
+)
+
+// Returns IDs of organizations in which user with the given ID is a member.
+type OrganizationsResolver func(UID string) ([]string, *Error.Status)
+
+// Authz can't depend on DB, so organizations are resolved via function set on DB initialization.
+var organizationsResolver OrganizationsResolver
+
+// Sets function which is used to resolve "target.organizations" attribute.
+// Until it's set, conditions referring to this attribute will deny all actions.
+func SetOrganizationsResolver(resolver OrganizationsResolver) {
+	organizationsResolver = resolver
+}
+
+// Returns attributes of the given action which are available for conditions.
+func actionAttributes(act ActionDTO.Any) policy.Attributes {
+	var basic *ActionDTO.Basic
+	var targeted *ActionDTO.UserTargeted
+
+	switch a := act.(type) {
+	case *ActionDTO.Basic:
+		basic = a
+	case *ActionDTO.UserTargeted:
+		basic, targeted = &a.Basic, a
+	default:
+		return policy.Attributes{}
+	}
+
+	attributes := policy.Attributes{
+		SubjectIDAttribute:           basic.RequesterUID,
+		SubjectRolesAttribute:        basic.RequesterRoles,
+		SubjectOrganizationAttribute: basic.OrganizationID,
+		SubjectImpersonatedAttribute: basic.IsImpersonated(),
+	}
+
+	if basic.RequesterIP != "" {
+		attributes[RequestIPAttribute] = basic.RequesterIP
+	}
+
+	if targeted != nil {
+		targetUID := targeted.TargetUID
+
+		attributes[TargetIDAttribute] = targetUID
+		attributes[TargetSelfAttribute] = targetUID == basic.RequesterUID
+		attributes[TargetOrganizationsAttribute] = policy.Lazy(func() (any, error) {
+			if organizationsResolver == nil {
+				return nil, errors.New("organizations resolver isn't set")
+			}
+			organizations, err := organizationsResolver(targetUID)
+			if err != nil {
+				return nil, err
+			}
+			return organizations, nil
+		})
+	}
+
+	return attributes
+}
+
+// Compiles conditions from config.
+// Each condition must refer to the registered context (see initContexts) and to the known attributes.
+func newConditions() (map[string]*policy.Expression, *time.Location, error) {
+	log.Info("Initializing conditions...", nil)
+
+	var configConditions []config.AuthzCondition
+	timezone := ""
+	if config.Authz != nil {
+		configConditions = config.Authz.Conditions
+		timezone = config.Authz.ConditionsTimezone
+	}
+
+	location := time.UTC
+	if timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(timezone); err != nil {
+			log.Error("Failed to initialize conditions", err.Error(), nil)
+			return nil, nil, err
+		}
+	}
+
+	conditions := make(map[string]*policy.Expression, len(configConditions))
+
+	for _, condition := range configConditions {
+		if _, ok := registeredContexts[condition.Context]; !ok {
+			err := errors.New("condition refers to unknown context " + condition.Context)
+			log.Error("Failed to initialize conditions", err.Error(), nil)
+			return nil, nil, err
+		}
+		if _, ok := conditions[condition.Context]; ok {
+			err := errors.New("condition for context " + condition.Context + " is defined more than once")
+			log.Error("Failed to initialize conditions", err.Error(), nil)
+			return nil, nil, err
+		}
+
+		expr, err := policy.Compile(condition.Expression)
+		if err != nil {
+			err = errors.New("condition for context " + condition.Context + " is invalid: " + err.Error())
+			log.Error("Failed to initialize conditions", err.Error(), nil)
+			return nil, nil, err
+		}
+
+		for _, attribute := range expr.Attributes() {
+			if !slices.Contains(knownAttributes, attribute) {
+				err := errors.New("condition for context " + condition.Context + " refers to unknown attribute " + attribute)
+				log.Error("Failed to initialize conditions", err.Error(), nil)
+				return nil, nil, err
+			}
+		}
+
+		conditions[condition.Context] = expr
+	}
+
+	log.Info("Initializing conditions: OK", nil)
+
+	return conditions, location, nil
+}
+
+// Evaluates condition of the given context (if there is any).
+// Condition which can't be evaluated (e.g. due to missing attribute) denies the action.
+func (s *snapshot) checkCondition(ctxString string, attributes policy.Attributes) *Error.Status {
+	condition, ok := s.conditions[ctxString]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now().In(s.location)
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+
+	attrs := make(policy.Attributes, len(attributes)+2)
+	for name, value := range attributes {
+		attrs[name] = value
+	}
+	attrs[RequestHourAttribute] = now.Hour()
+	attrs[RequestWeekdayAttribute] = weekday
+
+	allowed, err := condition.Evaluate(attrs)
+	if err != nil {
+		log.Error("Failed to evaluate condition of "+ctxString, err.Error(), nil)
+		return DeniedByCondition
+	}
+	if !allowed {
+		log.Error("Failed to authorize "+ctxString, "Condition isn't satisfied: "+condition.String(), nil)
+		return DeniedByCondition
+	}
+
+	return nil
+}
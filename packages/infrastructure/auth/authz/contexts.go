@@ -4,6 +4,9 @@ import (
 	rbac "github.com/abaxoth0/SentinelRBAC"
 )
 
+// All contexts created via newAuthzContext, key is context string (see stringFromContext).
+var registeredContexts = make(map[string]rbac.AuthorizationContext)
+
 func newAuthzContext(
 	entity *rbac.Entity,
 	actionName string,
@@ -20,33 +23,61 @@ func newAuthzContext(
 		}
 	}
 
-	return rbac.NewAuthorizationContext(entity, act, resource)
+	ctx := rbac.NewAuthorizationContext(entity, act, resource)
+
+	registeredContexts[stringFromContext(&ctx)] = ctx
+
+	return ctx
 }
 
 var (
-	userSoftDeleteUserContext          rbac.AuthorizationContext
-	userSoftDeleteSelfContext          rbac.AuthorizationContext
-	userRestoreUserContext             rbac.AuthorizationContext
-	userDropUserContext                rbac.AuthorizationContext
-	userDropAllSoftDeletedUsersContext rbac.AuthorizationContext
-	userChangeUserLoginContext         rbac.AuthorizationContext
-	userChangeSelfLoginContext         rbac.AuthorizationContext
-	userChangeUserPasswordContext      rbac.AuthorizationContext
-	userChangeSelfPasswordContext      rbac.AuthorizationContext
-	userChangeUserRolesContext         rbac.AuthorizationContext
-	userChangeSelfRolesContext         rbac.AuthorizationContext
-	userGetUserRolesContext            rbac.AuthorizationContext
-	userSearchUsersContext             rbac.AuthorizationContext
-	userLogoutUserContext              rbac.AuthorizationContext
-	userGetSessionContext              rbac.AuthorizationContext
-	userGetSelfSessionContext          rbac.AuthorizationContext
-	userAccessAPIDocsContext           rbac.AuthorizationContext
-	userGetSessionLocationContext      rbac.AuthorizationContext
-	userDeleteLocationContext          rbac.AuthorizationContext
-	userGetUserContext                 rbac.AuthorizationContext
-	userGetSelfContext                 rbac.AuthorizationContext
-	userIntrospectOAuthTokenContext    rbac.AuthorizationContext
-	userDropCacheContext               rbac.AuthorizationContext
+	userSoftDeleteUserContext             rbac.AuthorizationContext
+	userSoftDeleteSelfContext             rbac.AuthorizationContext
+	userRestoreUserContext                rbac.AuthorizationContext
+	userDropUserContext                   rbac.AuthorizationContext
+	userDropAllSoftDeletedUsersContext    rbac.AuthorizationContext
+	userChangeUserLoginContext            rbac.AuthorizationContext
+	userChangeSelfLoginContext            rbac.AuthorizationContext
+	userChangeUserPasswordContext         rbac.AuthorizationContext
+	userChangeSelfPasswordContext         rbac.AuthorizationContext
+	userChangeUserRolesContext            rbac.AuthorizationContext
+	userChangeSelfRolesContext            rbac.AuthorizationContext
+	userGetUserRolesContext               rbac.AuthorizationContext
+	userSearchUsersContext                rbac.AuthorizationContext
+	userLogoutUserContext                 rbac.AuthorizationContext
+	userGetSessionContext                 rbac.AuthorizationContext
+	userGetSelfSessionContext             rbac.AuthorizationContext
+	userGetLoginHistoryContext            rbac.AuthorizationContext
+	userGetSelfLoginHistoryContext        rbac.AuthorizationContext
+	userGetLoginChangeRequestsContext     rbac.AuthorizationContext
+	userGetSelfLoginChangeRequestsContext rbac.AuthorizationContext
+	userAccessAPIDocsContext              rbac.AuthorizationContext
+	userGetSessionLocationContext         rbac.AuthorizationContext
+	userDeleteLocationContext             rbac.AuthorizationContext
+	userGetUserContext                    rbac.AuthorizationContext
+	userGetSelfContext                    rbac.AuthorizationContext
+	userIntrospectOAuthTokenContext       rbac.AuthorizationContext
+	userDropCacheContext                  rbac.AuthorizationContext
+	userSubscribeToRevocationsContext     rbac.AuthorizationContext
+	userImpersonateUserContext            rbac.AuthorizationContext
+	userCreateRoleContext                 rbac.AuthorizationContext
+	userGetRoleContext                    rbac.AuthorizationContext
+	userUpdateRoleContext                 rbac.AuthorizationContext
+	userDeleteRoleContext                 rbac.AuthorizationContext
+	userReloadRBACContext                 rbac.AuthorizationContext
+	userGetActionGatePolicyContext        rbac.AuthorizationContext
+	userCheckAuthorizationContext         rbac.AuthorizationContext
+	userExplainAuthorizationContext       rbac.AuthorizationContext
+	userCreateOrganizationContext         rbac.AuthorizationContext
+	userGetOrganizationContext            rbac.AuthorizationContext
+	userDeleteOrganizationContext         rbac.AuthorizationContext
+	userGetOrganizationMembersContext     rbac.AuthorizationContext
+	userChangeOrganizationMembersContext  rbac.AuthorizationContext
+	userCreateGroupContext                rbac.AuthorizationContext
+	userGetGroupContext                   rbac.AuthorizationContext
+	userUpdateGroupContext                rbac.AuthorizationContext
+	userDeleteGroupContext                rbac.AuthorizationContext
+	userGetSchedulerJobsContext           rbac.AuthorizationContext
 )
 
 func initContexts() {
@@ -164,6 +195,34 @@ func initContexts() {
 		sessionResource,
 	)
 
+	userGetLoginHistoryContext = newAuthzContext(
+		&userEntity,
+		"get_login_history",
+		rbac.ReadPermission,
+		sessionResource,
+	)
+
+	userGetSelfLoginHistoryContext = newAuthzContext(
+		&userEntity,
+		"get_self_login_history",
+		rbac.SelfReadPermission,
+		sessionResource,
+	)
+
+	userGetLoginChangeRequestsContext = newAuthzContext(
+		&userEntity,
+		"get_login_change_requests",
+		rbac.ReadPermission,
+		userResource,
+	)
+
+	userGetSelfLoginChangeRequestsContext = newAuthzContext(
+		&userEntity,
+		"get_self_login_change_requests",
+		rbac.SelfReadPermission,
+		userResource,
+	)
+
 	userAccessAPIDocsContext = newAuthzContext(
 		&userEntity,
 		"access_api_docs",
@@ -213,5 +272,145 @@ func initContexts() {
 		cacheResource,
 	)
 
+	userSubscribeToRevocationsContext = newAuthzContext(
+		&userEntity,
+		"subscribe_to_revocations",
+		rbac.ReadPermission,
+		sessionResource,
+	)
+
+	userImpersonateUserContext = newAuthzContext(
+		&userEntity,
+		"impersonate",
+		rbac.ReadPermission,
+		userResource,
+	)
+
+	userCreateRoleContext = newAuthzContext(
+		&userEntity,
+		"create_role",
+		rbac.CreatePermission,
+		roleResource,
+	)
+
+	userGetRoleContext = newAuthzContext(
+		&userEntity,
+		"get_role",
+		rbac.ReadPermission,
+		roleResource,
+	)
+
+	userUpdateRoleContext = newAuthzContext(
+		&userEntity,
+		"update_role",
+		rbac.UpdatePermission,
+		roleResource,
+	)
+
+	userDeleteRoleContext = newAuthzContext(
+		&userEntity,
+		"delete_role",
+		rbac.DeletePermission,
+		roleResource,
+	)
+
+	userReloadRBACContext = newAuthzContext(
+		&userEntity,
+		"reload_rbac",
+		rbac.UpdatePermission,
+		roleResource,
+	)
+
+	userGetActionGatePolicyContext = newAuthzContext(
+		&userEntity,
+		"get_action_gate_policy",
+		rbac.ReadPermission,
+		roleResource,
+	)
+
+	userCheckAuthorizationContext = newAuthzContext(
+		&userEntity,
+		"check_authorization",
+		rbac.ReadPermission,
+		authzResource,
+	)
+
+	userExplainAuthorizationContext = newAuthzContext(
+		&userEntity,
+		"explain_authorization",
+		rbac.ReadPermission,
+		authzResource,
+	)
+
+	userCreateOrganizationContext = newAuthzContext(
+		&userEntity,
+		"create_organization",
+		rbac.CreatePermission,
+		organizationResource,
+	)
+
+	userGetOrganizationContext = newAuthzContext(
+		&userEntity,
+		"get_organization",
+		rbac.ReadPermission,
+		organizationResource,
+	)
+
+	userDeleteOrganizationContext = newAuthzContext(
+		&userEntity,
+		"delete_organization",
+		rbac.DeletePermission,
+		organizationResource,
+	)
+
+	userGetOrganizationMembersContext = newAuthzContext(
+		&userEntity,
+		"get_organization_members",
+		rbac.ReadPermission,
+		organizationResource,
+	)
+
+	userChangeOrganizationMembersContext = newAuthzContext(
+		&userEntity,
+		"change_organization_members",
+		rbac.UpdatePermission,
+		organizationResource,
+	)
+
+	userCreateGroupContext = newAuthzContext(
+		&userEntity,
+		"create_group",
+		rbac.CreatePermission,
+		groupResource,
+	)
+
+	userGetGroupContext = newAuthzContext(
+		&userEntity,
+		"get_group",
+		rbac.ReadPermission,
+		groupResource,
+	)
+
+	userUpdateGroupContext = newAuthzContext(
+		&userEntity,
+		"update_group",
+		rbac.UpdatePermission,
+		groupResource,
+	)
+
+	userDeleteGroupContext = newAuthzContext(
+		&userEntity,
+		"delete_group",
+		rbac.DeletePermission,
+		groupResource,
+	)
+
+	userGetSchedulerJobsContext = newAuthzContext(
+		&userEntity,
+		"get_scheduler_jobs",
+		rbac.ReadPermission,
+		schedulerResource,
+	)
+
 	log.Info("Initializing contexts: OK", nil)
 }
@@ -0,0 +1,210 @@
+package authz
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+const (
+	ReasonInsufficientPermissions = "insufficient-permissions"
+	ReasonDeniedByAGP             = "denied-by-agp"
+	ReasonConditional             = "conditional"
+)
+
+// Result of the authorization check.
+type Decision struct {
+	Allowed bool `json:"allowed" example:"false"`
+	// Why authorization was denied, empty if it was allowed.
+	// Either "insufficient-permissions", "denied-by-agp" or "conditional".
+	Reason string `json:"reason,omitempty" example:"insufficient-permissions"`
+	// Attribute-based condition of the context, omitted if there is no such condition.
+	// Roles alone can't satisfy it, so if roles are sufficient, then decision isn't allowed,
+	// instead its reason is "conditional": action is allowed only if request attributes satisfy this condition.
+	Condition string `json:"condition,omitempty" example:"target.self || subject.organization in target.organizations"`
+}
+
+var (
+	allowed                  = &Decision{Allowed: true}
+	deniedByPermissions      = &Decision{Allowed: false, Reason: ReasonInsufficientPermissions}
+	deniedByActionGatePolicy = &Decision{Allowed: false, Reason: ReasonDeniedByAGP}
+)
+
+type DecisionRequest struct {
+	ServiceID string
+	Entity    string
+	Action    string
+	Resource  string
+	Roles     []string
+}
+
+func (r *DecisionRequest) key(roles []string) string {
+	return r.ServiceID + "|" + r.Entity + ":" + r.Action + ":" + r.Resource + "|" + strings.Join(roles, ",")
+}
+
+// Max amount of decisions which can be cached per snapshot.
+// When limit is reached new decisions just won't be cached until next snapshot is applied.
+const decisionCacheSize = 10_000
+
+type decisionCache struct {
+	m    sync.Map
+	size atomic.Int64
+}
+
+func (c *decisionCache) get(key string) (*Decision, bool) {
+	v, ok := c.m.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return v.(*Decision), true
+}
+
+func (c *decisionCache) put(key string, decision *Decision) {
+	if c.size.Load() >= decisionCacheSize {
+		return
+	}
+	if _, loaded := c.m.LoadOrStore(key, decision); !loaded {
+		c.size.Add(1)
+	}
+}
+
+// Returns sorted copy of the given roles names without duplicates.
+func normalizeRolesNames(rolesNames []string) []string {
+	roles := slices.Clone(rolesNames)
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+func invalidDecisionRequest(msg string) *Error.Status {
+	return Error.NewStatusError(msg, http.StatusBadRequest)
+}
+
+// Evaluates authorization decision for the specified roles of any service schema.
+// Unlike authorization of Sentinel's own operations, denial isn't an error:
+// error is returned only if request itself is invalid (e.g. unknown service, entity, action or role).
+// Conditions aren't evaluated, since request has no attributes, so contexts with a condition are never allowed.
+func Check(req *DecisionRequest) (*Decision, *Error.Status) {
+	// Snapshot may be replaced concurrently, so it must be loaded only once
+	s := current.Load()
+
+	// Order and duplicates doesn't affect decision, so they shouldn't affect cache key either
+	roles := normalizeRolesNames(req.Roles)
+
+	key := req.key(roles)
+
+	if decision, ok := s.decisions.get(key); ok {
+		return decision, nil
+	}
+
+	decision, err := s.check(req, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	s.decisions.put(key, decision)
+
+	return decision, nil
+}
+
+// Authorization context, roles and AGP resolved from the decision request.
+type resolvedRequest struct {
+	ctx   rbac.AuthorizationContext
+	roles []rbac.Role
+	agp   *rbac.ActionGatePolicy
+	// Is context belongs to the schema of this service
+	own bool
+}
+
+func (s *snapshot) resolve(req *DecisionRequest, rolesNames []string) (*resolvedRequest, *Error.Status) {
+	if s.schema != nil && req.ServiceID == s.schema.ID {
+		ctx, ok := registeredContexts[req.Entity+":"+req.Action+":"+req.Resource]
+		if !ok {
+			return nil, invalidDecisionRequest(
+				"Unknown authorization context: " + req.Entity + ":" + req.Action + ":" + req.Resource,
+			)
+		}
+
+		roles, err := s.getRoles(rolesNames)
+		if err != nil {
+			return nil, err
+		}
+
+		s.applyResourcePermissions(roles, ctx.Resource)
+
+		return &resolvedRequest{ctx: ctx, roles: roles, agp: s.agp, own: true}, nil
+	}
+
+	schema, e := s.host.GetSchema(req.ServiceID)
+	if e != nil {
+		return nil, invalidDecisionRequest(e.Message)
+	}
+
+	ctx, err := contextFromSchema(schema, req)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := findRoles(schema, rolesNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resolvedRequest{ctx: ctx, roles: roles, agp: &schema.ActionGatePolicy}, nil
+}
+
+func (s *snapshot) check(req *DecisionRequest, rolesNames []string) (*Decision, *Error.Status) {
+	r, err := s.resolve(req, rolesNames)
+	if err != nil {
+		return nil, err
+	}
+	return s.decide(r)
+}
+
+func (s *snapshot) decide(r *resolvedRequest) (*Decision, *Error.Status) {
+	switch err := rbac.Authorize(&r.ctx, r.roles, r.agp); err {
+	case nil:
+		if condition, ok := s.conditions[stringFromContext(&r.ctx)]; ok && r.own {
+			return &Decision{Allowed: false, Reason: ReasonConditional, Condition: condition.String()}, nil
+		}
+		return allowed, nil
+	case rbac.InsufficientPermissions:
+		return deniedByPermissions, nil
+	case rbac.ActionDeniedByAGP:
+		return deniedByActionGatePolicy, nil
+	default:
+		return nil, invalidDecisionRequest(err.Error())
+	}
+}
+
+// Builds authorization context from entities and resources declared in the given schema.
+func contextFromSchema(schema *rbac.Schema, req *DecisionRequest) (rbac.AuthorizationContext, *Error.Status) {
+	entityIdx := slices.IndexFunc(schema.Entities, func(e rbac.Entity) bool {
+		return e.Name() == req.Entity
+	})
+	if entityIdx == -1 {
+		return rbac.AuthorizationContext{}, invalidDecisionRequest("Entity " + req.Entity + " doesn't exist")
+	}
+
+	entity := &schema.Entities[entityIdx]
+	action := rbac.Action(req.Action)
+
+	if !entity.HasAction(action) {
+		return rbac.AuthorizationContext{}, invalidDecisionRequest(
+			"Entity " + req.Entity + " doesn't have action " + req.Action,
+		)
+	}
+
+	resourceIdx := slices.IndexFunc(schema.Resources, func(r rbac.Resource) bool {
+		return r.Name() == req.Resource
+	})
+	if resourceIdx == -1 {
+		return rbac.AuthorizationContext{}, invalidDecisionRequest("Resource " + req.Resource + " doesn't exist")
+	}
+
+	return rbac.NewAuthorizationContext(entity, action, &schema.Resources[resourceIdx]), nil
+}
@@ -0,0 +1,177 @@
+package authz
+
+import (
+	Error "sentinel/packages/common/errors"
+	"slices"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+type RoleExplanation struct {
+	Name string `json:"name" example:"moderator"`
+	// Permissions which role grants for the resource of the context
+	Permissions uint16 `json:"permissions" example:"84"`
+	// Names of these permissions
+	PermissionsNames []string `json:"permissions-names" example:"read,update,delete"`
+	// Is role permissions were overridden for the resource of the context
+	Overridden bool `json:"overridden,omitempty" example:"false"`
+}
+
+// Detailed description of how authorization decision was made.
+type Explanation struct {
+	Decision
+	// "<entity>:<action>:<resource>"
+	Context string            `json:"context" example:"user:drop:cache"`
+	Roles   []RoleExplanation `json:"roles"`
+	// Permissions required by the action of the context
+	RequiredPermissions uint16 `json:"required-permissions" example:"64"`
+	// Union of permissions of all roles
+	GrantedPermissions uint16 `json:"granted-permissions" example:"84"`
+	// Required permissions which weren't granted by any role
+	MissingPermissions      uint16   `json:"missing-permissions" example:"0"`
+	MissingPermissionsNames []string `json:"missing-permissions-names"`
+	// Action Gate Policy rule for the context, omitted if there is no such rule
+	Rule *ActionGateRule `json:"rule,omitempty"`
+	// Is rule affected the decision: denied it or authorized action bypassing permissions check
+	RuleFired bool `json:"rule-fired" example:"true"`
+}
+
+var permissionsNames = []struct {
+	permission rbac.Permissions
+	name       string
+}{
+	{rbac.CreatePermission, "create"},
+	{rbac.SelfCreatePermission, "self-create"},
+	{rbac.ReadPermission, "read"},
+	{rbac.SelfReadPermission, "self-read"},
+	{rbac.UpdatePermission, "update"},
+	{rbac.SelfUpdatePermission, "self-update"},
+	{rbac.DeletePermission, "delete"},
+	{rbac.SelfDeletePermission, "self-delete"},
+}
+
+// Returns names of the permissions set in the given bitmask (same as in RBAC configuration file).
+func PermissionsNames(permissions rbac.Permissions) []string {
+	names := []string{}
+	for _, p := range permissionsNames {
+		if permissions&p.permission != 0 {
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
+
+// Same as Check, but instead of just a decision describes how it was made.
+// Explanations aren't cached.
+func Explain(req *DecisionRequest) (*Explanation, *Error.Status) {
+	return current.Load().explain(req)
+}
+
+// Explains decision using the given RBAC host instead of the one which is currently in use.
+// Contexts of Sentinel itself are available only if selfID (ID of Sentinel schema in the host) is specified,
+// in that case they are evaluated with built-in Action Gate Policy rules and rules from config (if it's loaded).
+// Doesn't affect current RBAC state.
+func DryRun(host *rbac.Host, selfID string, req *DecisionRequest) (*Explanation, *Error.Status) {
+	s := &snapshot{host: host}
+
+	if selfID != "" {
+		schema, err := host.GetSchema(selfID)
+		if err != nil {
+			return nil, invalidDecisionRequest(err.Message)
+		}
+
+		// Contexts are initialized on Init, which isn't called when Sentinel is used as CLI tool
+		if len(registeredContexts) == 0 {
+			initResources()
+			initContexts()
+		}
+
+		agp, rules, e := newAGP(schema)
+		if e != nil {
+			return nil, invalidDecisionRequest(e.Error())
+		}
+
+		conditions, location, e := newConditions()
+		if e != nil {
+			return nil, invalidDecisionRequest(e.Error())
+		}
+
+		s.schema, s.agp, s.agpRules = schema, agp, rules
+		s.conditions, s.location = conditions, location
+	}
+
+	return s.explain(req)
+}
+
+func (s *snapshot) explain(req *DecisionRequest) (*Explanation, *Error.Status) {
+	r, err := s.resolve(req, normalizeRolesNames(req.Roles))
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := s.decide(r)
+	if err != nil {
+		return nil, err
+	}
+
+	required, _ := r.ctx.Entity.GetRequiredActionPermissions(r.ctx.Action)
+
+	explanation := &Explanation{
+		Decision:            *decision,
+		Context:             stringFromContext(&r.ctx),
+		Roles:               make([]RoleExplanation, len(r.roles)),
+		RequiredPermissions: required,
+	}
+
+	for i, role := range r.roles {
+		_, overridden := s.resourcePermissions[role.Name][r.ctx.Resource.Name()]
+
+		explanation.Roles[i] = RoleExplanation{
+			Name:             role.Name,
+			Permissions:      role.Permissions,
+			PermissionsNames: PermissionsNames(role.Permissions),
+			Overridden:       r.own && overridden,
+		}
+		explanation.GrantedPermissions |= role.Permissions
+	}
+
+	explanation.MissingPermissions = required &^ explanation.GrantedPermissions
+	explanation.MissingPermissionsNames = PermissionsNames(explanation.MissingPermissions)
+
+	if rule, ok := r.agp.GetRule(&r.ctx); ok {
+		bypass, e := rule.Apply(r.ctx.Action, r.roles)
+		explanation.RuleFired = bypass || e != nil
+		explanation.Rule = s.describeRule(r, rule)
+	}
+
+	if condition, ok := s.conditions[explanation.Context]; ok && r.own {
+		explanation.Condition = condition.String()
+	}
+
+	return explanation, nil
+}
+
+func (s *snapshot) describeRule(r *resolvedRequest, rule *rbac.ActionGateRule) *ActionGateRule {
+	ctxString := stringFromContext(&r.ctx)
+
+	if r.own {
+		idx := slices.IndexFunc(s.agpRules, func(rule ActionGateRule) bool {
+			return rule.Context == ctxString
+		})
+		if idx != -1 {
+			return &s.agpRules[idx]
+		}
+	}
+
+	roles := make([]string, len(rule.Roles))
+	for i, role := range rule.Roles {
+		roles[i] = role.Name
+	}
+
+	return &ActionGateRule{
+		Context: ctxString,
+		Effect:  string(rule.Effect),
+		Roles:   roles,
+		Source:  SchemaRuleSource,
+	}
+}
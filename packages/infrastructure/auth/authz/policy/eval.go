@@ -0,0 +1,321 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+type evaluation struct {
+	attributes Attributes
+	// Values of already resolved lazy attributes
+	resolved map[string]any
+}
+
+func (e *evaluation) attribute(name string) (any, error) {
+	if value, ok := e.resolved[name]; ok {
+		return value, nil
+	}
+
+	raw, ok := e.attributes[name]
+	if !ok || raw == nil {
+		return nil, fmt.Errorf("attribute %s is not available", name)
+	}
+
+	if lazy, ok := raw.(Lazy); ok {
+		var err error
+		if raw, err = lazy(); err != nil {
+			return nil, fmt.Errorf("failed to resolve attribute %s: %w", name, err)
+		}
+	}
+
+	value, err := normalize(raw)
+	if err != nil {
+		return nil, fmt.Errorf("attribute %s: %w", name, err)
+	}
+
+	e.resolved[name] = value
+
+	return value, nil
+}
+
+// Converts value to one of the types which are used during evaluation:
+// string, float64, bool or []any.
+func normalize(value any) (any, error) {
+	switch v := value.(type) {
+	case string, float64, bool:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case []string:
+		list := make([]any, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list, nil
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			n, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = n
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", value)
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case []any:
+		return "list"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+type node interface {
+	eval(e *evaluation) (any, error)
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n *literalNode) eval(_ *evaluation) (any, error) {
+	return n.value, nil
+}
+
+type attributeNode struct {
+	name string
+}
+
+func (n *attributeNode) eval(e *evaluation) (any, error) {
+	return e.attribute(n.name)
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) eval(e *evaluation) (any, error) {
+	list := make([]any, len(n.items))
+	for i, item := range n.items {
+		value, err := item.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = value
+	}
+	return list, nil
+}
+
+func evalBool(n node, e *evaluation) (bool, error) {
+	value, err := n.eval(e)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected bool, got %s", typeName(value))
+	}
+	return b, nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(e *evaluation) (any, error) {
+	b, err := evalBool(n.operand, e)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type logicalNode struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n *logicalNode) eval(e *evaluation) (any, error) {
+	left, err := evalBool(n.left, e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short-circuit, so right operand may refer to attributes which are available only in some cases
+	if (n.op == "&&" && !left) || (n.op == "||" && left) {
+		return left, nil
+	}
+
+	return evalBool(n.right, e)
+}
+
+type comparisonNode struct {
+	op    string
+	left  node
+	right node
+}
+
+func equal(a any, b any) (bool, error) {
+	if typeName(a) != typeName(b) {
+		return false, fmt.Errorf("can't compare %s with %s", typeName(a), typeName(b))
+	}
+	if _, ok := a.([]any); ok {
+		return false, fmt.Errorf("lists can't be compared")
+	}
+	return a == b, nil
+}
+
+// Returns -1, 0 or 1. Only numbers and strings can be ordered.
+func order(a any, b any) (int, error) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1, nil
+			case x > y:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+	return 0, fmt.Errorf("can't order %s and %s", typeName(a), typeName(b))
+}
+
+func (n *comparisonNode) eval(e *evaluation) (any, error) {
+	left, err := n.left.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		eq, err := equal(left, right)
+		return !eq, err
+	case "in":
+		list, ok := right.([]any)
+		if !ok {
+			return nil, fmt.Errorf("right operand of \"in\" must be list, got %s", typeName(right))
+		}
+		for _, item := range list {
+			// List may contain values of different types, so mismatch isn't an error here
+			if typeName(item) == typeName(left) && item == left {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	cmp, err := order(left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default: // ">="
+		return cmp >= 0, nil
+	}
+}
+
+type function func(args []any) (any, error)
+
+// Built-in functions which can be called in expressions
+var functions = map[string]function{
+	// in_cidr(ip, range...) - is IP address in any of the specified CIDR ranges,
+	// each range can be either string or list of strings.
+	"in_cidr": inCIDR,
+}
+
+func inCIDR(args []any) (any, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("in_cidr expects IP and at least one CIDR range")
+	}
+
+	rawIP, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("in_cidr expects IP to be string, got %s", typeName(args[0]))
+	}
+	ip := net.ParseIP(rawIP)
+	if ip == nil {
+		return nil, fmt.Errorf("in_cidr: invalid IP address %q", rawIP)
+	}
+
+	ranges := []any{}
+	for _, arg := range args[1:] {
+		if list, ok := arg.([]any); ok {
+			ranges = append(ranges, list...)
+		} else {
+			ranges = append(ranges, arg)
+		}
+	}
+
+	for _, r := range ranges {
+		rawRange, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("in_cidr expects CIDR range to be string, got %s", typeName(r))
+		}
+		_, network, err := net.ParseCIDR(rawRange)
+		if err != nil {
+			return nil, fmt.Errorf("in_cidr: invalid CIDR range %q", rawRange)
+		}
+		if network.Contains(ip) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type callNode struct {
+	name string
+	fn   function
+	args []node
+}
+
+func (n *callNode) eval(e *evaluation) (any, error) {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	return n.fn(args)
+}
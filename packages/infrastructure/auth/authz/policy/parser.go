@@ -0,0 +1,207 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Grammar (from the lowest precedence to the highest):
+//
+//	or         = and { "||" and }
+//	and        = not { "&&" not }
+//	not        = "!" not | comparison
+//	comparison = operand [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" ) operand ]
+//	operand    = string | number | "true" | "false" | list | call | attribute | "(" or ")"
+//	list       = "[" [ or { "," or } ] "]"
+//	call       = name "(" [ or { "," or } ] ")"
+type parser struct {
+	tokens []token
+	pos    int
+	// Names of all attributes referred by the expression
+	attributes []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != eofToken {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) is(kind tokenKind, value string) bool {
+	t := p.peek()
+	return t.kind == kind && t.value == value
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.is(kind, value) {
+		return p.unexpected()
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == eofToken {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", t.value, t.pos)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(operatorToken, "||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(operatorToken, "&&") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.is(operatorToken, "!") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	isComparison := t.kind == operatorToken && t.value != "&&" && t.value != "||" && t.value != "!"
+	if !isComparison && !(t.kind == identToken && t.value == "in") {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &comparisonNode{op: t.value, left: left, right: right}, nil
+}
+
+// Parses comma-separated expressions until the closing punctuation.
+func (p *parser) parseSequence(closing string) ([]node, error) {
+	nodes := []node{}
+
+	if p.is(punctToken, closing) {
+		p.next()
+		return nodes, nil
+	}
+
+	for {
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+
+		if p.is(punctToken, ",") {
+			p.next()
+			continue
+		}
+		if err := p.expect(punctToken, closing); err != nil {
+			return nil, err
+		}
+		return nodes, nil
+	}
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.peek()
+
+	switch t.kind {
+	case stringToken:
+		p.next()
+		return &literalNode{value: t.value}, nil
+	case numberToken:
+		p.next()
+		n, _ := strconv.ParseFloat(t.value, 64)
+		return &literalNode{value: n}, nil
+	case punctToken:
+		switch t.value {
+		case "(":
+			p.next()
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(punctToken, ")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		case "[":
+			p.next()
+			items, err := p.parseSequence("]")
+			if err != nil {
+				return nil, err
+			}
+			return &listNode{items: items}, nil
+		}
+	case identToken:
+		p.next()
+		switch t.value {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "in":
+			return nil, fmt.Errorf("unexpected %q at position %d", t.value, t.pos)
+		}
+		if p.is(punctToken, "(") {
+			p.next()
+			fn, ok := functions[t.value]
+			if !ok {
+				return nil, fmt.Errorf("unknown function %q at position %d", t.value, t.pos)
+			}
+			args, err := p.parseSequence(")")
+			if err != nil {
+				return nil, err
+			}
+			return &callNode{name: t.value, fn: fn, args: args}, nil
+		}
+		p.attributes = append(p.attributes, t.value)
+		return &attributeNode{name: t.value}, nil
+	}
+
+	return nil, p.unexpected()
+}
@@ -0,0 +1,90 @@
+// Small expression language for attribute-based authorization conditions.
+//
+// Expression is evaluated against attributes - flat map of dotted names to values,
+// e.g. "subject.organization in target.organizations && in_cidr(request.ip, '10.0.0.0/8')"
+// (attributes available for authorization conditions are listed in the authz package).
+// Supported values are strings, numbers, booleans and lists of them.
+//
+// Evaluation is strict: referring to an unavailable attribute, comparing values
+// of different types or using non-boolean operand in logical expression is an error,
+// so conditions can't be accidentally satisfied due to misconfiguration.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Attribute name -> value.
+// Value can be Lazy, in that case it will be resolved only if expression refers to it.
+type Attributes map[string]any
+
+// Attribute value which is resolved on first access during evaluation.
+type Lazy func() (any, error)
+
+type Expression struct {
+	source     string
+	root       node
+	attributes []string
+}
+
+// Parses expression. Doesn't check if attributes referred by it exist, use Attributes for that.
+func Compile(src string) (*Expression, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.New("expression is empty")
+	}
+
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != eofToken {
+		return nil, p.unexpected()
+	}
+
+	slices.Sort(p.attributes)
+
+	return &Expression{
+		source:     src,
+		root:       root,
+		attributes: slices.Compact(p.attributes),
+	}, nil
+}
+
+// Returns source of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Returns sorted names of all attributes referred by the expression.
+func (e *Expression) Attributes() []string {
+	return slices.Clone(e.attributes)
+}
+
+// Evaluates expression with the given attributes.
+// Returns error if evaluation failed or expression result isn't a boolean.
+func (e *Expression) Evaluate(attributes Attributes) (bool, error) {
+	result, err := e.root.eval(&evaluation{
+		attributes: attributes,
+		resolved:   make(map[string]any),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression result must be bool, got %s", typeName(result))
+	}
+
+	return b, nil
+}
@@ -0,0 +1,187 @@
+package policy
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	valid := []string{
+		"true",
+		"subject.id == target.id",
+		"!(request.hour < 9) && request.hour < 18",
+		"request.weekday in [1, 2, 3, 4, 5]",
+		"subject.organization == '' || subject.organization in target.organizations",
+		`in_cidr(request.ip, "10.0.0.0/8", ["192.168.0.0/16", "fd00::/8"])`,
+		"[]  == []",
+	}
+
+	for _, src := range valid {
+		t.Run(src, func(t *testing.T) {
+			if _, err := Compile(src); err != nil {
+				t.Errorf("Compile(%q) returned error: %v", src, err)
+			}
+		})
+	}
+
+	invalid := []string{
+		"",
+		"   ",
+		"subject.id ==",
+		"(true",
+		"true)",
+		"[1, 2",
+		"'unterminated",
+		"a = b",
+		"a == b == c",
+		"in [1]",
+		"unknown_function(1)",
+		"1.2.3 == 1",
+		"true false",
+	}
+
+	for _, src := range invalid {
+		t.Run("invalid "+src, func(t *testing.T) {
+			if _, err := Compile(src); err == nil {
+				t.Errorf("Compile(%q) expected to return error", src)
+			}
+		})
+	}
+}
+
+func TestExpressionAttributes(t *testing.T) {
+	expr, err := Compile("subject.id == target.id || (subject.id in target.ids && request.hour > 9)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"request.hour", "subject.id", "target.id", "target.ids"}
+
+	if !slices.Equal(expr.Attributes(), expected) {
+		t.Errorf("Attributes() = %v, want %v", expr.Attributes(), expected)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	attributes := Attributes{
+		"subject.id":           "a",
+		"subject.roles":        []string{"user", "moderator"},
+		"subject.organization": "org-1",
+		"subject.impersonated": false,
+		"target.id":            "b",
+		"target.organizations": []string{"org-1", "org-2"},
+		"request.ip":           "10.1.2.3",
+		"request.hour":         14,
+		"request.weekday":      6,
+	}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{"subject.id == target.id", false},
+		{"subject.id != target.id", true},
+		{"'admin' in subject.roles", false},
+		{"'moderator' in subject.roles", true},
+		{"subject.organization in target.organizations", true},
+		{"request.hour >= 9 && request.hour < 18", true},
+		{"request.weekday in [1, 2, 3, 4, 5]", false},
+		{"!subject.impersonated", true},
+		{"in_cidr(request.ip, '10.0.0.0/8')", true},
+		{"in_cidr(request.ip, ['192.168.0.0/16', '172.16.0.0/12'])", false},
+		{"'b' > 'a' && 2.5 <= 2.5", true},
+		{"1 in ['1', 1]", true},
+		// Right operand isn't evaluated, so unavailable attribute isn't an error
+		{"subject.id == 'a' || missing.attribute", true},
+		{"subject.id == 'b' && missing.attribute", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			expr, err := Compile(tt.expression)
+			if err != nil {
+				t.Fatalf("Compile(%q) returned error: %v", tt.expression, err)
+			}
+
+			result, err := expr.Evaluate(attributes)
+			if err != nil {
+				t.Fatalf("Evaluate() returned error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Evaluate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	attributes := Attributes{
+		"subject.id":   "a",
+		"request.hour": 14,
+		"request.ip":   "",
+	}
+
+	tests := []string{
+		"missing.attribute",
+		"subject.id == 1",
+		"subject.id",
+		"request.hour && true",
+		"subject.id in 'abc'",
+		"[1] == [1]",
+		"true < false",
+		"in_cidr(request.ip, '10.0.0.0/8')",
+		"in_cidr('10.0.0.1', 'invalid')",
+		"in_cidr('10.0.0.1')",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			expr, err := Compile(src)
+			if err != nil {
+				t.Fatalf("Compile(%q) returned error: %v", src, err)
+			}
+			if _, err := expr.Evaluate(attributes); err == nil {
+				t.Errorf("Evaluate() expected to return error")
+			}
+		})
+	}
+}
+
+func TestLazyAttributes(t *testing.T) {
+	calls := 0
+
+	attributes := Attributes{
+		"target.organizations": Lazy(func() (any, error) {
+			calls++
+			return []string{"org-1"}, nil
+		}),
+		"target.broken": Lazy(func() (any, error) {
+			return nil, errors.New("lookup failed")
+		}),
+	}
+
+	expr, err := Compile("'org-1' in target.organizations && !('org-2' in target.organizations)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := expr.Evaluate(attributes)
+	if err != nil {
+		t.Fatalf("Evaluate() returned error: %v", err)
+	}
+	if !result {
+		t.Errorf("Evaluate() = false, want true")
+	}
+	if calls != 1 {
+		t.Errorf("Lazy attribute was resolved %d times, want 1", calls)
+	}
+
+	expr, err = Compile("'org-1' in target.broken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expr.Evaluate(attributes); err == nil {
+		t.Errorf("Evaluate() expected to return error of lazy attribute")
+	}
+}
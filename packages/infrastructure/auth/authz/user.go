@@ -1,91 +1,263 @@
 package authz
 
 import (
+	"net/http"
 	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/auth/authz/policy"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
 )
 
 type user struct {
-	//
+	// Attributes with which conditions are evaluated
+	attributes policy.Attributes
 }
 
-// Used for users authorization
+// Used for users authorization.
+// Conditions of contexts are evaluated without attributes of the action,
+// so if action is available use For instead.
 var User = user{}
 
+// Returns authorizer which evaluates conditions with attributes of the given action.
+func (u user) For(act ActionDTO.Any) user {
+	u.attributes = actionAttributes(act)
+	return u
+}
+
 func (u user) SoftDeleteUser(self bool, roles []string) *Error.Status {
 	if self {
-		return authorize(&userSoftDeleteSelfContext, roles)
+		return authorize(&userSoftDeleteSelfContext, roles, u.attributes)
 	}
-	return authorize(&userSoftDeleteUserContext, roles)
+	return authorize(&userSoftDeleteUserContext, roles, u.attributes)
 }
 
 func (u user) RestoreUser(roles []string) *Error.Status {
-	return authorize(&userRestoreUserContext, roles)
+	return authorize(&userRestoreUserContext, roles, u.attributes)
 }
 
 func (u user) DropUser(roles []string) *Error.Status {
-	return authorize(&userDropUserContext, roles)
+	return authorize(&userDropUserContext, roles, u.attributes)
 }
 
 func (u user) DropAllSoftDeletedUsers(roles []string) *Error.Status {
-	return authorize(&userDropAllSoftDeletedUsersContext, roles)
+	return authorize(&userDropAllSoftDeletedUsersContext, roles, u.attributes)
 }
 
 func (u user) ChangeUserLogin(self bool, roles []string) *Error.Status {
 	if self {
-		return authorize(&userChangeSelfLoginContext, roles)
+		return authorize(&userChangeSelfLoginContext, roles, u.attributes)
 	}
-	return authorize(&userChangeUserLoginContext, roles)
+	return authorize(&userChangeUserLoginContext, roles, u.attributes)
 }
 
 func (u user) ChangeUserPassword(self bool, roles []string) *Error.Status {
 	if self {
-		return authorize(&userChangeSelfPasswordContext, roles)
+		return authorize(&userChangeSelfPasswordContext, roles, u.attributes)
 	}
-	return authorize(&userChangeUserPasswordContext, roles)
+	return authorize(&userChangeUserPasswordContext, roles, u.attributes)
 }
 
 func (u user) ChangeUserRoles(self bool, roles []string) *Error.Status {
 	if self {
-		return authorize(&userChangeSelfRolesContext, roles)
+		return authorize(&userChangeSelfRolesContext, roles, u.attributes)
 	}
-	return authorize(&userChangeUserRolesContext, roles)
+	return authorize(&userChangeUserRolesContext, roles, u.attributes)
+}
+
+// Returns names of the roles which alone are sufficient to change roles of other users,
+// i.e. roles of the users who can approve role change requests.
+// Conditions of the context aren't taken into account, since they depend on the action.
+func RoleChangeApprovers() []string {
+	s := current.Load()
+
+	names := []string{}
+
+	for _, role := range s.schema.Roles {
+		roles := []rbac.Role{role}
+
+		s.applyResourcePermissions(roles, userChangeUserRolesContext.Resource)
+
+		if rbac.Authorize(&userChangeUserRolesContext, roles, s.agp) == nil {
+			names = append(names, role.Name)
+		}
+	}
+
+	return names
 }
 
 func (u user) GetUserRoles(roles []string) *Error.Status {
-	return authorize(&userGetUserRolesContext, roles)
+	return authorize(&userGetUserRolesContext, roles, u.attributes)
 }
 
 func (u user) SearchUsers(roles []string) *Error.Status {
-	return authorize(&userSearchUsersContext, roles)
+	return authorize(&userSearchUsersContext, roles, u.attributes)
 }
 
 func (u user) Logout(roles []string) *Error.Status {
-	return authorize(&userLogoutUserContext, roles)
+	return authorize(&userLogoutUserContext, roles, u.attributes)
 }
 
 func (u user) GetUserSession(self bool, roles []string) *Error.Status {
 	if self {
-		return authorize(&userGetSelfSessionContext, roles)
+		return authorize(&userGetSelfSessionContext, roles, u.attributes)
+	}
+	return authorize(&userGetSessionContext, roles, u.attributes)
+}
+
+func (u user) GetLoginHistory(self bool, roles []string) *Error.Status {
+	if self {
+		return authorize(&userGetSelfLoginHistoryContext, roles, u.attributes)
+	}
+	return authorize(&userGetLoginHistoryContext, roles, u.attributes)
+}
+
+func (u user) GetLoginChangeRequests(self bool, roles []string) *Error.Status {
+	if self {
+		return authorize(&userGetSelfLoginChangeRequestsContext, roles, u.attributes)
 	}
-	return authorize(&userGetSessionContext, roles)
+	return authorize(&userGetLoginChangeRequestsContext, roles, u.attributes)
 }
 
 func (u user) DropCache(roles []string) *Error.Status {
-	return authorize(&userDropCacheContext, roles)
+	return authorize(&userDropCacheContext, roles, u.attributes)
 }
 
 func (u user) AccessAPIDocs(roles []string) *Error.Status {
-	return authorize(&userAccessAPIDocsContext, roles)
+	return authorize(&userAccessAPIDocsContext, roles, u.attributes)
 }
 
 func (u user) GetSessionLocation(roles []string) *Error.Status {
-	return authorize(&userGetSessionLocationContext, roles)
+	return authorize(&userGetSessionLocationContext, roles, u.attributes)
 }
 
 func (u user) DeleteLocation(roles []string) *Error.Status {
-	return authorize(&userGetSessionLocationContext, roles)
+	return authorize(&userGetSessionLocationContext, roles, u.attributes)
 }
 
 func (u user) OAuthIntrospect(roles []string) *Error.Status {
-	return authorize(&userIntrospectOAuthTokenContext, roles)
+	return authorize(&userIntrospectOAuthTokenContext, roles, u.attributes)
+}
+
+func (u user) SubscribeToRevocations(roles []string) *Error.Status {
+	return authorize(&userSubscribeToRevocationsContext, roles, u.attributes)
+}
+
+func (u user) CreateRole(roles []string) *Error.Status {
+	return authorize(&userCreateRoleContext, roles, u.attributes)
+}
+
+func (u user) GetRole(roles []string) *Error.Status {
+	return authorize(&userGetRoleContext, roles, u.attributes)
+}
+
+func (u user) UpdateRole(roles []string) *Error.Status {
+	return authorize(&userUpdateRoleContext, roles, u.attributes)
+}
+
+func (u user) DeleteRole(roles []string) *Error.Status {
+	return authorize(&userDeleteRoleContext, roles, u.attributes)
+}
+
+func (u user) ReloadRBAC(roles []string) *Error.Status {
+	return authorize(&userReloadRBACContext, roles, u.attributes)
+}
+
+func (u user) GetActionGatePolicy(roles []string) *Error.Status {
+	return authorize(&userGetActionGatePolicyContext, roles, u.attributes)
+}
+
+func (u user) CheckAuthorization(roles []string) *Error.Status {
+	return authorize(&userCheckAuthorizationContext, roles, u.attributes)
+}
+
+func (u user) ExplainAuthorization(roles []string) *Error.Status {
+	return authorize(&userExplainAuthorizationContext, roles, u.attributes)
+}
+
+func (u user) CreateOrganization(roles []string) *Error.Status {
+	return authorize(&userCreateOrganizationContext, roles, u.attributes)
+}
+
+func (u user) GetOrganization(roles []string) *Error.Status {
+	return authorize(&userGetOrganizationContext, roles, u.attributes)
+}
+
+func (u user) DeleteOrganization(roles []string) *Error.Status {
+	return authorize(&userDeleteOrganizationContext, roles, u.attributes)
+}
+
+func (u user) GetOrganizationMembers(roles []string) *Error.Status {
+	return authorize(&userGetOrganizationMembersContext, roles, u.attributes)
+}
+
+func (u user) ChangeOrganizationMembers(roles []string) *Error.Status {
+	return authorize(&userChangeOrganizationMembersContext, roles, u.attributes)
+}
+
+func (u user) CreateGroup(roles []string) *Error.Status {
+	return authorize(&userCreateGroupContext, roles, u.attributes)
+}
+
+func (u user) GetGroup(roles []string) *Error.Status {
+	return authorize(&userGetGroupContext, roles, u.attributes)
+}
+
+// Used for both group roles and group members changes, since both of them changes roles of the users
+func (u user) UpdateGroup(roles []string) *Error.Status {
+	return authorize(&userUpdateGroupContext, roles, u.attributes)
+}
+
+func (u user) DeleteGroup(roles []string) *Error.Status {
+	return authorize(&userDeleteGroupContext, roles, u.attributes)
+}
+
+func (u user) GetSchedulerJobs(roles []string) *Error.Status {
+	return authorize(&userGetSchedulerJobsContext, roles, u.attributes)
+}
+
+var ImpersonationOfHigherPrivilegedUser = Error.NewStatusError(
+	"Can't impersonate user with higher privileges",
+	http.StatusForbidden,
+)
+
+// Self permissions are granted to everyone on their own resources,
+// so only these permissions are taken into account when comparing privileges.
+const privilegedPermissions = rbac.CreatePermission | rbac.ReadPermission | rbac.UpdatePermission | rbac.DeletePermission
+
+// Besides regular authorization also checks that target user doesn't have
+// any privileged permissions which requester doesn't have.
+// Privileges are compared for each resource, since permissions of roles can be overridden for specific resources.
+func (u user) ImpersonateUser(roles []string, targetRoles []string) *Error.Status {
+	if err := authorize(&userImpersonateUserContext, roles, u.attributes); err != nil {
+		return err
+	}
+
+	// Snapshot may be replaced concurrently, so it must be loaded only once
+	s := current.Load()
+
+	requesterRoles, err := s.getRoles(roles)
+	if err != nil {
+		return err
+	}
+
+	impersonatedRoles, err := s.getRoles(targetRoles)
+	if err != nil {
+		return err
+	}
+
+	// Empty resource name stands for base permissions of roles (resources without overrides)
+	resources := append([]string{""}, s.overriddenResources(requesterRoles, impersonatedRoles)...)
+
+	for _, resourceName := range resources {
+		permissions := s.mergePermissions(requesterRoles, resourceName)
+		targetPermissions := s.mergePermissions(impersonatedRoles, resourceName)
+
+		if targetPermissions&privilegedPermissions&^permissions != 0 {
+			log.Error("Failed to authorize impersonation", ImpersonationOfHigherPrivilegedUser.Error(), nil)
+			return ImpersonationOfHigherPrivilegedUser
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,208 @@
+// Session binding policy.
+//
+// On each refresh attributes of the session (browser, OS, device, IP address and its country)
+// are compared with the ones from which session was used before.
+// For each changed attribute policy defines an action: allow the change, send alert,
+// require step-up or revoke the session. Policy is configured per role and per token audience.
+package binding
+
+import (
+	"net"
+	"sentinel/packages/common/config"
+)
+
+type Attribute string
+
+const (
+	BrowserAttribute Attribute = "browser"
+	OSAttribute      Attribute = "os"
+	DeviceAttribute  Attribute = "device"
+	IPAttribute      Attribute = "ip"
+	CountryAttribute Attribute = "country"
+)
+
+// Action which must be taken in response to the change of session attribute.
+// Actions are ordered by severity, so they can be compared.
+type Action int8
+
+const (
+	AllowAction Action = iota
+	AlertAction
+	StepUpAction
+	RevokeAction
+)
+
+var actionNames = map[Action]string{
+	AllowAction:  "allow",
+	AlertAction:  "alert",
+	StepUpAction: "step-up",
+	RevokeAction: "revoke",
+}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// Returns action with the given name, second value is false if name is empty or unknown.
+func parseAction(name string) (Action, bool) {
+	for action, actionName := range actionNames {
+		if actionName == name {
+			return action, true
+		}
+	}
+	return AllowAction, false
+}
+
+type Policy struct {
+	// Attributes which aren't present here are allowed to change
+	Actions map[Attribute]Action
+	// IP addresses within the same prefix (in bits) are treated as the same address
+	IPv4Prefix int
+	IPv6Prefix int
+}
+
+// State of the session at some moment of time
+type Snapshot struct {
+	Browser string
+	OS      string
+	// Device identity without browser, so browser change isn't treated as device change
+	// (browser is bound separately). See sharedcontroller.newSnapshot.
+	Device string
+	IP     net.IP
+	// Empty if unknown
+	Country string
+}
+
+type Result struct {
+	Changed []Attribute
+	// The most severe action among actions for changed attributes
+	Action Action
+}
+
+// Reports whether both addresses are in the same network of the given prefix length.
+func sameNetwork(a net.IP, b net.IP, ipv4Prefix int, ipv6Prefix int) bool {
+	if a4, b4 := a.To4(), b.To4(); a4 != nil || b4 != nil {
+		if a4 == nil || b4 == nil {
+			return false
+		}
+		mask := net.CIDRMask(ipv4Prefix, 32)
+		return a4.Mask(mask).Equal(b4.Mask(mask))
+	}
+
+	mask := net.CIDRMask(ipv6Prefix, 128)
+
+	return a.Mask(mask).Equal(b.Mask(mask))
+}
+
+// Compares previous and current state of the session and returns changed attributes
+// and action which must be taken according to the policy.
+func Evaluate(previous *Snapshot, current *Snapshot, policy *Policy) Result {
+	result := Result{Changed: []Attribute{}}
+
+	changed := func(attr Attribute) {
+		result.Changed = append(result.Changed, attr)
+		if action := policy.Actions[attr]; action > result.Action {
+			result.Action = action
+		}
+	}
+
+	if previous.Browser != current.Browser {
+		changed(BrowserAttribute)
+	}
+	if previous.OS != current.OS {
+		changed(OSAttribute)
+	}
+	if previous.Device != current.Device {
+		changed(DeviceAttribute)
+	}
+	if previous.IP != nil && current.IP != nil &&
+		!sameNetwork(previous.IP, current.IP, policy.IPv4Prefix, policy.IPv6Prefix) {
+		changed(IPAttribute)
+	}
+	// If country of one of the states is unknown there are nothing to compare
+	if previous.Country != "" && current.Country != "" && previous.Country != current.Country {
+		changed(CountryAttribute)
+	}
+
+	return result
+}
+
+func configuredActions(p *config.SessionBindingPolicy) map[Attribute]string {
+	return map[Attribute]string{
+		BrowserAttribute: p.Browser,
+		OSAttribute:      p.OS,
+		DeviceAttribute:  p.Device,
+		IPAttribute:      p.IP,
+		CountryAttribute: p.Country,
+	}
+}
+
+// Merges default policy with overrides.
+// Actions and prefixes specified by overrides replace default ones,
+// if several overrides specify them, then the most severe action and the longest prefix are used.
+func resolve(def *config.SessionBindingPolicy, overrides []*config.SessionBindingPolicy) Policy {
+	policy := Policy{
+		Actions:    make(map[Attribute]Action, 5),
+		IPv4Prefix: def.IPv4Prefix,
+		IPv6Prefix: def.IPv6Prefix,
+	}
+
+	overridden := make(map[Attribute]bool, 5)
+	ipv4Overridden, ipv6Overridden := false, false
+
+	for _, override := range overrides {
+		for attr, name := range configuredActions(override) {
+			action, ok := parseAction(name)
+			if !ok {
+				continue
+			}
+			if !overridden[attr] || action > policy.Actions[attr] {
+				policy.Actions[attr] = action
+				overridden[attr] = true
+			}
+		}
+		if override.IPv4Prefix != 0 && (!ipv4Overridden || override.IPv4Prefix > policy.IPv4Prefix) {
+			policy.IPv4Prefix, ipv4Overridden = override.IPv4Prefix, true
+		}
+		if override.IPv6Prefix != 0 && (!ipv6Overridden || override.IPv6Prefix > policy.IPv6Prefix) {
+			policy.IPv6Prefix, ipv6Overridden = override.IPv6Prefix, true
+		}
+	}
+
+	for attr, name := range configuredActions(def) {
+		if overridden[attr] {
+			continue
+		}
+		if action, ok := parseAction(name); ok {
+			policy.Actions[attr] = action
+		}
+	}
+
+	// Unspecified prefix means that whole address is compared
+	if policy.IPv4Prefix == 0 {
+		policy.IPv4Prefix = 32
+	}
+	if policy.IPv6Prefix == 0 {
+		policy.IPv6Prefix = 128
+	}
+
+	return policy
+}
+
+// Returns policy from config for the session of the user with the given roles and token audience.
+func PolicyFor(roles []string, audience []string) Policy {
+	overrides := make([]*config.SessionBindingPolicy, 0, len(roles)+len(audience))
+
+	for _, role := range roles {
+		if p, ok := config.Auth.SessionBindingPerRole[role]; ok {
+			overrides = append(overrides, &p)
+		}
+	}
+	for _, aud := range audience {
+		if p, ok := config.Auth.SessionBindingPerAudience[aud]; ok {
+			overrides = append(overrides, &p)
+		}
+	}
+
+	return resolve(&config.Auth.SessionBinding, overrides)
+}
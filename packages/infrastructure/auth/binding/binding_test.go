@@ -0,0 +1,170 @@
+package binding
+
+import (
+	"maps"
+	"net"
+	"sentinel/packages/common/config"
+	"slices"
+	"testing"
+)
+
+var testPolicy = Policy{
+	Actions: map[Attribute]Action{
+		BrowserAttribute: StepUpAction,
+		OSAttribute:      StepUpAction,
+		DeviceAttribute:  RevokeAction,
+		CountryAttribute: AlertAction,
+	},
+	IPv4Prefix: 24,
+	IPv6Prefix: 64,
+}
+
+var base = Snapshot{
+	Browser: "Firefox",
+	OS:      "Linux",
+	Device:  "desktop Linux",
+	IP:      net.ParseIP("203.0.113.10"),
+	Country: "DE",
+}
+
+func with(modify func(s *Snapshot)) Snapshot {
+	s := base
+	modify(&s)
+	return s
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		current Snapshot
+		changed []Attribute
+		action  Action
+	}{
+		{
+			name:    "nothing changed",
+			current: base,
+			changed: []Attribute{},
+			action:  AllowAction,
+		},
+		{
+			name:    "IP changed within prefix",
+			current: with(func(s *Snapshot) { s.IP = net.ParseIP("203.0.113.200") }),
+			changed: []Attribute{},
+			action:  AllowAction,
+		},
+		{
+			name:    "IP changed outside prefix",
+			current: with(func(s *Snapshot) { s.IP = net.ParseIP("198.51.100.1") }),
+			changed: []Attribute{IPAttribute},
+			action:  AllowAction,
+		},
+		{
+			name:    "IP version changed",
+			current: with(func(s *Snapshot) { s.IP = net.ParseIP("2001:db8::1") }),
+			changed: []Attribute{IPAttribute},
+			action:  AllowAction,
+		},
+		{
+			name:    "country changed",
+			current: with(func(s *Snapshot) { s.Country = "US" }),
+			changed: []Attribute{CountryAttribute},
+			action:  AlertAction,
+		},
+		{
+			name:    "unknown country",
+			current: with(func(s *Snapshot) { s.Country = "" }),
+			changed: []Attribute{},
+			action:  AllowAction,
+		},
+		{
+			name:    "browser changed",
+			current: with(func(s *Snapshot) { s.Browser = "Chrome" }),
+			changed: []Attribute{BrowserAttribute},
+			action:  StepUpAction,
+		},
+		{
+			name: "most severe action is taken",
+			current: with(func(s *Snapshot) {
+				s.Browser = "Chrome"
+				s.Device = "mobile Pixel 7"
+			}),
+			changed: []Attribute{BrowserAttribute, DeviceAttribute},
+			action:  RevokeAction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Evaluate(&base, &tt.current, &testPolicy)
+
+			if !slices.Equal(result.Changed, tt.changed) {
+				t.Errorf("Changed = %v, want %v", result.Changed, tt.changed)
+			}
+			if result.Action != tt.action {
+				t.Errorf("Action = %s, want %s", result.Action, tt.action)
+			}
+		})
+	}
+}
+
+func TestSameNetwork(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"2001:db8:1:2::1", "2001:db8:1:2::ffff", true},
+		{"2001:db8:1:2::1", "2001:db8:1:3::1", false},
+		{"::ffff:203.0.113.1", "203.0.113.2", true},
+	}
+
+	for _, tt := range tests {
+		if same := sameNetwork(net.ParseIP(tt.a), net.ParseIP(tt.b), 24, 64); same != tt.same {
+			t.Errorf("sameNetwork(%s, %s) = %v, want %v", tt.a, tt.b, same, tt.same)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	def := &config.SessionBindingPolicy{
+		Browser:    "step-up",
+		Device:     "revoke",
+		IP:         "allow",
+		IPv4Prefix: 24,
+	}
+
+	t.Run("no overrides", func(t *testing.T) {
+		policy := resolve(def, nil)
+
+		want := map[Attribute]Action{
+			BrowserAttribute: StepUpAction,
+			DeviceAttribute:  RevokeAction,
+			IPAttribute:      AllowAction,
+		}
+		if !maps.Equal(policy.Actions, want) {
+			t.Errorf("Actions = %v, want %v", policy.Actions, want)
+		}
+		if policy.IPv4Prefix != 24 || policy.IPv6Prefix != 128 {
+			t.Errorf("Prefixes = %d, %d, want 24, 128", policy.IPv4Prefix, policy.IPv6Prefix)
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		policy := resolve(def, []*config.SessionBindingPolicy{
+			{Browser: "allow", IP: "alert", IPv4Prefix: 16},
+			{IP: "revoke", IPv4Prefix: 32},
+		})
+
+		want := map[Attribute]Action{
+			// Override can relax default action
+			BrowserAttribute: AllowAction,
+			DeviceAttribute:  RevokeAction,
+			IPAttribute:      RevokeAction,
+		}
+		if !maps.Equal(policy.Actions, want) {
+			t.Errorf("Actions = %v, want %v", policy.Actions, want)
+		}
+		if policy.IPv4Prefix != 32 {
+			t.Errorf("IPv4Prefix = %d, want 32", policy.IPv4Prefix)
+		}
+	})
+}
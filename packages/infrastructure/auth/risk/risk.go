@@ -0,0 +1,215 @@
+// Risk engine for logins and session refreshes.
+//
+// Location of the session is compared with recent locations of the user:
+// travel between them faster than configured speed (great-circle distance),
+// new country or new network (ASN) increase risk score of the session,
+// as well as login from the device which user hasn't used before.
+// Depending on score and configured thresholds session is either allowed,
+// or alert is sent, or step-up is required, or session is blocked.
+package risk
+
+import (
+	"math"
+	"sentinel/packages/common/config"
+	"slices"
+	"time"
+)
+
+type Reason string
+
+const (
+	// Travel from one of the recent locations would require speed higher than allowed
+	ImpossibleTravelReason Reason = "impossible_travel"
+	// User has never been seen in this country (among recent locations)
+	NewCountryReason Reason = "new_country"
+	// User has never been seen in this network (among recent locations).
+	// Network is identified by ISP (ASN organization) of the location.
+	NewASNReason Reason = "new_asn"
+	// Session is used from the device which wasn't seen before (there are no valid device cookie)
+	NewDeviceReason Reason = "new_device"
+)
+
+// Action which must be taken in response to the assessed risk.
+// Actions are ordered by severity, so they can be compared.
+type Action int8
+
+const (
+	NoAction Action = iota
+	AlertAction
+	StepUpAction
+	BlockAction
+)
+
+var actionNames = map[Action]string{
+	NoAction:     "none",
+	AlertAction:  "alert",
+	StepUpAction: "step_up",
+	BlockAction:  "block",
+}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+const MaxScore = 100
+
+// Location of the user at some moment of time.
+type Observation struct {
+	Country string
+	ISP     string
+	// Coordinates are ignored if both are zero (location wasn't resolved precisely)
+	Latitude  float64
+	Longitude float64
+	Time      time.Time
+	// Device flags are taken into account only for the current observation
+	NewDevice bool
+	// Device marked as trusted by the user
+	TrustedDevice bool
+	// Session marked as trusted by the user
+	TrustedSession bool
+}
+
+func (o *Observation) hasCoordinates() bool {
+	return o.Latitude != 0 || o.Longitude != 0
+}
+
+type Settings struct {
+	// km/h
+	MaxTravelSpeed float64
+	// Distances less than this are ignored (km), since GeoIP isn't precise
+	MinTravelDistance float64
+
+	ImpossibleTravelScore int
+	NewCountryScore       int
+	NewASNScore           int
+	NewDeviceScore        int
+
+	// Min score at which the action is taken. Zero threshold disables the action.
+	AlertThreshold  int
+	StepUpThreshold int
+	BlockThreshold  int
+}
+
+type Assessment struct {
+	Score   int
+	Reasons []Reason
+	Action  Action
+}
+
+const earthRadius = 6371.0 // km
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// Returns great-circle distance between two points in kilometers (haversine formula).
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func isImpossibleTravel(from *Observation, to *Observation, s *Settings) bool {
+	if !from.hasCoordinates() || !to.hasCoordinates() {
+		return false
+	}
+
+	distance := Distance(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+	if distance <= s.MinTravelDistance {
+		return false
+	}
+
+	hours := to.Time.Sub(from.Time).Hours()
+	if hours <= 0 {
+		// Both locations are used at the same time
+		return true
+	}
+
+	return distance/hours > s.MaxTravelSpeed
+}
+
+func actionFor(score int, s *Settings) Action {
+	switch {
+	case s.BlockThreshold > 0 && score >= s.BlockThreshold:
+		return BlockAction
+	case s.StepUpThreshold > 0 && score >= s.StepUpThreshold:
+		return StepUpAction
+	case s.AlertThreshold > 0 && score >= s.AlertThreshold:
+		return AlertAction
+	default:
+		return NoAction
+	}
+}
+
+// Assesses risk of the current observation based on user recent history.
+// If there are no history (e.g. first login), then there are no risk.
+func Assess(current Observation, history []Observation, s Settings) Assessment {
+	assessment := Assessment{Reasons: []Reason{}}
+
+	if len(history) == 0 {
+		return assessment
+	}
+
+	countries := make([]string, 0, len(history))
+	networks := make([]string, 0, len(history))
+	impossibleTravel := false
+
+	for i := range history {
+		countries = append(countries, history[i].Country)
+		if history[i].ISP != "" {
+			networks = append(networks, history[i].ISP)
+		}
+		if !impossibleTravel {
+			impossibleTravel = isImpossibleTravel(&history[i], &current, &s)
+		}
+	}
+
+	if impossibleTravel {
+		assessment.Score += s.ImpossibleTravelScore
+		assessment.Reasons = append(assessment.Reasons, ImpossibleTravelReason)
+	}
+	if current.Country != "" && !slices.Contains(countries, current.Country) {
+		assessment.Score += s.NewCountryScore
+		assessment.Reasons = append(assessment.Reasons, NewCountryReason)
+	}
+	// If ISP of previous locations is unknown there are nothing to compare with
+	if current.ISP != "" && len(networks) != 0 && !slices.Contains(networks, current.ISP) {
+		assessment.Score += s.NewASNScore
+		assessment.Reasons = append(assessment.Reasons, NewASNReason)
+	}
+
+	if current.NewDevice {
+		assessment.Score += s.NewDeviceScore
+		assessment.Reasons = append(assessment.Reasons, NewDeviceReason)
+	}
+
+	assessment.Score = min(assessment.Score, MaxScore)
+	assessment.Action = actionFor(assessment.Score, &s)
+
+	// User already proved ownership of the trusted device (or session), so step-up isn't required.
+	// Blocking isn't affected since it means that session is most likely compromised.
+	if (current.TrustedDevice || current.TrustedSession) && assessment.Action == StepUpAction {
+		assessment.Action = AlertAction
+	}
+
+	return assessment
+}
+
+// Returns settings from config.
+func ConfiguredSettings() Settings {
+	return Settings{
+		MaxTravelSpeed:        config.Risk.MaxTravelSpeed,
+		MinTravelDistance:     config.Risk.MinTravelDistance,
+		ImpossibleTravelScore: config.Risk.ImpossibleTravelScore,
+		NewCountryScore:       config.Risk.NewCountryScore,
+		NewASNScore:           config.Risk.NewASNScore,
+		NewDeviceScore:        config.Risk.NewDeviceScore,
+		AlertThreshold:        config.Risk.AlertThreshold,
+		StepUpThreshold:       config.Risk.StepUpThreshold,
+		BlockThreshold:        config.Risk.BlockThreshold,
+	}
+}
@@ -0,0 +1,204 @@
+package risk
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+var testSettings = Settings{
+	MaxTravelSpeed:        1000,
+	MinTravelDistance:     100,
+	ImpossibleTravelScore: 60,
+	NewCountryScore:       30,
+	NewASNScore:           15,
+	NewDeviceScore:        20,
+	AlertThreshold:        15,
+	StepUpThreshold:       40,
+	BlockThreshold:        90,
+}
+
+var (
+	berlin  = Observation{Country: "DE", ISP: "Deutsche Telekom AG", Latitude: 52.52, Longitude: 13.405}
+	hamburg = Observation{Country: "DE", ISP: "Vodafone GmbH", Latitude: 53.55, Longitude: 9.993}
+	newYork = Observation{Country: "US", ISP: "Verizon", Latitude: 40.7128, Longitude: -74.006}
+)
+
+func at(o Observation, t time.Time) Observation {
+	o.Time = t
+	return o
+}
+
+func fromDevice(o Observation, isNew bool, trusted bool) Observation {
+	o.NewDevice = isNew
+	o.TrustedDevice = trusted
+	return o
+}
+
+func fromSession(o Observation, trusted bool) Observation {
+	o.TrustedSession = trusted
+	return o
+}
+
+func TestDistance(t *testing.T) {
+	d := Distance(berlin.Latitude, berlin.Longitude, newYork.Latitude, newYork.Longitude)
+
+	// Actual distance is about 6385 km
+	if math.Abs(d-6385) > 20 {
+		t.Errorf("Distance(Berlin, New York) = %f, want ~6385", d)
+	}
+	if d := Distance(1, 1, 1, 1); d != 0 {
+		t.Errorf("Distance between same points = %f, want 0", d)
+	}
+}
+
+func TestAssess(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		current Observation
+		history []Observation
+		reasons []Reason
+		score   int
+		action  Action
+	}{
+		{
+			name:    "no history",
+			current: at(newYork, now),
+			reasons: []Reason{},
+			action:  NoAction,
+		},
+		{
+			name:    "same location",
+			current: at(berlin, now),
+			history: []Observation{at(berlin, now.Add(-time.Minute))},
+			reasons: []Reason{},
+			action:  NoAction,
+		},
+		{
+			name:    "new network in the same country",
+			current: at(hamburg, now),
+			history: []Observation{at(berlin, now.Add(-time.Hour*5))},
+			reasons: []Reason{NewASNReason},
+			score:   15,
+			action:  AlertAction,
+		},
+		{
+			name:    "new country reachable in time",
+			current: at(newYork, now),
+			history: []Observation{at(berlin, now.Add(-time.Hour*24))},
+			reasons: []Reason{NewCountryReason, NewASNReason},
+			score:   45,
+			action:  StepUpAction,
+		},
+		{
+			name:    "impossible travel",
+			current: at(newYork, now),
+			history: []Observation{at(berlin, now.Add(-time.Hour))},
+			reasons: []Reason{ImpossibleTravelReason, NewCountryReason, NewASNReason},
+			score:   100,
+			action:  BlockAction,
+		},
+		{
+			name:    "impossible travel to known country",
+			current: at(newYork, now),
+			history: []Observation{at(newYork, now.Add(-time.Hour*48)), at(berlin, now.Add(-time.Hour))},
+			reasons: []Reason{ImpossibleTravelReason},
+			score:   60,
+			action:  StepUpAction,
+		},
+		{
+			name:    "unknown coordinates",
+			current: at(Observation{Country: "US", ISP: "Verizon"}, now),
+			history: []Observation{at(berlin, now.Add(-time.Minute))},
+			reasons: []Reason{NewCountryReason, NewASNReason},
+			score:   45,
+			action:  StepUpAction,
+		},
+		{
+			name:    "unknown ISP",
+			current: at(Observation{Country: "DE", Latitude: berlin.Latitude, Longitude: berlin.Longitude}, now),
+			history: []Observation{at(berlin, now.Add(-time.Minute))},
+			reasons: []Reason{},
+			action:  NoAction,
+		},
+		{
+			name:    "new device without history",
+			current: fromDevice(at(berlin, now), true, false),
+			reasons: []Reason{},
+			action:  NoAction,
+		},
+		{
+			name:    "new device",
+			current: fromDevice(at(berlin, now), true, false),
+			history: []Observation{at(berlin, now.Add(-time.Minute))},
+			reasons: []Reason{NewDeviceReason},
+			score:   20,
+			action:  AlertAction,
+		},
+		{
+			name:    "trusted device downgrades step-up",
+			current: fromDevice(at(newYork, now), false, true),
+			history: []Observation{at(berlin, now.Add(-time.Hour*24))},
+			reasons: []Reason{NewCountryReason, NewASNReason},
+			score:   45,
+			action:  AlertAction,
+		},
+		{
+			name:    "trusted session downgrades step-up",
+			current: fromSession(at(newYork, now), true),
+			history: []Observation{at(berlin, now.Add(-time.Hour*24))},
+			reasons: []Reason{NewCountryReason, NewASNReason},
+			score:   45,
+			action:  AlertAction,
+		},
+		{
+			name:    "trusted session doesn't prevent block",
+			current: fromSession(at(newYork, now), true),
+			history: []Observation{at(berlin, now.Add(-time.Hour))},
+			reasons: []Reason{ImpossibleTravelReason, NewCountryReason, NewASNReason},
+			score:   100,
+			action:  BlockAction,
+		},
+		{
+			name:    "trusted device doesn't prevent block",
+			current: fromDevice(at(newYork, now), false, true),
+			history: []Observation{at(berlin, now.Add(-time.Hour))},
+			reasons: []Reason{ImpossibleTravelReason, NewCountryReason, NewASNReason},
+			score:   100,
+			action:  BlockAction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assessment := Assess(tt.current, tt.history, testSettings)
+
+			if !slices.Equal(assessment.Reasons, tt.reasons) {
+				t.Errorf("Reasons = %v, want %v", assessment.Reasons, tt.reasons)
+			}
+			if assessment.Score != tt.score {
+				t.Errorf("Score = %d, want %d", assessment.Score, tt.score)
+			}
+			if assessment.Action != tt.action {
+				t.Errorf("Action = %s, want %s", assessment.Action, tt.action)
+			}
+		})
+	}
+}
+
+func TestDisabledThresholds(t *testing.T) {
+	now := time.Now()
+
+	s := testSettings
+	s.BlockThreshold = 0
+	s.StepUpThreshold = 0
+
+	assessment := Assess(at(newYork, now), []Observation{at(berlin, now.Add(-time.Hour))}, s)
+
+	if assessment.Action != AlertAction {
+		t.Errorf("Action = %s, want %s", assessment.Action, AlertAction)
+	}
+}
@@ -0,0 +1,101 @@
+package roles
+
+import (
+	"slices"
+	"strings"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+// Difference between roles of this service schema before and after reload.
+type Diff struct {
+	AddedRoles          []string `json:"added-roles"`
+	RemovedRoles        []string `json:"removed-roles"`
+	ChangedRoles        []string `json:"changed-roles"`
+	AddedDefaultRoles   []string `json:"added-default-roles"`
+	RemovedDefaultRoles []string `json:"removed-default-roles"`
+}
+
+func newDiff(oldHost *rbac.Host, oldSchema *rbac.Schema, newHost *rbac.Host, newSchema *rbac.Schema) *Diff {
+	diff := &Diff{
+		AddedRoles:          []string{},
+		RemovedRoles:        []string{},
+		ChangedRoles:        []string{},
+		AddedDefaultRoles:   []string{},
+		RemovedDefaultRoles: []string{},
+	}
+
+	for _, newRole := range newSchema.Roles {
+		oldRole, ok := findRole(oldSchema.Roles, newRole.Name)
+		if !ok {
+			diff.AddedRoles = append(diff.AddedRoles, newRole.Name)
+			continue
+		}
+		if oldRole.Permissions != newRole.Permissions {
+			diff.ChangedRoles = append(diff.ChangedRoles, newRole.Name)
+		}
+	}
+	for _, oldRole := range oldSchema.Roles {
+		if _, ok := findRole(newSchema.Roles, oldRole.Name); !ok {
+			diff.RemovedRoles = append(diff.RemovedRoles, oldRole.Name)
+		}
+	}
+
+	oldDefaultRoles := rbac.GetRolesNames(oldHost.DefaultRoles)
+	newDefaultRoles := rbac.GetRolesNames(newHost.DefaultRoles)
+
+	for _, name := range newDefaultRoles {
+		if !slices.Contains(oldDefaultRoles, name) {
+			diff.AddedDefaultRoles = append(diff.AddedDefaultRoles, name)
+		}
+	}
+	for _, name := range oldDefaultRoles {
+		if !slices.Contains(newDefaultRoles, name) {
+			diff.RemovedDefaultRoles = append(diff.RemovedDefaultRoles, name)
+		}
+	}
+
+	return diff
+}
+
+func (d *Diff) IsEmpty() bool {
+	return len(d.AddedRoles) == 0 &&
+		len(d.RemovedRoles) == 0 &&
+		len(d.ChangedRoles) == 0 &&
+		len(d.AddedDefaultRoles) == 0 &&
+		len(d.RemovedDefaultRoles) == 0
+}
+
+func (d *Diff) String() string {
+	if d.IsEmpty() {
+		return "no changes"
+	}
+
+	parts := []string{}
+
+	for _, part := range []struct {
+		name  string
+		roles []string
+	}{
+		{"added roles", d.AddedRoles},
+		{"removed roles", d.RemovedRoles},
+		{"changed roles", d.ChangedRoles},
+		{"added default roles", d.AddedDefaultRoles},
+		{"removed default roles", d.RemovedDefaultRoles},
+	} {
+		if len(part.roles) != 0 {
+			parts = append(parts, part.name+": "+strings.Join(part.roles, ", "))
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func findRole(roles []rbac.Role, name string) (rbac.Role, bool) {
+	for _, role := range roles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return rbac.Role{}, false
+}
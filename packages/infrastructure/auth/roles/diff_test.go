@@ -0,0 +1,49 @@
+package roles
+
+import (
+	"slices"
+	"testing"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+func TestDiff(t *testing.T) {
+	user := rbac.NewRole("user", rbac.SelfReadPermission)
+	admin := rbac.NewRole("admin", rbac.ReadPermission)
+	guest := rbac.NewRole("guest", 0)
+
+	oldHost := &rbac.Host{DefaultRoles: []rbac.Role{user}}
+	oldSchema := &rbac.Schema{Roles: []rbac.Role{user, admin}}
+
+	newHost := &rbac.Host{DefaultRoles: []rbac.Role{guest}}
+	newSchema := &rbac.Schema{Roles: []rbac.Role{
+		rbac.NewRole("user", rbac.SelfReadPermission|rbac.SelfUpdatePermission),
+		guest,
+	}}
+
+	diff := newDiff(oldHost, oldSchema, newHost, newSchema)
+
+	tests := []struct {
+		name     string
+		actual   []string
+		expected []string
+	}{
+		{"added roles", diff.AddedRoles, []string{"guest"}},
+		{"removed roles", diff.RemovedRoles, []string{"admin"}},
+		{"changed roles", diff.ChangedRoles, []string{"user"}},
+		{"added default roles", diff.AddedDefaultRoles, []string{"guest"}},
+		{"removed default roles", diff.RemovedDefaultRoles, []string{"user"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.actual, tt.expected) {
+				t.Errorf("%s = %v, want %v", tt.name, tt.actual, tt.expected)
+			}
+		})
+	}
+
+	if diff := newDiff(oldHost, oldSchema, oldHost, oldSchema); !diff.IsEmpty() {
+		t.Errorf("Diff of identical hosts must be empty, got: %s", diff)
+	}
+}
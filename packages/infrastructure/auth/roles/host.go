@@ -0,0 +1,137 @@
+package roles
+
+import (
+	"sentinel/packages/common/config"
+	RoleDTO "sentinel/packages/core/role/DTO"
+	"sentinel/packages/infrastructure/auth/authz"
+	rolemapper "sentinel/packages/infrastructure/mappers/role"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+// Builds RBAC host from given roles. Schemas (as well as their entities and resources)
+// are taken from the base host, only roles are replaced.
+//
+// Roles of each schema are global roles merged with the schema specific roles
+// (if schema has role with the same name as global one, then schema specific role is used).
+// Roles of the schemas which doesn't exist in base host are ignored.
+//
+// Also returns resource permissions of the roles of this service schema.
+func buildHost(base *rbac.Host, roles []*RoleDTO.Full) (*rbac.Host, authz.ResourcePermissions) {
+	host := &rbac.Host{
+		DefaultRoles: []rbac.Role{},
+		GlobalRoles:  []rbac.Role{},
+		Schemas:      make([]rbac.Schema, 0, len(base.Schemas)),
+	}
+
+	globalRoles := []*RoleDTO.Full{}
+	schemasRoles := make(map[string][]*RoleDTO.Full)
+
+	for _, role := range roles {
+		if role.IsGlobal() {
+			globalRoles = append(globalRoles, role)
+			host.GlobalRoles = append(host.GlobalRoles, rolemapper.RoleFromFull(role))
+			if role.IsDefault {
+				host.DefaultRoles = append(host.DefaultRoles, rolemapper.RoleFromFull(role))
+			}
+			continue
+		}
+		schemasRoles[role.SchemaID] = append(schemasRoles[role.SchemaID], role)
+	}
+
+	overrides := authz.ResourcePermissions{}
+
+	for _, baseSchema := range base.Schemas {
+		schema := baseSchema
+		schema.Roles = []rbac.Role{}
+		schema.DefaultRoles = []rbac.Role{}
+
+		schemaRoles := mergeRoles(globalRoles, schemasRoles[schema.ID])
+
+		for _, role := range schemaRoles {
+			schema.Roles = append(schema.Roles, rolemapper.RoleFromFull(role))
+			if role.IsDefault && !role.IsGlobal() {
+				schema.DefaultRoles = append(schema.DefaultRoles, rolemapper.RoleFromFull(role))
+			}
+			if schema.ID == config.App.ServiceID && len(role.ResourcePermissions) != 0 {
+				overrides[role.Name] = make(map[string]rbac.Permissions, len(role.ResourcePermissions))
+				for resource, permissions := range role.ResourcePermissions {
+					overrides[role.Name][resource] = permissions
+				}
+			}
+		}
+
+		host.Schemas = append(host.Schemas, schema)
+	}
+
+	return host, overrides
+}
+
+// Schema specific roles overrides global roles with the same name.
+func mergeRoles(globalRoles []*RoleDTO.Full, schemaRoles []*RoleDTO.Full) []*RoleDTO.Full {
+	merged := make([]*RoleDTO.Full, 0, len(globalRoles)+len(schemaRoles))
+
+outer:
+	for _, globalRole := range globalRoles {
+		for _, schemaRole := range schemaRoles {
+			if schemaRole.Name == globalRole.Name {
+				continue outer
+			}
+		}
+		merged = append(merged, globalRole)
+	}
+
+	return append(merged, schemaRoles...)
+}
+
+// Converts roles of the given host into DTOs, which can be used for seeding.
+// Schema roles which are identical to the global roles are omitted.
+func rolesFromHost(host *rbac.Host) []*RoleDTO.Full {
+	dtos := []*RoleDTO.Full{}
+
+	isDefault := func(roles []rbac.Role, name string) bool {
+		for _, role := range roles {
+			if role.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, role := range host.GlobalRoles {
+		dtos = append(dtos, &RoleDTO.Full{
+			Name:        role.Name,
+			Permissions: role.Permissions,
+			IsDefault:   isDefault(host.DefaultRoles, role.Name),
+		})
+	}
+
+	for _, schema := range host.Schemas {
+		for _, role := range schema.Roles {
+			roleIsDefault := isDefault(schema.DefaultRoles, role.Name)
+
+			// Role was inherited from global roles
+			if !roleIsDefault && isGlobalRole(host, role) {
+				continue
+			}
+
+			dtos = append(dtos, &RoleDTO.Full{
+				SchemaID:    schema.ID,
+				Name:        role.Name,
+				Permissions: role.Permissions,
+				IsDefault:   roleIsDefault,
+			})
+		}
+	}
+
+	return dtos
+}
+
+func isGlobalRole(host *rbac.Host, role rbac.Role) bool {
+	for _, globalRole := range host.GlobalRoles {
+		if globalRole == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package roles
+
+import (
+	"sentinel/packages/common/config"
+	"sentinel/packages/common/config/configtest"
+	RoleDTO "sentinel/packages/core/role/DTO"
+	"testing"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+const (
+	serviceSchemaID = "cb663674-803e-4b06-bfeb-87c5cc86383e"
+	otherSchemaID   = "2fd9f71c-4ced-4607-af47-7e8cc21725a9"
+)
+
+func TestBuildHost(t *testing.T) {
+	configtest.Init()
+	config.App.ServiceID = serviceSchemaID
+
+	base := &rbac.Host{
+		Schemas: []rbac.Schema{{ID: serviceSchemaID}, {ID: otherSchemaID}},
+	}
+
+	host, overrides := buildHost(base, []*RoleDTO.Full{
+		{Name: "user", Permissions: rbac.SelfReadPermission, IsDefault: true},
+		{Name: "admin", Permissions: rbac.ReadPermission | rbac.DeletePermission,
+			ResourcePermissions: map[string]uint16{"cache": rbac.ReadPermission},
+		},
+		{SchemaID: otherSchemaID, Name: "user", Permissions: rbac.ReadPermission},
+		{SchemaID: otherSchemaID, Name: "editor", Permissions: rbac.UpdatePermission, IsDefault: true},
+		// Schema doesn't exist in base host, so role must be ignored
+		{SchemaID: "unknown", Name: "ghost", Permissions: rbac.ReadPermission},
+	})
+
+	if err := rbac.ValidateHost(host); err != nil {
+		t.Fatalf("Built host is invalid: %v", err)
+	}
+
+	if len(host.GlobalRoles) != 2 || len(host.DefaultRoles) != 1 || host.DefaultRoles[0].Name != "user" {
+		t.Errorf("Unexpected global roles: %v, default roles: %v", host.GlobalRoles, host.DefaultRoles)
+	}
+
+	service, _ := host.GetSchema(serviceSchemaID)
+	if len(service.Roles) != 2 {
+		t.Errorf("Service schema must have only global roles, got: %v", service.Roles)
+	}
+
+	other, _ := host.GetSchema(otherSchemaID)
+	if len(other.Roles) != 3 {
+		t.Errorf("Expected 3 roles in other schema, got: %v", other.Roles)
+	}
+	if role, _ := findRole(other.Roles, "user"); role.Permissions != rbac.ReadPermission {
+		t.Errorf("Schema specific role must override global one, got permissions: %d", role.Permissions)
+	}
+	if len(other.DefaultRoles) != 1 || other.DefaultRoles[0].Name != "editor" {
+		t.Errorf("Unexpected default roles of other schema: %v", other.DefaultRoles)
+	}
+	for _, schema := range host.Schemas {
+		if _, ok := findRole(schema.Roles, "ghost"); ok {
+			t.Errorf("Role of unknown schema was added to %s schema", schema.ID)
+		}
+	}
+
+	if overrides["admin"]["cache"] != rbac.ReadPermission || len(overrides) != 1 {
+		t.Errorf("Unexpected resource permissions: %v", overrides)
+	}
+}
+
+func TestRolesFromHostRoundTrip(t *testing.T) {
+	configtest.Init()
+	config.App.ServiceID = serviceSchemaID
+
+	user := rbac.NewRole("user", rbac.SelfReadPermission)
+	admin := rbac.NewRole("admin", rbac.ReadPermission|rbac.UpdatePermission)
+	restrictedUser := rbac.NewRole("user", 0)
+
+	base := &rbac.Host{
+		DefaultRoles: []rbac.Role{user},
+		GlobalRoles:  []rbac.Role{user, admin},
+		Schemas: []rbac.Schema{
+			{ID: serviceSchemaID, Roles: []rbac.Role{user, admin}},
+			{ID: otherSchemaID, Roles: []rbac.Role{restrictedUser, admin}},
+		},
+	}
+
+	dtos := rolesFromHost(base)
+
+	// 2 global roles + overridden "user" role of the other schema
+	if len(dtos) != 3 {
+		t.Fatalf("Expected 3 roles, got %d", len(dtos))
+	}
+
+	host, _ := buildHost(base, dtos)
+
+	for i, schema := range host.Schemas {
+		for _, expected := range base.Schemas[i].Roles {
+			role, ok := findRole(schema.Roles, expected.Name)
+			if !ok || role != expected {
+				t.Errorf("Schema %s: expected role %v, got %v", schema.ID, expected, role)
+			}
+		}
+	}
+}
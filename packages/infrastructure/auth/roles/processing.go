@@ -0,0 +1,69 @@
+package roles
+
+import (
+	"context"
+	"sentinel/packages/infrastructure/DB"
+	"strconv"
+	"time"
+)
+
+// How often role grants are checked for activation and expiration.
+// Grants are also checked at token issuance, so this only affects
+// how fast already issued tokens will be invalidated (via user version bump).
+// Role change requests are checked for expiration with the same interval.
+const processingInterval = time.Minute
+
+var stopProcessing context.CancelFunc
+
+// Starts periodic activation of scheduled role grants, expiration of outdated ones
+// and expiration of not approved role change requests.
+// DB must be initialized before calling this function.
+func StartProcessing() {
+	log.Info("Starting role grants and role change requests processing...", nil)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	stopProcessing = cancelFunc
+
+	go func() {
+		ticker := time.NewTicker(processingInterval)
+		defer ticker.Stop()
+
+		for {
+			processGrants()
+			expireRoleChangeRequests()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Info("Starting role grants and role change requests processing: OK", nil)
+}
+
+func processGrants() {
+	activated, expired, err := DB.Database.ProcessRoleGrants()
+	if err != nil {
+		// Error is already logged, grants will be processed on the next tick
+		return
+	}
+	if activated != 0 || expired != 0 {
+		log.Info(
+			"Role grants processed: "+strconv.Itoa(activated)+" activated, "+strconv.Itoa(expired)+" expired",
+			nil,
+		)
+	}
+}
+
+func expireRoleChangeRequests() {
+	expired, err := DB.Database.ExpireRoleChangeRequests()
+	if err != nil {
+		// Error is already logged, requests will be expired on the next tick
+		return
+	}
+	if expired != 0 {
+		log.Info("Role change requests expired: "+strconv.Itoa(expired), nil)
+	}
+}
@@ -0,0 +1,104 @@
+package roles
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/authz"
+	"slices"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+func configRejected(reason string) *Error.Status {
+	return Error.NewStatusError("RBAC configuration was rejected: "+reason, http.StatusConflict)
+}
+
+// Checks that new host can be safely applied and returns schema of this service from it.
+func validateHost(host *rbac.Host) (*rbac.Schema, *Error.Status) {
+	if err := rbac.ValidateHost(host); err != nil {
+		return nil, configRejected(err.Error())
+	}
+
+	schema, e := host.GetSchema(config.App.ServiceID)
+	if e != nil {
+		return nil, configRejected(e.Error())
+	}
+
+	roles := rbac.GetRolesNames(schema.Roles)
+
+	// Default roles are assigned to new users of this service
+	for _, defaultRole := range host.DefaultRoles {
+		if !slices.Contains(roles, defaultRole.Name) {
+			return nil, configRejected("default role '" + defaultRole.Name + "' doesn't exist in schema of this service")
+		}
+	}
+
+	assignedRoles, err := DB.Database.GetAssignedRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assignedRole := range assignedRoles {
+		if !slices.Contains(roles, assignedRole) {
+			return nil, configRejected("role '" + assignedRole + "' is assigned to users, but doesn't exist in schema of this service")
+		}
+	}
+
+	return schema, nil
+}
+
+// Reads RBAC configuration file, validates it and atomically applies it (along with Action Gate Policy).
+// If roles are stored in DB, then only schemas are taken from the file and roles are rebuilt from DB.
+//
+// Configuration is rejected if any role assigned to users or any default role doesn't exist in it,
+// in that case currently used configuration is preserved.
+//
+// Affects only this Sentinel instance.
+func ReloadConfig() (*Diff, *Error.Status) {
+	reloadMut.Lock()
+	defer reloadMut.Unlock()
+
+	log.Info("Reloading RBAC configuration...", nil)
+
+	fileHost, e := authz.LoadConfig()
+	if e != nil {
+		err := configRejected(e.Error())
+		log.Error("Failed to reload RBAC configuration", err.Error(), nil)
+		return nil, err
+	}
+
+	host := fileHost
+	var overrides authz.ResourcePermissions
+
+	if IsManageable() {
+		roles, err := DB.Database.LoadAllRoles()
+		if err != nil {
+			log.Error("Failed to reload RBAC configuration", err.Error(), nil)
+			return nil, err
+		}
+		host, overrides = buildHost(fileHost, roles)
+	}
+
+	schema, err := validateHost(host)
+	if err != nil {
+		log.Error("Failed to reload RBAC configuration", err.Error(), nil)
+		return nil, err
+	}
+
+	diff := newDiff(authz.GetHost(), authz.GetSchema(), host, schema)
+
+	if e := authz.Apply(host, overrides); e != nil {
+		log.Error("Failed to reload RBAC configuration", e.Error(), nil)
+		return nil, Error.StatusInternalError
+	}
+
+	if IsManageable() {
+		baseHost = fileHost
+	}
+
+	log.Info("Reloading RBAC configuration: OK ("+diff.String()+")", nil)
+
+	return diff, nil
+}
@@ -0,0 +1,223 @@
+package roles
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/logger"
+	RoleDTO "sentinel/packages/core/role/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/authz"
+	"sentinel/packages/infrastructure/cache"
+	"strings"
+	"sync"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+	"github.com/google/uuid"
+)
+
+var log = logger.NewSource("ROLES", logger.Default)
+
+// Name of the redis channel via which Sentinel instances are notified about roles changes
+const channelName = "rbac_roles_changed"
+
+const (
+	FileSource     = "file"
+	DatabaseSource = "database"
+)
+
+var (
+	// Host loaded from the RBAC configuration file.
+	// Used as base for hosts built from the roles stored in DB.
+	baseHost *rbac.Host
+	// Serializes reloads, so older roles can't overwrite newer ones
+	reloadMut sync.Mutex
+	cancel    context.CancelFunc
+	// Used to skip notifications about changes made by this instance, since they are already applied
+	instanceID = uuid.NewString()
+)
+
+// Returns true if roles are stored in DB and can be managed via API.
+func IsManageable() bool {
+	return config.Authz.RBACSource == DatabaseSource
+}
+
+var RolesAreNotManageable = Error.NewStatusError(
+	"Roles are loaded from RBAC configuration file and can't be changed via API (see 'rbac-source' config option)",
+	http.StatusConflict,
+)
+
+// Seeds DB with roles from RBAC configuration file (if there are no roles in DB yet),
+// applies roles from DB and starts listening to roles changes made by other Sentinel instances.
+// Does nothing if roles are loaded from configuration file.
+//
+// Authz, DB and cache must be initialized before calling this function.
+func Init() {
+	if !IsManageable() {
+		return
+	}
+
+	log.Info("Initializing...", nil)
+
+	baseHost = authz.GetHost()
+
+	if _, err := DB.Database.SeedRoles(rolesFromHost(baseHost)); err != nil {
+		log.Fatal("Failed to seed roles", err.Error(), nil)
+	}
+
+	if err := Reload(); err != nil {
+		log.Fatal("Failed to load roles", err.Error(), nil)
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+
+	msgs, err := cache.Client.Subscribe(ctx, channelName)
+	if err != nil {
+		cancelFunc()
+		log.Fatal("Failed to subscribe to roles changes", err.Error(), nil)
+		return
+	}
+
+	cancel = cancelFunc
+
+	go func() {
+		for msg := range msgs {
+			senderID, roleID, _ := strings.Cut(msg, ":")
+			if senderID == instanceID {
+				continue
+			}
+			log.Info("Role "+roleID+" was changed, reloading roles...", nil)
+			// Error is already logged
+			_ = Reload()
+		}
+	}()
+
+	log.Info("Initializing: OK", nil)
+}
+
+// Stops listening to roles changes and processing of role grants and role change requests.
+func Stop() {
+	if cancel != nil {
+		cancel()
+	}
+	if stopProcessing != nil {
+		stopProcessing()
+	}
+}
+
+// Loads roles from DB and applies them to the authz.
+// If resulting RBAC host is invalid, then currently used roles are preserved.
+func Reload() *Error.Status {
+	reloadMut.Lock()
+	defer reloadMut.Unlock()
+
+	return reload()
+}
+
+// Same as Reload, but reloadMut must be locked by the caller.
+func reload() *Error.Status {
+	log.Info("Reloading roles...", nil)
+
+	roles, err := DB.Database.LoadAllRoles()
+	if err != nil {
+		log.Error("Failed to reload roles", err.Error(), nil)
+		return err
+	}
+
+	host, overrides := buildHost(baseHost, roles)
+
+	if e := rbac.ValidateHost(host); e != nil {
+		log.Error("Failed to reload roles", e.Error(), nil)
+		return Error.NewStatusError("Invalid RBAC configuration: "+e.Error(), http.StatusInternalServerError)
+	}
+
+	if e := authz.Apply(host, overrides); e != nil {
+		log.Error("Failed to reload roles", e.Error(), nil)
+		return Error.StatusInternalError
+	}
+
+	log.Info("Reloading roles: OK", nil)
+
+	return nil
+}
+
+// Changes roles stored in DB. Roles as they'll be after the change are built via apply and validated,
+// and only if they are valid change is committed via commit, which must return ID of the changed role.
+// After that roles of this instance are reloaded and other Sentinel instances are notified about the change.
+//
+// apply receives roles which are currently stored in DB, it may modify and return them.
+func Change(
+	apply func(roles []*RoleDTO.Full) []*RoleDTO.Full,
+	commit func() (roleID string, err *Error.Status),
+) *Error.Status {
+	reloadMut.Lock()
+	defer reloadMut.Unlock()
+
+	log.Info("Validating roles change...", nil)
+
+	roles, err := DB.Database.LoadAllRoles()
+	if err != nil {
+		log.Error("Failed to validate roles change", err.Error(), nil)
+		return err
+	}
+
+	host, _ := buildHost(baseHost, apply(roles))
+
+	if _, err := validateHost(host); err != nil {
+		log.Error("Failed to validate roles change", err.Error(), nil)
+		return err
+	}
+
+	log.Info("Validating roles change: OK", nil)
+
+	roleID, err := commit()
+	if err != nil {
+		return err
+	}
+
+	// Change is already committed and validated, so only logging errors here
+	_ = reload()
+
+	if err := cache.Client.Publish(channelName, instanceID+":"+roleID); err != nil {
+		log.Error("Failed to notify other instances about change of role "+roleID, err.Error(), nil)
+	}
+
+	return nil
+}
+
+const maxRoleNameLength = 32
+
+var errInvalidRoleName = errors.New(
+	"role name must be non-empty, not longer than 32 characters and consist only of latin letters, digits, '_' and '-'",
+)
+
+func validateRoleName(name string) error {
+	if name == "" || len(name) > maxRoleNameLength {
+		return errInvalidRoleName
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return errInvalidRoleName
+		}
+	}
+	return nil
+}
+
+// Validates role name and checks that schema of the role exists.
+func Validate(dto *RoleDTO.Full) *Error.Status {
+	if err := validateRoleName(dto.Name); err != nil {
+		return Error.NewStatusError("Invalid role name: "+err.Error(), http.StatusBadRequest)
+	}
+
+	if dto.IsGlobal() {
+		return nil
+	}
+
+	if _, err := baseHost.GetSchema(dto.SchemaID); err != nil {
+		return Error.NewStatusError(err.Error(), http.StatusBadRequest)
+	}
+
+	return nil
+}
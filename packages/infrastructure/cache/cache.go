@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	Error "sentinel/packages/common/errors"
 	"sentinel/packages/infrastructure/cache/redis"
 	"time"
@@ -41,6 +42,16 @@ type client interface {
 	ProgressiveDeletePattern(pattern string) *Error.Status
 	// Same as Delete, but uses batch processing
 	ProgressiveDelete(keys []string) *Error.Status
+	// Publishes message to the specified channel
+	Publish(channel string, message string) *Error.Status
+	// Subscribes to the specified channel.
+	// Returned chan will be closed once ctx is done.
+	Subscribe(ctx context.Context, channel string) (<-chan string, *Error.Status)
+	// Appends new entry to the stream and returns it's ID.
+	// Length of the stream is (approximately) limited by maxLen, older entries will be evicted.
+	StreamAppend(stream string, data string, maxLen int64) (string, *Error.Status)
+	// Returns up to 'count' stream entries which was added after entry with specified ID.
+	StreamRead(stream string, afterID string, count int64) ([]redis.StreamEntry, *Error.Status)
 }
 
 var Client client = redis.New()
@@ -14,9 +14,11 @@ const (
 	SessionByID              = "session_by_id"
 	RevokedSessionByID       = "revoked_session_by_id"
 	SessionByDeviceAndUserID = "session_by_device_and_user_id"
+	ReauthCodeBySessionID    = "reauth_code_by_session_id"
 
 	LocationByID        = "location_by_id"
 	LocationBySessionID = "location_by_session_id"
+	LocationByIP        = "location_by_ip"
 )
 
 var KeyBase = map[string]string{
@@ -33,7 +35,9 @@ var KeyBase = map[string]string{
 	SessionByID:              SessionKeyPrefix + "id:",
 	RevokedSessionByID:       RevokedSessionKeyPrefix + "id:",
 	SessionByDeviceAndUserID: SessionKeyPrefix + "device_and_session:",
+	ReauthCodeBySessionID:    SessionKeyPrefix + "reauth_code:",
 
 	LocationByID:        LocationKeyPrefix + "id:",
 	LocationBySessionID: LocationKeyPrefix + "session:",
+	LocationByIP:        LocationKeyPrefix + "ip:",
 }
@@ -359,3 +359,106 @@ func (d *driver) ProgressiveDelete(keys []string) *Error.Status {
 
 	return nil
 }
+
+// Represents single entry of the redis stream
+type StreamEntry struct {
+	ID   string
+	Data string
+}
+
+// Name of the field in which stream entry data is stored
+const streamEntryDataField = "data"
+
+func (d *driver) Publish(channel string, message string) *Error.Status {
+	ctx, cancel := defaultTimeoutContext()
+	defer cancel()
+
+	// Not retrying here, cuz publishing isn't idempotent
+	err := d.client.Publish(ctx, channel, message).Err()
+
+	return handleError("Publish: "+channel, err)
+}
+
+func (d *driver) Subscribe(ctx context.Context, channel string) (<-chan string, *Error.Status) {
+	pubsub := d.client.Subscribe(ctx, channel)
+
+	// Wait for confirmation that subscription is created
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, handleError("Subscribe: "+channel, err)
+	}
+
+	log.Trace("Subscribe: "+channel, nil)
+
+	out := make(chan string)
+
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		// go-redis will automatically reconnect on connection failures
+		msgs := pubsub.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Trace("Unsubscribe: "+channel, nil)
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case out <- msg.Payload:
+				case <-ctx.Done():
+					log.Trace("Unsubscribe: "+channel, nil)
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (d *driver) StreamAppend(stream string, data string, maxLen int64) (string, *Error.Status) {
+	ctx, cancel := defaultTimeoutContext()
+	defer cancel()
+
+	// Not retrying here, cuz otherwise same entry may be appended twice
+	id, err := d.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: map[string]any{streamEntryDataField: data},
+	}).Result()
+
+	return id, handleError("Stream Append: "+stream, err)
+}
+
+func (d *driver) StreamRead(stream string, afterID string, count int64) ([]StreamEntry, *Error.Status) {
+	var msgs []redis.XMessage
+
+	err := d.retry(func(ctx context.Context) error {
+		var err error
+		// "(" makes range exclusive
+		msgs, err = d.client.XRangeN(ctx, stream, "("+afterID, "+", count).Result()
+		return err
+	})
+	if err := handleError("Stream Read: "+stream, err); err != nil {
+		return nil, err
+	}
+
+	entries := make([]StreamEntry, 0, len(msgs))
+
+	for _, msg := range msgs {
+		data, ok := msg.Values[streamEntryDataField].(string)
+		if !ok {
+			log.Error("Invalid stream entry", "Entry "+msg.ID+" of stream "+stream+" has no data", nil)
+			continue
+		}
+		entries = append(entries, StreamEntry{ID: msg.ID, Data: data})
+	}
+
+	return entries, nil
+}
@@ -12,6 +12,8 @@ var (
 	loginChangeAlertEmailBody string
 	//go:embed templates/password-change-alert-email.html
 	passwordChangeAlertEmailBody string
+	//go:embed templates/reauth-code-email.html
+	reauthCodeEmailBody string
 
 	escapedTokenPlaceholder string = url.QueryEscape(string(TokenPlaceholder))
 
@@ -21,6 +23,18 @@ var (
 	activationEmailTemplate string
 	//go:embed templates/new-session-alert-email.template.html
 	newSessionAlertEmailTemplate string
+	//go:embed templates/role-change-requested-email.template.html
+	roleChangeRequestedEmailTemplate string
+	//go:embed templates/role-change-approved-email.template.html
+	roleChangeApprovedEmailTemplate string
+	//go:embed templates/role-change-approval-required-email.template.html
+	roleChangeApprovalRequiredEmailTemplate string
+	//go:embed templates/suspicious-login-alert-email.template.html
+	suspiciousLoginAlertEmailTemplate string
+	//go:embed templates/login-change-confirmation-email.template.html
+	loginChangeConfirmationEmailTemplate string
+	//go:embed templates/login-change-requested-email.template.html
+	loginChangeRequestedEmailTemplate string
 
 	// Must be initialized via email.Run()
 	forgotPasswordEmailBody string
@@ -28,8 +42,34 @@ var (
 	activationEmailBody string
 	// Must be initialized via email.Run()
 	newSessionAlertEmailBody string
+	// Must be initialized via email.Run()
+	roleChangeRequestedEmailBody string
+	// Must be initialized via email.Run()
+	roleChangeApprovedEmailBody string
+	// Must be initialized via email.Run()
+	roleChangeApprovalRequiredEmailBody string
+	// Must be initialized via email.Run()
+	suspiciousLoginAlertEmailBody string
+	// Must be initialized via email.Run()
+	loginChangeConfirmationEmailBody string
+	// Must be initialized via email.Run()
+	loginChangeRequestedEmailBody string
 )
 
+// Adds token placeholder to the query params of the specified url
+func newTokenRedirectURL(rawURL string, param string) string {
+	redirectURL, err := url.Parse(rawURL)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	query := redirectURL.Query()
+	query.Add(param, string(TokenPlaceholder))
+	redirectURL.RawQuery = query.Encode()
+
+	return redirectURL.String()
+}
+
 func initTemplateEmailsBodies() {
 	type activationEmailTemplateValues struct {
 		ActivationURL string
@@ -50,17 +90,8 @@ func initTemplateEmailsBodies() {
 		ResetPasswordURL string
 	}
 
-	redirectURL, err := url.Parse(config.App.PasswordResetRedirectURL)
-	if err != nil {
-		panic(err.Error())
-	}
-
-	query := redirectURL.Query()
-	query.Add("passwordResetToken", string(TokenPlaceholder))
-	redirectURL.RawQuery = query.Encode()
-
 	passwordResetEmailValues := passwordResetEmailTemplateValues{
-		ResetPasswordURL: redirectURL.String(),
+		ResetPasswordURL: newTokenRedirectURL(config.App.PasswordResetRedirectURL, "passwordResetToken"),
 	}
 
 	b, err = parseEmailTemplate(passwordResetEmailTemplate, passwordResetEmailValues)
@@ -84,4 +115,82 @@ func initTemplateEmailsBodies() {
 	}
 
 	newSessionAlertEmailBody = b
+
+	type roleChangeEmailTemplateValues struct {
+		Roles string
+	}
+
+	roleChangeEmailValues := roleChangeEmailTemplateValues{
+		Roles: string(RolesPlaceholder),
+	}
+
+	b, err = parseEmailTemplate(roleChangeRequestedEmailTemplate, roleChangeEmailValues)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	roleChangeRequestedEmailBody = b
+
+	b, err = parseEmailTemplate(roleChangeApprovedEmailTemplate, roleChangeEmailValues)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	roleChangeApprovedEmailBody = b
+
+	b, err = parseEmailTemplate(roleChangeApprovalRequiredEmailTemplate, roleChangeEmailValues)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	roleChangeApprovalRequiredEmailBody = b
+
+	type suspiciousLoginAlertEmailTemplateValues struct {
+		Location string
+		Reasons  string
+	}
+
+	suspiciousLoginAlertEmailValues := suspiciousLoginAlertEmailTemplateValues{
+		Location: string(LocationPlaceholder),
+		Reasons:  string(ReasonsPlaceholder),
+	}
+
+	b, err = parseEmailTemplate(suspiciousLoginAlertEmailTemplate, suspiciousLoginAlertEmailValues)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	suspiciousLoginAlertEmailBody = b
+
+	type loginChangeConfirmationEmailTemplateValues struct {
+		ConfirmURL string
+	}
+
+	loginChangeConfirmationEmailValues := loginChangeConfirmationEmailTemplateValues{
+		ConfirmURL: newTokenRedirectURL(config.App.LoginChangeConfirmRedirectURL, "loginChangeToken"),
+	}
+
+	b, err = parseEmailTemplate(loginChangeConfirmationEmailTemplate, loginChangeConfirmationEmailValues)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	loginChangeConfirmationEmailBody = b
+
+	type loginChangeRequestedEmailTemplateValues struct {
+		Login     string
+		CancelURL string
+	}
+
+	loginChangeRequestedEmailValues := loginChangeRequestedEmailTemplateValues{
+		Login:     string(LoginPlaceholder),
+		CancelURL: newTokenRedirectURL(config.App.LoginChangeCancelRedirectURL, "loginChangeToken"),
+	}
+
+	b, err = parseEmailTemplate(loginChangeRequestedEmailTemplate, loginChangeRequestedEmailValues)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	loginChangeRequestedEmailBody = b
 }
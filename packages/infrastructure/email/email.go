@@ -25,14 +25,28 @@ const (
 	PasswordChangeAlertEmail
 	LoginChangeAlertEmail
 	NewSessionAlertEmail
+	RoleChangeRequestedEmail
+	RoleChangeApprovedEmail
+	SuspiciousLoginAlertEmail
+	LoginChangeConfirmationEmail
+	LoginChangeRequestedEmail
+	RoleChangeApprovalRequiredEmail
+	ReauthCodeEmail
 )
 
 var emailsNames = map[EmailType]string{
-	PasswordResetEmail:       "forgot pasword",
-	ActivationEmail:          "activation",
-	PasswordChangeAlertEmail: "password change alert",
-	LoginChangeAlertEmail:    "login change alert",
-	NewSessionAlertEmail:     "new session alert",
+	PasswordResetEmail:              "forgot pasword",
+	ActivationEmail:                 "activation",
+	PasswordChangeAlertEmail:        "password change alert",
+	LoginChangeAlertEmail:           "login change alert",
+	NewSessionAlertEmail:            "new session alert",
+	RoleChangeRequestedEmail:        "role change requested",
+	RoleChangeApprovedEmail:         "role change approved",
+	SuspiciousLoginAlertEmail:       "suspicious login alert",
+	LoginChangeConfirmationEmail:    "login change confirmation",
+	LoginChangeRequestedEmail:       "login change requested",
+	RoleChangeApprovalRequiredEmail: "role change approval required",
+	ReauthCodeEmail:                 "re-authentication code",
 }
 
 func (t EmailType) Name() (string, bool) {
@@ -43,11 +57,18 @@ func (t EmailType) Name() (string, bool) {
 }
 
 var emailsSubjects = map[EmailType]string{
-	PasswordResetEmail:       "Password reset",
-	ActivationEmail:          "Account activation",
-	PasswordChangeAlertEmail: "Security Alert: password changed",
-	LoginChangeAlertEmail:    "Security Alert: login changed",
-	NewSessionAlertEmail:     "Security Alert: new sign-in",
+	PasswordResetEmail:              "Password reset",
+	ActivationEmail:                 "Account activation",
+	PasswordChangeAlertEmail:        "Security Alert: password changed",
+	LoginChangeAlertEmail:           "Security Alert: login changed",
+	NewSessionAlertEmail:            "Security Alert: new sign-in",
+	RoleChangeRequestedEmail:        "Security Alert: role change requested",
+	RoleChangeApprovedEmail:         "Security Alert: role change approved",
+	SuspiciousLoginAlertEmail:       "Security Alert: suspicious sign-in",
+	LoginChangeConfirmationEmail:    "Login change confirmation",
+	LoginChangeRequestedEmail:       "Security Alert: login change requested",
+	RoleChangeApprovalRequiredEmail: "Role change request awaits approval",
+	ReauthCodeEmail:                 "Re-authentication code",
 }
 
 func (t EmailType) Subject() (string, bool) {
@@ -85,6 +106,10 @@ type SubstitutionPlaceholder string
 const (
 	TokenPlaceholder    SubstitutionPlaceholder = "{{token}}"
 	LocationPlaceholder SubstitutionPlaceholder = "{{location}}"
+	RolesPlaceholder    SubstitutionPlaceholder = "{{roles}}"
+	ReasonsPlaceholder  SubstitutionPlaceholder = "{{reasons}}"
+	LoginPlaceholder    SubstitutionPlaceholder = "{{login}}"
+	CodePlaceholder     SubstitutionPlaceholder = "{{code}}"
 )
 
 type Substitutions = map[SubstitutionPlaceholder]string
@@ -165,6 +190,22 @@ func (e *Email) Send() *Error.Status {
 		body = loginChangeAlertEmailBody
 	case NewSessionAlertEmail:
 		body = substitute(newSessionAlertEmailBody, LocationPlaceholder, e.substitutions)
+	case RoleChangeRequestedEmail:
+		body = substitute(roleChangeRequestedEmailBody, RolesPlaceholder, e.substitutions)
+	case RoleChangeApprovedEmail:
+		body = substitute(roleChangeApprovedEmailBody, RolesPlaceholder, e.substitutions)
+	case RoleChangeApprovalRequiredEmail:
+		body = substitute(roleChangeApprovalRequiredEmailBody, RolesPlaceholder, e.substitutions)
+	case SuspiciousLoginAlertEmail:
+		body = substitute(suspiciousLoginAlertEmailBody, LocationPlaceholder, e.substitutions)
+		body = substitute(body, ReasonsPlaceholder, e.substitutions)
+	case LoginChangeConfirmationEmail:
+		body = substitute(loginChangeConfirmationEmailBody, TokenPlaceholder, e.substitutions)
+	case LoginChangeRequestedEmail:
+		body = substitute(loginChangeRequestedEmailBody, TokenPlaceholder, e.substitutions)
+		body = substitute(body, LoginPlaceholder, e.substitutions)
+	case ReauthCodeEmail:
+		body = substitute(reauthCodeEmailBody, CodePlaceholder, e.substitutions)
 	default:
 		log.Panic("Failed to send email", "Invalid email type", nil)
 		return Error.StatusInternalError
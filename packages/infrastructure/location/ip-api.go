@@ -4,10 +4,8 @@ import (
 	"context"
 	"net"
 	"net/http"
-	"sentinel/packages/common/config"
 	"sentinel/packages/common/encoding/json"
 	Error "sentinel/packages/common/errors"
-	"sentinel/packages/common/logger"
 	"sentinel/packages/common/structs"
 	LocationDTO "sentinel/packages/core/location/DTO"
 	"time"
@@ -15,8 +13,6 @@ import (
 	"github.com/sony/gobreaker/v2"
 )
 
-var log = logger.NewSource("LOCATION PROVIDER", logger.Default)
-
 const fields string = "?fields=status,message,country,countryCode,region,regionName,city,lat,lon,isp"
 
 type geoIpResponseBody struct {
@@ -26,33 +22,41 @@ type geoIpResponseBody struct {
 	LocationDTO.Full `json:",inline"`
 }
 
-var circuitBreaker = gobreaker.NewCircuitBreaker[*LocationDTO.Full](gobreaker.Settings{
-	Name:        "Location provider",
-	Interval:    time.Second,
-	Timeout:     time.Second * 20,
-	MaxRequests: 10,
-})
-
 var requestTimeout = time.Second * 5
 
-// Returns user location in raw string format based on specified ip address
-func GetLocationFromIP(ip string) (*LocationDTO.Full, *Error.Status) {
-	log.Trace("Getting location for "+ip+"...", nil)
+// Online provider which uses http://ip-api.com.
+// IMPORTANT: User IPs are sent to the third party.
+type ipAPIProvider struct {
+	circuitBreaker *gobreaker.CircuitBreaker[*LocationDTO.Full]
+}
 
-	if config.Debug.Enabled && config.Debug.LocationIP != "" && ip != config.Debug.LocationIP {
-		log.Debug("IP changed: "+ip+" -> "+config.Debug.LocationIP, nil)
-		ip = config.Debug.LocationIP
+func newIPAPIProvider() *ipAPIProvider {
+	return &ipAPIProvider{
+		circuitBreaker: gobreaker.NewCircuitBreaker[*LocationDTO.Full](gobreaker.Settings{
+			Name:        "Location provider",
+			Interval:    time.Second,
+			Timeout:     time.Second * 20,
+			MaxRequests: 10,
+		}),
 	}
+}
+
+func (p *ipAPIProvider) Name() string {
+	return IPAPIProviderName
+}
 
-	dto, err := circuitBreaker.Execute(func() (*LocationDTO.Full, error) {
+func (p *ipAPIProvider) Lookup(ip net.IP) (*LocationDTO.Full, *Error.Status) {
+	rawIP := ip.String()
+
+	dto, err := p.circuitBreaker.Execute(func() (*LocationDTO.Full, error) {
 		var res *http.Response
 		var err error
 
 		structs.SetTimeout(context.Background(), requestTimeout, func(ctx context.Context) {
-			res, err = http.Get("http://ip-api.com/json/" + ip + fields)
+			res, err = http.Get("http://ip-api.com/json/" + rawIP + fields)
 		})
 		if err != nil {
-			log.Error("Failed to get location for "+ip, err.Error(), nil)
+			log.Error("Failed to get location for "+rawIP, err.Error(), nil)
 			return nil, Error.NewStatusError(
 				"Failed to get user location:"+err.Error(),
 				http.StatusInternalServerError,
@@ -68,16 +72,13 @@ func GetLocationFromIP(ip string) (*LocationDTO.Full, *Error.Status) {
 			)
 		}
 		if body.Status != "success" {
-			log.Error("Failed to get location for "+ip, body.Message, nil)
+			log.Error("Failed to get location for "+rawIP, body.Message, nil)
 			return nil, Error.NewStatusError(
 				"Failed to read response body from location provider",
 				http.StatusInternalServerError,
 			)
 		}
 
-		body.Full.CreatedAt = time.Now()
-		body.Full.IP = net.ParseIP(ip)
-
 		return &body.Full, nil
 	})
 
@@ -93,7 +94,9 @@ func GetLocationFromIP(ip string) (*LocationDTO.Full, *Error.Status) {
 		return nil, Error.StatusInternalError
 	}
 
-	log.Trace("Getting location for "+ip+": OK", nil)
-
 	return dto, nil
 }
+
+func (p *ipAPIProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,198 @@
+package locationprovider
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	Error "sentinel/packages/common/errors"
+	LocationDTO "sentinel/packages/core/location/DTO"
+	"sync"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+var locationNotFound = Error.NewStatusError(
+	"Location of the IP address wasn't found",
+	http.StatusInternalServerError,
+)
+
+// Database file which is reopened once it's changed on disk.
+type mmdbFile struct {
+	path    string
+	reader  *geoip2.Reader
+	modTime time.Time
+}
+
+func openMMDBFile(path string) (*mmdbFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mmdbFile{
+		path:    path,
+		reader:  reader,
+		modTime: info.ModTime(),
+	}, nil
+}
+
+// Returns true if file was modified since it was opened.
+func (f *mmdbFile) changed() bool {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		// File may be missing for a moment while it's being replaced
+		return false
+	}
+	return !info.ModTime().Equal(f.modTime)
+}
+
+// Offline provider which uses MaxMind-format (mmdb) databases: City (required) and ASN (optional).
+// Databases are checked for changes periodically and reloaded without restart.
+type mmdbProvider struct {
+	mut  sync.RWMutex
+	city *mmdbFile
+	// Can be nil
+	asn *mmdbFile
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newMMDBProvider(cityPath string, asnPath string, reloadInterval time.Duration) (*mmdbProvider, error) {
+	if cityPath == "" {
+		return nil, errors.New("path to City database isn't specified")
+	}
+
+	city, err := openMMDBFile(cityPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var asn *mmdbFile
+	if asnPath != "" {
+		if asn, err = openMMDBFile(asnPath); err != nil {
+			city.reader.Close()
+			return nil, err
+		}
+	}
+
+	p := &mmdbProvider{
+		city: city,
+		asn:  asn,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go p.watch(reloadInterval)
+
+	return p, nil
+}
+
+func (p *mmdbProvider) watch(interval time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.reload(&p.city)
+			p.reload(&p.asn)
+		}
+	}
+}
+
+// Reopens database file if it was changed. Old file is kept in use if new one can't be opened.
+func (p *mmdbProvider) reload(file **mmdbFile) {
+	p.mut.RLock()
+	current := *file
+	p.mut.RUnlock()
+
+	if current == nil || !current.changed() {
+		return
+	}
+
+	log.Info("Reloading "+current.path+"...", nil)
+
+	updated, err := openMMDBFile(current.path)
+	if err != nil {
+		log.Error("Failed to reload "+current.path, err.Error(), nil)
+		return
+	}
+
+	p.mut.Lock()
+	*file = updated
+	p.mut.Unlock()
+
+	// No one can use old reader at this point, since it's replaced under write lock
+	if err := current.reader.Close(); err != nil {
+		log.Error("Failed to close "+current.path, err.Error(), nil)
+	}
+
+	log.Info("Reloading "+current.path+": OK", nil)
+}
+
+func (p *mmdbProvider) Name() string {
+	return MMDBProviderName
+}
+
+func (p *mmdbProvider) Lookup(ip net.IP) (*LocationDTO.Full, *Error.Status) {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	city, err := p.city.reader.City(ip)
+	if err != nil {
+		log.Error("Failed to get location for "+ip.String(), err.Error(), nil)
+		return nil, Error.StatusInternalError
+	}
+	if city.Country.IsoCode == "" {
+		log.Error("Failed to get location for "+ip.String(), "IP address isn't present in the City database", nil)
+		return nil, locationNotFound
+	}
+
+	dto := &LocationDTO.Full{
+		Country:   city.Country.IsoCode,
+		City:      city.City.Names["en"],
+		Latitude:  float32(city.Location.Latitude),
+		Longitude: float32(city.Location.Longitude),
+	}
+	if len(city.Subdivisions) != 0 {
+		dto.Region = city.Subdivisions[0].IsoCode
+	}
+
+	if p.asn != nil {
+		// ISP is optional, so lookup failure isn't critical
+		if asn, err := p.asn.reader.ASN(ip); err == nil {
+			dto.ISP = asn.AutonomousSystemOrganization
+		} else {
+			log.Error("Failed to get ISP for "+ip.String(), err.Error(), nil)
+		}
+	}
+
+	return dto, nil
+}
+
+func (p *mmdbProvider) Close() error {
+	close(p.stop)
+	<-p.done
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	err := p.city.reader.Close()
+	if p.asn != nil {
+		err = errors.Join(err, p.asn.reader.Close())
+	}
+
+	return err
+}
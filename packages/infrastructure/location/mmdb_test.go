@@ -0,0 +1,319 @@
+package locationprovider
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Minimal encoder of the MaxMind DB format (https://maxmind.github.io/MaxMind-DB/),
+// enough to build test databases in which every IPv4 address resolves to the same record.
+
+type mmdbDouble float64
+
+type mmdbUint16 uint16
+
+type mmdbUint32 uint32
+
+type mmdbUint64 uint64
+
+func mmdbControl(buf *bytes.Buffer, typ byte, size int) {
+	extended := typ > 7
+
+	first := typ << 5
+	if extended {
+		first = 0
+	}
+
+	switch {
+	case size < 29:
+		buf.WriteByte(first | byte(size))
+	case size < 29+256:
+		buf.WriteByte(first | 29)
+	default:
+		panic("mmdb: value is too large for the test encoder")
+	}
+
+	if extended {
+		buf.WriteByte(typ - 7)
+	}
+
+	if size >= 29 {
+		buf.WriteByte(byte(size - 29))
+	}
+}
+
+func mmdbUint(buf *bytes.Buffer, typ byte, v uint64, maxSize int) {
+	raw := make([]byte, 8)
+	binary.BigEndian.PutUint64(raw, v)
+
+	raw = bytes.TrimLeft(raw[8-maxSize:], "\x00")
+
+	mmdbControl(buf, typ, len(raw))
+	buf.Write(raw)
+}
+
+func mmdbEncode(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case string:
+		mmdbControl(buf, 2, len(v))
+		buf.WriteString(v)
+	case mmdbDouble:
+		mmdbControl(buf, 3, 8)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(float64(v)))
+	case mmdbUint16:
+		mmdbUint(buf, 5, uint64(v), 2)
+	case mmdbUint32:
+		mmdbUint(buf, 6, uint64(v), 4)
+	case mmdbUint64:
+		mmdbUint(buf, 9, uint64(v), 8)
+	case map[string]any:
+		mmdbControl(buf, 7, len(v))
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			mmdbEncode(buf, key)
+			mmdbEncode(buf, v[key])
+		}
+	case []any:
+		mmdbControl(buf, 11, len(v))
+		for _, item := range v {
+			mmdbEncode(buf, item)
+		}
+	default:
+		panic("mmdb: unsupported type")
+	}
+}
+
+// Returns IPv4 database with the single search tree node,
+// both records of which are pointing to the given record.
+func newMMDB(databaseType string, record map[string]any) []byte {
+	const nodeCount = 1
+
+	var buf bytes.Buffer
+
+	// Record value greater than node count is a pointer to data section:
+	// nodeCount + 16 (data section separator) + offset in data section.
+	pointer := []byte{0, 0, nodeCount + 16}
+	buf.Write(pointer)
+	buf.Write(pointer)
+
+	buf.Write(make([]byte, 16))
+
+	mmdbEncode(&buf, record)
+
+	buf.WriteString("\xAB\xCD\xEFMaxMind.com")
+
+	mmdbEncode(&buf, map[string]any{
+		"binary_format_major_version": mmdbUint16(2),
+		"binary_format_minor_version": mmdbUint16(0),
+		"build_epoch":                 mmdbUint64(time.Now().Unix()),
+		"database_type":               databaseType,
+		"description":                 map[string]any{"en": "Test database"},
+		"ip_version":                  mmdbUint16(4),
+		"languages":                   []any{"en"},
+		"node_count":                  mmdbUint32(nodeCount),
+		"record_size":                 mmdbUint16(24),
+	})
+
+	return buf.Bytes()
+}
+
+func newCityMMDB(country string, city string) []byte {
+	return newMMDB("GeoLite2-City", map[string]any{
+		"country": map[string]any{"iso_code": country},
+		"city":    map[string]any{"names": map[string]any{"en": city}},
+		"location": map[string]any{
+			"latitude":  mmdbDouble(52.52),
+			"longitude": mmdbDouble(13.405),
+		},
+	})
+}
+
+func newASNMMDB(organization string) []byte {
+	return newMMDB("GeoLite2-ASN", map[string]any{
+		"autonomous_system_number":       mmdbUint32(3320),
+		"autonomous_system_organization": organization,
+	})
+}
+
+// Replaces file atomically (as database updaters do) and ensures that its modification time is changed.
+func replaceFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, data, 0o644))
+	require.NoError(t, os.Chtimes(tmp, modTime, modTime))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+// Waits until lookup of the test IP returns location in the expected country.
+func waitForCountry(t *testing.T, p *mmdbProvider, country string) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		location, err := p.Lookup(testIP)
+		require.Nil(t, err)
+		if location.Country == country {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("database wasn't reloaded: country %s was expected", country)
+}
+
+var testIP = net.ParseIP("203.0.113.10")
+
+func TestMMDBProvider(t *testing.T) {
+	dir := t.TempDir()
+	cityPath := filepath.Join(dir, "city.mmdb")
+	asnPath := filepath.Join(dir, "asn.mmdb")
+
+	require.NoError(t, os.WriteFile(cityPath, newCityMMDB("DE", "Berlin"), 0o644))
+	require.NoError(t, os.WriteFile(asnPath, newASNMMDB("Deutsche Telekom AG"), 0o644))
+
+	t.Run("lookup", func(t *testing.T) {
+		p, err := newMMDBProvider(cityPath, asnPath, time.Hour)
+		require.NoError(t, err)
+		defer p.Close()
+
+		location, e := p.Lookup(testIP)
+		require.Nil(t, e)
+		assert.Equal(t, "DE", location.Country)
+		assert.Equal(t, "Berlin", location.City)
+		assert.Equal(t, "Deutsche Telekom AG", location.ISP)
+		assert.InDelta(t, 52.52, location.Latitude, 0.001)
+		assert.InDelta(t, 13.405, location.Longitude, 0.001)
+	})
+
+	t.Run("ASN database is optional", func(t *testing.T) {
+		p, err := newMMDBProvider(cityPath, "", time.Hour)
+		require.NoError(t, err)
+		defer p.Close()
+
+		location, e := p.Lookup(testIP)
+		require.Nil(t, e)
+		assert.Equal(t, "DE", location.Country)
+		assert.Empty(t, location.ISP)
+	})
+
+	t.Run("City database is required", func(t *testing.T) {
+		_, err := newMMDBProvider("", asnPath, time.Hour)
+		assert.Error(t, err)
+
+		_, err = newMMDBProvider(filepath.Join(dir, "missing.mmdb"), "", time.Hour)
+		assert.Error(t, err)
+	})
+}
+
+func TestMMDBProviderReload(t *testing.T) {
+	dir := t.TempDir()
+	cityPath := filepath.Join(dir, "city.mmdb")
+	asnPath := filepath.Join(dir, "asn.mmdb")
+
+	modTime := time.Now().Add(-time.Hour)
+
+	replaceFile(t, cityPath, newCityMMDB("DE", "Berlin"), modTime)
+	replaceFile(t, asnPath, newASNMMDB("Deutsche Telekom AG"), modTime)
+
+	p, err := newMMDBProvider(cityPath, asnPath, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer p.Close()
+
+	waitForCountry(t, p, "DE")
+
+	t.Run("changed databases are reloaded", func(t *testing.T) {
+		modTime = modTime.Add(time.Minute)
+
+		replaceFile(t, cityPath, newCityMMDB("US", "New York"), modTime)
+		replaceFile(t, asnPath, newASNMMDB("Verizon Business"), modTime)
+
+		waitForCountry(t, p, "US")
+
+		// Both databases are reloaded on the same tick, but ASN may be reloaded right after City
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			location, e := p.Lookup(testIP)
+			require.Nil(t, e)
+			if location.ISP == "Verizon Business" {
+				assert.Equal(t, "New York", location.City)
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatal("ASN database wasn't reloaded")
+	})
+
+	t.Run("invalid database isn't loaded", func(t *testing.T) {
+		modTime = modTime.Add(time.Minute)
+
+		replaceFile(t, cityPath, []byte("not a database"), modTime)
+
+		// Give watcher enough time to try to reload the database
+		time.Sleep(100 * time.Millisecond)
+
+		location, e := p.Lookup(testIP)
+		require.Nil(t, e)
+		assert.Equal(t, "US", location.Country)
+
+		// Valid database is loaded once it's available
+		modTime = modTime.Add(time.Minute)
+
+		replaceFile(t, cityPath, newCityMMDB("FR", "Paris"), modTime)
+
+		waitForCountry(t, p, "FR")
+	})
+
+	t.Run("lookups during reload", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		stop := make(chan struct{})
+		errs := make(chan string, 8)
+
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					if _, e := p.Lookup(testIP); e != nil {
+						errs <- e.Error()
+						return
+					}
+				}
+			}()
+		}
+
+		for _, country := range []string{"DE", "US", "FR"} {
+			modTime = modTime.Add(time.Minute)
+			replaceFile(t, cityPath, newCityMMDB(country, "City"), modTime)
+			waitForCountry(t, p, country)
+		}
+
+		close(stop)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Errorf("Lookup() failed during reload: %s", err)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package locationprovider
+
+import (
+	"net"
+	"net/http"
+	"sentinel/packages/common/config"
+	pbencoding "sentinel/packages/common/encoding/protobuf"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/logger"
+	LocationDTO "sentinel/packages/core/location/DTO"
+	"sentinel/packages/infrastructure/cache"
+	"time"
+)
+
+var log = logger.NewSource("LOCATION PROVIDER", logger.Default)
+
+// Names of the providers which can be selected in config
+const (
+	IPAPIProviderName = "ip-api"
+	MMDBProviderName  = "mmdb"
+)
+
+// Resolves location of the IP address.
+// Returned DTO must have only location fields (country, region, city, coordinates, ISP) filled in.
+type Provider interface {
+	Name() string
+	Lookup(ip net.IP) (*LocationDTO.Full, *Error.Status)
+	// Releases all resources held by provider
+	Close() error
+}
+
+var provider Provider
+
+// Initializes provider selected in config.
+// Until this function is called GetLocationFromIP will return error.
+func Init() {
+	log.Info("Initializing "+config.Location.Provider+" provider...", nil)
+
+	switch config.Location.Provider {
+	case IPAPIProviderName:
+		provider = newIPAPIProvider()
+	case MMDBProviderName:
+		p, err := newMMDBProvider(
+			config.Location.MMDBCityPath,
+			config.Location.MMDBASNPath,
+			config.Location.MMDBReloadInterval(),
+		)
+		if err != nil {
+			log.Fatal("Failed to initialize "+config.Location.Provider+" provider", err.Error(), nil)
+			return
+		}
+		provider = p
+	default:
+		log.Fatal("Failed to initialize location provider", "Unknown provider: "+config.Location.Provider, nil)
+		return
+	}
+
+	log.Info("Initializing "+config.Location.Provider+" provider: OK", nil)
+}
+
+func Stop() {
+	if provider == nil {
+		return
+	}
+
+	log.Info("Stopping "+provider.Name()+" provider...", nil)
+
+	if err := provider.Close(); err != nil {
+		log.Error("Failed to stop "+provider.Name()+" provider", err.Error(), nil)
+		return
+	}
+
+	log.Info("Stopping "+provider.Name()+" provider: OK", nil)
+}
+
+func getCachedLocation(ip string) (*LocationDTO.Full, bool) {
+	key := cache.KeyBase[cache.LocationByIP] + ip
+
+	cached, hit := cache.Client.Get(key)
+	if !hit {
+		return nil, false
+	}
+
+	dto, err := pbencoding.UnmarshallFullLocationDTO([]byte(cached))
+	if err != nil {
+		// Cached data is invalid, so it must be deleted to prevent same error in future
+		cache.Client.Delete(key)
+		return nil, false
+	}
+
+	return dto, true
+}
+
+func cacheLocation(ip string, dto *LocationDTO.Full) {
+	if raw, err := pbencoding.MarshallFullLocationDTO(dto); err == nil {
+		cache.Client.SetWithTTL(cache.KeyBase[cache.LocationByIP]+ip, raw, config.Location.CacheTTL())
+	}
+}
+
+// Returns user location based on specified ip address.
+// Results are cached per IP (see config.Location.CacheTTL).
+func GetLocationFromIP(ip string) (*LocationDTO.Full, *Error.Status) {
+	log.Trace("Getting location for "+ip+"...", nil)
+
+	if config.Debug.Enabled && config.Debug.LocationIP != "" && ip != config.Debug.LocationIP {
+		log.Debug("IP changed: "+ip+" -> "+config.Debug.LocationIP, nil)
+		ip = config.Debug.LocationIP
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		log.Error("Failed to get location for "+ip, "Invalid IP address", nil)
+		return nil, Error.NewStatusError("Invalid IP address: "+ip, http.StatusBadRequest)
+	}
+
+	dto, hit := getCachedLocation(ip)
+	if !hit {
+		if provider == nil {
+			log.Error("Failed to get location for "+ip, "Location provider isn't initialized", nil)
+			return nil, Error.StatusInternalError
+		}
+
+		var err *Error.Status
+
+		dto, err = provider.Lookup(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		cacheLocation(ip, dto)
+	}
+
+	dto.ID = ""
+	dto.SessionID = ""
+	dto.DeletedAt = time.Time{}
+	dto.CreatedAt = time.Now()
+	dto.IP = addr
+
+	log.Trace("Getting location for "+ip+": OK", nil)
+
+	return dto, nil
+}
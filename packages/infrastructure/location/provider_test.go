@@ -0,0 +1,197 @@
+package locationprovider
+
+import (
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sentinel/packages/common/config"
+	"sentinel/packages/common/config/configtest"
+	Error "sentinel/packages/common/errors"
+	LocationDTO "sentinel/packages/core/location/DTO"
+	"sentinel/packages/infrastructure/cache"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mr *miniredis.Miniredis
+
+func TestMain(m *testing.M) {
+	configtest.Init()
+
+	config.Cache.RawPoolTimeout = "1s"
+	config.Cache.RawOperationTimeout = "1s"
+	config.Cache.RawTTL = "1m"
+	config.Location.RawCacheTTL = "1h"
+
+	var err error
+	if mr, err = miniredis.Run(); err != nil {
+		panic(err)
+	}
+
+	config.Secret.CacheURI = mr.Addr()
+
+	cache.Client.Connect()
+
+	code := m.Run()
+
+	cache.Client.Close()
+	mr.Close()
+
+	os.Exit(code)
+}
+
+// Provider which counts lookups and resolves each IP to the same location
+type countingProvider struct {
+	lookups atomic.Int32
+	err     *Error.Status
+}
+
+func (p *countingProvider) Name() string {
+	return "counting"
+}
+
+func (p *countingProvider) Lookup(ip net.IP) (*LocationDTO.Full, *Error.Status) {
+	p.lookups.Add(1)
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &LocationDTO.Full{
+		Country:   "DE",
+		Region:    "BE",
+		City:      "Berlin",
+		Latitude:  52.52,
+		Longitude: 13.405,
+		ISP:       "Deutsche Telekom AG",
+	}, nil
+}
+
+func (p *countingProvider) Close() error {
+	return nil
+}
+
+// Replaces provider with a new counting one and drops all cached locations
+func useCountingProvider(t *testing.T) *countingProvider {
+	mr.FlushAll()
+
+	p := &countingProvider{}
+
+	original := provider
+	provider = p
+	t.Cleanup(func() {
+		provider = original
+	})
+
+	return p
+}
+
+func TestGetLocationFromIPCache(t *testing.T) {
+	const ip = "203.0.113.10"
+
+	key := cache.KeyBase[cache.LocationByIP] + ip
+
+	t.Run("miss", func(t *testing.T) {
+		p := useCountingProvider(t)
+
+		location, err := GetLocationFromIP(ip)
+		require.Nil(t, err)
+
+		assert.Equal(t, int32(1), p.lookups.Load())
+		assert.Equal(t, "DE", location.Country)
+		assert.Equal(t, "Berlin", location.City)
+		assert.True(t, location.IP.Equal(net.ParseIP(ip)))
+
+		assert.True(t, mr.Exists(key))
+		assert.Equal(t, config.Location.CacheTTL(), mr.TTL(key))
+	})
+
+	t.Run("hit", func(t *testing.T) {
+		p := useCountingProvider(t)
+
+		first, err := GetLocationFromIP(ip)
+		require.Nil(t, err)
+
+		second, err := GetLocationFromIP(ip)
+		require.Nil(t, err)
+
+		assert.Equal(t, int32(1), p.lookups.Load())
+		assert.Equal(t, first.Country, second.Country)
+		assert.Equal(t, first.City, second.City)
+		assert.Equal(t, first.ISP, second.ISP)
+		assert.Equal(t, first.Latitude, second.Latitude)
+		assert.True(t, second.IP.Equal(net.ParseIP(ip)))
+		assert.Empty(t, second.ID)
+		assert.Empty(t, second.SessionID)
+	})
+
+	t.Run("locations are cached per IP", func(t *testing.T) {
+		p := useCountingProvider(t)
+
+		_, err := GetLocationFromIP(ip)
+		require.Nil(t, err)
+
+		_, err = GetLocationFromIP("198.51.100.1")
+		require.Nil(t, err)
+
+		assert.Equal(t, int32(2), p.lookups.Load())
+	})
+
+	t.Run("expired entry", func(t *testing.T) {
+		p := useCountingProvider(t)
+
+		_, err := GetLocationFromIP(ip)
+		require.Nil(t, err)
+
+		mr.FastForward(config.Location.CacheTTL() + time.Second)
+
+		_, err = GetLocationFromIP(ip)
+		require.Nil(t, err)
+
+		assert.Equal(t, int32(2), p.lookups.Load())
+	})
+
+	t.Run("invalid cached data", func(t *testing.T) {
+		p := useCountingProvider(t)
+
+		require.NoError(t, mr.Set(key, "invalid"))
+
+		location, err := GetLocationFromIP(ip)
+		require.Nil(t, err)
+
+		assert.Equal(t, int32(1), p.lookups.Load())
+		assert.Equal(t, "DE", location.Country)
+
+		// Invalid entry is replaced with the valid one
+		_, err = GetLocationFromIP(ip)
+		require.Nil(t, err)
+
+		assert.Equal(t, int32(1), p.lookups.Load())
+	})
+
+	t.Run("failed lookups aren't cached", func(t *testing.T) {
+		p := useCountingProvider(t)
+		p.err = locationNotFound
+
+		_, err := GetLocationFromIP(ip)
+		assert.Equal(t, locationNotFound, err)
+		assert.False(t, mr.Exists(key))
+
+		_, err = GetLocationFromIP(ip)
+		assert.Equal(t, locationNotFound, err)
+		assert.Equal(t, int32(2), p.lookups.Load())
+	})
+
+	t.Run("invalid IP", func(t *testing.T) {
+		p := useCountingProvider(t)
+
+		_, err := GetLocationFromIP("not an IP")
+		assert.NotNil(t, err)
+		assert.Equal(t, int32(0), p.lookups.Load())
+	})
+}
@@ -6,5 +6,11 @@ import (
 )
 
 func TargetedActionDTOFromClaims(targetUID string, claims *token.Claims) *ActionDTO.UserTargeted {
-	return ActionDTO.NewUserTargeted(targetUID, claims.Subject, claims.Roles)
+	act := ActionDTO.NewUserTargeted(targetUID, claims.Subject, claims.Roles)
+
+	if claims.Actor != nil {
+		act.ImpersonatorUID = claims.Actor.Subject
+	}
+
+	return act
 }
@@ -0,0 +1,73 @@
+package rolemapper
+
+import (
+	RoleDTO "sentinel/packages/core/role/DTO"
+
+	rbac "github.com/abaxoth0/SentinelRBAC"
+)
+
+func PermissionsToBitmask(p *RoleDTO.Permissions) rbac.Permissions {
+	var bitmask rbac.Permissions
+
+	flags := []struct {
+		set        bool
+		permission rbac.Permissions
+	}{
+		{p.Create, rbac.CreatePermission},
+		{p.SelfCreate, rbac.SelfCreatePermission},
+		{p.Read, rbac.ReadPermission},
+		{p.SelfRead, rbac.SelfReadPermission},
+		{p.Update, rbac.UpdatePermission},
+		{p.SelfUpdate, rbac.SelfUpdatePermission},
+		{p.Delete, rbac.DeletePermission},
+		{p.SelfDelete, rbac.SelfDeletePermission},
+	}
+
+	for _, flag := range flags {
+		if flag.set {
+			bitmask |= flag.permission
+		}
+	}
+
+	return bitmask
+}
+
+func PermissionsFromBitmask(bitmask rbac.Permissions) RoleDTO.Permissions {
+	return RoleDTO.Permissions{
+		Create:     bitmask&rbac.CreatePermission != 0,
+		SelfCreate: bitmask&rbac.SelfCreatePermission != 0,
+		Read:       bitmask&rbac.ReadPermission != 0,
+		SelfRead:   bitmask&rbac.SelfReadPermission != 0,
+		Update:     bitmask&rbac.UpdatePermission != 0,
+		SelfUpdate: bitmask&rbac.SelfUpdatePermission != 0,
+		Delete:     bitmask&rbac.DeletePermission != 0,
+		SelfDelete: bitmask&rbac.SelfDeletePermission != 0,
+	}
+}
+
+func PublicFromFull(dto *RoleDTO.Full) *RoleDTO.Public {
+	var resourcePermissions map[string]RoleDTO.Permissions
+
+	if len(dto.ResourcePermissions) != 0 {
+		resourcePermissions = make(map[string]RoleDTO.Permissions, len(dto.ResourcePermissions))
+		for resource, permissions := range dto.ResourcePermissions {
+			resourcePermissions[resource] = PermissionsFromBitmask(permissions)
+		}
+	}
+
+	return &RoleDTO.Public{
+		ID:                  dto.ID,
+		SchemaID:            dto.SchemaID,
+		Name:                dto.Name,
+		Permissions:         PermissionsFromBitmask(dto.Permissions),
+		ResourcePermissions: resourcePermissions,
+		IsDefault:           dto.IsDefault,
+		CreatedAt:           dto.CreatedAt,
+		UpdatedAt:           dto.UpdatedAt,
+	}
+}
+
+// Resource permissions aren't included, since RBAC roles doesn't support them (see authz.ResourcePermissions)
+func RoleFromFull(dto *RoleDTO.Full) rbac.Role {
+	return rbac.NewRole(dto.Name, dto.Permissions)
+}
@@ -6,6 +6,16 @@ import (
 )
 
 func PayloadFromClaims(claims *token.Claims) *UserDTO.Payload {
+	var authTime int64
+	if claims.AuthTime != nil {
+		authTime = claims.AuthTime.Unix()
+	}
+
+	var impersonatorID string
+	if claims.Actor != nil {
+		impersonatorID = claims.Actor.Subject
+	}
+
 	return &UserDTO.Payload{
 		ID:        claims.Subject,
 		Login:     claims.Login,
@@ -13,5 +23,10 @@ func PayloadFromClaims(claims *token.Claims) *UserDTO.Payload {
 		Roles:     claims.Roles,
 		Version:   claims.Version,
 		Audience:  claims.Audience,
+		AuthTime:  authTime,
+		AMR:       claims.AMR,
+
+		ImpersonatorID: impersonatorID,
+		OrganizationID: claims.Organization,
 	}
 }
@@ -0,0 +1,152 @@
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"sentinel/packages/common/logger"
+	"sentinel/packages/infrastructure/cache"
+	"sync"
+)
+
+var log = logger.NewSource("REVOCATION", logger.Default)
+
+// Amount of events that can be buffered for a single subscriber.
+// If subscriber doesn't keep up, it will be unsubscribed
+// (it's expected that client will reconnect using Last-Event-ID).
+const subscriptionBufferSize = 64
+
+type Subscription struct {
+	events chan *Event
+}
+
+// Returned chan will be closed once subscription is canceled.
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+type broker struct {
+	mut     sync.Mutex
+	subs    map[*Subscription]struct{}
+	cancel  context.CancelFunc
+	stopped bool
+}
+
+func newBroker() *broker {
+	return &broker{
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+func (b *broker) subscribe() *Subscription {
+	sub := &Subscription{
+		events: make(chan *Event, subscriptionBufferSize),
+	}
+
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	// There won't be any new events, so just close subscription right away
+	if b.stopped {
+		close(sub.events)
+		return sub
+	}
+
+	b.subs[sub] = struct{}{}
+
+	return sub
+}
+
+func (b *broker) unsubscribe(sub *Subscription) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.remove(sub)
+}
+
+// Mutex must be locked by the caller
+func (b *broker) remove(sub *Subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.events)
+}
+
+func (b *broker) dispatch(e *Event) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	for sub := range b.subs {
+		select {
+		case sub.events <- e:
+		default:
+			log.Warning("Subscriber doesn't keep up with events, unsubscribing it", nil)
+			b.remove(sub)
+		}
+	}
+}
+
+func (b *broker) stop() {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.stopped = true
+
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+}
+
+var defaultBroker = newBroker()
+
+// Starts listening to revocation events published by all Sentinel instances.
+// Cache connection must be established before calling this function.
+func Start() {
+	log.Info("Starting events listener...", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	msgs, err := cache.Client.Subscribe(ctx, channelName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to start events listener", err.Error(), nil)
+		return
+	}
+
+	defaultBroker.cancel = cancel
+
+	go func() {
+		for msg := range msgs {
+			e := new(Event)
+			if err := json.Unmarshal([]byte(msg), e); err != nil {
+				log.Error("Failed to decode event", err.Error(), nil)
+				continue
+			}
+			defaultBroker.dispatch(e)
+		}
+	}()
+
+	log.Info("Starting events listener: OK", nil)
+}
+
+// Stops listening to revocation events and cancels all subscriptions.
+func Stop() {
+	log.Info("Stopping events listener...", nil)
+
+	if defaultBroker.cancel != nil {
+		defaultBroker.cancel()
+	}
+	defaultBroker.stop()
+
+	log.Info("Stopping events listener: OK", nil)
+}
+
+// Subscribes to revocation events published after this call.
+// Subscription must be canceled via Unsubscribe once it's no longer needed.
+func Subscribe() *Subscription {
+	return defaultBroker.subscribe()
+}
+
+func Unsubscribe(sub *Subscription) {
+	defaultBroker.unsubscribe(sub)
+}
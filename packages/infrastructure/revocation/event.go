@@ -0,0 +1,77 @@
+package revocation
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type EventType string
+
+const (
+	// Session was revoked, all tokens issued for it are no longer valid
+	SessionRevokedEvent EventType = "session_revoked"
+	// User data was changed, all tokens with version lower then specified are no longer valid
+	UserVersionChangedEvent EventType = "user_version_changed"
+)
+
+type Event struct {
+	// ID of the event in the revocation stream. Can be used as Last-Event-ID.
+	ID        string    `json:"id,omitempty"`
+	Type      EventType `json:"type"`
+	UserID    string    `json:"user_id"`
+	SessionID string    `json:"session_id,omitempty"`
+	Version   uint32    `json:"version,omitempty"`
+	// Unix timestamp (seconds)
+	Timestamp int64 `json:"timestamp"`
+}
+
+func NewSessionRevokedEvent(UID string, sessionID string) *Event {
+	return &Event{
+		Type:      SessionRevokedEvent,
+		UserID:    UID,
+		SessionID: sessionID,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+func NewUserVersionChangedEvent(UID string, version uint32) *Event {
+	return &Event{
+		Type:      UserVersionChangedEvent,
+		UserID:    UID,
+		Version:   version,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+// Redis stream entry ID format: <milliseconds>-<sequence number>
+var eventIdRegExp = regexp.MustCompile(`^\d+-\d+$`)
+
+func IsValidEventID(id string) bool {
+	return eventIdRegExp.MatchString(id)
+}
+
+func parseEventID(id string) (ms uint64, seq uint64) {
+	rawMs, rawSeq, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(rawMs, 10, 64)
+	seq, _ = strconv.ParseUint(rawSeq, 10, 64)
+	return ms, seq
+}
+
+// Returns -1 if event with ID 'a' is older then event with ID 'b',
+// 1 if it's newer and 0 if IDs are equal.
+// Both IDs must be valid (see IsValidEventID).
+func CompareEventIDs(a, b string) int {
+	aMs, aSeq := parseEventID(a)
+	bMs, bSeq := parseEventID(b)
+
+	switch {
+	case aMs < bMs, aMs == bMs && aSeq < bSeq:
+		return -1
+	case aMs > bMs, aMs == bMs && aSeq > bSeq:
+		return 1
+	default:
+		return 0
+	}
+}
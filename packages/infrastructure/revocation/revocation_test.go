@@ -0,0 +1,89 @@
+package revocation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidEventID(t *testing.T) {
+	assert.True(t, IsValidEventID("1700000000000-0"))
+	assert.True(t, IsValidEventID("1700000000000-15"))
+	assert.False(t, IsValidEventID(""))
+	assert.False(t, IsValidEventID("1700000000000"))
+	assert.False(t, IsValidEventID("1700000000000-"))
+	assert.False(t, IsValidEventID("abc-1"))
+	assert.False(t, IsValidEventID("$"))
+}
+
+func TestCompareEventIDs(t *testing.T) {
+	assert.Equal(t, 0, CompareEventIDs("10-1", "10-1"))
+	assert.Equal(t, -1, CompareEventIDs("10-1", "10-2"))
+	assert.Equal(t, 1, CompareEventIDs("10-2", "10-1"))
+	assert.Equal(t, -1, CompareEventIDs("9-5", "10-0"))
+	assert.Equal(t, 1, CompareEventIDs("11-0", "10-5"))
+	// Must be compared as numbers, not as strings
+	assert.Equal(t, 1, CompareEventIDs("100-0", "99-0"))
+}
+
+func TestBroker(t *testing.T) {
+	t.Run("dispatch to all subscribers", func(t *testing.T) {
+		b := newBroker()
+
+		sub1 := b.subscribe()
+		sub2 := b.subscribe()
+
+		e := NewSessionRevokedEvent("uid", "sid")
+		b.dispatch(e)
+
+		assert.Same(t, e, <-sub1.Events())
+		assert.Same(t, e, <-sub2.Events())
+	})
+
+	t.Run("unsubscribe closes subscription", func(t *testing.T) {
+		b := newBroker()
+
+		sub := b.subscribe()
+		b.unsubscribe(sub)
+		// Must not panic on second call
+		b.unsubscribe(sub)
+
+		_, ok := <-sub.Events()
+		assert.False(t, ok)
+
+		b.dispatch(NewUserVersionChangedEvent("uid", 2))
+		assert.Empty(t, b.subs)
+	})
+
+	t.Run("slow subscriber is unsubscribed", func(t *testing.T) {
+		b := newBroker()
+
+		slow := b.subscribe()
+
+		for range subscriptionBufferSize + 1 {
+			b.dispatch(NewUserVersionChangedEvent("uid", 2))
+		}
+
+		received := 0
+		for range slow.Events() {
+			received++
+		}
+
+		assert.Equal(t, subscriptionBufferSize, received)
+		assert.Empty(t, b.subs)
+	})
+
+	t.Run("stop closes all subscriptions", func(t *testing.T) {
+		b := newBroker()
+
+		sub := b.subscribe()
+		b.stop()
+
+		_, ok := <-sub.Events()
+		assert.False(t, ok)
+
+		late := b.subscribe()
+		_, ok = <-late.Events()
+		assert.False(t, ok)
+	})
+}
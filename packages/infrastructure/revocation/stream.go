@@ -0,0 +1,96 @@
+package revocation
+
+import (
+	"encoding/json"
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/infrastructure/cache"
+)
+
+const (
+	// Name of the redis stream that stores recent events (used for replaying missed events)
+	streamKey = "revocation_events_stream"
+	// Name of the redis channel via which events are delivered to all Sentinel instances
+	channelName = "revocation_events"
+)
+
+var InvalidEventID = Error.NewStatusError(
+	"Invalid event ID (expected format: <milliseconds>-<sequence>)",
+	http.StatusBadRequest,
+)
+
+// Saves event in the revocation stream and publishes it to all Sentinel instances.
+// On success ID of the event will be set.
+func Publish(e *Event) *Error.Status {
+	log.Trace("Publishing "+string(e.Type)+" event for user "+e.UserID+"...", nil)
+
+	e.ID = ""
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Error("Failed to publish "+string(e.Type)+" event", err.Error(), nil)
+		return Error.StatusInternalError
+	}
+
+	id, status := cache.Client.StreamAppend(streamKey, string(data), config.Auth.RevocationStreamMaxLen)
+	if status != nil {
+		log.Error("Failed to publish "+string(e.Type)+" event", status.Error(), nil)
+		return status
+	}
+
+	e.ID = id
+
+	msg, err := json.Marshal(e)
+	if err != nil {
+		log.Error("Failed to publish "+string(e.Type)+" event", err.Error(), nil)
+		return Error.StatusInternalError
+	}
+
+	if status := cache.Client.Publish(channelName, string(msg)); status != nil {
+		log.Error("Failed to publish "+string(e.Type)+" event", status.Error(), nil)
+		return status
+	}
+
+	log.Trace("Publishing "+string(e.Type)+" event for user "+e.UserID+": OK", nil)
+
+	return nil
+}
+
+// Same as Publish, but only logs error instead of returning it.
+// Designed for cases when event is published after changes was already commited,
+// so failure to publish it can't (and shouldn't) affect the result of operation.
+func TryPublish(e *Event) {
+	_ = Publish(e)
+}
+
+// Returns all events which are still stored in the revocation stream and were added after event with specified ID.
+func Since(lastEventID string) ([]*Event, *Error.Status) {
+	if !IsValidEventID(lastEventID) {
+		return nil, InvalidEventID
+	}
+
+	log.Trace("Getting events since "+lastEventID+"...", nil)
+
+	entries, err := cache.Client.StreamRead(streamKey, lastEventID, config.Auth.RevocationStreamMaxLen)
+	if err != nil {
+		log.Error("Failed to get events since "+lastEventID, err.Error(), nil)
+		return nil, err
+	}
+
+	events := make([]*Event, 0, len(entries))
+
+	for _, entry := range entries {
+		e := new(Event)
+		if err := json.Unmarshal([]byte(entry.Data), e); err != nil {
+			log.Error("Failed to decode event "+entry.ID, err.Error(), nil)
+			continue
+		}
+		e.ID = entry.ID
+		events = append(events, e)
+	}
+
+	log.Trace("Getting events since "+lastEventID+": OK", nil)
+
+	return events, nil
+}
@@ -0,0 +1,214 @@
+package scheduler
+
+import (
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parsed cron expression. Each field is a bit set of allowed values.
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// True if day of month or day of week doesn't start with "*".
+	// If both of them are restricted, then day matches if any of them matches (as in standard cron).
+	dayOfMonthRestricted bool
+	dayOfWeekRestricted  bool
+}
+
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	minuteField     = cronField{"minute", 0, 59}
+	hourField       = cronField{"hour", 0, 23}
+	dayOfMonthField = cronField{"day of month", 1, 31}
+	monthField      = cronField{"month", 1, 12}
+	// 7 is Sunday as well as 0
+	dayOfWeekField = cronField{"day of week", 0, 7}
+)
+
+var macros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// Parses cron expression in format "<minute> <hour> <day of month> <month> <day of week>".
+// Each field supports "*", single values, ranges ("1-5"), steps ("*/15", "0-30/10") and lists ("1,15,30").
+// Macros @hourly, @daily, @weekly, @monthly and @yearly are supported as well.
+func ParseSchedule(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if macro, ok := macros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.New("invalid cron expression '" + expr + "': expected 5 fields, got " + strconv.Itoa(len(fields)))
+	}
+
+	var err error
+	s := new(Schedule)
+
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dayOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dayOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1
+		s.dayOfWeek &^= 1 << 7
+	}
+
+	s.dayOfMonthRestricted = !strings.HasPrefix(fields[2], "*")
+	s.dayOfWeekRestricted = !strings.HasPrefix(fields[4], "*")
+
+	return s, nil
+}
+
+func (f cronField) parse(raw string) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(raw, ",") {
+		values, err := f.parsePart(part)
+		if err != nil {
+			return 0, err
+		}
+		set |= values
+	}
+
+	return set, nil
+}
+
+func (f cronField) parsePart(part string) (uint64, error) {
+	invalid := func(reason string) error {
+		return errors.New("invalid " + f.name + " '" + part + "': " + reason)
+	}
+
+	rangePart, rawStep, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(rawStep)
+		if err != nil || step < 1 {
+			return 0, invalid("step must be a positive integer")
+		}
+	}
+
+	var from, to int
+
+	if rangePart == "*" {
+		from, to = f.min, f.max
+	} else {
+		rawFrom, rawTo, isRange := strings.Cut(rangePart, "-")
+
+		var err error
+		if from, err = strconv.Atoi(rawFrom); err != nil {
+			return 0, invalid("integer expected")
+		}
+		to = from
+		if isRange {
+			if to, err = strconv.Atoi(rawTo); err != nil {
+				return 0, invalid("integer expected")
+			}
+		} else if hasStep {
+			// "5/15" means "5-<max>/15"
+			to = f.max
+		}
+	}
+
+	if from < f.min || to > f.max {
+		return 0, invalid("value must be between " + strconv.Itoa(f.min) + " and " + strconv.Itoa(f.max))
+	}
+	if from > to {
+		return 0, invalid("start of the range is greater than its end")
+	}
+
+	var set uint64
+	for v := from; v <= to; v += step {
+		set |= 1 << v
+	}
+
+	return set, nil
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<v) != 0
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dom := has(s.dayOfMonth, t.Day())
+	dow := has(s.dayOfWeek, int(t.Weekday()))
+
+	if s.dayOfMonthRestricted && s.dayOfWeekRestricted {
+		return dom || dow
+	}
+
+	return dom && dow
+}
+
+// Max time range in which next activation is searched.
+// Expressions like "0 0 30 2 *" are never activated.
+const maxNextSearchRange = time.Hour * 24 * 366 * 5
+
+// Returns time of the next activation of the schedule which is strictly after t (in UTC, with minute precision).
+// Returns zero time if there are no activations in the next 5 years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC()
+	limit := t.Add(maxNextSearchRange)
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			// Jump straight to the next allowed minute of the current hour (if there is any)
+			next := s.minute >> (t.Minute() + 1)
+			if next == 0 {
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			} else {
+				t = t.Add(time.Minute * time.Duration(bits.TrailingZeros64(next)+1))
+			}
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
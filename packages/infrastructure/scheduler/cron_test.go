@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, expr string) *Schedule {
+	t.Helper()
+
+	s, err := ParseSchedule(expr)
+	if err != nil {
+		t.Fatalf("Failed to parse '%s': %v", expr, err)
+	}
+
+	return s
+}
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@never",
+	}
+
+	for _, expr := range invalid {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("Expected error for '%s'", expr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	now := date(2025, time.July, 16, 10, 7)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", date(2025, time.July, 16, 10, 8)},
+		{"*/15 * * * *", date(2025, time.July, 16, 10, 15)},
+		{"7 * * * *", date(2025, time.July, 16, 11, 7)},
+		{"5/20 * * * *", date(2025, time.July, 16, 10, 25)},
+		{"0,30 9-17 * * *", date(2025, time.July, 16, 10, 30)},
+		{"0 3 * * *", date(2025, time.July, 17, 3, 0)},
+		{"@daily", date(2025, time.July, 17, 0, 0)},
+		{"@hourly", date(2025, time.July, 16, 11, 0)},
+		{"@weekly", date(2025, time.July, 20, 0, 0)},
+		{"@monthly", date(2025, time.August, 1, 0, 0)},
+		{"0 0 * * 7", date(2025, time.July, 20, 0, 0)},
+		{"0 0 * * 1-5", date(2025, time.July, 17, 0, 0)},
+		{"0 0 29 2 *", date(2028, time.February, 29, 0, 0)},
+		{"0 12 31 * *", date(2025, time.July, 31, 12, 0)},
+		// Day matches if either day of month or day of week matches
+		{"0 0 1 * 5", date(2025, time.July, 18, 0, 0)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, test := range tests {
+		if next := mustParse(t, test.expr).Next(now); !next.Equal(test.expected) {
+			t.Errorf("'%s': expected %v, got %v", test.expr, test.expected, next)
+		}
+	}
+}
+
+func TestScheduleNextIsStrictlyAfter(t *testing.T) {
+	s := mustParse(t, "0 * * * *")
+
+	now := date(2025, time.January, 1, 0, 0)
+
+	if next := s.Next(now); !next.Equal(date(2025, time.January, 1, 1, 0)) {
+		t.Errorf("Expected next hour, got %v", next)
+	}
+	if next := s.Next(now.Add(time.Second * 30)); !next.Equal(date(2025, time.January, 1, 1, 0)) {
+		t.Errorf("Expected next hour, got %v", next)
+	}
+}
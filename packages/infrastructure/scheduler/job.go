@@ -0,0 +1,217 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sentinel/packages/common/config"
+	JobDTO "sentinel/packages/core/job/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metrics of the job collected by this instance since its start
+type Metrics struct {
+	// Runs made by this instance
+	Runs     int64 `json:"runs" example:"12"`
+	Failures int64 `json:"failures" example:"0"`
+	// Runs skipped cuz job was run by another instance
+	Skipped int64 `json:"skipped" example:"3"`
+	// Total amount of purged (or archived) rows
+	AffectedRows    int64      `json:"affected-rows" example:"4210"`
+	LastRunAt       *time.Time `json:"last-run-at,omitempty" example:"2025-07-20T23:54:14.503Z"`
+	LastRunStatus   string     `json:"last-run-status,omitempty" example:"succeeded"`
+	LastRunDuration int64      `json:"last-run-duration-ms" example:"153"`
+}
+
+type JobStatus struct {
+	Name     string `json:"name" example:"expired-sessions"`
+	Schedule string `json:"schedule" example:"*/30 * * * *"`
+	// How long records are kept before they are purged
+	Retention string `json:"retention" example:"720h"`
+	Mode      string `json:"mode" example:"purge"`
+	// Time of the next run on this instance, absent if scheduler is disabled
+	NextRunAt *time.Time `json:"next-run-at,omitempty" example:"2025-07-21T00:00:00Z"`
+	Metrics   Metrics    `json:"metrics"`
+}
+
+var errInterrupted = errors.New("interrupted by shutdown")
+
+type job struct {
+	name      string
+	schedule  *Schedule
+	settings  config.SchedulerJob
+	retention time.Duration
+	purge     purgeFunc
+
+	mut       sync.Mutex
+	nextRunAt time.Time
+	metrics   Metrics
+}
+
+func (j *job) status() *JobStatus {
+	j.mut.Lock()
+	defer j.mut.Unlock()
+
+	status := &JobStatus{
+		Name:      j.name,
+		Schedule:  j.settings.Schedule,
+		Retention: j.settings.RawRetention,
+		Mode:      j.settings.Mode,
+		Metrics:   j.metrics,
+	}
+	if !j.nextRunAt.IsZero() {
+		nextRunAt := j.nextRunAt
+		status.NextRunAt = &nextRunAt
+	}
+
+	return status
+}
+
+func (j *job) setNextRunAt(t time.Time) {
+	j.mut.Lock()
+	j.nextRunAt = t
+	j.mut.Unlock()
+}
+
+func (j *job) loop(ctx context.Context) {
+	defer running.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Warning("Job "+j.name+" won't be run, since its schedule has no activations", nil)
+			return
+		}
+
+		j.setNextRunAt(next)
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		j.run(ctx, next)
+	}
+}
+
+// Runs the job if it's not running on another instance and wasn't already run since scheduledAt.
+func (j *job) run(ctx context.Context, scheduledAt time.Time) {
+	unlock, acquired, err := DB.Database.TryLockJob(j.name)
+	if err != nil {
+		log.Error("Failed to run job "+j.name, err.Error(), nil)
+		j.record(nil, err)
+		return
+	}
+	if !acquired {
+		log.Trace("Job "+j.name+" is running on another instance, run skipped", nil)
+		j.skip()
+		return
+	}
+	defer unlock()
+
+	// Another instance could finish this run before lock was acquired
+	alreadyRun, err := DB.Database.HasJobRunSince(j.name, scheduledAt.Local())
+	if err != nil {
+		log.Error("Failed to run job "+j.name, err.Error(), nil)
+		j.record(nil, err)
+		return
+	}
+	if alreadyRun {
+		log.Trace("Job "+j.name+" was already run by another instance, run skipped", nil)
+		j.skip()
+		return
+	}
+
+	log.Info("Running job "+j.name+"...", nil)
+
+	run := &JobDTO.Run{
+		Job:        j.name,
+		InstanceID: instanceID,
+		StartedAt:  time.Now(),
+	}
+
+	purge := &JobDTO.Purge{
+		Before:  run.StartedAt.Add(-j.retention),
+		Archive: j.settings.Mode == ArchiveMode,
+		Limit:   config.Scheduler.BatchSize,
+	}
+
+	var runErr error
+
+	for {
+		if ctx.Err() != nil {
+			runErr = errInterrupted
+			break
+		}
+
+		count, err := j.purge(purge)
+		run.AffectedRows += count
+		if err != nil {
+			runErr = err
+			break
+		}
+		if count == 0 {
+			break
+		}
+	}
+
+	run.FinishedAt = time.Now()
+	run.Status = JobDTO.SucceededStatus
+	if runErr != nil {
+		run.Status = JobDTO.FailedStatus
+		run.Error = runErr.Error()
+	}
+
+	// Error is already logged, run just won't be present in the job history
+	_ = DB.Database.SaveJobRun(run)
+
+	j.record(run, runErr)
+
+	if runErr != nil {
+		log.Error(
+			"Failed to run job "+j.name+" (affected rows: "+strconv.FormatInt(run.AffectedRows, 10)+")",
+			runErr.Error(),
+			nil,
+		)
+		return
+	}
+
+	log.Info(
+		"Running job "+j.name+": OK (affected rows: "+strconv.FormatInt(run.AffectedRows, 10)+
+			", duration: "+run.FinishedAt.Sub(run.StartedAt).String()+")",
+		nil,
+	)
+}
+
+func (j *job) skip() {
+	j.mut.Lock()
+	j.metrics.Skipped++
+	j.mut.Unlock()
+}
+
+// Updates metrics of the job. Run is nil if job failed before it was started.
+func (j *job) record(run *JobDTO.Run, err error) {
+	j.mut.Lock()
+	defer j.mut.Unlock()
+
+	if err != nil {
+		j.metrics.Failures++
+	}
+	if run == nil {
+		return
+	}
+
+	startedAt := run.StartedAt
+
+	j.metrics.Runs++
+	j.metrics.AffectedRows += run.AffectedRows
+	j.metrics.LastRunAt = &startedAt
+	j.metrics.LastRunStatus = run.Status
+	j.metrics.LastRunDuration = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+}
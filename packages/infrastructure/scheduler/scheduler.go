@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/logger"
+	JobDTO "sentinel/packages/core/job/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+var log = logger.NewSource("SCHEDULER", logger.Default)
+
+const (
+	ExpiredSessionsJob   = "expired-sessions"
+	OrphanedLocationsJob = "orphaned-locations"
+	SoftDeletedUsersJob  = "soft-deleted-users"
+	AuditJob             = "audit"
+	JobHistoryJob        = "job-history"
+)
+
+const (
+	PurgeMode   = "purge"
+	ArchiveMode = "archive"
+)
+
+// Purges a single batch of outdated records, returns amount of purged records
+type purgeFunc = func(purge *JobDTO.Purge) (int64, *Error.Status)
+
+// Job name -> purge function
+func purgeFuncs() map[string]purgeFunc {
+	return map[string]purgeFunc{
+		ExpiredSessionsJob:   DB.Database.PurgeExpiredSessions,
+		OrphanedLocationsJob: DB.Database.PurgeOrphanedLocations,
+		SoftDeletedUsersJob:  DB.Database.PurgeSoftDeletedUsers,
+		AuditJob:             DB.Database.PurgeAuditRecords,
+		JobHistoryJob:        DB.Database.PurgeJobRuns,
+	}
+}
+
+// Returns true if there is a job with the specified name (job may be disabled).
+func IsKnownJob(name string) bool {
+	_, ok := purgeFuncs()[name]
+	return ok
+}
+
+var (
+	// Configured jobs sorted by name, set in Start
+	jobs []*job
+	// ID of this instance in the job history
+	instanceID     string
+	stopScheduling context.CancelFunc
+	running        sync.WaitGroup
+)
+
+func newInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return host + ":" + strconv.Itoa(os.Getpid())
+}
+
+func initJobs() {
+	log.Info("Initializing jobs...", nil)
+
+	funcs := purgeFuncs()
+
+	jobs = make([]*job, 0, len(config.Scheduler.Jobs))
+
+	for name, settings := range config.Scheduler.Jobs {
+		purge, ok := funcs[name]
+		if !ok {
+			log.Fatal("Failed to initialize jobs", "Unknown job: "+name, nil)
+		}
+
+		schedule, err := ParseSchedule(settings.Schedule)
+		if err != nil {
+			log.Fatal("Failed to initialize jobs", "Invalid schedule of job "+name+": "+err.Error(), nil)
+		}
+
+		// Login history is built from sessions and their audit records,
+		// so sessions must be kept at least as long as audit records, otherwise history would be lost.
+		if audit, ok := config.Scheduler.Jobs[AuditJob]; ok && name == ExpiredSessionsJob && audit.Retention() > settings.Retention() {
+			log.Warning(
+				"Retention of job "+name+" is less than retention of job "+AuditJob+", "+audit.RawRetention+" will be used instead",
+				nil,
+			)
+			settings.RawRetention = audit.RawRetention
+		}
+
+		jobs = append(jobs, &job{
+			name:      name,
+			schedule:  schedule,
+			settings:  settings,
+			retention: settings.Retention(),
+			purge:     purge,
+		})
+	}
+
+	slices.SortFunc(jobs, func(a, b *job) int {
+		if a.name < b.name {
+			return -1
+		}
+		if a.name > b.name {
+			return 1
+		}
+		return 0
+	})
+
+	log.Info("Initializing jobs: OK", nil)
+}
+
+// Starts all configured jobs, does nothing except jobs initialization if scheduler is disabled.
+// Each job is run only by one instance at a time, since runs are synchronized via Postgres advisory locks.
+// DB must be initialized before calling this function.
+func Start() {
+	initJobs()
+
+	if !config.Scheduler.Enabled {
+		log.Info("Scheduler is disabled, jobs won't be run on this instance", nil)
+		return
+	}
+
+	log.Info("Starting scheduler...", nil)
+
+	instanceID = newInstanceID()
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	stopScheduling = cancelFunc
+
+	for _, j := range jobs {
+		running.Add(1)
+		go j.loop(ctx)
+	}
+
+	log.Info("Starting scheduler: OK", nil)
+}
+
+// Stops scheduling of the jobs and waits until running jobs are interrupted.
+// Jobs are interrupted only between batches, so it doesn't take long.
+func Stop() {
+	if stopScheduling == nil {
+		return
+	}
+
+	log.Info("Stopping scheduler...", nil)
+
+	stopScheduling()
+	running.Wait()
+
+	log.Info("Stopping scheduler: OK", nil)
+}
+
+// Returns status of all configured jobs.
+func Jobs() []*JobStatus {
+	statuses := make([]*JobStatus, len(jobs))
+
+	for i, j := range jobs {
+		statuses[i] = j.status()
+	}
+
+	return statuses
+}
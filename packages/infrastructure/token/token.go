@@ -35,25 +35,50 @@ func (t *SignedToken) TTL() int64 {
 type tokenHeaders = map[string]string
 
 const (
-	SessionIdClaimsKey = "jti"
-	ServiceIdClaimsKey = "iss"
-	UserIdClaimsKey    = "sub"
-	IssuedAtClaimsKey  = "iat"
-	ExpiresAtClaimsKey = "exp"
-	AudienceClaimsKey  = "aud"
-	UserRolesClaimsKey = "roles"
-	UserLoginClaimsKey = "login"
-	VersionClaimsKey   = "version"
+	SessionIdClaimsKey    = "jti"
+	ServiceIdClaimsKey    = "iss"
+	UserIdClaimsKey       = "sub"
+	IssuedAtClaimsKey     = "iat"
+	ExpiresAtClaimsKey    = "exp"
+	AudienceClaimsKey     = "aud"
+	UserRolesClaimsKey    = "roles"
+	UserLoginClaimsKey    = "login"
+	VersionClaimsKey      = "version"
+	AuthTimeClaimsKey     = "auth_time"
+	AMRClaimsKey          = "amr"
+	ActorClaimsKey        = "act"
+	OrganizationClaimsKey = "org"
+)
+
+// IDs of the keys in JWKS (see "kid" header of JWT).
+const (
+	AccessTokenKeyID  = "access-1"
+	RefreshTokenKeyID = "refresh-1"
 )
 
 type Claims struct {
 	Roles   []string `json:"roles"`
 	Login   string   `json:"login"`
 	Version uint32   `json:"version"`
+	// Time when user was authenticated (OpenID Connect Core 1.0, p2).
+	// Unlike "iat", it isn't updated on tokens refresh.
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	// Authentication methods references (RFC 8176)
+	AMR []string `json:"amr,omitempty"`
+	// Set only for impersonation tokens (RFC 8693 p4.1)
+	Actor *ActorClaims `json:"act,omitempty"`
+	// ID of the organization to which token is scoped, roles in such tokens are roles of the user in this organization.
+	// Empty if token isn't scoped to any organization.
+	Organization string `json:"org,omitempty"`
 
 	jwt.RegisteredClaims
 }
 
+// Identifies the party which acts on behalf of the token subject
+type ActorClaims struct {
+	Subject string `json:"sub"`
+}
+
 var audienceLookup map[string]struct{}
 var isInit = false
 
@@ -98,9 +123,11 @@ func newSignedToken(
 
 	now := jwt.NewNumericDate(time.Now())
 	claims := Claims{
-		Login:   payload.Login,
-		Roles:   payload.Roles,
-		Version: payload.Version,
+		Login:        payload.Login,
+		Roles:        payload.Roles,
+		Version:      payload.Version,
+		AMR:          payload.AMR,
+		Organization: payload.OrganizationID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        payload.SessionID,
 			Issuer:    config.App.ServiceID,
@@ -112,6 +139,14 @@ func newSignedToken(
 		},
 	}
 
+	if payload.ImpersonatorID != "" {
+		claims.Actor = &ActorClaims{Subject: payload.ImpersonatorID}
+	}
+
+	if payload.AuthTime != 0 {
+		claims.AuthTime = jwt.NewNumericDate(time.Unix(payload.AuthTime, 0))
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
 
 	for key := range headers {
@@ -137,6 +172,7 @@ func NewAccessToken(payload *UserDTO.Payload) (*SignedToken, *Error.Status) {
 		payload.Audience,
 		tokenHeaders{
 			"typ": "at+jwt",
+			"kid": AccessTokenKeyID,
 		},
 	)
 	if err != nil {
@@ -148,6 +184,36 @@ func NewAccessToken(payload *UserDTO.Payload) (*SignedToken, *Error.Status) {
 	return token, nil
 }
 
+// Creates short-lived access token for impersonation of the payload subject.
+// Impersonator must be specified in payload (see UserDTO.Payload.ImpersonatorID).
+// There are no refresh token for impersonation, once this token expired impersonation must be started again.
+func NewImpersonationToken(payload *UserDTO.Payload) (*SignedToken, *Error.Status) {
+	log.Trace("Creating new impersonation token...", nil)
+
+	if payload.ImpersonatorID == "" {
+		log.Error("Failed to create impersonation token", "Impersonator isn't specified", nil)
+		return nil, Error.StatusInternalError
+	}
+
+	token, err := newSignedToken(
+		payload,
+		config.Auth.ImpersonationTokenTTL(),
+		config.Secret.AccessTokenPrivateKey,
+		payload.Audience,
+		tokenHeaders{
+			"typ": "at+jwt",
+			"kid": AccessTokenKeyID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace("Creating new impersonation token: OK", nil)
+
+	return token, nil
+}
+
 func NewRefreshToken(payload *UserDTO.Payload) (*SignedToken, *Error.Status) {
 	log.Trace("Creating new refresh token...", nil)
 
@@ -156,7 +222,9 @@ func NewRefreshToken(payload *UserDTO.Payload) (*SignedToken, *Error.Status) {
 		config.Auth.RefreshTokenTTL(),
 		config.Secret.RefreshTokenPrivateKey,
 		[]string{config.Auth.SelfAudience},
-		nil,
+		tokenHeaders{
+			"kid": RefreshTokenKeyID,
+		},
 	)
 	if err != nil {
 		return nil, err
@@ -229,6 +297,66 @@ func NewPasswordResetToken(uid string, login string) (*SignedToken, *Error.Statu
 	return token, nil
 }
 
+// Creates long-lived token which identifies device of the user.
+// Device ID is stored in "jti" claim.
+func NewDeviceToken(uid string, deviceID string) (*SignedToken, *Error.Status) {
+	log.Trace("Creating new device token...", nil)
+
+	token, err := newSignedToken(
+		&UserDTO.Payload{
+			ID:        uid,
+			SessionID: deviceID,
+		},
+		config.Auth.DeviceTokenTTL(),
+		config.Secret.DeviceTokenPrivateKey,
+		[]string{config.Auth.SelfAudience},
+		tokenHeaders{
+			"typ": "device",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace("Creating new device token: OK", nil)
+
+	return token, nil
+}
+
+// Types of login change tokens (see "typ" header of JWT).
+// Both types are signed with the same key, so type must be checked after parsing.
+const (
+	LoginChangeConfirmationTokenType = "login-change-confirmation"
+	LoginChangeCancellationTokenType = "login-change-cancellation"
+)
+
+// Creates token for confirmation or cancellation of login change.
+// ID of the login change request is stored in "jti" claim.
+func NewLoginChangeToken(uid string, login string, requestID string, tokenType string, ttl time.Duration) (*SignedToken, *Error.Status) {
+	log.Trace("Creating new "+tokenType+" token...", nil)
+
+	token, err := newSignedToken(
+		&UserDTO.Payload{
+			ID:        uid,
+			Login:     login,
+			SessionID: requestID,
+		},
+		ttl,
+		config.Secret.LoginChangeTokenPrivateKey,
+		[]string{config.Auth.SelfAudience},
+		tokenHeaders{
+			"typ": tokenType,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace("Creating new "+tokenType+" token: OK", nil)
+
+	return token, nil
+}
+
 var jwtParserOptions = []jwt.ParserOption{
 	jwt.WithLeeway(5 * time.Second),
 }
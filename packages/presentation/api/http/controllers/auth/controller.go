@@ -7,6 +7,7 @@ import (
 	Error "sentinel/packages/common/errors"
 	"sentinel/packages/common/validation"
 	ActionDTO "sentinel/packages/core/action/DTO"
+	UserDTO "sentinel/packages/core/user/DTO"
 	"sentinel/packages/infrastructure/DB"
 	"sentinel/packages/infrastructure/auth/authn"
 	"sentinel/packages/infrastructure/email"
@@ -54,14 +55,19 @@ func GetCSRFToken(ctx echo.Context) error {
 }
 
 // @Summary 		Login into the service
-// @Description 	Login endpoint
+// @Description 	Login endpoint.
+// @Description 	If user reached max amount of active sessions, then depending on config either login is rejected (409)
+// @Description 	or least recently used sessions are revoked, in the last case they are listed in evictedSessions.
+// @Description 	Sign-in from the suspicious location (see risk config) is either blocked (403)
+// @Description 	or succeeds, but requires re-authentication before accessing sensitive endpoints.
 // @ID 				login
 // @Tags			auth
 // @Param 			credentials body requestbody.Auth true "User credentials and audience"
 // @Accept			json
 // @Produce			json
 // @Success			200 			{object} 	responsebody.Token
-// @Failure			400,401,500 	{object} 	responsebody.Error
+// @Header 			200 			{string} 	X-Step-Up-Required 			"Set to 'true' when sign-in looks suspicious and re-authentication is required (see /v1/auth/reauth)"
+// @Failure			400,401,403,409,500 {object} 	responsebody.Error
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error			"Session revoked"
@@ -92,7 +98,7 @@ func Login(ctx echo.Context) error {
 		return err
 	}
 
-	return SharedController.Authenticate(ctx, user, body.Audience)
+	return SharedController.Authenticate(ctx, user, body.Audience, authn.PasswordMethod)
 }
 
 // @Summary 		Revoke user session
@@ -103,7 +109,7 @@ func Login(ctx echo.Context) error {
 // @Accept			json
 // @Produce			json
 // @Success			200
-// @Failure			400,401,500 	{object} 	responsebody.Error
+// @Failure			400,401,403,500	{object} 	responsebody.Error
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Router			/v1/auth [delete]
@@ -161,15 +167,21 @@ func Logout(ctx echo.Context) error {
 }
 
 // @Summary 		Refreshes auth tokens
-// @Description 	Create new access and refresh tokens and update current session info
+// @Description 	Create new access and refresh tokens and update current session info.
+// @Description 	Session which exceeded its idle timeout or max lifetime is revoked (491), user must log in again.
+// @Description 	Session used from the suspicious location (see risk config) is either revoked (491)
+// @Description 	or requires re-authentication before accessing sensitive endpoints.
+// @Description 	Changes of browser, OS, device, IP network or country are handled according to the session binding policy:
+// @Description 	session is either refreshed, or alert is sent, or re-authentication is required, or session is revoked (491).
 // @ID 				refresh
 // @Tags			auth
 // @Param 			X-Refresh-Token header string true "Refresh Token (sent as HTTP-Only cookie in actual requests)"
 // @Accept			json
 // @Produce			json
 // @Success			200
+// @Header 			200 			{string} 	X-Step-Up-Required 		"Set to 'true' when session looks suspicious and re-authentication is required (see /v1/auth/reauth)"
 // @Failure			400,401,500 	{object} 	responsebody.Error
-// @Failure			491 			{object} 	responsebody.Error		"Session revoked"
+// @Failure			491 			{object} 	responsebody.Error		"Session revoked, expired or blocked due to suspicious location or binding policy"
 // @Header 			491 			{string} 	X-Session-Revoked 		"Set to 'true' if current user session was revoked"
 // @Router			/v1/auth [put]
 // @Security		CSRF_Header
@@ -210,6 +222,153 @@ func Refresh(ctx echo.Context) error {
 	)
 }
 
+// @Summary 		Re-authenticate user
+// @Description 	Confirms user identity by password and issues new auth tokens with updated authentication time.
+// @Description 	Required by endpoints which responded with 401 and "X-Step-Up-Required" header.
+// @Description 	Users without password (e.g. registered via OAuth) can re-authenticate via one-time code (see /v1/auth/reauth/code).
+// @ID 				reauthenticate
+// @Tags			auth
+// @Param 			password body requestbody.UserPassword true "User password"
+// @Accept			json
+// @Produce			json
+// @Success			200 			{object} 	responsebody.Token
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/auth/reauth [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Reauthenticate(ctx echo.Context) error {
+	var body RequestBody.UserPassword
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	reqMeta := request.GetMetadata(ctx)
+
+	// Copy, since payload in the request context mustn't be modified
+	payload := *SharedController.GetUserPayload(ctx)
+
+	controller.Log.Info("Re-authenticating user "+payload.ID+"...", reqMeta)
+
+	user, err := DB.Database.GetUserByID(payload.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := authn.CompareHashAndPassword(user.Password, body.Password); err != nil {
+		controller.Log.Error("Failed to re-authenticate user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	if err := completeReauthentication(ctx, user, &payload, authn.PasswordMethod); err != nil {
+		return err
+	}
+
+	controller.Log.Info("Re-authenticating user "+payload.ID+": OK", reqMeta)
+
+	return nil
+}
+
+// Updates authentication time of the current session and responds with new auth tokens
+func completeReauthentication(ctx echo.Context, user *UserDTO.Full, payload *UserDTO.Payload, authMethod string) error {
+	SharedController.SetAuthentication(payload, authMethod)
+
+	accessToken, refreshToken, err := SharedController.UpdateSession(ctx, nil, user, payload)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetCookie(cookie.NewAuthCookie(refreshToken))
+
+	return ctx.JSON(
+		http.StatusOK,
+		ResponseBody.Token{
+			Message:     "Пользователь успешно аутентифицирован повторно",
+			AccessToken: accessToken.String(),
+			ExpiresIn:   int(accessToken.TTL()) / 1000,
+		},
+	)
+}
+
+// @Summary 		Switch organization
+// @Description 	Issues new auth tokens scoped to the specified organization, such tokens will contain roles of the user in this organization.
+// @Description 	Organization-scoped tokens can be used only on organization endpoints (/v1/organizations/{orgID}/...).
+// @Description 	If organization ID isn't specified, then issued tokens won't be scoped to any organization.
+// @ID 				switch-organization
+// @Tags			auth
+// @Param 			body body requestbody.SwitchOrganization true "Organization ID"
+// @Accept			json
+// @Produce			json
+// @Success			200 				{object} 	responsebody.Token
+// @Failure			400,401,403,500		{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/auth/organization [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func SwitchOrganization(ctx echo.Context) error {
+	var body RequestBody.SwitchOrganization
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	if body.OrganizationID != "" {
+		if e := validation.UUID(body.OrganizationID); e != nil {
+			return e.ToStatus(
+				"Organization ID is missing",
+				"Organization ID has invalid format (expected UUID)",
+			)
+		}
+	}
+
+	reqMeta := request.GetMetadata(ctx)
+
+	// Copy, since payload in the request context mustn't be modified
+	payload := *SharedController.GetUserPayload(ctx)
+
+	controller.Log.Info("Switching organization of user "+payload.ID+" to '"+body.OrganizationID+"'...", reqMeta)
+
+	user, err := DB.Database.GetUserByID(payload.ID)
+	if err != nil {
+		return err
+	}
+
+	roles, err := SharedController.GetTokenRoles(user, body.OrganizationID)
+	if err != nil {
+		controller.Log.Error("Failed to switch organization of user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	payload.OrganizationID = body.OrganizationID
+	payload.Roles = roles
+	payload.Version = user.Version
+
+	accessToken, refreshToken, err := SharedController.UpdateSession(ctx, nil, user, &payload)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetCookie(cookie.NewAuthCookie(refreshToken))
+
+	controller.Log.Info("Switching organization of user "+payload.ID+" to '"+body.OrganizationID+"': OK", reqMeta)
+
+	return ctx.JSON(
+		http.StatusOK,
+		ResponseBody.Token{
+			Message:     "Организация успешно изменена",
+			AccessToken: accessToken.String(),
+			ExpiresIn:   int(accessToken.TTL()) / 1000,
+		},
+	)
+}
+
 // @Summary 		Verifies user authentication
 // @Description 	Verify that user is logged-in
 // @ID 				verify
@@ -244,7 +403,7 @@ func GetJWKs(ctx echo.Context) error {
 			{
 				Kty: "OKP",
 				Alg: "EdDSA",
-				Kid: "access-1",
+				Kid: token.AccessTokenKeyID,
 				Use: "sig",
 				Crv: "Ed25519",
 				X:   base64.RawURLEncoding.EncodeToString(config.Secret.AccessTokenPublicKey),
@@ -253,7 +412,7 @@ func GetJWKs(ctx echo.Context) error {
 			{
 				Kty: "OKP",
 				Alg: "EdDSA",
-				Kid: "refresh-1",
+				Kid: token.RefreshTokenKeyID,
 				Use: "sig",
 				Crv: "Ed25519",
 				X:   base64.RawURLEncoding.EncodeToString(config.Secret.RefreshTokenPublicKey),
@@ -273,6 +432,7 @@ func GetJWKs(ctx echo.Context) error {
 // @Produce			json
 // @Success			200
 // @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -0,0 +1,135 @@
+package authcontroller
+
+import (
+	"net/http"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/authn"
+	"sentinel/packages/infrastructure/cache"
+	"sentinel/packages/infrastructure/email"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const reauthCodeTTL = 5 * time.Minute
+
+// @Summary 		Send re-authentication code
+// @Description 	Sends one-time code to the login of the current user, which can be used for re-authentication
+// @Description 	via /v1/auth/reauth/code/verify. Designed for users without password (e.g. registered via OAuth).
+// @Description 	Code is bound to the current session and expires in 5 minutes, new code replaces previous one.
+// @ID 				send-reauth-code
+// @Tags			auth
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/auth/reauth/code [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func SendReauthCode(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	payload := SharedController.GetUserPayload(ctx)
+
+	controller.Log.Info("Sending re-authentication code to user "+payload.ID+"...", reqMeta)
+
+	code, hash, err := authn.NewOneTimeCode()
+	if err != nil {
+		controller.Log.Error("Failed to send re-authentication code to user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	if err := cache.Client.SetWithTTL(
+		cache.KeyBase[cache.ReauthCodeBySessionID]+payload.SessionID,
+		hash,
+		reauthCodeTTL,
+	); err != nil {
+		controller.Log.Error("Failed to send re-authentication code to user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	if err := email.EnqueueEmail(email.ReauthCodeEmail, payload.Login, email.Substitutions{
+		email.CodePlaceholder: code,
+	}); err != nil {
+		controller.Log.Error("Failed to send re-authentication code to user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Sending re-authentication code to user "+payload.ID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Re-authenticate user via one-time code
+// @Description 	Same as /v1/auth/reauth, but confirms user identity by one-time code sent via /v1/auth/reauth/code.
+// @Description 	Code can be used only once and only within the session which requested it.
+// @Description 	Code is invalidated after the first attempt, so if it was wrong, then new code must be requested.
+// @ID 				reauthenticate-with-code
+// @Tags			auth
+// @Param 			code body requestbody.OneTimeCode true "One-time code"
+// @Accept			json
+// @Produce			json
+// @Success			200 			{object} 	responsebody.Token
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/auth/reauth/code/verify [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func ReauthenticateWithCode(ctx echo.Context) error {
+	var body RequestBody.OneTimeCode
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	reqMeta := request.GetMetadata(ctx)
+
+	// Copy, since payload in the request context mustn't be modified
+	payload := *SharedController.GetUserPayload(ctx)
+
+	controller.Log.Info("Re-authenticating user "+payload.ID+" via one-time code...", reqMeta)
+
+	key := cache.KeyBase[cache.ReauthCodeBySessionID] + payload.SessionID
+
+	hash, ok := cache.Client.Get(key)
+	if !ok {
+		controller.Log.Error("Failed to re-authenticate user "+payload.ID, "Code wasn't requested or has expired", reqMeta)
+		return authn.InvalidOneTimeCode
+	}
+
+	// Code must be used only once, even if it's wrong, otherwise it could be brute forced
+	if err := cache.Client.Delete(key); err != nil {
+		controller.Log.Error("Failed to re-authenticate user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	if err := authn.CompareHashAndOneTimeCode(hash, body.Code); err != nil {
+		controller.Log.Error("Failed to re-authenticate user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	user, err := DB.Database.GetUserByID(payload.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := completeReauthentication(ctx, user, &payload, authn.OneTimeCodeMethod); err != nil {
+		return err
+	}
+
+	controller.Log.Info("Re-authenticating user "+payload.ID+" via one-time code: OK", reqMeta)
+
+	return nil
+}
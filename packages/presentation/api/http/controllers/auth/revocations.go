@@ -0,0 +1,152 @@
+package authcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sentinel/packages/infrastructure/auth/authz"
+	"sentinel/packages/infrastructure/revocation"
+	"sentinel/packages/infrastructure/token"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// Comment lines are sent with this interval to prevent proxies from closing idle connection
+	revocationStreamHeartbeatInterval = 15 * time.Second
+	// Time (in ms) after which client should try to reconnect if connection was lost
+	revocationStreamRetryDelay = 3000
+)
+
+func writeRevocationEvent(ctx echo.Context, e *revocation.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	res := ctx.Response()
+
+	if _, err := fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+		return err
+	}
+
+	res.Flush()
+
+	return nil
+}
+
+// @Summary 		Stream of session revocations
+// @Description 	Server-Sent Events stream of session revocations and user version changes. Designed for resource servers.
+// @Description 	Stream will be closed once access token expires, after that client must reconnect with a new one.
+// @Description 	To replay missed events specify ID of the last received event in Last-Event-ID header (only a limited amount of recent events can be replayed).
+// @ID 				stream-revocations
+// @Tags			auth
+// @Param 			Last-Event-ID header string false "ID of the last received event"
+// @Produce			text/event-stream
+// @Success			200 			{object} 	revocation.Event
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/auth/revocations [get]
+// @Security		BearerAuth
+func StreamRevocations(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	act := SharedController.GetBasicAction(ctx)
+
+	if err := authz.User.For(act).SubscribeToRevocations(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	lastEventID := ctx.Request().Header.Get("Last-Event-ID")
+	if lastEventID != "" && !revocation.IsValidEventID(lastEventID) {
+		return revocation.InvalidEventID
+	}
+
+	accessToken := ctx.Get("access_token").(*jwt.Token)
+	expiresAt := accessToken.Claims.(*token.Claims).ExpiresAt.Time
+
+	controller.Log.Info("Opening revocation stream for user "+act.RequesterUID+"...", reqMeta)
+
+	// Subscribing before replaying missed events, so nothing will be lost in between
+	sub := revocation.Subscribe()
+	defer revocation.Unsubscribe(sub)
+
+	var missedEvents []*revocation.Event
+
+	if lastEventID != "" {
+		events, err := revocation.Since(lastEventID)
+		if err != nil {
+			return err
+		}
+		missedEvents = events
+	}
+
+	res := ctx.Response()
+
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Connection", "keep-alive")
+	// Disables response buffering in nginx
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", revocationStreamRetryDelay); err != nil {
+		return nil
+	}
+	res.Flush()
+
+	controller.Log.Info("Opening revocation stream for user "+act.RequesterUID+": OK", reqMeta)
+
+	for _, e := range missedEvents {
+		if err := writeRevocationEvent(ctx, e); err != nil {
+			controller.Log.Error("Failed to write revocation event", err.Error(), reqMeta)
+			return nil
+		}
+		lastEventID = e.ID
+	}
+
+	heartbeat := time.NewTicker(revocationStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	expiration := time.NewTimer(time.Until(expiresAt))
+	defer expiration.Stop()
+
+	for {
+		select {
+		case <-ctx.Request().Context().Done():
+			controller.Log.Info("Revocation stream closed by client", reqMeta)
+			return nil
+		case <-expiration.C:
+			controller.Log.Info("Revocation stream closed: access token expired", reqMeta)
+			return nil
+		case e, ok := <-sub.Events():
+			if !ok {
+				// Client was too slow or service is shutting down,
+				// in both cases client should reconnect with Last-Event-ID.
+				controller.Log.Info("Revocation stream closed: subscription canceled", reqMeta)
+				return nil
+			}
+			// Event was already sent during replay
+			if lastEventID != "" && revocation.CompareEventIDs(e.ID, lastEventID) <= 0 {
+				continue
+			}
+			if err := writeRevocationEvent(ctx, e); err != nil {
+				controller.Log.Error("Failed to write revocation event", err.Error(), reqMeta)
+				return nil
+			}
+			lastEventID = e.ID
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
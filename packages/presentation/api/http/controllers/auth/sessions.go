@@ -0,0 +1,161 @@
+package authcontroller
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	"sentinel/packages/infrastructure/DB"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+	ResponseBody "sentinel/packages/presentation/data/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary 		Revoke all other sessions
+// @Description 	Revokes all sessions of the current user except the current one
+// @ID 				revoke-other-sessions
+// @Tags			auth
+// @Accept			json
+// @Produce			json
+// @Success			200 			{object} 	responsebody.RevokedSessions
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/auth/sessions [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func RevokeOtherSessions(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	payload := SharedController.GetUserPayload(ctx)
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(payload.ID)
+
+	controller.Log.Info("Revoking other sessions of user "+payload.ID+"...", reqMeta)
+
+	sessions, err := DB.Database.RevokeAllUserSessionsExcept(act, payload.SessionID)
+	if err != nil {
+		controller.Log.Error("Failed to revoke other sessions of user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Revoking other sessions of user "+payload.ID+": OK", reqMeta)
+
+	return ctx.JSON(http.StatusOK, ResponseBody.RevokedSessions{Sessions: sessions})
+}
+
+func getSessionIDParam(ctx echo.Context) (string, *Error.Status) {
+	sessionID := ctx.Param("sessionID")
+
+	if e := validation.UUID(sessionID); e != nil {
+		return "", e.ToStatus(
+			"Session ID is missing",
+			"Session ID has invalid format (expected UUID)",
+		)
+	}
+
+	return sessionID, nil
+}
+
+// @Summary 		Rename session
+// @Description 	Sets friendly name of the device for the session of the current user
+// @ID 				rename-session
+// @Tags			auth
+// @Param 			sessionID path string true "Session ID"
+// @Param 			body body requestbody.RenameSession true "New session name"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500	{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/auth/{sessionID} [patch]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func RenameSession(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	sessionID, err := getSessionIDParam(ctx)
+	if err != nil {
+		controller.Log.Error("Failed to rename session", err.Error(), reqMeta)
+		return err
+	}
+
+	var body RequestBody.RenameSession
+
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	payload := SharedController.GetUserPayload(ctx)
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(payload.ID)
+
+	controller.Log.Info("Renaming session "+sessionID+"...", reqMeta)
+
+	if err := DB.Database.UpdateSessionLabel(act, sessionID, &body.Name, nil); err != nil {
+		controller.Log.Error("Failed to rename session "+sessionID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Renaming session "+sessionID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Mark session as trusted
+// @Description 	Marks (or unmarks) session of the current user as trusted. Requires recent authentication.
+// @Description 	Step-up isn't required when trusted session is used from the suspicious location (security alert is still sent).
+// @ID 				trust-session
+// @Tags			auth
+// @Param 			sessionID path string true "Session ID"
+// @Param 			body body requestbody.TrustSession true "Trusted flag"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/auth/{sessionID}/trusted [put]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func TrustSession(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	sessionID, err := getSessionIDParam(ctx)
+	if err != nil {
+		controller.Log.Error("Failed to change session trust", err.Error(), reqMeta)
+		return err
+	}
+
+	var body RequestBody.TrustSession
+
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	payload := SharedController.GetUserPayload(ctx)
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(payload.ID)
+
+	controller.Log.Info("Changing trust of session "+sessionID+"...", reqMeta)
+
+	if err := DB.Database.UpdateSessionLabel(act, sessionID, nil, body.Trusted); err != nil {
+		controller.Log.Error("Failed to change trust of session "+sessionID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Changing trust of session "+sessionID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
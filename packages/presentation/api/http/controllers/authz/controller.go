@@ -0,0 +1,176 @@
+package authzcontroller
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/infrastructure/auth/authz"
+	"sentinel/packages/infrastructure/token"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Token is only verified (signature, expiration, claims), revocation of its session isn't checked
+// to keep latency low. If it's required, then token must be introspected (see /v1/auth/oauth/introspect).
+func newDecisionRequest(body *RequestBody.AuthzCheck) (*authz.DecisionRequest, *Error.Status) {
+	roles := body.Roles
+
+	if body.Token != "" {
+		tk, err := token.ParseSingedToken(body.Token, config.Secret.AccessTokenPublicKey)
+		if err != nil {
+			return nil, Error.NewStatusError(err.Error(), http.StatusBadRequest)
+		}
+		roles = tk.Claims.(*token.Claims).Roles
+	}
+
+	return &authz.DecisionRequest{
+		ServiceID: body.ServiceID,
+		Entity:    body.Entity,
+		Action:    body.Action,
+		Resource:  body.Resource,
+		Roles:     roles,
+	}, nil
+}
+
+func check(body *RequestBody.AuthzCheck) (*authz.Decision, *Error.Status) {
+	req, err := newDecisionRequest(body)
+	if err != nil {
+		return nil, err
+	}
+	return authz.Check(req)
+}
+
+// @Summary 		Check authorization
+// @Description 	Evaluate authorization decision for the subject in the specified service.
+// @Description 	Subject can be specified either by its roles or by its access token (revocation of token's session isn't checked).
+// @Description 	If authorization is denied, then reason will be specified: "insufficient-permissions" or "denied-by-agp".
+// @Description 	Attribute-based conditions of Sentinel's own contexts aren't evaluated: if roles are sufficient for such context,
+// @Description 	then it isn't allowed, reason is "conditional" and condition is specified.
+// @ID 				check-authorization
+// @Tags			authz
+// @Param 			body body requestbody.AuthzCheck true "Authorization context and subject"
+// @Accept			json
+// @Produce			json
+// @Success			200 				{object} 	authz.Decision
+// @Failure			400,401,403,429,500	{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/authz/check [post]
+// @Security		BearerAuth
+func Check(ctx echo.Context) error {
+	act := SharedController.GetBasicAction(ctx)
+
+	if err := authz.User.For(act).CheckAuthorization(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	var body RequestBody.AuthzCheck
+
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	decision, err := check(&body)
+	if err != nil {
+		controller.Log.Error("Failed to check authorization", err.Error(), request.GetMetadata(ctx))
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, decision)
+}
+
+// @Summary 		Check authorization (batch)
+// @Description 	Same as /v1/authz/check, but for multiple checks at once (up to 100).
+// @Description 	Decisions are returned in the same order as checks. If any check is invalid, then whole request fails.
+// @ID 				check-authorization-batch
+// @Tags			authz
+// @Param 			body body requestbody.AuthzBatchCheck true "Checks"
+// @Accept			json
+// @Produce			json
+// @Success			200 				{array} 	authz.Decision
+// @Failure			400,401,403,429,500	{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/authz/check/batch [post]
+// @Security		BearerAuth
+func CheckBatch(ctx echo.Context) error {
+	act := SharedController.GetBasicAction(ctx)
+
+	if err := authz.User.For(act).CheckAuthorization(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	var body RequestBody.AuthzBatchCheck
+
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	decisions := make([]*authz.Decision, len(body.Checks))
+
+	for i := range body.Checks {
+		decision, err := check(&body.Checks[i])
+		if err != nil {
+			controller.Log.Error("Failed to check authorization", err.Error(), request.GetMetadata(ctx))
+			return err
+		}
+		decisions[i] = decision
+	}
+
+	return ctx.JSON(http.StatusOK, decisions)
+}
+
+// @Summary 		Explain authorization
+// @Description 	Same as /v1/authz/check, but describes how decision was made: evaluated roles and their permissions,
+// @Description 	permissions required by the action, missing permissions and the Action Gate Policy rule for the context (if any).
+// @Description 	Only users with "admin" role can do that.
+// @ID 				explain-authorization
+// @Tags			authz
+// @Param 			body body requestbody.AuthzCheck true "Authorization context and subject"
+// @Accept			json
+// @Produce			json
+// @Success			200 				{object} 	authz.Explanation
+// @Failure			400,401,403,429,500	{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/authz/explain [post]
+// @Security		BearerAuth
+func Explain(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	act := SharedController.GetBasicAction(ctx)
+
+	if err := authz.User.For(act).ExplainAuthorization(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	var body RequestBody.AuthzCheck
+
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	req, err := newDecisionRequest(&body)
+	if err != nil {
+		controller.Log.Error("Failed to explain authorization", err.Error(), reqMeta)
+		return err
+	}
+
+	explanation, err := authz.Explain(req)
+	if err != nil {
+		controller.Log.Error("Failed to explain authorization", err.Error(), reqMeta)
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, explanation)
+}
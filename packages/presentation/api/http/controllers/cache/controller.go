@@ -19,6 +19,7 @@ import (
 // @Produce			json
 // @Success			200
 // @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -34,7 +35,7 @@ func Drop(ctx echo.Context) error {
 
 	act := SharedController.GetBasicAction(ctx)
 
-	if err := authz.User.DropCache(act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).DropCache(act.RequesterRoles); err != nil {
 		controller.Log.Error("Failed to clear cache", err.Error(), reqMeta)
 		return err
 	}
@@ -18,7 +18,7 @@ func Swagger(ctx echo.Context) error {
 	if !config.Debug.Enabled {
 		payload := SharedController.GetUserPayload(ctx)
 
-		if err := authz.User.AccessAPIDocs(payload.Roles); err != nil {
+		if err := authz.User.For(SharedController.GetBasicAction(ctx)).AccessAPIDocs(payload.Roles); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,330 @@
+package groupcontroller
+
+import (
+	"net/http"
+	GroupDTO "sentinel/packages/core/group/DTO"
+	"sentinel/packages/infrastructure/DB"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+func bindReason(ctx echo.Context) string {
+	var body RequestBody.ActionReason
+	if err := ctx.Bind(&body); err != nil {
+		// Action reason is optional, so even if binding failed this won't be a critical problem
+		controller.Log.Error("Failed to bind request", err.Error(), request.GetMetadata(ctx))
+	}
+	return body.GetReason()
+}
+
+// @Summary 		Get all groups
+// @Description 	Get all user groups with their roles
+// @ID 				get-all-groups
+// @Tags			group
+// @Accept			json
+// @Produce			json
+// @Success			200 			{array} 	groupdto.Full
+// @Failure			401,403,500		{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/groups [get]
+// @Security		BearerAuth
+func GetAll(ctx echo.Context) error {
+	groups, err := DB.Database.GetAllGroups(SharedController.GetBasicAction(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, groups)
+}
+
+// @Summary 		Get group
+// @Description 	Get user group by its ID
+// @ID 				get-group
+// @Tags			group
+// @Param 			groupID path string true "Group ID"
+// @Accept			json
+// @Produce			json
+// @Success			200 					{object} 	groupdto.Full
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/groups/{groupID} [get]
+// @Security		BearerAuth
+func Get(ctx echo.Context) error {
+	group, err := DB.Database.GetGroupByID(SharedController.GetBasicAction(ctx), ctx.Param("groupID"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, group)
+}
+
+// @Summary 		Create group
+// @Description 	Create new user group. Roles of the group are inherited by all its members.
+// @Description 	Privileged roles can't be assigned to groups.
+// @ID 				create-group
+// @Tags			group
+// @Param 			body body requestbody.CreateGroup true "Group"
+// @Accept			json
+// @Produce			json
+// @Success			201 				{object} 	groupdto.Full
+// @Failure			400,401,403,409,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/groups [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Create(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.CreateGroup
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	act := SharedController.GetBasicAction(ctx)
+	act.Reason = body.GetReason()
+
+	dto := &GroupDTO.Full{
+		Name:  body.Name,
+		Roles: body.Roles,
+	}
+
+	controller.Log.Info("Creating group "+dto.Name+"...", reqMeta)
+
+	if _, err := DB.Database.CreateGroup(act, dto); err != nil {
+		controller.Log.Error("Failed to create group "+dto.Name, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Creating group "+dto.Name+": OK", reqMeta)
+
+	return ctx.JSON(http.StatusCreated, dto)
+}
+
+// @Summary 		Change group roles
+// @Description 	Change roles of the user group. All members of the group will have to refresh their tokens.
+// @Description 	Privileged roles can't be assigned to groups.
+// @ID 				change-group-roles
+// @Tags			group
+// @Param 			groupID path string 						true "Group ID"
+// @Param 			body 	body requestbody.ChangeGroupRoles 	true "New roles of the group"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/groups/{groupID}/roles [put]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func ChangeRoles(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.ChangeGroupRoles
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	id := ctx.Param("groupID")
+
+	act := SharedController.GetBasicAction(ctx)
+	act.Reason = body.GetReason()
+
+	controller.Log.Info("Changing roles of group "+id+"...", reqMeta)
+
+	if err := DB.Database.ChangeGroupRoles(act, id, body.Roles); err != nil {
+		controller.Log.Error("Failed to change roles of group "+id, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Changing roles of group "+id+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Delete group
+// @Description 	Delete user group. All its members will lose roles of the group.
+// @ID 				delete-group
+// @Tags			group
+// @Param 			groupID path string 					true 	"Group ID"
+// @Param 			body 	body requestbody.ActionReason 	false 	"Reason of deletion"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/groups/{groupID} [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Delete(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	id := ctx.Param("groupID")
+
+	act := SharedController.GetBasicAction(ctx)
+	act.Reason = bindReason(ctx)
+
+	controller.Log.Info("Deleting group "+id+"...", reqMeta)
+
+	if err := DB.Database.DeleteGroup(act, id); err != nil {
+		controller.Log.Error("Failed to delete group "+id, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Deleting group "+id+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Get group members
+// @Description 	Get all members of the user group
+// @ID 				get-group-members
+// @Tags			group
+// @Param 			groupID path string true "Group ID"
+// @Accept			json
+// @Produce			json
+// @Success			200 					{array} 	groupdto.Member
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/groups/{groupID}/members [get]
+// @Security		BearerAuth
+func GetMembers(ctx echo.Context) error {
+	members, err := DB.Database.GetGroupMembers(SharedController.GetBasicAction(ctx), ctx.Param("groupID"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, members)
+}
+
+// @Summary 		Add group member
+// @Description 	Add user to the group, user will inherit roles of the group after tokens refresh
+// @ID 				add-group-member
+// @Tags			group
+// @Param 			groupID path string 					true 	"Group ID"
+// @Param 			uid 	path string 					true 	"User ID"
+// @Param 			body 	body requestbody.ActionReason 	false 	"Reason"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,409,500	{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/groups/{groupID}/members/{uid} [put]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func AddMember(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	groupID := ctx.Param("groupID")
+
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+	act.Reason = bindReason(ctx)
+
+	controller.Log.Info("Adding user "+act.TargetUID+" to group "+groupID+"...", reqMeta)
+
+	if err := DB.Database.AddGroupMember(act, groupID); err != nil {
+		controller.Log.Error("Failed to add user "+act.TargetUID+" to group "+groupID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Adding user "+act.TargetUID+" to group "+groupID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Remove group member
+// @Description 	Remove user from the group, user will lose roles of the group after tokens refresh
+// @ID 				remove-group-member
+// @Tags			group
+// @Param 			groupID path string 					true 	"Group ID"
+// @Param 			uid 	path string 					true 	"User ID"
+// @Param 			body 	body requestbody.ActionReason 	false 	"Reason"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/groups/{groupID}/members/{uid} [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func RemoveMember(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	groupID := ctx.Param("groupID")
+
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+	act.Reason = bindReason(ctx)
+
+	controller.Log.Info("Removing user "+act.TargetUID+" from group "+groupID+"...", reqMeta)
+
+	if err := DB.Database.RemoveGroupMember(act, groupID); err != nil {
+		controller.Log.Error("Failed to remove user "+act.TargetUID+" from group "+groupID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Removing user "+act.TargetUID+" from group "+groupID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Get user groups
+// @Description 	Get groups in which user is a member
+// @ID 				get-user-groups
+// @Tags			group
+// @Param 			uid path string true "User ID"
+// @Accept			json
+// @Produce			json
+// @Success			200 				{array} 	groupdto.Full
+// @Failure			400,401,403,500		{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/groups [get]
+// @Security		BearerAuth
+func GetUserGroups(ctx echo.Context) error {
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+
+	groups, err := DB.Database.GetUserGroups(act)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, groups)
+}
@@ -4,10 +4,13 @@ import (
 	"crypto/ed25519"
 	"net/http"
 	"sentinel/packages/common/config"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	"sentinel/packages/infrastructure/DB"
 	"sentinel/packages/infrastructure/auth/authz"
 	"sentinel/packages/infrastructure/token"
 	controller "sentinel/packages/presentation/api/http/controllers"
 	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
 	RequestBody "sentinel/packages/presentation/data/request"
 	ResponseBody "sentinel/packages/presentation/data/response"
 
@@ -29,6 +32,7 @@ func Init() {
 
 // @Summary 		OAuth 2.0 Token Introspection
 // @Description 	RFC 7662 (https://datatracker.ietf.org/doc/html/rfc7662). Valid token types are: access, refresh and activate.
+// @Description 	If token is invalid, expired or it's session was revoked, then only "active" field with false value will be returned.
 // @ID 				oauth-introspect
 // @Tags			oauth
 // @Param 			Token body requestbody.Introspect true "OAuth2.0 token which must be introspected"
@@ -41,7 +45,7 @@ func Init() {
 func IntrospectOAuthToken(ctx echo.Context) error {
 	act := SharedController.GetBasicAction(ctx)
 
-	if err := authz.User.OAuthIntrospect(act.RequesterRoles); err != nil {
+	if err := authz.User.For(act).OAuthIntrospect(act.RequesterRoles); err != nil {
 		return err
 	}
 
@@ -69,11 +73,25 @@ func IntrospectOAuthToken(ctx echo.Context) error {
 
 	tk, err := token.ParseSingedToken(body.Token, key)
 	if err != nil {
-		return echo.NewHTTPError(err.Status(), "Failed to parse specified token: "+err.Error())
+		if !token.IsTokenError(err) {
+			return err
+		}
+		// RFC 7662 p2.2: If token isn't active, then only "active" field must be returned
+		controller.Log.Trace("Introspected token is invalid: "+err.Error(), request.GetMetadata(ctx))
+		return ctx.JSON(http.StatusOK, ResponseBody.Introspection{Active: false})
 	}
 
 	claims := tk.Claims.(*token.Claims)
 
+	// Activation tokens aren't bound to any session
+	if body.Type != "activate" {
+		act := ActionDTO.NewUserTargeted(claims.Subject, claims.Subject, claims.Roles)
+		if _, err := DB.Database.GetRevokedSessionByID(act, claims.ID); err == nil {
+			controller.Log.Trace("Introspected token belongs to revoked session", request.GetMetadata(ctx))
+			return ctx.JSON(http.StatusOK, ResponseBody.Introspection{Active: false})
+		}
+	}
+
 	return ctx.JSON(http.StatusOK, ResponseBody.Introspection{
 		Active:    true,
 		SessionID: claims.ID,
@@ -7,6 +7,7 @@ import (
 	"sentinel/packages/common/encoding/json"
 	Error "sentinel/packages/common/errors"
 	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/authn"
 	"sentinel/packages/presentation/api"
 	controller "sentinel/packages/presentation/api/http/controllers"
 	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
@@ -109,7 +110,7 @@ type googleUserInfoPartialResponse struct {
 // @Accept			json
 // @Produce			json
 // @Success			200 			{object} 	responsebody.Token
-// @Failure			400,403,408,500	{object} 	responsebody.Error
+// @Failure			400,403,408,409,500	{object} 	responsebody.Error
 // @Router			/v1/auth/oauth/google/callback [get]
 // @Security 		OAuthSession
 func GoogleCallback(ctx echo.Context) error {
@@ -220,8 +221,8 @@ func GoogleCallback(ctx echo.Context) error {
 			)
 		}
 
-		return SharedController.AuthenticateWithNewSession(ctx, user, []string{config.Auth.SelfAudience})
+		return SharedController.AuthenticateWithNewSession(ctx, user, []string{config.Auth.SelfAudience}, authn.FederatedMethod)
 	}
 
-	return SharedController.Authenticate(ctx, user, []string{config.Auth.SelfAudience})
+	return SharedController.Authenticate(ctx, user, []string{config.Auth.SelfAudience}, authn.FederatedMethod)
 }
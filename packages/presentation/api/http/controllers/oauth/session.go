@@ -2,6 +2,7 @@ package oauthcontroller
 
 import (
 	"errors"
+	"sentinel/packages/common/util"
 	"sentinel/packages/infrastructure/cache"
 	controller "sentinel/packages/presentation/api/http/controllers"
 	"strings"
@@ -37,7 +38,7 @@ func validateOAuthSession(ctx echo.Context, provider authProvider) error {
 	if !ok {
 		return errors.New("OAuth session wasn't found")
 	}
-	if oauthSession.IP != ctx.RealIP() {
+	if !util.SameIP(oauthSession.IP, ctx.RealIP()) {
 		return errors.New("OAuth session IP mismatch")
 	}
 	if ctx.QueryParam("state") != oauthSession.State {
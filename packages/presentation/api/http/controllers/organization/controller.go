@@ -0,0 +1,290 @@
+package organizationcontroller
+
+import (
+	"net/http"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	OrganizationDTO "sentinel/packages/core/organization/DTO"
+	"sentinel/packages/infrastructure/DB"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func getTargetedAction(ctx echo.Context) *ActionDTO.UserTargeted {
+	return SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+}
+
+// @Summary 		Create organization
+// @Description 	Create new organization. Can't be done with organization-scoped token.
+// @ID 				create-organization
+// @Tags			organization
+// @Param 			body body requestbody.CreateOrganization true "Organization"
+// @Accept			json
+// @Produce			json
+// @Success			201 				{object} 	organizationdto.Full
+// @Failure			400,401,403,409,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Create(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.CreateOrganization
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	act := SharedController.GetBasicAction(ctx)
+	act.Reason = body.GetReason()
+
+	dto := &OrganizationDTO.Full{Name: body.Name}
+
+	controller.Log.Info("Creating organization "+dto.Name+"...", reqMeta)
+
+	if _, err := DB.Database.CreateOrganization(act, dto); err != nil {
+		controller.Log.Error("Failed to create organization "+dto.Name, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Creating organization "+dto.Name+": OK", reqMeta)
+
+	return ctx.JSON(http.StatusCreated, dto)
+}
+
+// @Summary 		Get organization
+// @Description 	Get organization by its ID
+// @ID 				get-organization
+// @Tags			organization
+// @Param 			orgID path string true "Organization ID"
+// @Accept			json
+// @Produce			json
+// @Success			200 					{object} 	organizationdto.Full
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID} [get]
+// @Security		BearerAuth
+func Get(ctx echo.Context) error {
+	dto, err := DB.Database.GetOrganizationByID(SharedController.GetBasicAction(ctx), ctx.Param("orgID"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, dto)
+}
+
+// @Summary 		Delete organization
+// @Description 	Delete organization with all its memberships. Can't be done with organization-scoped token.
+// @ID 				delete-organization
+// @Tags			organization
+// @Param 			orgID 	path 	string 						true 	"Organization ID"
+// @Param 			body 	body 	requestbody.ActionReason 	false 	"Reason of deletion"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID} [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Delete(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.ActionReason
+	if err := ctx.Bind(&body); err != nil {
+		// Action reason is optional, so even if binding failed this won't be a critical problem
+		controller.Log.Error("Failed to bind request", err.Error(), reqMeta)
+	}
+
+	id := ctx.Param("orgID")
+
+	act := SharedController.GetBasicAction(ctx)
+	act.Reason = body.GetReason()
+
+	controller.Log.Info("Deleting organization "+id+"...", reqMeta)
+
+	if err := DB.Database.DeleteOrganization(act, id); err != nil {
+		controller.Log.Error("Failed to delete organization "+id, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Deleting organization "+id+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Get organization members
+// @Description 	Get members of the organization with their roles in it
+// @ID 				get-organization-members
+// @Tags			organization
+// @Param 			orgID 		path 	string 	true 	"Organization ID"
+// @Param 			page 		query 	int 	true 	"Page"
+// @Param 			pageSize 	query 	int 	true 	"Elements per page"
+// @Accept			json
+// @Produce			json
+// @Success			200 					{array} 	organizationdto.Member
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID}/members [get]
+// @Security		BearerAuth
+func GetMembers(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	page, err := strconv.Atoi(ctx.QueryParam("page"))
+	if err != nil {
+		errMsg := "Query param 'page' is missing or isn't an integer number"
+		controller.Log.Error("Failed to get organization members", errMsg, reqMeta)
+		return echo.NewHTTPError(http.StatusBadRequest, errMsg)
+	}
+	pageSize, err := strconv.Atoi(ctx.QueryParam("pageSize"))
+	if err != nil {
+		errMsg := "Query param 'pageSize' is missing or isn't an integer number"
+		controller.Log.Error("Failed to get organization members", errMsg, reqMeta)
+		return echo.NewHTTPError(http.StatusBadRequest, errMsg)
+	}
+
+	members, e := DB.Database.GetMembers(SharedController.GetBasicAction(ctx), ctx.Param("orgID"), page, pageSize)
+	if e != nil {
+		return e
+	}
+
+	return ctx.JSON(http.StatusOK, members)
+}
+
+// @Summary 		Get organization member
+// @Description 	Get member of the organization with his roles in it
+// @ID 				get-organization-member
+// @Tags			organization
+// @Param 			orgID 	path 	string 	true 	"Organization ID"
+// @Param 			uid 	path 	string 	true 	"User ID"
+// @Accept			json
+// @Produce			json
+// @Success			200 					{object} 	organizationdto.Member
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID}/members/{uid} [get]
+// @Security		BearerAuth
+func GetMember(ctx echo.Context) error {
+	member, err := DB.Database.GetMember(getTargetedAction(ctx), ctx.Param("orgID"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, member)
+}
+
+// @Summary 		Set organization member
+// @Description 	Add user to the organization or change his roles in it if he is already a member.
+// @Description 	Member must refresh his organization-scoped tokens to get new roles.
+// @Description 	New members can be added only with global (not organization-scoped) token. Privileged roles can't be assigned.
+// @ID 				set-organization-member
+// @Tags			organization
+// @Param 			orgID 	path 	string 								true 	"Organization ID"
+// @Param 			uid 	path 	string 								true 	"User ID"
+// @Param 			body 	body 	requestbody.SetOrganizationMember 	true 	"Roles of the user in the organization"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID}/members/{uid} [put]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func SetMember(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.SetOrganizationMember
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	orgID := ctx.Param("orgID")
+
+	act := getTargetedAction(ctx)
+	act.Reason = body.GetReason()
+
+	controller.Log.Info("Setting member "+act.TargetUID+" of organization "+orgID+"...", reqMeta)
+
+	if err := DB.Database.SetMember(act, orgID, body.Roles); err != nil {
+		controller.Log.Error("Failed to set member "+act.TargetUID+" of organization "+orgID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Setting member "+act.TargetUID+" of organization "+orgID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Remove organization member
+// @Description 	Remove user from the organization, his organization-scoped tokens will be invalidated
+// @ID 				remove-organization-member
+// @Tags			organization
+// @Param 			orgID 	path 	string 						true 	"Organization ID"
+// @Param 			uid 	path 	string 						true 	"User ID"
+// @Param 			body 	body 	requestbody.ActionReason 	false 	"Reason of removal"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID}/members/{uid} [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func RemoveMember(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.ActionReason
+	if err := ctx.Bind(&body); err != nil {
+		// Action reason is optional, so even if binding failed this won't be a critical problem
+		controller.Log.Error("Failed to bind request", err.Error(), reqMeta)
+	}
+
+	orgID := ctx.Param("orgID")
+
+	act := getTargetedAction(ctx)
+	act.Reason = body.GetReason()
+
+	controller.Log.Info("Removing member "+act.TargetUID+" from organization "+orgID+"...", reqMeta)
+
+	if err := DB.Database.RemoveMember(act, orgID); err != nil {
+		controller.Log.Error("Failed to remove member "+act.TargetUID+" from organization "+orgID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Removing member "+act.TargetUID+" from organization "+orgID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
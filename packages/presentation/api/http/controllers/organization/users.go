@@ -0,0 +1,76 @@
+package organizationcontroller
+
+import (
+	AuthController "sentinel/packages/presentation/api/http/controllers/auth"
+	UserController "sentinel/packages/presentation/api/http/controllers/user"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handlers below are organization-scoped versions of the global ones.
+// Scoping itself is done by middlewares and DB layer, so they just delegate to the global handlers.
+
+// @Summary 		Organization users search
+// @Description 	Search users with pagination among members of the organization
+// @ID 				search-organization-users
+// @Tags			organization
+// @Param 			orgID 		path 	string 	true 	"Organization ID"
+// @Param 			filter 		query 	string 	true 	"Search filter"
+// @Param 			page 		query 	int 	true 	"Search page"
+// @Param 			pageSize 	query 	int 	true 	"Elements per page"
+// @Accept			json
+// @Produce			json
+// @Success			200				{object}	[]userdto.Public
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID}/users/search [get]
+// @Security		BearerAuth
+func SearchUsers(ctx echo.Context) error {
+	return UserController.SearchUsers(ctx)
+}
+
+// @Summary 		Get organization member sessions
+// @Description 	Get all active sessions of the organization member
+// @ID 				get-organization-member-sessions
+// @Tags			organization
+// @Param 			orgID 	path 	string 	true 	"Organization ID"
+// @Param 			uid 	path 	string 	true 	"User ID"
+// @Accept			json
+// @Produce			json
+// @Success			200					{object}	[]responsebody.UserSession
+// @Failure			400,401,403,404,500	{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID}/members/{uid}/sessions [get]
+// @Security		BearerAuth
+func GetMemberSessions(ctx echo.Context) error {
+	return UserController.GetUserSessions(ctx)
+}
+
+// @Summary 		Revoke all organization member sessions
+// @Description 	Revoke all existing non-revoked sessions of the organization member
+// @ID 				revoke-all-organization-member-sessions
+// @Tags			organization
+// @Param 			orgID 	path 	string 	true 	"Organization ID"
+// @Param 			uid 	path 	string 	true 	"User ID"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/organizations/{orgID}/members/{uid}/sessions [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func RevokeAllMemberSessions(ctx echo.Context) error {
+	return AuthController.RevokeAllUserSessions(ctx)
+}
@@ -37,7 +37,7 @@ func GetAll(ctx echo.Context) error {
 
 	controller.Log.Info("Getting roles for service '"+serviceID+"'...", reqMeta)
 
-	schema, err := authz.Host.GetSchema(serviceID)
+	schema, err := authz.GetHost().GetSchema(serviceID)
 	if err != nil {
 		controller.Log.Error("Failed to get roles of service '"+serviceID+"'", err.Error(), reqMeta)
 		return echo.NewHTTPError(http.StatusBadRequest, err.Message)
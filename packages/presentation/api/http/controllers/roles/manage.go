@@ -0,0 +1,367 @@
+package rolescontroller
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	RoleDTO "sentinel/packages/core/role/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/authz"
+	"sentinel/packages/infrastructure/auth/roles"
+	rolemapper "sentinel/packages/infrastructure/mappers/role"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+func resourcePermissionsFromRequest(raw map[string]RoleDTO.Permissions) map[string]uint16 {
+	resourcePermissions := make(map[string]uint16, len(raw))
+	for resource, permissions := range raw {
+		resourcePermissions[resource] = rolemapper.PermissionsToBitmask(&permissions)
+	}
+	return resourcePermissions
+}
+
+// @Summary 		Get all roles
+// @Description 	Get all roles of all schemas stored in DB (including their permissions)
+// @ID 				get-managed-roles
+// @Tags			roles
+// @Accept			json
+// @Produce			json
+// @Success			200 			{array} 	roledto.Public
+// @Failure			401,403,409,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/rbac/roles [get]
+// @Security		BearerAuth
+func GetManaged(ctx echo.Context) error {
+	if !roles.IsManageable() {
+		return roles.RolesAreNotManageable
+	}
+
+	dtos, err := DB.Database.GetAllRoles(SharedController.GetBasicAction(ctx))
+	if err != nil {
+		return err
+	}
+
+	publicDTOs := make([]*RoleDTO.Public, len(dtos))
+	for i, dto := range dtos {
+		publicDTOs[i] = rolemapper.PublicFromFull(dto)
+	}
+
+	return ctx.JSON(http.StatusOK, publicDTOs)
+}
+
+// @Summary 		Get role
+// @Description 	Get role stored in DB by its ID
+// @ID 				get-managed-role
+// @Tags			roles
+// @Param 			id path string true "Role ID"
+// @Accept			json
+// @Produce			json
+// @Success			200 				{object} 	roledto.Public
+// @Failure			400,401,403,404,409,500	{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/rbac/roles/{id} [get]
+// @Security		BearerAuth
+func GetManagedRole(ctx echo.Context) error {
+	if !roles.IsManageable() {
+		return roles.RolesAreNotManageable
+	}
+
+	dto, err := DB.Database.GetRoleByID(SharedController.GetBasicAction(ctx), ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, rolemapper.PublicFromFull(dto))
+}
+
+// @Summary 		Create role
+// @Description 	Create new role. Change will be propagated to all Sentinel instances.
+// @Description 	Change is rejected if resulting RBAC configuration is invalid (e.g. default role doesn't exist in schema of this service).
+// @ID 				create-role
+// @Tags			roles
+// @Param 			body body requestbody.CreateRole true "Role"
+// @Accept			json
+// @Produce			json
+// @Success			201 				{object} 	roledto.Public
+// @Failure			400,401,403,409,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/rbac/roles [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Create(ctx echo.Context) error {
+	if !roles.IsManageable() {
+		return roles.RolesAreNotManageable
+	}
+
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.CreateRole
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	dto := &RoleDTO.Full{
+		SchemaID:            body.SchemaID,
+		Name:                body.Name,
+		Permissions:         rolemapper.PermissionsToBitmask(&body.Permissions),
+		ResourcePermissions: resourcePermissionsFromRequest(body.ResourcePermissions),
+		IsDefault:           body.IsDefault,
+	}
+
+	if err := roles.Validate(dto); err != nil {
+		controller.Log.Error("Failed to create role", err.Error(), reqMeta)
+		return err
+	}
+
+	act := SharedController.GetBasicAction(ctx)
+	act.Reason = body.GetReason()
+
+	// Checked before validation of the change, so its result won't be exposed to unauthorized users
+	if err := authz.User.For(act).CreateRole(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	controller.Log.Info("Creating role "+dto.Name+"...", reqMeta)
+
+	err := roles.Change(
+		func(stored []*RoleDTO.Full) []*RoleDTO.Full {
+			return append(stored, dto)
+		},
+		func() (string, *Error.Status) {
+			_, err := DB.Database.CreateRole(act, dto)
+			return dto.ID, err
+		},
+	)
+	if err != nil {
+		controller.Log.Error("Failed to create role "+dto.Name, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Creating role "+dto.Name+": OK", reqMeta)
+
+	return ctx.JSON(http.StatusCreated, rolemapper.PublicFromFull(dto))
+}
+
+// @Summary 		Update role
+// @Description 	Update permissions of the role. Name and schema of the role can't be changed.
+// @Description 	Change will be propagated to all Sentinel instances.
+// @Description 	Change is rejected if resulting RBAC configuration is invalid (e.g. default role doesn't exist in schema of this service).
+// @ID 				update-role
+// @Tags			roles
+// @Param 			id path string true "Role ID"
+// @Param 			body body requestbody.UpdateRole true "New role permissions"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,409,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/rbac/roles/{id} [put]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Update(ctx echo.Context) error {
+	if !roles.IsManageable() {
+		return roles.RolesAreNotManageable
+	}
+
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.UpdateRole
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	id := ctx.Param("id")
+
+	act := SharedController.GetBasicAction(ctx)
+	act.Reason = body.GetReason()
+
+	// Checked before validation of the change, so its result won't be exposed to unauthorized users
+	if err := authz.User.For(act).UpdateRole(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	controller.Log.Info("Updating role "+id+"...", reqMeta)
+
+	dto := &RoleDTO.Full{
+		Permissions:         rolemapper.PermissionsToBitmask(&body.Permissions),
+		ResourcePermissions: resourcePermissionsFromRequest(body.ResourcePermissions),
+		IsDefault:           body.IsDefault,
+	}
+
+	err := roles.Change(
+		func(stored []*RoleDTO.Full) []*RoleDTO.Full {
+			for _, role := range stored {
+				if role.ID == id {
+					role.Permissions = dto.Permissions
+					role.ResourcePermissions = dto.ResourcePermissions
+					role.IsDefault = dto.IsDefault
+				}
+			}
+			return stored
+		},
+		func() (string, *Error.Status) {
+			return id, DB.Database.UpdateRole(act, id, dto)
+		},
+	)
+	if err != nil {
+		controller.Log.Error("Failed to update role "+id, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Updating role "+id+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Delete role
+// @Description 	Delete role. Role can't be deleted while it's assigned to any user
+// @Description 	(unless it's schema specific role and there are global role with the same name).
+// @Description 	Change will be propagated to all Sentinel instances.
+// @Description 	Change is rejected if resulting RBAC configuration is invalid (e.g. default role doesn't exist in schema of this service).
+// @ID 				delete-role
+// @Tags			roles
+// @Param 			id path string true "Role ID"
+// @Param 			body body requestbody.ActionReason false "Reason of deletion"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,409,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/rbac/roles/{id} [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Delete(ctx echo.Context) error {
+	if !roles.IsManageable() {
+		return roles.RolesAreNotManageable
+	}
+
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.ActionReason
+	if err := ctx.Bind(&body); err != nil {
+		// Action reason is optional, so even if binding failed this won't be a critical problem
+		controller.Log.Error("Failed to bind request", err.Error(), reqMeta)
+	}
+
+	id := ctx.Param("id")
+
+	act := SharedController.GetBasicAction(ctx)
+	act.Reason = body.GetReason()
+
+	// Checked before validation of the change, so its result won't be exposed to unauthorized users
+	if err := authz.User.For(act).DeleteRole(act.RequesterRoles); err != nil {
+		return err
+	}
+
+	controller.Log.Info("Deleting role "+id+"...", reqMeta)
+
+	err := roles.Change(
+		func(stored []*RoleDTO.Full) []*RoleDTO.Full {
+			return slices.DeleteFunc(stored, func(role *RoleDTO.Full) bool {
+				return role.ID == id
+			})
+		},
+		func() (string, *Error.Status) {
+			return id, DB.Database.DeleteRole(act, id)
+		},
+	)
+	if err != nil {
+		controller.Log.Error("Failed to delete role "+id, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Deleting role "+id+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Reload RBAC configuration
+// @Description 	Reloads RBAC configuration file and atomically applies it along with Action Gate Policy.
+// @Description 	Configuration is rejected if any role assigned to users or any default role doesn't exist in it.
+// @Description 	Affects only Sentinel instance which handled this request (to reload all instances send SIGHUP to each of them).
+// @ID 				reload-rbac
+// @Tags			roles
+// @Accept			json
+// @Produce			json
+// @Success			200 				{object} 	roles.Diff
+// @Failure			401,403,409,500		{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/rbac/reload [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Reload(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	payload := SharedController.GetUserPayload(ctx)
+
+	if err := authz.User.For(SharedController.GetBasicAction(ctx)).ReloadRBAC(payload.Roles); err != nil {
+		return err
+	}
+
+	controller.Log.Info("Reloading RBAC configuration by user "+payload.ID+"...", reqMeta)
+
+	diff, err := roles.ReloadConfig()
+	if err != nil {
+		controller.Log.Error("Failed to reload RBAC configuration by user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Reloading RBAC configuration by user "+payload.ID+": OK", reqMeta)
+
+	return ctx.JSON(http.StatusOK, diff)
+}
+
+// @Summary 		Get Action Gate Policy
+// @Description 	Get all active Action Gate Policy rules (built-in and from config) with contexts they match
+// @ID 				get-action-gate-policy
+// @Tags			roles
+// @Accept			json
+// @Produce			json
+// @Success			200 			{array} 	authz.ActionGateRule
+// @Failure			401,403,500		{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/rbac/agp [get]
+// @Security		BearerAuth
+func GetActionGatePolicy(ctx echo.Context) error {
+	payload := SharedController.GetUserPayload(ctx)
+
+	if err := authz.User.For(SharedController.GetBasicAction(ctx)).GetActionGatePolicy(payload.Roles); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, authz.GetActionGatePolicy())
+}
@@ -0,0 +1,91 @@
+package schedulercontroller
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/authz"
+	"sentinel/packages/infrastructure/scheduler"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary 		Get scheduler jobs
+// @Description 	Get configured background jobs with their schedule and metrics.
+// @Description 	Metrics are collected by the Sentinel instance which handled this request since its start.
+// @ID 				get-scheduler-jobs
+// @Tags			scheduler
+// @Accept			json
+// @Produce			json
+// @Success			200 			{array} 	scheduler.JobStatus
+// @Failure			401,403,500		{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/scheduler/jobs [get]
+// @Security		BearerAuth
+func GetJobs(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	act := SharedController.GetBasicAction(ctx)
+
+	if err := authz.User.For(act).GetSchedulerJobs(act.RequesterRoles); err != nil {
+		controller.Log.Error("Failed to get scheduler jobs", err.Error(), reqMeta)
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, scheduler.Jobs())
+}
+
+// @Summary 		Get job history
+// @Description 	Get runs of the background job made by all Sentinel instances, most recent runs go first.
+// @ID 				get-scheduler-job-runs
+// @Tags			scheduler
+// @Param 			job 		path 	string 	true 	"Job name"
+// @Param 			page 		query 	int 	true 	"Page"
+// @Param 			pageSize 	query 	int 	true 	"Elements per page"
+// @Accept			json
+// @Produce			json
+// @Success			200 					{array} 	jobdto.Run
+// @Failure			400,401,403,404,500		{object} 	responsebody.Error
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/scheduler/jobs/{job}/runs [get]
+// @Security		BearerAuth
+func GetJobRuns(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	job := ctx.Param("job")
+	if !scheduler.IsKnownJob(job) {
+		err := Error.NewStatusError("Job "+job+" doesn't exist", http.StatusNotFound)
+		controller.Log.Error("Failed to get job history", err.Error(), reqMeta)
+		return err
+	}
+
+	page, err := strconv.Atoi(ctx.QueryParam("page"))
+	if err != nil {
+		errMsg := "Query param 'page' is missing or isn't an integer number"
+		controller.Log.Error("Failed to get job history", errMsg, reqMeta)
+		return echo.NewHTTPError(http.StatusBadRequest, errMsg)
+	}
+	pageSize, err := strconv.Atoi(ctx.QueryParam("pageSize"))
+	if err != nil {
+		errMsg := "Query param 'pageSize' is missing or isn't an integer number"
+		controller.Log.Error("Failed to get job history", errMsg, reqMeta)
+		return echo.NewHTTPError(http.StatusBadRequest, errMsg)
+	}
+
+	runs, e := DB.Database.GetJobRuns(SharedController.GetBasicAction(ctx), job, page, pageSize)
+	if e != nil {
+		return e
+	}
+
+	return ctx.JSON(http.StatusOK, runs)
+}
@@ -15,22 +15,61 @@ import (
 	"sentinel/packages/presentation/api/http/cookie"
 	"sentinel/packages/presentation/api/http/request"
 	ResponseBody "sentinel/packages/presentation/data/response"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
-func AuthenticateWithNewSession(ctx echo.Context, user *UserDTO.Full, audience []string) error {
+var notOrganizationMember = Error.NewStatusError(
+	"User is not a member of this organization",
+	http.StatusForbidden,
+)
+
+// Returns roles which must be put in the user tokens.
+// If orgID isn't empty, then these are roles of the user in this organization,
+// otherwise these are effective roles of the user (own roles and active role grants).
+func GetTokenRoles(user *UserDTO.Full, orgID string) ([]string, *Error.Status) {
+	if orgID == "" {
+		return DB.Database.GetEffectiveRoles(user)
+	}
+
+	roles, err := DB.Database.GetMemberRoles(orgID, user.ID)
+	if err != nil {
+		if err == Error.StatusNotFound {
+			return nil, notOrganizationMember
+		}
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// Sets time and method of authentication for the given payload.
+// Must be called each time user proves his identity (login, re-authentication).
+func SetAuthentication(payload *UserDTO.Payload, authMethod string) {
+	payload.AuthTime = time.Now().Unix()
+	payload.AMR = []string{authMethod}
+}
+
+func AuthenticateWithNewSession(ctx echo.Context, user *UserDTO.Full, audience []string, authMethod string) error {
+	roles, err := DB.Database.GetEffectiveRoles(user)
+	if err != nil {
+		return err
+	}
+
 	payload := &UserDTO.Payload{
 		ID:        user.ID,
 		Login:     user.Login,
-		Roles:     user.Roles,
+		Roles:     roles,
 		SessionID: uuid.NewString(),
 		Version:   user.Version,
 		Audience:  audience,
 	}
 
-	accessToken, refreshToken, err := token.NewAuthTokens(payload)
+	SetAuthentication(payload, authMethod)
+
+	evict, err := checkSessionLimit(user, roles)
 	if err != nil {
 		return err
 	}
@@ -40,13 +79,23 @@ func AuthenticateWithNewSession(ctx echo.Context, user *UserDTO.Full, audience [
 		return err
 	}
 
+	capSessionExpiration(session, roles)
+
 	if err := DB.Database.SaveSession(session); err != nil {
 		return Error.NewStatusError("Failed to save session", http.StatusInternalServerError)
 	}
 
 	act := ActionDTO.NewUserTargeted(user.ID, user.ID, user.Roles)
 
-	newLocation, err := updateOrCreateLocation(act, session.ID, session.IpAddress)
+	newLocation, _, err := updateOrCreateLocation(act, session.ID, session.IpAddress)
+	if err != nil {
+		if e := DB.Database.RevokeSession(act, session.ID); e != nil {
+			return e
+		}
+		return err
+	}
+
+	device, isNewDevice, err := identifySessionDevice(ctx, user, session.ID)
 	if err != nil {
 		if e := DB.Database.RevokeSession(act, session.ID); e != nil {
 			return e
@@ -54,6 +103,28 @@ func AuthenticateWithNewSession(ctx echo.Context, user *UserDTO.Full, audience [
 		return err
 	}
 
+	assessment, err := assessSessionRisk(ctx, user, session, newLocation, nil, time.Time{}, device, isNewDevice)
+	if err != nil {
+		if e := DB.Database.RevokeSession(act, session.ID); e != nil {
+			return e
+		}
+		return err
+	}
+	if err := applyRiskAction(ctx, act, session.ID, payload, assessment, loginBlockedByRisk); err != nil {
+		return err
+	}
+
+	accessToken, refreshToken, err := token.NewAuthTokens(payload)
+	if err != nil {
+		return err
+	}
+
+	// New session is established, so now other sessions can be evicted
+	evicted, err := evictSessions(user, evict)
+	if err != nil {
+		return err
+	}
+
 	email.EnqueueEmail(email.NewSessionAlertEmail, user.Login, email.Substitutions{
 		email.LocationPlaceholder: newLocation.String(),
 	})
@@ -68,11 +139,13 @@ func AuthenticateWithNewSession(ctx echo.Context, user *UserDTO.Full, audience [
 			Message:     "Пользователь успешно авторизован",
 			AccessToken: accessToken.String(),
 			ExpiresIn:   int(accessToken.TTL()) / 1000,
+
+			EvictedSessions: evicted,
 		},
 	)
 }
 
-func Authenticate(ctx echo.Context, user *UserDTO.Full, audience []string) error {
+func Authenticate(ctx echo.Context, user *UserDTO.Full, audience []string, authMethod string) error {
 	if tk, err := GetRefreshToken(ctx); err == nil {
 		reqMeta := request.GetMetadata(ctx)
 
@@ -87,11 +160,16 @@ func Authenticate(ctx echo.Context, user *UserDTO.Full, audience []string) error
 			goto regular_login
 		}
 
+		SetAuthentication(payload, authMethod)
+
 		accessToken, refreshToken, err := UpdateSession(ctx, nil, user, payload)
 		if err != nil {
 			if err == authz.InsufficientPermissions || err == authz.DeniedByActionGatePolicy {
 				return err
 			}
+			if err == SessionBlockedByRisk {
+				return loginBlockedByRisk
+			}
 			controller.Log.Error("Failed to update user session. Switch to regular login process", err.Error(), reqMeta)
 			goto regular_login
 		}
@@ -124,13 +202,22 @@ regular_login:
 
 		controller.Log.Info("Already existing user session was found for the specified device. Proceeding with it", reqMeta)
 
-		accessToken, refreshToken, err := UpdateSession(ctx, session, user, &UserDTO.Payload{
+		roles, err := DB.Database.GetEffectiveRoles(user)
+		if err != nil {
+			return err
+		}
+
+		payload := &UserDTO.Payload{
 			ID:        user.ID,
 			Login:     user.Login,
-			Roles:     user.Roles,
+			Roles:     roles,
 			SessionID: session.ID,
 			Version:   user.Version,
-		})
+		}
+
+		SetAuthentication(payload, authMethod)
+
+		accessToken, refreshToken, err := UpdateSession(ctx, session, user, payload)
 		if err == nil {
 			ctx.SetCookie(cookie.NewAuthCookie(refreshToken))
 
@@ -150,7 +237,10 @@ regular_login:
 		if err == authz.InsufficientPermissions || err == authz.DeniedByActionGatePolicy {
 			return err
 		}
+		if err == SessionBlockedByRisk {
+			return loginBlockedByRisk
+		}
 	}
 
-	return AuthenticateWithNewSession(ctx, user, audience)
+	return AuthenticateWithNewSession(ctx, user, audience, authMethod)
 }
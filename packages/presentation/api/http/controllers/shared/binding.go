@@ -0,0 +1,105 @@
+package sharedcontroller
+
+import (
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	LocationDTO "sentinel/packages/core/location/DTO"
+	SessionDTO "sentinel/packages/core/session/DTO"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/binding"
+	"sentinel/packages/infrastructure/email"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	"sentinel/packages/presentation/api/http/request"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Returned by UpdateSession if session was revoked due to the session binding policy.
+var SessionBindingViolated = Error.NewStatusError(
+	"Session was revoked: it's used from another browser, device or network",
+	Error.SessionRevoked,
+)
+
+// Returns identity of the session device without browser.
+// Device ID of desktops consists of OS and browser (see getDesktopDeviceID),
+// so for them only OS is used, otherwise browser change would be treated as device change.
+func getBindingDevice(session *SessionDTO.Full) string {
+	if session.DeviceType == string(desktop) {
+		return session.DeviceType + " " + session.OS
+	}
+	return session.DeviceType + " " + session.DeviceID
+}
+
+func newSnapshot(session *SessionDTO.Full, location *LocationDTO.Full) *binding.Snapshot {
+	snapshot := &binding.Snapshot{
+		Browser: session.Browser,
+		OS:      session.OS,
+		Device:  getBindingDevice(session),
+		IP:      session.IpAddress,
+	}
+
+	if location != nil {
+		snapshot.Country = location.Country
+	}
+
+	return snapshot
+}
+
+// Compares previous and current state of the session and applies binding policy
+// for roles and audience of the payload to the changed attributes.
+// previousLocation and currentLocation can be nil (if unknown).
+// If session must be revoked it's revoked and SessionBindingViolated is returned.
+func enforceSessionBinding(
+	ctx echo.Context,
+	user *UserDTO.Full,
+	payload *UserDTO.Payload,
+	previous *SessionDTO.Full,
+	current *SessionDTO.Full,
+	previousLocation *LocationDTO.Full,
+	currentLocation *LocationDTO.Full,
+) *Error.Status {
+	policy := binding.PolicyFor(payload.Roles, payload.Audience)
+
+	result := binding.Evaluate(
+		newSnapshot(previous, previousLocation),
+		newSnapshot(current, currentLocation),
+		&policy,
+	)
+	if len(result.Changed) == 0 {
+		return nil
+	}
+
+	changes := make([]string, len(result.Changed))
+	for i, attr := range result.Changed {
+		changes[i] = string(attr) + " changed"
+	}
+
+	controller.Log.Info(
+		"Session "+current.ID+" binding: "+strings.Join(changes, ", ")+". Action: "+result.Action.String(),
+		request.GetMetadata(ctx),
+	)
+
+	if result.Action >= binding.AlertAction && currentLocation != nil {
+		email.EnqueueEmail(email.SuspiciousLoginAlertEmail, user.Login, email.Substitutions{
+			email.LocationPlaceholder: currentLocation.String(),
+			email.ReasonsPlaceholder:  strings.Join(changes, ", "),
+		})
+	}
+
+	switch result.Action {
+	case binding.RevokeAction:
+		act := ActionDTO.NewUserTargeted(payload.ID, payload.ID, payload.Roles)
+		act.Reason = "session binding: " + strings.Join(changes, ", ")
+
+		if err := DB.Database.RevokeSession(act, current.ID); err != nil {
+			return err
+		}
+		return SessionBindingViolated
+	case binding.StepUpAction:
+		requireStepUp(ctx, payload)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package sharedcontroller
+
+import (
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	DeviceDTO "sentinel/packages/core/device/DTO"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/token"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	"sentinel/packages/presentation/api/http/cookie"
+	"sentinel/packages/presentation/api/http/request"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Returns ID of the device from the device cookie.
+// Returns empty string if cookie is missing, invalid or was issued for another user.
+func GetDeviceID(ctx echo.Context, UID string) string {
+	deviceCookie, err := ctx.Cookie(cookie.DeviceTokenCookieKey)
+	if err != nil {
+		return ""
+	}
+
+	tk, e := token.ParseSingedToken(deviceCookie.Value, config.Secret.DeviceTokenPublicKey)
+	if e != nil {
+		return ""
+	}
+
+	claims := tk.Claims.(*token.Claims)
+	if claims.Subject != UID {
+		return ""
+	}
+
+	return claims.ID
+}
+
+func newDevice(ctx echo.Context, UID string) (*DeviceDTO.Full, *Error.Status) {
+	ua, err := getUserAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceType, _ := getDeviceInfo(ua)
+	os, _ := getOS(ua)
+
+	now := time.Now()
+
+	return &DeviceDTO.Full{
+		ID:          uuid.NewString(),
+		UserID:      UID,
+		UserAgent:   ua.String,
+		DeviceType:  string(deviceType),
+		OS:          os,
+		Browser:     ua.Name,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}, nil
+}
+
+// Identifies device from which session with the given ID is used and binds session to it.
+// If device is unknown (device cookie is missing, invalid or was issued for another user),
+// then it's registered as new one. Device cookie is (re)issued in both cases.
+func identifySessionDevice(
+	ctx echo.Context,
+	user *UserDTO.Full,
+	sessionID string,
+) (device *DeviceDTO.Full, isNew bool, err *Error.Status) {
+	reqMeta := request.GetMetadata(ctx)
+
+	controller.Log.Trace("Identifying device of session "+sessionID+"...", reqMeta)
+
+	if deviceID := GetDeviceID(ctx, user.ID); deviceID != "" {
+		device, err = DB.Database.GetUserDevice(user.ID, deviceID)
+		if err != nil && err != Error.StatusNotFound {
+			return nil, false, err
+		}
+		// If device wasn't found, then it was removed by the user, so it must be registered again
+	}
+
+	if device == nil {
+		device, err = newDevice(ctx, user.ID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		act := ActionDTO.NewUserTargeted(user.ID, user.ID, user.Roles)
+
+		if err := DB.Database.RegisterDevice(&act.Basic, device); err != nil {
+			return nil, false, err
+		}
+
+		isNew = true
+	} else {
+		if err := DB.Database.TouchDevice(device.ID, ctx.Request().UserAgent()); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := DB.Database.BindSessionToDevice(sessionID, device.ID); err != nil {
+		return nil, false, err
+	}
+
+	deviceToken, err := token.NewDeviceToken(user.ID, device.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ctx.SetCookie(cookie.NewDeviceCookie(deviceToken))
+
+	controller.Log.Trace("Identifying device of session "+sessionID+": OK", reqMeta)
+
+	return device, isNew, nil
+}
@@ -4,60 +4,62 @@ import (
 	"net"
 	"sentinel/packages/common/config"
 	Error "sentinel/packages/common/errors"
-	LocationProvider "sentinel/packages/common/location"
 	ActionDTO "sentinel/packages/core/action/DTO"
 	LocationDTO "sentinel/packages/core/location/DTO"
 	"sentinel/packages/infrastructure/DB"
+	LocationProvider "sentinel/packages/infrastructure/location"
 	controller "sentinel/packages/presentation/api/http/controllers"
 )
 
 // If location for session with specified ID already exists - updates this location.
 // If there are no location for this session - creates new location for it.
-func updateOrCreateLocation(act *ActionDTO.UserTargeted, sessionID string, ip net.IP) (*LocationDTO.Full, *Error.Status) {
+// Returns new location (nil if IP address hasn't changed) and previous location of the session (nil if there was none).
+func updateOrCreateLocation(
+	act *ActionDTO.UserTargeted,
+	sessionID string,
+	ip net.IP,
+) (newLocation *LocationDTO.Full, previous *LocationDTO.Full, err *Error.Status) {
 	controller.Log.Trace("Updating location for session "+sessionID+"...", nil)
 
-	var err *Error.Status
-	var newLocation *LocationDTO.Full
-
 	location, err := DB.Database.GetLocationBySessionID(act, sessionID)
 	if err != nil {
 		if err != Error.StatusNotFound {
-			return nil, err
+			return nil, nil, err
 		}
 
-		newLocation, err = LocationProvider.GetLocationFromIP(ip.To4().String())
+		newLocation, err = LocationProvider.GetLocationFromIP(ip.String())
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		newLocation.SessionID = sessionID
 
 		if err := DB.Database.SaveLocation(newLocation); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	} else {
 		if config.Debug.Enabled && config.Debug.LocationIP != "" {
-			controller.Log.Debug("Request IP changed: "+ip.To4().String()+" -> "+config.Debug.LocationIP, nil)
+			controller.Log.Debug("Request IP changed: "+ip.String()+" -> "+config.Debug.LocationIP, nil)
 			ip = net.ParseIP(config.Debug.LocationIP)
 		}
 		if ip.Equal(location.IP) {
 			controller.Log.Info("Location update skipped: IP address of location hasn't changed", nil)
-			return nil, nil
+			return nil, location, nil
 		}
 
-		newLocation, err = LocationProvider.GetLocationFromIP(ip.To4().String())
+		newLocation, err = LocationProvider.GetLocationFromIP(ip.String())
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		newLocation.SessionID = sessionID
 
 		if err := DB.Database.UpdateLocation(location.ID, newLocation); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	controller.Log.Trace("Updating location for session "+sessionID+":OK", nil)
 
-	return newLocation, nil
+	return newLocation, location, nil
 }
@@ -0,0 +1,156 @@
+package sharedcontroller
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	DeviceDTO "sentinel/packages/core/device/DTO"
+	LocationDTO "sentinel/packages/core/location/DTO"
+	SessionDTO "sentinel/packages/core/session/DTO"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/risk"
+	"sentinel/packages/infrastructure/email"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	"sentinel/packages/presentation/api/http/request"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var loginBlockedByRisk = Error.NewStatusError(
+	"Sign-in was blocked: it looks suspicious. If that was you, contact support",
+	http.StatusForbidden,
+)
+
+// Returned by UpdateSession if session was revoked since it's used from the suspicious location.
+var SessionBlockedByRisk = Error.NewStatusError(
+	"Session was revoked: it's used from the suspicious location",
+	Error.SessionRevoked,
+)
+
+func newObservation(location *LocationDTO.Full, seenAt time.Time) risk.Observation {
+	return risk.Observation{
+		Country:   location.Country,
+		ISP:       location.ISP,
+		Latitude:  float64(location.Latitude),
+		Longitude: float64(location.Longitude),
+		Time:      seenAt,
+	}
+}
+
+// Assesses risk of the given session which was just used from the given location.
+// previous is the location from which this session was used before (can be nil),
+// it's seen at previousSeenAt. device is the device from which session is used now.
+// Assessment is saved for audit and if risk is high enough user is alerted via email.
+func assessSessionRisk(
+	ctx echo.Context,
+	user *UserDTO.Full,
+	session *SessionDTO.Full,
+	location *LocationDTO.Full,
+	previous *LocationDTO.Full,
+	previousSeenAt time.Time,
+	device *DeviceDTO.Full,
+	isNewDevice bool,
+) (risk.Assessment, *Error.Status) {
+	if !config.Risk.Enabled {
+		return risk.Assessment{}, nil
+	}
+
+	reqMeta := request.GetMetadata(ctx)
+
+	sessionID := session.ID
+
+	controller.Log.Trace("Assessing risk of session "+sessionID+"...", reqMeta)
+
+	visits, err := DB.Database.GetRecentUserLocations(user.ID, sessionID, config.Risk.HistorySize)
+	if err != nil {
+		return risk.Assessment{}, err
+	}
+
+	history := make([]risk.Observation, 0, len(visits)+1)
+	if previous != nil {
+		history = append(history, newObservation(previous, previousSeenAt))
+	}
+	for _, visit := range visits {
+		history = append(history, newObservation(visit.Full, visit.SeenAt))
+	}
+
+	now := time.Now()
+
+	current := newObservation(location, now)
+	current.NewDevice = isNewDevice
+	current.TrustedDevice = device.Trusted
+	current.TrustedSession = session.Trusted
+
+	assessment := risk.Assess(current, history, risk.ConfiguredSettings())
+
+	reasons := make([]string, len(assessment.Reasons))
+	for i, reason := range assessment.Reasons {
+		reasons[i] = string(reason)
+	}
+
+	if err := DB.Database.SaveSessionRisk(&SessionDTO.Risk{
+		SessionID: sessionID,
+		UserID:    user.ID,
+		IP:        location.IP,
+		Country:   location.Country,
+		Score:     assessment.Score,
+		Reasons:   reasons,
+		Action:    assessment.Action.String(),
+		CreatedAt: now,
+	}); err != nil {
+		return risk.Assessment{}, err
+	}
+
+	if assessment.Action != risk.NoAction {
+		controller.Log.Info(
+			"Session "+sessionID+" looks suspicious: "+strings.Join(reasons, ", ")+". Action: "+assessment.Action.String(),
+			reqMeta,
+		)
+	}
+
+	if assessment.Action >= risk.AlertAction {
+		email.EnqueueEmail(email.SuspiciousLoginAlertEmail, user.Login, email.Substitutions{
+			email.LocationPlaceholder: location.String(),
+			email.ReasonsPlaceholder:  strings.Join(reasons, ", "),
+		})
+	}
+
+	controller.Log.Trace("Assessing risk of session "+sessionID+": OK", reqMeta)
+
+	return assessment, nil
+}
+
+// Applies action of the risk assessment to the session and its token payload.
+// If session is blocked it's revoked and blockErr is returned.
+// If step-up is required, authentication time of the payload is reset.
+func applyRiskAction(
+	ctx echo.Context,
+	act *ActionDTO.UserTargeted,
+	sessionID string,
+	payload *UserDTO.Payload,
+	assessment risk.Assessment,
+	blockErr *Error.Status,
+) *Error.Status {
+	switch assessment.Action {
+	case risk.BlockAction:
+		if err := DB.Database.RevokeSession(act, sessionID); err != nil {
+			return err
+		}
+		return blockErr
+	case risk.StepUpAction:
+		requireStepUp(ctx, payload)
+	}
+
+	return nil
+}
+
+// Resets authentication time of the payload, so user must re-authenticate
+// before accessing endpoints which require recent authentication.
+func requireStepUp(ctx echo.Context, payload *UserDTO.Payload) {
+	payload.AuthTime = 0
+	ctx.Response().Header().Set("X-Step-Up-Required", "true")
+}
@@ -0,0 +1,91 @@
+package sharedcontroller
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	SessionDTO "sentinel/packages/core/session/DTO"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	"strconv"
+)
+
+var sessionLimitReached = Error.NewStatusError(
+	"Max amount of active sessions is reached, log out from one of the sessions and try again",
+	http.StatusConflict,
+)
+
+// Reason of sessions revocation due to session limit
+const sessionLimitReason = "session limit"
+
+// Returns sessions which must be revoked, so new session can be created without exceeding the limit.
+// Sessions must be sorted by last use (least recently used first). Zero limit means that there are no limit.
+func sessionsToEvict(sessions []*SessionDTO.Full, limit int) []*SessionDTO.Full {
+	if limit <= 0 || len(sessions) < limit {
+		return nil
+	}
+	return sessions[:len(sessions)-limit+1]
+}
+
+// Must be called before creation of the new session.
+// If user already reached the session limit, then, depending on config, either rejects login
+// or returns least recently used sessions which must be evicted via evictSessions.
+// Sessions aren't revoked here, since login still can fail (e.g. due to risk assessment),
+// and user mustn't lose their sessions in such case.
+func checkSessionLimit(user *UserDTO.Full, roles []string) ([]*SessionDTO.Full, *Error.Status) {
+	limit := config.Auth.MaxSessionsFor(roles)
+	if limit == 0 {
+		return nil, nil
+	}
+
+	sessions, err := DB.Database.GetActiveUserSessions(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	evict := sessionsToEvict(sessions, limit)
+	if len(evict) == 0 {
+		return nil, nil
+	}
+
+	if config.Auth.SessionLimitPolicy == config.RejectSessionLimitPolicy {
+		controller.Log.Error(
+			"Failed to authenticate user '"+user.Login+"'",
+			"Session limit is reached ("+strconv.Itoa(limit)+")",
+			nil,
+		)
+		return nil, sessionLimitReached
+	}
+
+	return evict, nil
+}
+
+// Revokes sessions returned by checkSessionLimit. Returns revoked sessions.
+// Must be called only after the new session was successfully established.
+func evictSessions(user *UserDTO.Full, evict []*SessionDTO.Full) ([]*SessionDTO.Public, *Error.Status) {
+	if len(evict) == 0 {
+		return nil, nil
+	}
+
+	act := ActionDTO.NewUserTargeted(user.ID, user.ID, user.Roles)
+	act.Reason = sessionLimitReason
+
+	evicted := make([]*SessionDTO.Public, 0, len(evict))
+
+	for _, session := range evict {
+		controller.Log.Info("Evicting session "+session.ID+" of user '"+user.Login+"' due to session limit...", nil)
+
+		if err := DB.Database.RevokeSession(act, session.ID); err != nil {
+			controller.Log.Error("Failed to evict session "+session.ID+" of user '"+user.Login+"'", err.Error(), nil)
+			return nil, err
+		}
+
+		controller.Log.Info("Evicting session "+session.ID+" of user '"+user.Login+"' due to session limit: OK", nil)
+
+		evicted = append(evicted, session.MakePublic())
+	}
+
+	return evicted, nil
+}
@@ -0,0 +1,78 @@
+package sharedcontroller
+
+import (
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	ActionDTO "sentinel/packages/core/action/DTO"
+	SessionDTO "sentinel/packages/core/session/DTO"
+	"sentinel/packages/infrastructure/DB"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	"time"
+)
+
+// Both have status of revoked session, so client will drop its tokens and ask user to log in again
+var (
+	sessionIdleTimeoutExceeded = Error.NewStatusError(
+		"Session has expired due to inactivity, please log in again",
+		Error.SessionRevoked,
+	)
+	sessionMaxLifetimeExceeded = Error.NewStatusError(
+		"Session has reached its max lifetime, please log in again",
+		Error.SessionRevoked,
+	)
+)
+
+// Reasons of sessions revocation due to timeouts
+var sessionTimeoutReasons = map[*Error.Status]string{
+	sessionIdleTimeoutExceeded: "idle timeout",
+	sessionMaxLifetimeExceeded: "max lifetime",
+}
+
+func checkSessionTimeouts(session *SessionDTO.Full, idleTimeout time.Duration, maxLifetime time.Duration, now time.Time) *Error.Status {
+	if maxLifetime != 0 && now.Sub(session.CreatedAt) > maxLifetime {
+		return sessionMaxLifetimeExceeded
+	}
+	if idleTimeout != 0 && now.Sub(session.LastUsedAt) > idleTimeout {
+		return sessionIdleTimeoutExceeded
+	}
+	return nil
+}
+
+// Limits expiration time of the session by its max lifetime.
+func capSessionExpiration(session *SessionDTO.Full, roles []string) {
+	_, maxLifetime := config.Auth.SessionTimeoutsFor(roles)
+
+	if maxLifetime == 0 {
+		return
+	}
+
+	if deadline := session.CreatedAt.Add(maxLifetime); session.ExpiresAt.After(deadline) {
+		session.ExpiresAt = deadline
+	}
+}
+
+// Checks if session exceeded idle timeout or max lifetime for the given roles.
+// If so, then session is revoked and error is returned, so it can't be used or refreshed anymore.
+func EnforceSessionTimeouts(session *SessionDTO.Full, roles []string) *Error.Status {
+	idleTimeout, maxLifetime := config.Auth.SessionTimeoutsFor(roles)
+
+	err := checkSessionTimeouts(session, idleTimeout, maxLifetime, time.Now())
+	if err == nil {
+		return nil
+	}
+
+	controller.Log.Info("Revoking session "+session.ID+" due to "+sessionTimeoutReasons[err]+"...", nil)
+
+	act := ActionDTO.NewUserTargeted(session.UserID, session.UserID, roles)
+	act.Reason = sessionTimeoutReasons[err]
+
+	if e := DB.Database.RevokeSession(act, session.ID); e != nil {
+		// Session must not be used anyway
+		controller.Log.Error("Failed to revoke session "+session.ID+" due to "+act.Reason, e.Error(), nil)
+		return err
+	}
+
+	controller.Log.Info("Revoking session "+session.ID+" due to "+sessionTimeoutReasons[err]+": OK", nil)
+
+	return err
+}
@@ -149,19 +149,8 @@ func createSession(ctx echo.Context, ID string, UID string, ttl time.Duration) (
 	}, nil
 }
 
-var deviceIDMismatch = Error.NewStatusError(
-	"Detected new device ID",
-	http.StatusConflict,
-)
-var browserMismatch = Error.NewStatusError(
-	"Detected new browser",
-	http.StatusConflict,
-)
-var osMismatch = Error.NewStatusError(
-	"Detected new OS",
-	http.StatusConflict,
-)
-
+// Returns actual state of the given session.
+// Browser, OS and device of the session may change, it's up to the session binding policy to decide what to do then.
 func actualizeSession(
 	ctx echo.Context,
 	session *SessionDTO.Full,
@@ -172,25 +161,9 @@ func actualizeSession(
 		return nil, err
 	}
 
-	_, deviceID := getDeviceInfo(ua)
+	deviceType, deviceID := getDeviceInfo(ua)
 	os, osVersion := getOS(ua)
 
-	// Browser mismatch
-	// Sessions mustn't be shared across them, so each there are must be new session for each browser
-	if session.Browser != ua.Name {
-		return nil, browserMismatch
-	}
-	// OS mismatch
-	// In case with PC, each OS must be treated as new device
-	if session.OS != os {
-		return nil, osMismatch
-	}
-	// Device ID mismatch (for mobiles and tablets it's actual device model, for OS it's OS + browser name)
-	// e.g. was some Android-based phone (samsung, for example), but new one is on iPhone or on some PC on Windows
-	if session.DeviceID != deviceID {
-		return nil, deviceIDMismatch
-	}
-
 	now := time.Now()
 
 	return &SessionDTO.Full{
@@ -198,15 +171,18 @@ func actualizeSession(
 		UserID:         session.UserID,
 		UserAgent:      ua.String,
 		IpAddress:      net.ParseIP(ctx.RealIP()),
-		DeviceID:       session.DeviceID,
-		DeviceType:     session.DeviceType,
-		OS:             session.OS,
+		DeviceID:       deviceID,
+		DeviceType:     string(deviceType),
+		OS:             os,
 		OSVersion:      osVersion,
-		Browser:        session.Browser,
+		Browser:        ua.Name,
 		BrowserVersion: ua.Version,
 		CreatedAt:      session.CreatedAt,
 		LastUsedAt:     now,
 		ExpiresAt:      now.Add(ttl),
+		// Labels are set by the user, so they are kept as is
+		Name:    session.Name,
+		Trusted: session.Trusted,
 	}, nil
 }
 
@@ -238,10 +214,18 @@ func UpdateSession(
 
 	isSessionSet := session != nil
 
+	// Version is also bumped when role grant becomes active or expires,
+	// so roles are recalculated only if user was changed.
 	if user.Version != payload.Version {
+		// Membership could be changed or revoked as well, in the last case token can't be refreshed
+		roles, err := GetTokenRoles(user, payload.OrganizationID)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		payload.ID = user.ID
 		payload.Login = user.Login
-		payload.Roles = user.Roles
+		payload.Roles = roles
 		payload.Version = user.Version
 	}
 
@@ -256,8 +240,8 @@ func UpdateSession(
 		}
 	}
 
-	accessToken, refreshToken, err = token.NewAuthTokens(payload)
-	if err != nil {
+	// Expired session can't be refreshed, user must log in again
+	if err := EnforceSessionTimeouts(session, payload.Roles); err != nil {
 		return nil, nil, err
 	}
 
@@ -266,6 +250,8 @@ func UpdateSession(
 		return nil, nil, err
 	}
 
+	capSessionExpiration(newSession, payload.Roles)
+
 	// If session was specified in this function args need to ensure that this session is exists in DB.
 	// If it wasn't specified then this session is queried from DB in this function, so there are no need in this check.
 	if isSessionSet {
@@ -279,7 +265,42 @@ func UpdateSession(
 		return nil, nil, err
 	}
 
-	if _, err := updateOrCreateLocation(act, newSession.ID, newSession.IpAddress); err != nil {
+	newLocation, previousLocation, err := updateOrCreateLocation(act, newSession.ID, newSession.IpAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currentLocation := previousLocation
+	if newLocation != nil {
+		currentLocation = newLocation
+	}
+
+	if err := enforceSessionBinding(
+		ctx, user, payload, session, newSession, previousLocation, currentLocation,
+	); err != nil {
+		return nil, nil, err
+	}
+
+	device, isNewDevice, err := identifySessionDevice(ctx, user, newSession.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Risk is assessed only when session is used from the new location
+	if newLocation != nil {
+		assessment, err := assessSessionRisk(
+			ctx, user, newSession, newLocation, previousLocation, session.LastUsedAt, device, isNewDevice,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := applyRiskAction(ctx, act, newSession.ID, payload, assessment, SessionBlockedByRisk); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	accessToken, refreshToken, err = token.NewAuthTokens(payload)
+	if err != nil {
 		return nil, nil, err
 	}
 
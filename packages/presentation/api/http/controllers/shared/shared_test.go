@@ -1,10 +1,14 @@
 package sharedcontroller
 
 import (
+	"net"
+	"slices"
 	"testing"
+	"time"
 
 	Error "sentinel/packages/common/errors"
 	SessionDTO "sentinel/packages/core/session/DTO"
+	"sentinel/packages/infrastructure/auth/binding"
 
 	"github.com/mileusna/useragent"
 )
@@ -64,157 +68,142 @@ func TestDeviceDetectionLogic(t *testing.T) {
 	})
 }
 
-func TestErrorResponseCodes(t *testing.T) {
-	t.Run("session conflicts return HTTP 409", func(t *testing.T) {
-		if deviceIDMismatch.Status() != 409 {
-			t.Error("Device mismatch should be conflict error")
-		}
-		if browserMismatch.Status() != 409 {
-			t.Error("Browser mismatch should be conflict error")
-		}
-		if osMismatch.Status() != 409 {
-			t.Error("OS mismatch should be conflict error")
-		}
-	})
-}
-
-func TestSessionActualizationLogic(t *testing.T) {
-	// Test the core business logic for session reuse validation
-	// This validates whether existing sessions can be safely continued
-
-	t.Run("allows matching browsers", func(t *testing.T) {
-		// Session with same browser should be allowed
-		oldSession := &SessionDTO.Full{
-			Browser:  "Chrome",
-			OS:       "Windows",
-			DeviceID: "WindowsChrome",
-		}
-
-		ua := useragent.UserAgent{
-			Desktop: true,
-			OS:      "Windows",
-			Name:    "Chrome", // Same browser
-		}
-
-		result, err := simulateSessionActualization(oldSession, ua, mobile, "WindowsChrome")
-		if err != nil {
-			t.Errorf("Expected successful session actualization, got error: %v", err)
-		}
-		if result == nil {
-			t.Error("Expected valid session result")
-		}
-	})
-
-	t.Run("rejects browser mismatch", func(t *testing.T) {
-		// Different browser should be rejected (sessions can't be shared)
-		oldSession := &SessionDTO.Full{
-			Browser: "Chrome",
-		}
-
-		ua := useragent.UserAgent{
-			Desktop: true,
-			Name:    "Firefox", // Different browser
-		}
-
-		_, err := simulateSessionActualization(oldSession, ua, desktop, "WindowsFirefox")
-		if err == nil {
-			t.Error("Expected browser mismatch error")
-		}
-		if err.Status() != 409 {
-			t.Errorf("Expected conflict error (409), got %d", err.Status())
-		}
-	})
-
-	t.Run("rejects OS mismatch for desktops", func(t *testing.T) {
-		// Different OS should be rejected for desktops (new device)
-		oldSession := &SessionDTO.Full{
-			Browser:  "Chrome",
-			OS:       "Windows",
-			DeviceID: "WindowsChrome",
-		}
-
-		ua := useragent.UserAgent{
-			Desktop: true,
-			OS:      "Linux", // Different OS
-			Name:    "Chrome",
-		}
-
-		_, err := simulateSessionActualization(oldSession, ua, desktop, "LinuxChrome")
-		if err == nil {
-			t.Error("Expected OS mismatch error")
-		}
-		if err.Status() != 409 {
-			t.Errorf("Expected conflict error (409), got %d", err.Status())
-		}
-	})
-
-	t.Run("rejects device ID mismatch", func(t *testing.T) {
-		// Different device ID should be rejected (mobile device changed)
-		oldSession := &SessionDTO.Full{
-			Browser:  "Safari",
-			OS:       "iOS",
-			DeviceID: "iPhone",
-		}
+func TestSessionBindingChanges(t *testing.T) {
+	// Changes of browser, OS and device don't reject the session by themselves,
+	// they are reported as changed attributes to which binding policy is applied.
+	policy := &binding.Policy{
+		Actions: map[binding.Attribute]binding.Action{
+			binding.BrowserAttribute: binding.StepUpAction,
+			binding.OSAttribute:      binding.StepUpAction,
+			binding.DeviceAttribute:  binding.RevokeAction,
+		},
+		IPv4Prefix: 32,
+		IPv6Prefix: 128,
+	}
 
-		ua := useragent.UserAgent{
-			Mobile: true,
-			OS:     "iOS",
-			Name:   "Safari",
-			Device: "iPad", // Different device model
-		}
+	oldSession := &SessionDTO.Full{
+		Browser:    "Chrome",
+		OS:         "Windows",
+		DeviceID:   "WindowsChrome",
+		DeviceType: string(desktop),
+		IpAddress:  net.ParseIP("203.0.113.10"),
+	}
 
-		_, err := simulateSessionActualization(oldSession, ua, mobile, "iPad")
-		if err == nil {
-			t.Error("Expected device ID mismatch error")
-		}
-		if err.Status() != 409 {
-			t.Errorf("Expected conflict error (409), got %d", err.Status())
-		}
-	})
+	tests := []struct {
+		name    string
+		ua      useragent.UserAgent
+		changed []binding.Attribute
+		action  binding.Action
+	}{
+		{
+			name:    "same browser and OS",
+			ua:      useragent.UserAgent{Desktop: true, OS: "Windows", Name: "Chrome"},
+			changed: []binding.Attribute{},
+			action:  binding.AllowAction,
+		},
+		{
+			// Device ID of desktops includes browser, but browser change isn't a device change
+			name:    "browser changed",
+			ua:      useragent.UserAgent{Desktop: true, OS: "Windows", Name: "Firefox"},
+			changed: []binding.Attribute{binding.BrowserAttribute},
+			action:  binding.StepUpAction,
+		},
+		{
+			name:    "OS changed",
+			ua:      useragent.UserAgent{Desktop: true, OS: "Linux", Name: "Chrome"},
+			changed: []binding.Attribute{binding.OSAttribute, binding.DeviceAttribute},
+			action:  binding.RevokeAction,
+		},
+		{
+			name:    "device changed",
+			ua:      useragent.UserAgent{Mobile: true, OS: "Windows", Name: "Chrome", Device: "Pixel 7"},
+			changed: []binding.Attribute{binding.DeviceAttribute},
+			action:  binding.RevokeAction,
+		},
+	}
 
-	t.Run("allows device ID change for same OS/browser", func(t *testing.T) {
-		// Same OS/browser combo should allow device ID flexibility
-		oldSession := &SessionDTO.Full{
-			Browser:  "Chrome",
-			OS:       "Windows",
-			DeviceID: "WindowsChrome",
-		}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceType, deviceID := getDeviceInfo(tt.ua)
+
+			newSession := &SessionDTO.Full{
+				Browser:    tt.ua.Name,
+				OS:         tt.ua.OS,
+				DeviceID:   deviceID,
+				DeviceType: string(deviceType),
+				IpAddress:  oldSession.IpAddress,
+			}
+
+			result := binding.Evaluate(newSnapshot(oldSession, nil), newSnapshot(newSession, nil), policy)
+
+			if !slices.Equal(result.Changed, tt.changed) {
+				t.Errorf("Changed = %v, want %v", result.Changed, tt.changed)
+			}
+			if result.Action != tt.action {
+				t.Errorf("Action = %s, want %s", result.Action, tt.action)
+			}
+		})
+	}
+}
 
-		ua := useragent.UserAgent{
-			Desktop: true,
-			OS:      "Windows",
-			Name:    "Chrome",
-		}
+func TestSessionsToEvict(t *testing.T) {
+	// Sorted by last use, least recently used first
+	sessions := []*SessionDTO.Full{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected []string
+	}{
+		{"no limit", 0, nil},
+		{"limit isn't reached", 4, nil},
+		{"limit is reached", 3, []string{"1"}},
+		{"limit was lowered", 1, []string{"1", "2", "3"}},
+		{"limit is exceeded", 2, []string{"1", "2"}},
+	}
 
-		result, err := simulateSessionActualization(oldSession, ua, desktop, "WindowsChrome")
-		if err != nil {
-			t.Errorf("Expected successful session actualization for same OS/browser, got error: %v", err)
-		}
-		if result == nil {
-			t.Error("Expected valid session result")
-		}
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evicted := sessionsToEvict(sessions, tt.limit)
+
+			if len(evicted) != len(tt.expected) {
+				t.Fatalf("Expected %d sessions to be evicted, got %d", len(tt.expected), len(evicted))
+			}
+			for i, session := range evicted {
+				if session.ID != tt.expected[i] {
+					t.Errorf("Expected session %s to be evicted, got %s", tt.expected[i], session.ID)
+				}
+			}
+		})
+	}
 }
 
-// Helper function to simulate the core logic of actualizeSession without dependencies
-func simulateSessionActualization(oldSession *SessionDTO.Full, ua useragent.UserAgent, deviceType deviceType, deviceID string) (*SessionDTO.Full, *Error.Status) {
-	// Replicate the core validation logic from actualizeSession
-	if oldSession.Browser != ua.Name {
-		return nil, browserMismatch
-	}
-	if oldSession.OS != ua.OS {
-		return nil, osMismatch
+func TestCheckSessionTimeouts(t *testing.T) {
+	now := time.Now()
+
+	session := &SessionDTO.Full{
+		CreatedAt:  now.Add(-10 * time.Hour),
+		LastUsedAt: now.Add(-2 * time.Hour),
 	}
-	if oldSession.DeviceID != deviceID {
-		return nil, deviceIDMismatch
+
+	tests := []struct {
+		name        string
+		idleTimeout time.Duration
+		maxLifetime time.Duration
+		expected    *Error.Status
+	}{
+		{"no limits", 0, 0, nil},
+		{"within limits", 3 * time.Hour, 11 * time.Hour, nil},
+		{"idle timeout exceeded", time.Hour, 0, sessionIdleTimeoutExceeded},
+		{"max lifetime exceeded", 0, 9 * time.Hour, sessionMaxLifetimeExceeded},
+		{"max lifetime takes precedence", time.Hour, 9 * time.Hour, sessionMaxLifetimeExceeded},
 	}
 
-	return &SessionDTO.Full{
-		ID:         oldSession.ID,
-		UserID:     oldSession.UserID,
-		Browser:    ua.Name,
-		OS:         ua.OS,
-		DeviceID:   deviceID,
-		DeviceType: string(deviceType),
-	}, nil
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkSessionTimeouts(session, tt.idleTimeout, tt.maxLifetime, now); err != tt.expected {
+				t.Errorf("checkSessionTimeouts() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
 }
@@ -2,10 +2,13 @@ package usercontroller
 
 import (
 	"fmt"
+	"net"
 	"net/http"
+	"sentinel/packages/common/config"
 	Error "sentinel/packages/common/errors"
 	"sentinel/packages/common/validation"
 	ActionDTO "sentinel/packages/core/action/DTO"
+	SessionDTO "sentinel/packages/core/session/DTO"
 	UserDTO "sentinel/packages/core/user/DTO"
 	"sentinel/packages/infrastructure/DB"
 	"sentinel/packages/infrastructure/auth/authn"
@@ -18,6 +21,7 @@ import (
 	RequestBody "sentinel/packages/presentation/data/request"
 	ResponseBody "sentinel/packages/presentation/data/response"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -92,6 +96,7 @@ func getReasonedAction(ctx echo.Context, body RequestBody.ReasonGetter) (*Action
 // @Produce			json
 // @Success			200
 // @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -171,6 +176,7 @@ func Restore(ctx echo.Context) error {
 // @Produce			json
 // @Success			200
 // @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -209,6 +215,7 @@ func Drop(ctx echo.Context) error {
 // @Produce			json
 // @Success			200
 // @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -278,6 +285,7 @@ func BulkRestore(ctx echo.Context) error {
 // @Produce			json
 // @Success			200
 // @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -366,11 +374,12 @@ func update(ctx echo.Context, body RequestBody.UpdateUser) error {
 
 	switch b := body.(type) {
 	case *RequestBody.ChangeLogin:
-		oldUser, err = DB.Database.GetUserByID(act.TargetUID)
-		if err != nil {
-			return err
+		var loginChangeRequest *UserDTO.LoginChangeRequest
+		loginChangeRequest, err = DB.Database.RequestLoginChange(act, b.Login, sendLoginChangeRequestEmails)
+		// Login will be changed only after confirmation via new login
+		if err == nil {
+			return ctx.JSON(http.StatusAccepted, loginChangeRequest)
 		}
-		err = DB.Database.ChangeLogin(act, b.Login)
 	case *RequestBody.ChangePassword:
 		oldUser, err = DB.Database.GetUserByID(act.TargetUID)
 		if err != nil {
@@ -378,7 +387,18 @@ func update(ctx echo.Context, body RequestBody.UpdateUser) error {
 		}
 		err = DB.Database.ChangePassword(act, b.NewPassword)
 	case *RequestBody.ChangeRoles:
-		err = DB.Database.ChangeRoles(act, b.Roles)
+		oldUser, err = DB.Database.GetUserByID(act.TargetUID)
+		if err != nil {
+			return err
+		}
+		var roleChangeRequest *UserDTO.RoleChangeRequest
+		roleChangeRequest, err = DB.Database.RequestRolesChange(act, b.Roles)
+		// Privileged roles were added, so change will be applied only after approval
+		if err == nil && roleChangeRequest != nil {
+			sendRoleChangeAlert(email.RoleChangeRequestedEmail, roleChangeRequest, oldUser.Login)
+			notifyRoleChangeApprovers(roleChangeRequest)
+			return ctx.JSON(http.StatusAccepted, roleChangeRequest)
+		}
 	default:
 		controller.Log.Panic(
 			"Invalid update call",
@@ -406,8 +426,6 @@ func sendSecurityAlert(login string, body RequestBody.UpdateUser) *Error.Status
 	var emailType email.EmailType
 
 	switch body.(type) {
-	case *RequestBody.ChangeLogin:
-		emailType = email.LoginChangeAlertEmail
 	case *RequestBody.ChangePassword:
 		emailType = email.PasswordChangeAlertEmail
 	default:
@@ -422,7 +440,9 @@ func sendSecurityAlert(login string, body RequestBody.UpdateUser) *Error.Status
 }
 
 // @Summary 		Change user login
-// @Description 	Change user login
+// @Description 	Request change of user login. Login isn't changed right away, instead pending login change request is created and returned.
+// @Description 	Confirmation link is sent to the new login and cancellation link is sent to the old one.
+// @Description 	Login will be changed only after confirmation (see /v1/user/login/confirm), earlier pending requests of the user are cancelled.
 // @ID 				change-user-login
 // @Tags			user
 // @Param 			uid 					path 	string 								true 	"User ID"
@@ -430,8 +450,9 @@ func sendSecurityAlert(login string, body RequestBody.UpdateUser) *Error.Status
 // @Param 			newLoginAndPassword 	body 	requestbody.LoginAndPassword		false 	"New user login and password (required if user tries to change his own login)"
 // @Accept			json
 // @Produce			json
-// @Success			200
-// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Success			202				{object} 	userdto.LoginChangeRequest 	"Login change request was created and awaits confirmation"
+// @Failure			400,401,403,409,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -455,6 +476,7 @@ func ChangeLogin(ctx echo.Context) error {
 // @Produce			json
 // @Success			200
 // @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -468,7 +490,9 @@ func ChangePassword(ctx echo.Context) error {
 }
 
 // @Summary 		Change user roles
-// @Description 	Change user roles
+// @Description 	Change user roles. If new roles adds any of the privileged roles (see "privileged-roles" in config),
+// @Description 	then roles won't be changed right away, instead pending role change request will be created and returned.
+// @Description 	Such request must be approved by another user (see /v1/user/roles/requests), users who can approve it are notified via email.
 // @ID 				change-user-roles
 // @Tags			user
 // @Param 			uid 					path 	string 							true 	"User ID"
@@ -477,7 +501,9 @@ func ChangePassword(ctx echo.Context) error {
 // @Accept			json
 // @Produce			json
 // @Success			200
+// @Success			202				{object} 	userdto.RoleChangeRequest 	"Role change request was created and awaits approval"
 // @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
 // @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
 // @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
 // @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
@@ -619,7 +645,8 @@ func SearchUsers(ctx echo.Context) error {
 }
 
 // @Summary 		Get user sessions
-// @Description 	Get all active user sessions
+// @Description 	Get all active user sessions with their last known location (null if unknown).
+// @Description 	Session of the current request is marked as "current".
 // @ID 				get-user-sessions
 // @Tags			user
 // @Param 			uid path string true "User ID"
@@ -647,9 +674,7 @@ func GetUserSessions(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, errMsg)
 	}
 
-	payload := SharedController.GetUserPayload(ctx)
-
-	act := ActionDTO.NewUserTargeted(uid, payload.ID, payload.Roles)
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(uid)
 
 	// Get locations for this sessions (in SQL query?)
 	sessions, err := DB.Database.GetUserSessions(act)
@@ -657,16 +682,23 @@ func GetUserSessions(ctx echo.Context) error {
 		return err
 	}
 
+	currentSessionID := SharedController.GetUserPayload(ctx).SessionID
+
 	res := make([]ResponseBody.UserSession, 0, len(sessions))
 
 	for _, session := range sessions {
-		location, err := DB.Database.GetLocationBySessionID(act, session.ID)
-		if err == nil {
-			res = append(res, ResponseBody.UserSession{
-				Session:  session,
-				Location: location.MakePublic(),
-			})
+		userSession := ResponseBody.UserSession{
+			Session: session,
+			Current: session.ID == currentSessionID,
 		}
+
+		// Location may be unknown (e.g. for local IPs) or unavailable for requester,
+		// such sessions are still returned, but without location
+		if location, err := DB.Database.GetLocationBySessionID(act, session.ID); err == nil {
+			userSession.Location = location.MakePublic()
+		}
+
+		res = append(res, userSession)
 	}
 
 	return ctx.JSON(http.StatusOK, res)
@@ -701,11 +733,9 @@ func GetUser(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, errMSg)
 	}
 
-	payload := SharedController.GetUserPayload(ctx)
-
-	act := ActionDTO.NewUserTargeted(uid, payload.ID, payload.Roles)
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(uid)
 
-	err := authz.User.GetUserSession(act.RequesterUID == act.TargetUID, act.RequesterRoles)
+	err := authz.User.For(act).GetUserSession(act.RequesterUID == act.TargetUID, act.RequesterRoles)
 	if err != nil {
 		return err
 	}
@@ -717,3 +747,124 @@ func GetUser(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, user.Basic.MakePublic())
 }
+
+// @Summary 		Impersonate user
+// @Description 	Issues short-lived access token of the specified user with "act" claim naming the requester.
+// @Description 	Impersonation token can't be refreshed and can't be used for destructive operations.
+// @Description 	Users with higher privileges than requester can't be impersonated.
+// @Description 	Start of impersonation (with its reason) is recorded in the audit, so token isn't issued if it can't be recorded.
+// @ID 				impersonate-user
+// @Tags			user
+// @Param 			uid path string true "User ID"
+// @Param 			body body requestbody.Impersonate false "Reason and audience of impersonation"
+// @Accept			json
+// @Produce			json
+// @Success			200 			{object} 	responsebody.Token
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/impersonate [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func Impersonate(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	uid := ctx.Param("uid")
+
+	if e := validation.UUID(uid); e != nil {
+		errMSg := e.ToStatus(
+			"User ID is missing in URL path",
+			"User ID has invalid format (expected UUID)",
+		).Error()
+		controller.Log.Error("Failed to impersonate user", errMSg, reqMeta)
+		return echo.NewHTTPError(http.StatusBadRequest, errMSg)
+	}
+
+	var body RequestBody.Impersonate
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	payload := SharedController.GetUserPayload(ctx)
+
+	if uid == payload.ID {
+		return echo.NewHTTPError(http.StatusBadRequest, "Can't impersonate yourself")
+	}
+
+	controller.Log.Info("Impersonating user "+uid+" by user "+payload.ID+"...", reqMeta)
+
+	target, err := DB.Database.GetUserByID(uid)
+	if err != nil {
+		return err
+	}
+
+	targetRoles, err := DB.Database.GetEffectiveRoles(target)
+	if err != nil {
+		return err
+	}
+
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(uid)
+
+	if err := authz.User.For(act).ImpersonateUser(payload.Roles, targetRoles); err != nil {
+		controller.Log.Error("Failed to impersonate user "+uid+" by user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	audience := body.Audience
+	if len(audience) == 0 {
+		audience = []string{config.Auth.SelfAudience}
+	}
+
+	startedAt := time.Now()
+
+	tk, err := token.NewImpersonationToken(&UserDTO.Payload{
+		ID:      target.ID,
+		Login:   target.Login,
+		Roles:   targetRoles,
+		Version: target.Version,
+		// Token is bound to the session of impersonator,
+		// so it will be rejected once this session is revoked.
+		SessionID:      payload.SessionID,
+		Audience:       audience,
+		AuthTime:       payload.AuthTime,
+		AMR:            payload.AMR,
+		ImpersonatorID: payload.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Impersonation mustn't be started without a trace, so token isn't returned if it can't be saved
+	if err := DB.Database.SaveImpersonation(&SessionDTO.Impersonation{
+		ImpersonatorID:     payload.ID,
+		ImpersonatedUserID: target.ID,
+		SessionID:          payload.SessionID,
+		Audience:           audience,
+		Reason:             body.GetReason(),
+		IP:                 net.ParseIP(ctx.RealIP()),
+		StartedAt:          startedAt,
+		ExpiresAt:          startedAt.Add(config.Auth.ImpersonationTokenTTL()),
+	}); err != nil {
+		controller.Log.Error("Failed to impersonate user "+uid+" by user "+payload.ID, err.Error(), reqMeta)
+		return err
+	}
+
+	logMsg := "Impersonating user " + uid + " by user " + payload.ID + ": OK"
+	if reason := body.GetReason(); reason != "" {
+		logMsg += " (reason: " + reason + ")"
+	}
+	controller.Log.Info(logMsg, reqMeta)
+
+	return ctx.JSON(
+		http.StatusOK,
+		ResponseBody.Token{
+			Message:     "Токен имперсонации успешно создан",
+			AccessToken: tk.String(),
+			ExpiresIn:   int(tk.TTL()) / 1000,
+		},
+	)
+}
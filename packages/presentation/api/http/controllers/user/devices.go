@@ -0,0 +1,209 @@
+package usercontroller
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	"sentinel/packages/common/validation"
+	"sentinel/packages/infrastructure/DB"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+	ResponseBody "sentinel/packages/presentation/data/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+func getDeviceIDParam(ctx echo.Context) (string, *Error.Status) {
+	deviceID := ctx.Param("deviceID")
+
+	if e := validation.UUID(deviceID); e != nil {
+		return "", e.ToStatus(
+			"Device ID is missing",
+			"Device ID has invalid format (expected UUID)",
+		)
+	}
+
+	return deviceID, nil
+}
+
+// @Summary 		Get user devices
+// @Description 	Get devices from which user has logged in, most recently seen devices go first.
+// @Description 	Device of the current request is marked as "current".
+// @ID 				get-user-devices
+// @Tags			user
+// @Param 			uid path string true "User ID"
+// @Accept			json
+// @Produce			json
+// @Success			200				{array}		responsebody.UserDevice
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/devices [get]
+// @Security		BearerAuth
+func GetUserDevices(ctx echo.Context) error {
+	uid := ctx.Param("uid")
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(uid)
+
+	devices, err := DB.Database.GetUserDevices(act)
+	if err != nil {
+		return err
+	}
+
+	currentDeviceID := SharedController.GetDeviceID(ctx, act.RequesterUID)
+
+	res := make([]ResponseBody.UserDevice, len(devices))
+
+	for i, device := range devices {
+		res[i] = ResponseBody.UserDevice{
+			Device:  device,
+			Current: device.ID == currentDeviceID,
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary 		Remove user device
+// @Description 	Removes device of the user and revokes all sessions which were created from it.
+// @Description 	Requires recent authentication.
+// @ID 				remove-user-device
+// @Tags			user
+// @Param 			uid 		path string true "User ID"
+// @Param 			deviceID 	path string true "Device ID"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/devices/{deviceID} [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func RemoveDevice(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	deviceID, err := getDeviceIDParam(ctx)
+	if err != nil {
+		controller.Log.Error("Failed to remove device", err.Error(), reqMeta)
+		return err
+	}
+
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+
+	controller.Log.Info("Removing device "+deviceID+"...", reqMeta)
+
+	if err := DB.Database.RemoveDevice(act, deviceID); err != nil {
+		controller.Log.Error("Failed to remove device "+deviceID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Removing device "+deviceID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Rename device
+// @Description 	Sets friendly name of the device of the current user
+// @ID 				rename-user-device
+// @Tags			user
+// @Param 			uid 		path string true "User ID"
+// @Param 			deviceID 	path string true "Device ID"
+// @Param 			body body requestbody.RenameDevice true "New device name"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500	{object} 	responsebody.Error
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/devices/{deviceID} [patch]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func RenameDevice(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	deviceID, err := getDeviceIDParam(ctx)
+	if err != nil {
+		controller.Log.Error("Failed to rename device", err.Error(), reqMeta)
+		return err
+	}
+
+	var body RequestBody.RenameDevice
+
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+
+	controller.Log.Info("Renaming device "+deviceID+"...", reqMeta)
+
+	if err := DB.Database.UpdateDeviceLabel(act, deviceID, &body.Name, nil); err != nil {
+		controller.Log.Error("Failed to rename device "+deviceID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Renaming device "+deviceID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// @Summary 		Mark device as trusted
+// @Description 	Marks (or unmarks) device of the current user as trusted.
+// @Description 	Step-up isn't required for suspicious logins from trusted devices (security alert is still sent).
+// @Description 	Requires recent authentication.
+// @ID 				trust-user-device
+// @Tags			user
+// @Param 			uid 		path string true "User ID"
+// @Param 			deviceID 	path string true "Device ID"
+// @Param 			body body requestbody.TrustDevice true "Trusted flag"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/devices/{deviceID}/trusted [put]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func TrustDevice(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	deviceID, err := getDeviceIDParam(ctx)
+	if err != nil {
+		controller.Log.Error("Failed to change device trust", err.Error(), reqMeta)
+		return err
+	}
+
+	var body RequestBody.TrustDevice
+
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+
+	controller.Log.Info("Changing trust of device "+deviceID+"...", reqMeta)
+
+	if err := DB.Database.UpdateDeviceLabel(act, deviceID, nil, body.Trusted); err != nil {
+		controller.Log.Error("Failed to change trust of device "+deviceID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Changing trust of device "+deviceID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
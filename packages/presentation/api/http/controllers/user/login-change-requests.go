@@ -0,0 +1,182 @@
+package usercontroller
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/email"
+	"sentinel/packages/infrastructure/token"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Sends confirmation link to the new login and cancellation link to the old one.
+// Unlike other alerts, failure is returned, since without these emails request can't be resolved.
+// Called before request is saved, so request isn't created if emails can't be enqueued.
+func sendLoginChangeRequestEmails(req *UserDTO.LoginChangeRequest) *Error.Status {
+	confirmationToken, err := token.NewLoginChangeToken(
+		req.UserID,
+		req.NewLogin,
+		req.ID,
+		token.LoginChangeConfirmationTokenType,
+		config.App.LoginChangeTokenTTL(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Cancellation link must work until the end of grace period of the latest possible confirmation
+	cancellationToken, err := token.NewLoginChangeToken(
+		req.UserID,
+		req.OldLogin,
+		req.ID,
+		token.LoginChangeCancellationTokenType,
+		config.App.LoginChangeTokenTTL()+config.App.LoginChangeGracePeriod(),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = email.EnqueueEmail(email.LoginChangeConfirmationEmail, req.NewLogin, email.Substitutions{
+		email.TokenPlaceholder: confirmationToken.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return email.EnqueueEmail(email.LoginChangeRequestedEmail, req.OldLogin, email.Substitutions{
+		email.TokenPlaceholder: cancellationToken.String(),
+		email.LoginPlaceholder: req.NewLogin,
+	})
+}
+
+// @Summary 		Confirm login change
+// @Description 	Confirm login change via token sent to the new login. Login of the user is changed right away,
+// @Description 	but change still can be reverted via cancellation link until the end of grace period (see "login-change-grace-period" in config).
+// @ID 				confirm-login-change
+// @Tags			user
+// @Param 			token 	body 	requestbody.LoginChangeToken 	true 	"Login change confirmation token"
+// @Accept			json
+// @Produce			json
+// @Success			200 				{object} 	userdto.LoginChangeRequest
+// @Failure			400,401,404,409,500	{object} 	responsebody.Error
+// @Router			/v1/user/login/confirm [post]
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func ConfirmLoginChange(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.LoginChangeToken
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	controller.Log.Info("Confirming login change...", reqMeta)
+
+	req, err := DB.Database.ConfirmLoginChange(body.Token)
+	if err != nil {
+		controller.Log.Error("Failed to confirm login change", err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Confirming login change: OK", reqMeta)
+
+	// Same as sendSecurityAlert, failure means only that email wasn't pushed in mailer queue
+	if err := email.EnqueueEmail(email.LoginChangeAlertEmail, req.OldLogin, nil); err != nil {
+		controller.Log.Error("Failed to enqueue login change alert email for "+req.OldLogin, err.Error(), reqMeta)
+	}
+
+	return ctx.JSON(http.StatusOK, req)
+}
+
+// @Summary 		Cancel login change
+// @Description 	Cancel login change via token sent to the old login. If change was already confirmed,
+// @Description 	but its grace period isn't over yet, then old login is restored and all sessions of the user are revoked.
+// @ID 				cancel-login-change
+// @Tags			user
+// @Param 			token 	body 	requestbody.LoginChangeToken 	true 	"Login change cancellation token"
+// @Accept			json
+// @Produce			json
+// @Success			200 				{object} 	userdto.LoginChangeRequest
+// @Failure			400,401,404,409,500	{object} 	responsebody.Error
+// @Router			/v1/user/login/cancel [post]
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func CancelLoginChange(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.LoginChangeToken
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	controller.Log.Info("Cancelling login change...", reqMeta)
+
+	req, err := DB.Database.CancelLoginChange(body.Token)
+	if err != nil {
+		controller.Log.Error("Failed to cancel login change", err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Cancelling login change: OK", reqMeta)
+
+	return ctx.JSON(http.StatusOK, req)
+}
+
+// @Summary 		Get user login change requests
+// @Description 	Get all login change requests of the user, including resolved ones. Newest requests go first.
+// @ID 				get-user-login-change-requests
+// @Tags			user
+// @Param 			uid 	path 	string 	true 	"User ID"
+// @Accept			json
+// @Produce			json
+// @Success			200				{array}		userdto.LoginChangeRequest
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/login/requests [get]
+// @Security		BearerAuth
+func GetUserLoginChangeRequests(ctx echo.Context) error {
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+
+	requests, err := DB.Database.GetUserLoginChangeRequests(act)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, requests)
+}
+
+// @Summary 		Get login change requests
+// @Description 	Get login change requests of all users. Newest requests go first.
+// @ID 				get-login-change-requests
+// @Tags			user
+// @Param 			status 	query 	string 	false 	"Filter by status" Enums(pending, confirmed, cancelled, reverted, expired)
+// @Accept			json
+// @Produce			json
+// @Success			200				{array}		userdto.LoginChangeRequest
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/login/requests [get]
+// @Security		BearerAuth
+func GetLoginChangeRequests(ctx echo.Context) error {
+	act := SharedController.GetBasicAction(ctx)
+
+	requests, err := DB.Database.GetLoginChangeRequests(act, ctx.QueryParam("status"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, requests)
+}
@@ -0,0 +1,262 @@
+package usercontroller
+
+import (
+	"encoding/csv"
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	SessionDTO "sentinel/packages/core/session/DTO"
+	"sentinel/packages/infrastructure/DB"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func parseTimeQueryParam(ctx echo.Context, name string) (time.Time, *Error.Status) {
+	raw := ctx.QueryParam(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, Error.NewStatusError(
+			"Query param '"+name+"' has invalid format (RFC 3339 expected)",
+			http.StatusBadRequest,
+		)
+	}
+
+	return t, nil
+}
+
+func getLoginHistoryFilter(ctx echo.Context) (*SessionDTO.LoginHistoryFilter, *Error.Status) {
+	from, err := parseTimeQueryParam(ctx, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseTimeQueryParam(ctx, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionDTO.LoginHistoryFilter{
+		From:       from,
+		To:         to,
+		Country:    ctx.QueryParam("country"),
+		DeviceType: ctx.QueryParam("deviceType"),
+	}, nil
+}
+
+// @Summary 		Get user login history
+// @Description 	Get logins of the user (including revoked sessions) with their last known location, most recent logins go first.
+// @ID 				get-user-login-history
+// @Tags			user
+// @Param 			uid 		path 	string 	true 	"User ID"
+// @Param 			page 		query 	int 	true 	"Page"
+// @Param 			pageSize 	query 	int 	true 	"Elements per page"
+// @Param 			from 		query 	string 	false 	"Logins made at or after this moment (RFC 3339)"
+// @Param 			to 			query 	string 	false 	"Logins made before this moment (RFC 3339)"
+// @Param 			country 	query 	string 	false 	"Country code (ISO 3166-1 alpha-2)"
+// @Param 			deviceType 	query 	string 	false 	"Device type (desktop, mobile, tablet)"
+// @Accept			json
+// @Produce			json
+// @Success			200				{array}		sessiondto.Login
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/logins [get]
+// @Security		BearerAuth
+func GetLoginHistory(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	page, err := strconv.Atoi(ctx.QueryParam("page"))
+	if err != nil {
+		errMsg := "Query param 'page' is missing or isn't an integer number"
+		controller.Log.Error("Failed to get user login history", errMsg, reqMeta)
+		return echo.NewHTTPError(http.StatusBadRequest, errMsg)
+	}
+	pageSize, err := strconv.Atoi(ctx.QueryParam("pageSize"))
+	if err != nil {
+		errMsg := "Query param 'pageSize' is missing or isn't an integer number"
+		controller.Log.Error("Failed to get user login history", errMsg, reqMeta)
+		return echo.NewHTTPError(http.StatusBadRequest, errMsg)
+	}
+
+	filter, e := getLoginHistoryFilter(ctx)
+	if e != nil {
+		controller.Log.Error("Failed to get user login history", e.Error(), reqMeta)
+		return e
+	}
+
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+
+	logins, e := DB.Database.GetLoginHistory(act, filter, page, pageSize)
+	if e != nil {
+		return e
+	}
+
+	return ctx.JSON(http.StatusOK, logins)
+}
+
+var loginHistoryCSVHeader = []string{
+	"session_id",
+	"login_ip_address",
+	"ip_address",
+	"user_agent",
+	"device_type",
+	"os",
+	"browser",
+	"country",
+	"region",
+	"city",
+	"isp",
+	"logged_in_at",
+	"last_used_at",
+	"revoked_at",
+	"revoked_by",
+	"revoke_reason",
+}
+
+// Prevents CSV formula injection: spreadsheet applications evaluate cells starting with these characters
+// as formulas, while most of the values come from the user (e.g. user agent, revoke reason).
+// Such values are prefixed with ', so they are treated as text (OWASP recommendation).
+func escapeCSVValue(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+func loginCSVRecord(login *SessionDTO.Login) []string {
+	var revokedAt string
+	if login.RevokedAt != nil {
+		revokedAt = login.RevokedAt.UTC().Format(time.RFC3339)
+	}
+
+	record := []string{
+		login.SessionID,
+		login.LoginIpAddress,
+		login.IpAddress,
+		login.UserAgent,
+		login.DeviceType,
+		login.OS,
+		login.Browser,
+		login.Country,
+		login.Region,
+		login.City,
+		login.ISP,
+		login.LoggedInAt.UTC().Format(time.RFC3339),
+		login.LastUsedAt.UTC().Format(time.RFC3339),
+		revokedAt,
+		login.RevokedBy,
+		login.RevokeReason,
+	}
+
+	for i, value := range record {
+		record[i] = escapeCSVValue(value)
+	}
+
+	return record
+}
+
+func writeLoginHistoryCSV(ctx echo.Context, logins []*SessionDTO.Login) error {
+	res := ctx.Response()
+
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(res)
+
+	if err := w.Write(loginHistoryCSVHeader); err != nil {
+		return err
+	}
+	for _, login := range logins {
+		if err := w.Write(loginCSVRecord(login)); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+// @Summary 		Export user login history
+// @Description 	Export logins of the user matching the filter as CSV or JSON file, most recent logins go first.
+// @Description 	Up to 10000 logins are exported. Requires recent authentication.
+// @ID 				export-user-login-history
+// @Tags			user
+// @Param 			uid 		path 	string 	true 	"User ID"
+// @Param 			format 		query 	string 	false 	"Export format: csv or json (default)"
+// @Param 			from 		query 	string 	false 	"Logins made at or after this moment (RFC 3339)"
+// @Param 			to 			query 	string 	false 	"Logins made before this moment (RFC 3339)"
+// @Param 			country 	query 	string 	false 	"Country code (ISO 3166-1 alpha-2)"
+// @Param 			deviceType 	query 	string 	false 	"Device type (desktop, mobile, tablet)"
+// @Accept			json
+// @Produce			json,text/csv
+// @Success			200				{array}		sessiondto.Login
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Header 			401 			{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/logins/export [get]
+// @Security		BearerAuth
+func ExportLoginHistory(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	format := ctx.QueryParam("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		errMsg := "Invalid export format: " + format + ". Supported formats: csv, json"
+		controller.Log.Error("Failed to export user login history", errMsg, reqMeta)
+		return echo.NewHTTPError(http.StatusBadRequest, errMsg)
+	}
+
+	filter, err := getLoginHistoryFilter(ctx)
+	if err != nil {
+		controller.Log.Error("Failed to export user login history", err.Error(), reqMeta)
+		return err
+	}
+
+	uid := ctx.Param("uid")
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(uid)
+
+	controller.Log.Info("Exporting login history of user "+uid+"...", reqMeta)
+
+	logins, err := DB.Database.ExportLoginHistory(act, filter)
+	if err != nil {
+		controller.Log.Error("Failed to export login history of user "+uid, err.Error(), reqMeta)
+		return err
+	}
+
+	ctx.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		`attachment; filename="logins-`+uid+"."+format+`"`,
+	)
+
+	if format == "csv" {
+		if e := writeLoginHistoryCSV(ctx, logins); e != nil {
+			controller.Log.Error("Failed to export login history of user "+uid, e.Error(), reqMeta)
+			return e
+		}
+	} else if e := ctx.JSON(http.StatusOK, logins); e != nil {
+		return e
+	}
+
+	controller.Log.Info("Exporting login history of user "+uid+": OK", reqMeta)
+
+	return nil
+}
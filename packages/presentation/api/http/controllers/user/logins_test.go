@@ -0,0 +1,54 @@
+package usercontroller
+
+import (
+	"testing"
+	"time"
+
+	SessionDTO "sentinel/packages/core/session/DTO"
+)
+
+func TestEscapeCSVValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"Mozilla/5.0", "Mozilla/5.0"},
+		{"=HYPERLINK(\"http://evil.com\")", "'=HYPERLINK(\"http://evil.com\")"},
+		{"+1+1", "'+1+1"},
+		{"-1+1", "'-1+1"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\t=1+1", "'\t=1+1"},
+		{"\r=1+1", "'\r=1+1"},
+		{"a=1+1", "a=1+1"},
+	}
+
+	for _, tt := range tests {
+		if escaped := escapeCSVValue(tt.value); escaped != tt.expected {
+			t.Errorf("escapeCSVValue(%q) = %q, want %q", tt.value, escaped, tt.expected)
+		}
+	}
+}
+
+func TestLoginCSVRecord(t *testing.T) {
+	record := loginCSVRecord(&SessionDTO.Login{
+		SessionID:    "35b92582-7694-4958-9751-1fef710cb94d",
+		UserAgent:    "=cmd|' /C calc'!A0",
+		RevokeReason: "@evil",
+		LoggedInAt:   time.Now(),
+		LastUsedAt:   time.Now(),
+	})
+
+	if len(record) != len(loginHistoryCSVHeader) {
+		t.Fatalf("len(record) = %d, want %d", len(record), len(loginHistoryCSVHeader))
+	}
+	if record[0] != "35b92582-7694-4958-9751-1fef710cb94d" {
+		t.Errorf("session_id = %q, want it unchanged", record[0])
+	}
+	if record[3] != "'=cmd|' /C calc'!A0" {
+		t.Errorf("user_agent = %q, want it escaped", record[3])
+	}
+	if record[15] != "'@evil" {
+		t.Errorf("revoke_reason = %q, want it escaped", record[15])
+	}
+}
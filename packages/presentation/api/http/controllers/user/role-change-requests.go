@@ -0,0 +1,173 @@
+package usercontroller
+
+import (
+	"net/http"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB"
+	"sentinel/packages/infrastructure/auth/authz"
+	"sentinel/packages/infrastructure/email"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Same as sendSecurityAlert, failure means only that email wasn't pushed in mailer queue,
+// so it's just logged and doesn't affect the response.
+func sendRoleChangeAlert(emailType email.EmailType, req *UserDTO.RoleChangeRequest, logins ...string) {
+	for _, login := range logins {
+		err := email.EnqueueEmail(emailType, login, email.Substitutions{
+			email.RolesPlaceholder: strings.Join(req.Roles, ", "),
+		})
+		if err != nil {
+			controller.Log.Error("Failed to enqueue role change alert email for "+login, err.Error(), nil)
+		}
+	}
+}
+
+// Notifies users who can approve the request, except its author and
+// the user whose roles it changes, since they can't approve it.
+func notifyRoleChangeApprovers(req *UserDTO.RoleChangeRequest) {
+	logins, err := DB.Database.GetLoginsByRoles(authz.RoleChangeApprovers())
+	if err != nil {
+		controller.Log.Error("Failed to get approvers of role change request "+req.ID, err.Error(), nil)
+		return
+	}
+
+	for _, uid := range []string{req.UserID, req.RequestedByUserID} {
+		if user, err := DB.Database.GetUserByID(uid); err == nil {
+			logins = slices.DeleteFunc(logins, func(login string) bool { return login == user.Login })
+		}
+	}
+
+	if len(logins) == 0 {
+		controller.Log.Warning("Role change request "+req.ID+" has no one to approve it", nil)
+		return
+	}
+
+	sendRoleChangeAlert(email.RoleChangeApprovalRequiredEmail, req, logins...)
+}
+
+// @Summary 		Get role change requests
+// @Description 	Get role change requests which were created due to addition of the privileged roles. Newest requests go first.
+// @ID 				get-role-change-requests
+// @Tags			user
+// @Param 			status 	query 	string 	false 	"Filter by status" Enums(pending, approved, cancelled, expired)
+// @Accept			json
+// @Produce			json
+// @Success			200				{array}		userdto.RoleChangeRequest
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/roles/requests [get]
+// @Security		BearerAuth
+func GetRoleChangeRequests(ctx echo.Context) error {
+	act := SharedController.GetBasicAction(ctx)
+
+	requests, err := DB.Database.GetRoleChangeRequests(act, ctx.QueryParam("status"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, requests)
+}
+
+// @Summary 		Approve role change request
+// @Description 	Approve pending role change request and apply its roles to the user.
+// @Description 	Request can't be approved by its author or by the user whose roles it changes.
+// @Description 	If reason isn't specified, then reason of the request will be used.
+// @ID 				approve-role-change-request
+// @Tags			user
+// @Param 			requestID 	path 	string 						true 	"Role change request ID"
+// @Param 			body 		body 	requestbody.ActionReason 	false 	"Reason of approval"
+// @Accept			json
+// @Produce			json
+// @Success			200 					{object} 	userdto.RoleChangeRequest
+// @Failure			400,401,403,404,409,500	{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/roles/requests/{requestID}/approve [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func ApproveRoleChangeRequest(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	act, err := getReasonedAction(ctx, new(RequestBody.ActionReason))
+	if err != nil {
+		return err
+	}
+
+	requestID := ctx.Param("requestID")
+
+	controller.Log.Info("Approving role change request "+requestID+"...", reqMeta)
+
+	req, e := DB.Database.ApproveRoleChangeRequest(act, requestID)
+	if e != nil {
+		controller.Log.Error("Failed to approve role change request "+requestID, e.Error(), reqMeta)
+		return e
+	}
+
+	controller.Log.Info("Approving role change request "+requestID+": OK", reqMeta)
+
+	logins := make([]string, 0, 2)
+	for _, uid := range []string{req.UserID, req.RequestedByUserID} {
+		if user, err := DB.Database.GetUserByID(uid); err == nil {
+			logins = append(logins, user.Login)
+		}
+	}
+
+	sendRoleChangeAlert(email.RoleChangeApprovedEmail, req, logins...)
+
+	return ctx.JSON(http.StatusOK, req)
+}
+
+// @Summary 		Cancel role change request
+// @Description 	Cancel pending role change request, roles of the user won't be changed.
+// @ID 				cancel-role-change-request
+// @Tags			user
+// @Param 			requestID 	path 	string 						true 	"Role change request ID"
+// @Param 			body 		body 	requestbody.ActionReason 	false 	"Reason of cancellation"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,409,500	{object} 	responsebody.Error
+// @Header 			401 					{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 					{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 					{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 					{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 					{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/roles/requests/{requestID} [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func CancelRoleChangeRequest(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	act, err := getReasonedAction(ctx, new(RequestBody.ActionReason))
+	if err != nil {
+		return err
+	}
+
+	requestID := ctx.Param("requestID")
+
+	controller.Log.Info("Cancelling role change request "+requestID+"...", reqMeta)
+
+	if e := DB.Database.CancelRoleChangeRequest(act, requestID); e != nil {
+		controller.Log.Error("Failed to cancel role change request "+requestID, e.Error(), reqMeta)
+		return e
+	}
+
+	controller.Log.Info("Cancelling role change request "+requestID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
@@ -0,0 +1,133 @@
+package usercontroller
+
+import (
+	"net/http"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB"
+	controller "sentinel/packages/presentation/api/http/controllers"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	RequestBody "sentinel/packages/presentation/data/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary 		Grant role to user
+// @Description 	Assign role to user for a limited period of time and/or starting from the specified moment.
+// @Description 	Granted roles are added to the user's tokens while grant is active. Requires same permissions as changing user roles.
+// @Description 	Privileged roles (see "privileged-roles" in config) can't be granted, since they require approval.
+// @ID 				grant-user-role
+// @Tags			user
+// @Param 			uid 	path 	string 					true 	"User ID"
+// @Param 			body 	body 	requestbody.GrantRole 	true 	"Role grant"
+// @Accept			json
+// @Produce			json
+// @Success			201 				{object} 	userdto.RoleGrant
+// @Failure			400,401,403,404,409,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/roles/grants [post]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func GrantRole(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	var body RequestBody.GrantRole
+	if err := controller.BindAndValidate(ctx, &body); err != nil {
+		return err
+	}
+
+	basicAct := SharedController.GetBasicAction(ctx)
+	basicAct.Reason = body.GetReason()
+
+	act := basicAct.ToUserTargeted(ctx.Param("uid"))
+
+	controller.Log.Info("Granting role "+body.Role+" to user "+act.TargetUID+"...", reqMeta)
+
+	grant := &UserDTO.RoleGrant{
+		Role:      body.Role,
+		NotBefore: body.NotBefore,
+		ExpiresAt: body.ExpiresAt,
+	}
+
+	if _, err := DB.Database.GrantRole(act, grant); err != nil {
+		controller.Log.Error("Failed to grant role "+body.Role+" to user "+act.TargetUID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Granting role "+body.Role+" to user "+act.TargetUID+": OK", reqMeta)
+
+	return ctx.JSON(http.StatusCreated, grant)
+}
+
+// @Summary 		Get user role grants
+// @Description 	Get all role grants of the user, including not yet active ones. Expired grants are removed automatically.
+// @ID 				get-user-role-grants
+// @Tags			user
+// @Param 			uid	path string true "User ID"
+// @Accept			json
+// @Produce			json
+// @Success			200				{array}		userdto.RoleGrant
+// @Failure			400,401,403,500	{object} 	responsebody.Error
+// @Failure			490 			{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 			{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 			{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 			{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/roles/grants [get]
+// @Security		BearerAuth
+func GetRoleGrants(ctx echo.Context) error {
+	act := SharedController.GetBasicAction(ctx).ToUserTargeted(ctx.Param("uid"))
+
+	grants, err := DB.Database.GetRoleGrants(act)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, grants)
+}
+
+// @Summary 		Revoke user role grant
+// @Description 	Delete role grant before its expiration. Requires same permissions as changing user roles.
+// @ID 				revoke-user-role-grant
+// @Tags			user
+// @Param 			uid 	path 	string 					true 	"User ID"
+// @Param 			grantID path 	string 					true 	"Grant ID"
+// @Accept			json
+// @Produce			json
+// @Success			200
+// @Failure			400,401,403,404,500	{object} 	responsebody.Error
+// @Header 			401 				{string} 	X-Step-Up-Required 			"Set to 'true' when re-authentication is required (see /v1/auth/reauth)"
+// @Failure			490 				{object} 	responsebody.Error 			"User data desynchronization"
+// @Header 			490 				{string} 	X-Token-Refresh-Required 	"Set to 'true' when token refresh is required"
+// @Failure			491 				{object} 	responsebody.Error 			"Session revoked"
+// @Header 			491 				{string} 	X-Session-Revoked 			"Set to 'true' if current user session was revoked"
+// @Router			/v1/user/{uid}/roles/grants/{grantID} [delete]
+// @Security		BearerAuth
+// @Security		CSRF_Header
+// @Security		CSRF_Cookie
+func RevokeRoleGrant(ctx echo.Context) error {
+	reqMeta := request.GetMetadata(ctx)
+
+	basicAct, err := getReasonedAction(ctx, new(RequestBody.ActionReason))
+	if err != nil {
+		return err
+	}
+
+	act := basicAct.ToUserTargeted(ctx.Param("uid"))
+	grantID := ctx.Param("grantID")
+
+	controller.Log.Info("Revoking role grant "+grantID+" of user "+act.TargetUID+"...", reqMeta)
+
+	if err := DB.Database.RevokeRoleGrant(act, grantID); err != nil {
+		controller.Log.Error("Failed to revoke role grant "+grantID+" of user "+act.TargetUID, err.Error(), reqMeta)
+		return err
+	}
+
+	controller.Log.Info("Revoking role grant "+grantID+" of user "+act.TargetUID+": OK", reqMeta)
+
+	return ctx.NoContent(http.StatusOK)
+}
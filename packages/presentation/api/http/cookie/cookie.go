@@ -14,6 +14,9 @@ import (
 
 const RefreshTokenCookieKey string = "refreshToken"
 
+// Device cookie outlives sessions, so it isn't deleted on logout
+const DeviceTokenCookieKey string = "deviceToken"
+
 func DeleteCookie(ctx echo.Context, cookie *http.Cookie) {
 	reqMeta := request.GetMetadata(ctx)
 
@@ -59,3 +62,14 @@ func NewAuthCookie(refreshToken *token.SignedToken) *http.Cookie {
 		Secure:   config.HTTP.Secured,
 	}
 }
+
+func NewDeviceCookie(deviceToken *token.SignedToken) *http.Cookie {
+	return &http.Cookie{
+		Name:     DeviceTokenCookieKey,
+		Value:    deviceToken.String(),
+		Path:     "/",
+		MaxAge:   int(deviceToken.TTL()) / 1000,
+		HttpOnly: true,
+		Secure:   config.HTTP.Secured,
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+var ForbiddenWhileImpersonating = Error.NewStatusError(
+	"This operation can't be performed while impersonating another user",
+	http.StatusForbidden,
+)
+
+// Denies access if request was made with impersonation token.
+// Must be applied to all destructive endpoints.
+//
+// IMPORTANT: Works only if route\group was secured via 'secure' middleware.
+func ForbidImpersonation(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		payload := SharedController.GetUserPayload(ctx)
+
+		if payload.IsImpersonated() {
+			log.Error(
+				"User "+payload.ImpersonatorID+" tried to perform destructive operation while impersonating user "+payload.ID,
+				ForbiddenWhileImpersonating.Error(),
+				request.GetMetadata(ctx),
+			)
+			return ForbiddenWhileImpersonating
+		}
+
+		return next(ctx)
+	}
+}
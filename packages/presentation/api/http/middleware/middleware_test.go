@@ -3,8 +3,13 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"sentinel/packages/common/config"
+	"sentinel/packages/common/config/configtest"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/presentation/api/http/request"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -180,6 +185,86 @@ func TestSensitivityMiddleware(t *testing.T) {
 	})
 }
 
+func TestRequireRecentAuthMiddleware(t *testing.T) {
+	configtest.Init()
+	config.Auth.RawDefaultEndpointMaxAuthAge = "0s"
+	config.Auth.RawSensitiveEndpointMaxAuthAge = "10m"
+
+	run := func(sensivity EndpointSensivity, authTime time.Time) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		ctx.Set("Secured", true)
+		ctx.Set("user_payload", &UserDTO.Payload{AuthTime: authTime.Unix()})
+
+		handler := request.Middleware(Sensivity(sensivity)(RequireRecentAuth(func(ctx echo.Context) error {
+			return ctx.String(http.StatusOK, "OK")
+		})))
+
+		return rec, handler(ctx)
+	}
+
+	t.Run("allows recently authenticated user", func(t *testing.T) {
+		rec, err := run(SensitiveEndpoint, time.Now().Add(-time.Minute))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("requires step-up if authentication is too old", func(t *testing.T) {
+		rec, err := run(SensitiveEndpoint, time.Now().Add(-time.Hour))
+
+		assert.Equal(t, StepUpRequired, err)
+		assert.Equal(t, "true", rec.Header().Get("X-Step-Up-Required"))
+		assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `error="insufficient_user_authentication"`)
+		assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `max_age="600"`)
+	})
+
+	t.Run("requires step-up if token has no auth time", func(t *testing.T) {
+		_, err := run(SensitiveEndpoint, time.Unix(0, 0))
+
+		assert.Equal(t, StepUpRequired, err)
+	})
+
+	t.Run("doesn't limit auth age if it's zero for endpoint sensivity", func(t *testing.T) {
+		rec, err := run(DefaultEndpoint, time.Now().Add(-time.Hour))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestForbidImpersonationMiddleware(t *testing.T) {
+	run := func(payload *UserDTO.Payload) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		ctx.Set("Secured", true)
+		ctx.Set("user_payload", payload)
+
+		handler := request.Middleware(ForbidImpersonation(func(ctx echo.Context) error {
+			return ctx.String(http.StatusOK, "OK")
+		}))
+
+		return rec, handler(ctx)
+	}
+
+	t.Run("allows regular tokens", func(t *testing.T) {
+		rec, err := run(&UserDTO.Payload{ID: "user"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("forbids impersonation tokens", func(t *testing.T) {
+		_, err := run(&UserDTO.Payload{ID: "user", ImpersonatorID: "admin"})
+
+		assert.Equal(t, ForbiddenWhileImpersonating, err)
+	})
+}
+
 func TestCSRFSecureCompare(t *testing.T) {
 	t.Run("secure compare matches equal strings", func(t *testing.T) {
 		result := secureCompare("token123", "token123")
@@ -286,3 +371,95 @@ func TestCSRFDoubleSubmitMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
 }
+
+func TestResolveOrganizationScope(t *testing.T) {
+	run := func(allowed bool, orgParam string, payload *UserDTO.Payload) (string, error) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		if allowed {
+			ctx.Set(organizationScopeKey, true)
+		}
+		if orgParam != "" {
+			ctx.SetParamNames("orgID")
+			ctx.SetParamValues(orgParam)
+		}
+
+		orgID, err := resolveOrganizationScope(ctx, payload)
+		if err != nil {
+			return orgID, err
+		}
+		return orgID, nil
+	}
+
+	t.Run("global token on regular route isn't scoped", func(t *testing.T) {
+		orgID, err := run(false, "", &UserDTO.Payload{ID: "user"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, orgID)
+	})
+
+	t.Run("forbids organization-scoped token on regular route", func(t *testing.T) {
+		_, err := run(false, "", &UserDTO.Payload{ID: "user", OrganizationID: "org"})
+
+		assert.Equal(t, ForbiddenForOrganizationScope, err)
+	})
+
+	t.Run("allows organization-scoped token on its organization route", func(t *testing.T) {
+		orgID, err := run(true, "org", &UserDTO.Payload{ID: "user", OrganizationID: "org"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "org", orgID)
+	})
+
+	t.Run("forbids organization-scoped token on another organization route", func(t *testing.T) {
+		_, err := run(true, "another-org", &UserDTO.Payload{ID: "user", OrganizationID: "org"})
+
+		assert.Equal(t, ForbiddenOutsideOfOrganization, err)
+	})
+
+	t.Run("scopes global token to organization from route", func(t *testing.T) {
+		orgID, err := run(true, "org", &UserDTO.Payload{ID: "user"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "org", orgID)
+	})
+}
+
+func TestRateLimiterMixedIPTraffic(t *testing.T) {
+	limited := request.Middleware(Sensivity(DefaultEndpoint)(NewRateLimiter().Max3reqPerMinute()(func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	})))
+
+	send := func(ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderXRealIP, ip)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		assert.NoError(t, limited(ctx))
+
+		return rec.Code
+	}
+
+	tests := []struct {
+		name     string
+		ip       string
+		expected int
+	}{
+		{"first IPv4 request", "203.0.113.1", http.StatusOK},
+		{"first IPv6 request", "2001:db8:1:2::1", http.StatusOK},
+		{"another IPv4 address isn't affected", "203.0.113.2", http.StatusOK},
+		{"another IPv6 /64 network isn't affected", "2001:db8:1:3::1", http.StatusOK},
+		{"same IPv4 address is limited", "203.0.113.1", http.StatusTooManyRequests},
+		{"IPv4-mapped IPv6 address is limited as IPv4", "::ffff:203.0.113.2", http.StatusTooManyRequests},
+		{"another address in the same IPv6 /64 network is limited", "2001:db8:1:2:ffff::1", http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, send(tt.ip))
+		})
+	}
+}
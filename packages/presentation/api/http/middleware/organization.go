@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	Error "sentinel/packages/common/errors"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/DB"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+var ForbiddenForOrganizationScope = Error.NewStatusError(
+	"This operation can't be performed with organization-scoped token",
+	http.StatusForbidden,
+)
+
+var ForbiddenOutsideOfOrganization = Error.NewStatusError(
+	"Token is scoped to another organization",
+	http.StatusForbidden,
+)
+
+var userIsNotOrganizationMember = Error.NewStatusError(
+	"User is not a member of this organization",
+	http.StatusNotFound,
+)
+
+const organizationScopeKey = "organization_scope_allowed"
+
+// Allows access to the route with organization-scoped tokens.
+// By default all secured routes deny such tokens, so tenant admins can't use them
+// outside of the organization-scoped endpoints.
+//
+// IMPORTANT: Must be applied before 'secure' middleware.
+func AllowOrganizationScope(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		ctx.Set(organizationScopeKey, true)
+		return next(ctx)
+	}
+}
+
+// Returns ID of the organization to which action must be scoped.
+//
+// Organization-scoped tokens are allowed only if route was marked via AllowOrganizationScope,
+// and if route has 'orgID' param, then it must be the same organization to which token is scoped.
+// Global tokens used on such routes are scoped to organization from 'orgID' param.
+func resolveOrganizationScope(ctx echo.Context, payload *UserDTO.Payload) (string, *Error.Status) {
+	allowed, _ := ctx.Get(organizationScopeKey).(bool)
+	orgID := ctx.Param("orgID")
+
+	if !allowed {
+		if payload.IsOrganizationScoped() {
+			return "", ForbiddenForOrganizationScope
+		}
+		return "", nil
+	}
+
+	if orgID == "" {
+		return payload.OrganizationID, nil
+	}
+
+	if payload.IsOrganizationScoped() && payload.OrganizationID != orgID {
+		return "", ForbiddenOutsideOfOrganization
+	}
+
+	return orgID, nil
+}
+
+// Denies access if user from 'uid' param isn't a member of organization from 'orgID' param.
+// Used on organization-scoped routes which reuse global handlers, so tenant admin can't touch users outside of his organization.
+//
+// IMPORTANT: Works only if route\group was secured via 'secure' middleware.
+func RequireOrganizationMember(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		reqMeta := request.GetMetadata(ctx)
+
+		act := SharedController.GetBasicAction(ctx)
+		orgID, uid := ctx.Param("orgID"), ctx.Param("uid")
+
+		log.Trace("Checking if user "+uid+" is a member of organization "+orgID+"...", reqMeta)
+
+		if act.OrganizationID != orgID {
+			return ForbiddenOutsideOfOrganization
+		}
+
+		isMember, err := DB.Database.IsMember(orgID, uid)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			log.Error("User "+act.RequesterUID+" tried to access user outside of organization "+orgID, userIsNotOrganizationMember.Error(), reqMeta)
+			return userIsNotOrganizationMember
+		}
+
+		log.Trace("Checking if user "+uid+" is a member of organization "+orgID+": OK", reqMeta)
+
+		return next(ctx)
+	}
+}
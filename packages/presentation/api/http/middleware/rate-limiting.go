@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"sentinel/packages/common/util"
 	"sentinel/packages/presentation/api/http/request"
 	ResponseBody "sentinel/packages/presentation/data/response"
 	"strconv"
@@ -16,8 +17,10 @@ type rateLimiter struct {
 	//
 }
 
+// IPv6 clients are limited per /64 network, otherwise they could bypass limits
+// just by rotating addresses inside of the network they own.
 func rateLimiterIdentifierExtractor(ctx echo.Context) (string, error) {
-	return ctx.RealIP(), nil
+	return util.IPNetwork(ctx.RealIP()), nil
 }
 
 func rateLimiterDenyHandler(window time.Duration) func(ctx echo.Context, id string, err error) error {
@@ -124,6 +127,18 @@ func (l *rateLimiter) Max1reqPerSecond() echo.MiddlewareFunc {
 	})
 }
 
+func (l *rateLimiter) Max100reqPerSecond() echo.MiddlewareFunc {
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      100,
+			Burst:     50,
+			ExpiresIn: time.Minute,
+		}),
+		DenyHandler:         rateLimiterDenyHandler(100),
+		IdentifierExtractor: rateLimiterIdentifierExtractor,
+	})
+}
+
 func (l *rateLimiter) Max10reqPerSecond() echo.MiddlewareFunc {
 	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
 		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
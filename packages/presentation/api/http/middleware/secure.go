@@ -8,6 +8,7 @@ import (
 	"sentinel/packages/infrastructure/DB"
 	UserMapper "sentinel/packages/infrastructure/mappers/user"
 	"sentinel/packages/infrastructure/token"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
 	"sentinel/packages/presentation/api/http/request"
 	"strings"
 
@@ -50,11 +51,29 @@ func Secure(next echo.HandlerFunc) echo.HandlerFunc {
 		payload := UserMapper.PayloadFromClaims(accessToken.Claims.(*token.Claims))
 
 		act := ActionDTO.NewUserTargeted(payload.ID, payload.ID, payload.Roles)
+		act.ImpersonatorUID = payload.ImpersonatorID
+		act.RequesterIP = ctx.RealIP()
+
+		orgID, e := resolveOrganizationScope(ctx, payload)
+		if e != nil {
+			return e
+		}
+		act.OrganizationID = orgID
 
 		if _, err := DB.Database.GetRevokedSessionByID(act, payload.SessionID); err == nil {
 			return Error.StatusSessionRevoked
 		}
 
+		session, e := DB.Database.GetActiveSessionByID(payload.SessionID)
+		if e != nil && e != Error.StatusNotFound {
+			return e
+		}
+		if e == nil {
+			if err := SharedController.EnforceSessionTimeouts(session, payload.Roles); err != nil {
+				return err
+			}
+		}
+
 		ctx.Set("access_token", accessToken)
 		ctx.Set("user_payload", payload)
 		ctx.Set("basic_action", &act.Basic)
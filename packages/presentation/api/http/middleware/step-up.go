@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"sentinel/packages/common/config"
+	Error "sentinel/packages/common/errors"
+	SharedController "sentinel/packages/presentation/api/http/controllers/shared"
+	"sentinel/packages/presentation/api/http/request"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var StepUpRequired = Error.NewStatusError(
+	"Re-authentication required: too much time has passed since last authentication",
+	http.StatusUnauthorized,
+)
+
+// Returns max time since last user authentication for endpoint with given sensivity.
+// Zero value means that there are no limit.
+func maxAuthAge(s EndpointSensivity) time.Duration {
+	switch s {
+	case DefaultEndpoint:
+		return config.Auth.DefaultEndpointMaxAuthAge()
+	case SensitiveEndpoint:
+		return config.Auth.SensitiveEndpointMaxAuthAge()
+	default:
+		return 0
+	}
+}
+
+// Sets headers which tells client that re-authentication is required.
+// WWW-Authenticate header matches RFC 9470 (https://datatracker.ietf.org/doc/html/rfc9470).
+func setStepUpRequiredHeaders(ctx echo.Context, maxAge time.Duration) {
+	ctx.Response().Header().Set("X-Step-Up-Required", "true")
+	ctx.Response().Header().Set(
+		"WWW-Authenticate",
+		`Bearer realm="api", error="insufficient_user_authentication", error_description="`+
+			StepUpRequired.Error()+`", max_age="`+strconv.FormatInt(int64(maxAge.Seconds()), 10)+`"`,
+	)
+}
+
+// Allows access only if user was authenticated not earlier than max auth age
+// of the endpoint sensivity (see config). Otherwise user must re-authenticate
+// via /auth/reauth (by password) or via /auth/reauth/code/verify (by one-time code).
+//
+// Must be applied to all secured endpoints which change state (except re-authentication ones),
+// whether endpoint requires recent authentication is determined only by its sensivity.
+//
+// IMPORTANT: Works only if route\group was secured via 'secure' middleware
+// and sensivity was set via 'Sensivity' middleware.
+func RequireRecentAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		reqMeta := request.GetMetadata(ctx)
+
+		maxAge := maxAuthAge(GetSensivity(ctx))
+		if maxAge == 0 {
+			return next(ctx)
+		}
+
+		log.Trace("Checking user authentication age...", reqMeta)
+
+		payload := SharedController.GetUserPayload(ctx)
+
+		// Tokens issued before "auth_time" claim was introduced doesn't have it,
+		// in that case AuthTime is 0, so re-authentication will be required anyway.
+		if time.Since(time.Unix(payload.AuthTime, 0)) > maxAge {
+			setStepUpRequiredHeaders(ctx, maxAge)
+			return StepUpRequired
+		}
+
+		log.Trace("Checking user authentication age: OK", reqMeta)
+
+		return next(ctx)
+	}
+}
@@ -0,0 +1,376 @@
+// Tests of the client package (pkg/client) against in-process Sentinel router.
+// Client is a separate module which can't depend on Sentinel, so these tests live here.
+package router_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sentinel/packages/common/config"
+	"sentinel/packages/common/config/configtest"
+	UserDTO "sentinel/packages/core/user/DTO"
+	"sentinel/packages/infrastructure/token"
+	"sentinel/packages/presentation/api/http/router"
+	"sentinel/pkg/client"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testServiceID = "cb663674-803e-4b06-bfeb-87c5cc86383e"
+	testAudience  = "urn:api:test"
+	selfAudience  = "urn:api:auth"
+)
+
+func newKey() ed25519.PrivateKey {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		panic(err)
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+func initConfig() {
+	configtest.Init()
+
+	config.HTTP.Domain = "localhost"
+	config.HTTP.Secured = true
+	config.HTTP.AllowedOrigins = []string{"https://localhost"}
+
+	config.Auth.RawAccessTokenTTL = "10m"
+	config.Auth.RawRefreshTokenTTL = "1h"
+	config.Auth.TokenAudience = []string{selfAudience, testAudience}
+	config.Auth.SelfAudience = selfAudience
+
+	config.App.ServiceID = testServiceID
+
+	config.Secret.AccessTokenPrivateKey = newKey()
+	config.Secret.AccessTokenPublicKey = config.Secret.AccessTokenPrivateKey.Public().(ed25519.PublicKey)
+	config.Secret.RefreshTokenPrivateKey = newKey()
+	config.Secret.RefreshTokenPublicKey = config.Secret.RefreshTokenPrivateKey.Public().(ed25519.PublicKey)
+}
+
+// In-process Sentinel router
+var sentinel *httptest.Server
+
+// Counts all requests to JWKS endpoint
+var jwksRequests atomic.Int64
+
+func TestMain(m *testing.M) {
+	initConfig()
+	token.Init()
+
+	r := router.Create()
+
+	sentinel = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == client.JWKSPath {
+			n := jwksRequests.Add(1)
+			// Each request is made from a "different" IP, so tests won't be affected by rate limiting
+			req.Header.Set("X-Real-IP", fmt.Sprintf("10.0.%d.%d", n/256%256, n%256))
+		}
+		r.ServeHTTP(w, req)
+	}))
+
+	code := m.Run()
+
+	sentinel.Close()
+
+	os.Exit(code)
+}
+
+func newConfig() client.Config {
+	return client.Config{
+		BaseURL:                sentinel.URL,
+		Audience:               testAudience,
+		Issuer:                 testServiceID,
+		HTTPClient:             sentinel.Client(),
+		JWKSMinRefreshInterval: time.Hour,
+	}
+}
+
+func newPayload(audience ...string) *UserDTO.Payload {
+	return &UserDTO.Payload{
+		ID:        "d529a8d2-1eb4-4bce-82aa-e62095dbc653",
+		Login:     "user@mail.com",
+		Roles:     []string{"user"},
+		Version:   3,
+		SessionID: "35b92582-7694-4958-9751-1fef710cb94d",
+		Audience:  audience,
+	}
+}
+
+func newAccessToken(t *testing.T, audience ...string) string {
+	tk, err := token.NewAccessToken(newPayload(audience...))
+	require.Nil(t, err)
+	return tk.String()
+}
+
+func newVerifier(t *testing.T, cfg client.Config) *client.Verifier {
+	verifier, err := client.NewVerifier(cfg)
+	require.NoError(t, err)
+	return verifier
+}
+
+func signCustomToken(t *testing.T, key ed25519.PrivateKey, headers map[string]any, claims jwt.Claims) string {
+	tk := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	for k, v := range headers {
+		tk.Header[k] = v
+	}
+	str, err := tk.SignedString(key)
+	require.NoError(t, err)
+	return str
+}
+
+func validClaims() *token.Claims {
+	now := time.Now()
+	return &token.Claims{
+		Roles:   []string{"user"},
+		Login:   "user@mail.com",
+		Version: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "35b92582-7694-4958-9751-1fef710cb94d",
+			Issuer:    testServiceID,
+			Subject:   "d529a8d2-1eb4-4bce-82aa-e62095dbc653",
+			Audience:  jwt.ClaimStrings{testAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	verifier := newVerifier(t, newConfig())
+	ctx := context.Background()
+
+	t.Run("valid access token", func(t *testing.T) {
+		payload, err := verifier.Verify(ctx, newAccessToken(t, testAudience))
+		require.NoError(t, err)
+
+		expected := newPayload(testAudience)
+		assert.Equal(t, expected.ID, payload.ID)
+		assert.Equal(t, expected.Login, payload.Login)
+		assert.Equal(t, expected.Roles, payload.Roles)
+		assert.Equal(t, expected.Version, payload.Version)
+		assert.Equal(t, expected.SessionID, payload.SessionID)
+		assert.Equal(t, expected.Audience, payload.Audience)
+	})
+
+	t.Run("JWKS is cached", func(t *testing.T) {
+		before := jwksRequests.Load()
+
+		for range 5 {
+			_, err := verifier.Verify(ctx, newAccessToken(t, testAudience))
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, before, jwksRequests.Load())
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		_, err := verifier.Verify(ctx, "")
+		assert.Equal(t, client.ErrTokenMissing, err)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := verifier.Verify(ctx, "not.a.token")
+		assert.Equal(t, client.ErrTokenMalformed, err)
+	})
+
+	t.Run("audience mismatch", func(t *testing.T) {
+		_, err := verifier.Verify(ctx, newAccessToken(t, selfAudience))
+		assert.Equal(t, client.ErrTokenAudienceMismatch, err)
+	})
+
+	t.Run("refresh token is rejected", func(t *testing.T) {
+		tk, err := token.NewRefreshToken(newPayload(selfAudience))
+		require.Nil(t, err)
+
+		_, e := newVerifier(t, client.Config{
+			BaseURL:    sentinel.URL,
+			Audience:   selfAudience,
+			HTTPClient: sentinel.Client(),
+		}).Verify(ctx, tk.String())
+		assert.Equal(t, client.ErrTokenInvalidType, e)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		claims := validClaims()
+		claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+		claims.NotBefore = claims.IssuedAt
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+		tk := signCustomToken(t, config.Secret.AccessTokenPrivateKey, map[string]any{
+			"typ": "at+jwt",
+			"kid": token.AccessTokenKeyID,
+		}, claims)
+
+		_, err := verifier.Verify(ctx, tk)
+		assert.Equal(t, client.ErrTokenExpired, err)
+	})
+
+	t.Run("issuer mismatch", func(t *testing.T) {
+		claims := validClaims()
+		claims.Issuer = "unknown"
+
+		tk := signCustomToken(t, config.Secret.AccessTokenPrivateKey, map[string]any{
+			"typ": "at+jwt",
+			"kid": token.AccessTokenKeyID,
+		}, claims)
+
+		_, err := verifier.Verify(ctx, tk)
+		assert.Equal(t, client.ErrTokenIssuerMismatch, err)
+	})
+
+	t.Run("missing required claims", func(t *testing.T) {
+		claims := validClaims()
+		claims.ID = ""
+
+		tk := signCustomToken(t, config.Secret.AccessTokenPrivateKey, map[string]any{
+			"typ": "at+jwt",
+			"kid": token.AccessTokenKeyID,
+		}, claims)
+
+		_, err := verifier.Verify(ctx, tk)
+		assert.Equal(t, client.ErrTokenMissingRequiredClaims, err)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		tk := signCustomToken(t, newKey(), map[string]any{
+			"typ": "at+jwt",
+			"kid": token.AccessTokenKeyID,
+		}, validClaims())
+
+		_, err := verifier.Verify(ctx, tk)
+		assert.Equal(t, client.ErrTokenInvalidSignature, err)
+	})
+
+	t.Run("token without kid", func(t *testing.T) {
+		tk := signCustomToken(t, config.Secret.AccessTokenPrivateKey, map[string]any{
+			"typ": "at+jwt",
+		}, validClaims())
+
+		_, err := verifier.Verify(ctx, tk)
+		assert.NoError(t, err)
+	})
+}
+
+func TestJWKSKeyRefresh(t *testing.T) {
+	cfg := newConfig()
+	cfg.JWKSMinRefreshInterval = time.Nanosecond
+
+	verifier := newVerifier(t, cfg)
+	ctx := context.Background()
+
+	_, err := verifier.Verify(ctx, newAccessToken(t, testAudience))
+	require.NoError(t, err)
+
+	t.Run("unknown kid triggers refresh", func(t *testing.T) {
+		before := jwksRequests.Load()
+
+		tk := signCustomToken(t, newKey(), map[string]any{
+			"typ": "at+jwt",
+			"kid": "access-2",
+		}, validClaims())
+
+		_, err := verifier.Verify(ctx, tk)
+		assert.Equal(t, client.ErrTokenUnknownKey, err)
+		assert.Equal(t, before+1, jwksRequests.Load())
+	})
+
+	t.Run("refresh is throttled", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.JWKSMinRefreshInterval = time.Hour
+
+		verifier := newVerifier(t, cfg)
+
+		_, err := verifier.Verify(ctx, newAccessToken(t, testAudience))
+		require.NoError(t, err)
+
+		before := jwksRequests.Load()
+
+		for range 3 {
+			tk := signCustomToken(t, newKey(), map[string]any{
+				"typ": "at+jwt",
+				"kid": "access-2",
+			}, validClaims())
+
+			_, err := verifier.Verify(ctx, tk)
+			assert.Equal(t, client.ErrTokenUnknownKey, err)
+		}
+
+		assert.Equal(t, before, jwksRequests.Load())
+	})
+
+	t.Run("unavailable JWKS", func(t *testing.T) {
+		verifier := newVerifier(t, client.Config{
+			BaseURL:  "http://127.0.0.1:1",
+			Audience: testAudience,
+		})
+
+		_, err := verifier.Verify(ctx, newAccessToken(t, testAudience))
+		assert.True(t, errors.Is(err, client.ErrJWKSUnavailable))
+		assert.False(t, client.IsTokenError(err))
+	})
+}
+
+func TestMiddlewares(t *testing.T) {
+	verifier := newVerifier(t, newConfig())
+
+	t.Run("net/http", func(t *testing.T) {
+		handler := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload := client.PayloadFromContext(r.Context())
+			fmt.Fprint(w, payload.ID)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "invalid_request")
+
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+newAccessToken(t, testAudience))
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, newPayload().ID, rec.Body.String())
+	})
+
+	t.Run("echo", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/", func(ctx echo.Context) error {
+			return ctx.String(http.StatusOK, client.PayloadFromEcho(ctx).Login)
+		}, verifier.EchoMiddleware)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+newAccessToken(t, selfAudience))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "invalid_token")
+
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+newAccessToken(t, testAudience))
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, newPayload().Login, rec.Body.String())
+	})
+}
@@ -7,10 +7,14 @@ import (
 	"sentinel/packages/common/logger"
 	Activation "sentinel/packages/presentation/api/http/controllers/activation"
 	Auth "sentinel/packages/presentation/api/http/controllers/auth"
+	Authz "sentinel/packages/presentation/api/http/controllers/authz"
 	Cache "sentinel/packages/presentation/api/http/controllers/cache"
 	Docs "sentinel/packages/presentation/api/http/controllers/docs"
+	Group "sentinel/packages/presentation/api/http/controllers/group"
 	OAuth "sentinel/packages/presentation/api/http/controllers/oauth"
+	Organization "sentinel/packages/presentation/api/http/controllers/organization"
 	Roles "sentinel/packages/presentation/api/http/controllers/roles"
+	Scheduler "sentinel/packages/presentation/api/http/controllers/scheduler"
 	User "sentinel/packages/presentation/api/http/controllers/user"
 	"sentinel/packages/presentation/api/http/middleware"
 	"sentinel/packages/presentation/api/http/request"
@@ -98,7 +102,7 @@ func Create() *echo.Echo {
 	authGroup.GET(
 		rootPath, Auth.Verify, middleware.Sensivity(middleware.DefaultEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync,
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync,
 	)
 	authGroup.POST(
 		rootPath, Auth.Login, middleware.Sensivity(middleware.SensitiveEndpoint),
@@ -118,12 +122,52 @@ func Create() *echo.Echo {
 	authGroup.DELETE(
 		"/:sessionID", Auth.Logout, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	authGroup.PATCH(
+		"/:sessionID", Auth.RenameSession, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	authGroup.PUT(
+		"/:sessionID/trusted", Auth.TrustSession, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	authGroup.DELETE(
+		"/sessions", Auth.RevokeOtherSessions, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	authGroup.DELETE(
 		"/sessions/:uid", Auth.RevokeAllUserSessions, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	authGroup.POST(
+		"/reauth", Auth.Reauthenticate, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max5reqPerMinute(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	authGroup.POST(
+		"/reauth/code", Auth.SendReauthCode, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max3reqPerMinute(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	authGroup.POST(
+		"/reauth/code/verify", Auth.ReauthenticateWithCode, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max5reqPerMinute(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	authGroup.POST(
+		"/organization", Auth.SwitchOrganization, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	authGroup.GET(
+		"/revocations", Auth.StreamRevocations, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
 	)
 	authGroup.POST(
 		"/forgot-password", Auth.ForgotPassword, middleware.Sensivity(middleware.SensitiveEndpoint),
@@ -160,32 +204,32 @@ func Create() *echo.Echo {
 	userGroup.DELETE(
 		"/:uid", User.SoftDelete, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	userGroup.PUT(
 		"/:uid/restore", User.Restore, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	userGroup.DELETE(
 		rootPath, User.BulkSoftDelete, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	userGroup.PUT(
 		rootPath, User.BulkRestore, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	userGroup.DELETE(
 		"/:uid/drop", User.Drop, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	userGroup.DELETE(
 		"/all/drop", User.DropAllDeleted, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	userGroup.POST(
 		"/login/available", User.IsLoginAvailable, middleware.Sensivity(middleware.InsignificantEndpoint),
@@ -199,17 +243,77 @@ func Create() *echo.Echo {
 	userGroup.PATCH(
 		"/:uid/login", User.ChangeLogin, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	userGroup.GET(
+		"/:uid/login/requests", User.GetUserLoginChangeRequests, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+	)
+	userGroup.GET(
+		"/login/requests", User.GetLoginChangeRequests, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+	)
+	userGroup.POST(
+		"/login/confirm", User.ConfirmLoginChange, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max3reqPerMinute(),
+		middleware.DoubleSubmitCSRF,
+	)
+	userGroup.POST(
+		"/login/cancel", User.CancelLoginChange, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max3reqPerMinute(),
+		middleware.DoubleSubmitCSRF,
 	)
 	userGroup.PATCH(
 		"/:uid/password", User.ChangePassword, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	userGroup.PATCH(
 		"/:uid/roles", User.ChangeRoles, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.Secure, middleware.CheckUserSync, middleware.DoubleSubmitCSRF,
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	userGroup.GET(
+		"/roles/requests", User.GetRoleChangeRequests, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+	)
+	userGroup.POST(
+		"/roles/requests/:requestID/approve", User.ApproveRoleChangeRequest, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	userGroup.DELETE(
+		"/roles/requests/:requestID", User.CancelRoleChangeRequest, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	userGroup.GET(
+		"/:uid/roles/grants", User.GetRoleGrants, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+	)
+	userGroup.POST(
+		"/:uid/roles/grants", User.GrantRole, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	userGroup.DELETE(
+		"/:uid/roles/grants/:grantID", User.RevokeRoleGrant, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	userGroup.GET(
+		"/:uid/groups", Group.GetUserGroups, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+	)
+	userGroup.POST(
+		"/:uid/impersonate", User.Impersonate, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
 	)
 	userGroup.GET(
 		"/activation/:token", Activation.Activate, middleware.Sensivity(middleware.DefaultEndpoint),
@@ -229,6 +333,36 @@ func Create() *echo.Echo {
 		limit.Max1reqPerSecond(),
 		middleware.Secure, middleware.CheckUserSync,
 	)
+	userGroup.GET(
+		"/:uid/logins", User.GetLoginHistory, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+	)
+	userGroup.GET(
+		"/:uid/logins/export", User.ExportLoginHistory, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth,
+	)
+	userGroup.GET(
+		"/:uid/devices", User.GetUserDevices, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+	)
+	userGroup.DELETE(
+		"/:uid/devices/:deviceID", User.RemoveDevice, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	userGroup.PATCH(
+		"/:uid/devices/:deviceID", User.RenameDevice, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	userGroup.PUT(
+		"/:uid/devices/:deviceID/trusted", User.TrustDevice, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
 	userGroup.GET(
 		"/:uid", User.GetUser, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
@@ -242,12 +376,173 @@ func Create() *echo.Echo {
 		limit.Max1reqPerSecond(),
 	)
 
+	rbacRolesGroup := apiV1.Group("/rbac/roles", middleware.Secure, middleware.CheckUserSync)
+
+	rbacRolesGroup.GET(
+		rootPath, Roles.GetManaged, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+	)
+	rbacRolesGroup.GET(
+		"/:id", Roles.GetManagedRole, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+	)
+	rbacRolesGroup.POST(
+		rootPath, Roles.Create, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	rbacRolesGroup.PUT(
+		"/:id", Roles.Update, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	rbacRolesGroup.DELETE(
+		"/:id", Roles.Delete, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+
+	apiV1.GET(
+		"/rbac/agp", Roles.GetActionGatePolicy, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+	)
+	apiV1.POST(
+		"/rbac/reload", Roles.Reload, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync,
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+
+	authzGroup := apiV1.Group("/authz", middleware.Secure, middleware.CheckUserSync)
+
+	authzGroup.POST(
+		"/check", Authz.Check, middleware.Sensivity(middleware.InsignificantEndpoint),
+		limit.Max100reqPerSecond(),
+	)
+	authzGroup.POST(
+		"/check/batch", Authz.CheckBatch, middleware.Sensivity(middleware.InsignificantEndpoint),
+		limit.Max100reqPerSecond(),
+	)
+	authzGroup.POST(
+		"/explain", Authz.Explain, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max10reqPerSecond(),
+	)
+
+	groupsGroup := apiV1.Group("/groups", middleware.Secure, middleware.CheckUserSync, middleware.NoCache)
+
+	groupsGroup.GET(
+		rootPath, Group.GetAll, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+	)
+	groupsGroup.GET(
+		"/:groupID", Group.Get, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+	)
+	groupsGroup.POST(
+		rootPath, Group.Create, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	groupsGroup.PUT(
+		"/:groupID/roles", Group.ChangeRoles, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	groupsGroup.DELETE(
+		"/:groupID", Group.Delete, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	groupsGroup.GET(
+		"/:groupID/members", Group.GetMembers, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+	)
+	groupsGroup.PUT(
+		"/:groupID/members/:uid", Group.AddMember, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	groupsGroup.DELETE(
+		"/:groupID/members/:uid", Group.RemoveMember, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+
+	// Organization-scoped tokens are allowed only on routes marked via AllowOrganizationScope
+	organizationGroup := apiV1.Group("/organizations", middleware.NoCache)
+
+	organizationGroup.POST(
+		rootPath, Organization.Create, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	organizationGroup.GET(
+		"/:orgID", Organization.Get, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync,
+	)
+	organizationGroup.DELETE(
+		"/:orgID", Organization.Delete, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.Secure, middleware.CheckUserSync, middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	organizationGroup.GET(
+		"/:orgID/members", Organization.GetMembers, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync,
+	)
+	organizationGroup.GET(
+		"/:orgID/members/:uid", Organization.GetMember, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync,
+	)
+	organizationGroup.PUT(
+		"/:orgID/members/:uid", Organization.SetMember, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync,
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	organizationGroup.DELETE(
+		"/:orgID/members/:uid", Organization.RemoveMember, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync,
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	organizationGroup.GET(
+		"/:orgID/members/:uid/sessions", Organization.GetMemberSessions, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync, middleware.RequireOrganizationMember,
+	)
+	organizationGroup.DELETE(
+		"/:orgID/members/:uid/sessions", Organization.RevokeAllMemberSessions, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync, middleware.RequireOrganizationMember,
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+	organizationGroup.GET(
+		"/:orgID/users/search", Organization.SearchUsers, middleware.Sensivity(middleware.SensitiveEndpoint),
+		limit.Max1reqPerSecond(),
+		middleware.AllowOrganizationScope, middleware.Secure, middleware.CheckUserSync,
+	)
+
 	cacheGroup := apiV1.Group("/cache", middleware.Secure, middleware.CheckUserSync, middleware.NoCache)
 
 	cacheGroup.DELETE(
 		rootPath, Cache.Drop, middleware.Sensivity(middleware.SensitiveEndpoint),
 		limit.Max1reqPerSecond(),
-		middleware.DoubleSubmitCSRF,
+		middleware.RequireRecentAuth, middleware.ForbidImpersonation, middleware.DoubleSubmitCSRF,
+	)
+
+	schedulerGroup := apiV1.Group("/scheduler", middleware.Secure, middleware.CheckUserSync, middleware.NoCache)
+
+	schedulerGroup.GET(
+		"/jobs", Scheduler.GetJobs, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
+	)
+	schedulerGroup.GET(
+		"/jobs/:job/runs", Scheduler.GetJobRuns, middleware.Sensivity(middleware.DefaultEndpoint),
+		limit.Max1reqPerSecond(),
 	)
 
 	docsGroupMiddlewares := []echo.MiddlewareFunc{middleware.Sensivity(middleware.SensitiveEndpoint),
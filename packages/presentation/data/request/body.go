@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	Error "sentinel/packages/common/errors"
+	RoleDTO "sentinel/packages/core/role/DTO"
 	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 /*
@@ -138,6 +141,135 @@ func (b *ActionReason) GetReason() string {
 	return b.Reason
 }
 
+// swagger:model GrantRoleRequest
+type GrantRole struct {
+	ActionReason `json:",inline"`
+	Role         string `json:"role" example:"admin"`
+	// Optional, if not specified then grant will be active right away
+	NotBefore *time.Time `json:"not-before" example:"2025-07-20T23:54:14.503Z"`
+	// Optional, if not specified then grant will never expire
+	ExpiresAt *time.Time `json:"expires-at" example:"2025-07-21T03:54:14.503Z"`
+}
+
+func (b *GrantRole) Validate() *Error.Status {
+	if b.Role == "" {
+		return missingFieldValue("role")
+	}
+	if strings.ReplaceAll(b.Role, " ", "") == "" {
+		return invalidFieldValue("role")
+	}
+	return nil
+}
+
+// swagger:model ImpersonateRequest
+type Impersonate struct {
+	ActionReason `json:",inline"`
+	// Optional, by default token will be issued only for this service
+	Audience []string `json:"audience" example:"urn:api:auth"`
+}
+
+func (b *Impersonate) Validate() *Error.Status {
+	for _, aud := range b.Audience {
+		if strings.ReplaceAll(aud, " ", "") == "" {
+			return invalidFieldValue("audience")
+		}
+	}
+	return nil
+}
+
+// swagger:model UpdateRoleRequest
+type UpdateRole struct {
+	ActionReason `json:",inline"`
+	Permissions  RoleDTO.Permissions `json:"permissions"`
+	// Optional, overrides role permissions for the specified resources
+	ResourcePermissions map[string]RoleDTO.Permissions `json:"resource-permissions"`
+	// Is role must be assigned to all new users
+	IsDefault bool `json:"is-default"`
+}
+
+func (b *UpdateRole) Validate() *Error.Status {
+	for resource := range b.ResourcePermissions {
+		if strings.ReplaceAll(resource, " ", "") == "" {
+			return invalidFieldValue("resource-permissions")
+		}
+	}
+	return nil
+}
+
+// swagger:model CreateRoleRequest
+type CreateRole struct {
+	UpdateRole `json:",inline"`
+	// Optional, if empty then role will be global (shared by all schemas)
+	SchemaID string `json:"schema-id" example:"cb663674-803e-4b06-bfeb-87c5cc86383e"`
+	Name     string `json:"name" example:"editor"`
+}
+
+func (b *CreateRole) Validate() *Error.Status {
+	if b.Name == "" {
+		return missingFieldValue("name")
+	}
+	if strings.ReplaceAll(b.Name, " ", "") == "" {
+		return invalidFieldValue("name")
+	}
+	return b.UpdateRole.Validate()
+}
+
+// swagger:model AuthzCheckRequest
+type AuthzCheck struct {
+	// Roles of the subject. Ignored if token is specified.
+	Roles []string `json:"roles" example:"user"`
+	// Access token of the subject, its roles will be used.
+	Token     string `json:"token" example:"eyJhbGciOiJFZER..."`
+	ServiceID string `json:"service-id" example:"sentinel"`
+	Entity    string `json:"entity" example:"user"`
+	Action    string `json:"action" example:"get_user"`
+	Resource  string `json:"resource" example:"user"`
+}
+
+func (b *AuthzCheck) Validate() *Error.Status {
+	if b.Token == "" && len(b.Roles) == 0 {
+		return missingFieldValue("roles")
+	}
+	if b.ServiceID == "" {
+		return missingFieldValue("service-id")
+	}
+	if b.Entity == "" {
+		return missingFieldValue("entity")
+	}
+	if b.Action == "" {
+		return missingFieldValue("action")
+	}
+	if b.Resource == "" {
+		return missingFieldValue("resource")
+	}
+	return nil
+}
+
+const maxAuthzBatchSize = 100
+
+// swagger:model AuthzBatchCheckRequest
+type AuthzBatchCheck struct {
+	Checks []AuthzCheck `json:"checks"`
+}
+
+func (b *AuthzBatchCheck) Validate() *Error.Status {
+	if len(b.Checks) == 0 {
+		return missingFieldValue("checks")
+	}
+	if len(b.Checks) > maxAuthzBatchSize {
+		return Error.NewStatusError(
+			fmt.Sprintf("Invalid request body: field 'checks' can't contain more than %d items", maxAuthzBatchSize),
+			http.StatusBadRequest,
+		)
+	}
+	for i := range b.Checks {
+		if err := b.Checks[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // swagger:model UserLoginAndPasswordRequest
 type LoginAndPassword struct {
 	UserLogin    `json:",inline"`
@@ -283,7 +415,150 @@ func (b *PasswordReset) Validate() *Error.Status {
 	return nil
 }
 
+type LoginChangeToken struct {
+	Token string `json:"token" example:"eyJhbGciOiJFZER..."`
+}
+
+func (b *LoginChangeToken) Validate() *Error.Status {
+	if b.Token == "" {
+		return missingFieldValue("token")
+	}
+	return nil
+}
+
+type OneTimeCode struct {
+	Code string `json:"code" example:"042917"`
+}
+
+func (b *OneTimeCode) Validate() *Error.Status {
+	if b.Code == "" {
+		return missingFieldValue("code")
+	}
+	return nil
+}
+
 type RestoreUser struct {
 	UserLogin    `json:",inline"`
 	ActionReason `json:",inline"`
 }
+
+// swagger:model CreateOrganizationRequest
+type CreateOrganization struct {
+	ActionReason `json:",inline"`
+	Name         string `json:"name" example:"Acme Corp"`
+}
+
+func (b *CreateOrganization) Validate() *Error.Status {
+	if b.Name == "" {
+		return missingFieldValue("name")
+	}
+	if strings.ReplaceAll(b.Name, " ", "") == "" {
+		return invalidFieldValue("name")
+	}
+	return nil
+}
+
+// swagger:model SetOrganizationMemberRequest
+type SetOrganizationMember struct {
+	ActionReason `json:",inline"`
+	// Roles of the user in the organization
+	UserRoles `json:",inline"`
+}
+
+func (b *SetOrganizationMember) Validate() *Error.Status {
+	return b.UserRoles.Validate()
+}
+
+// swagger:model SwitchOrganizationRequest
+type SwitchOrganization struct {
+	// Optional, if empty then tokens won't be scoped to any organization
+	OrganizationID string `json:"organization-id" example:"5c0e1a7b-3f2d-4c8e-9b6a-1d2e3f4a5b6c"`
+}
+
+func (b *SwitchOrganization) Validate() *Error.Status {
+	return nil
+}
+
+// swagger:model CreateGroupRequest
+type CreateGroup struct {
+	ActionReason `json:",inline"`
+	Name         string `json:"name" example:"developers"`
+	// Roles which will be inherited by all members of the group
+	UserRoles `json:",inline"`
+}
+
+func (b *CreateGroup) Validate() *Error.Status {
+	if b.Name == "" {
+		return missingFieldValue("name")
+	}
+	if strings.ReplaceAll(b.Name, " ", "") == "" {
+		return invalidFieldValue("name")
+	}
+	return b.UserRoles.Validate()
+}
+
+// swagger:model ChangeGroupRolesRequest
+type ChangeGroupRoles struct {
+	ActionReason `json:",inline"`
+	UserRoles    `json:",inline"`
+}
+
+func (b *ChangeGroupRoles) Validate() *Error.Status {
+	return b.UserRoles.Validate()
+}
+
+// Max length of the session name (in characters)
+const MaxSessionNameLength = 64
+
+// swagger:model RenameSessionRequest
+type RenameSession struct {
+	// Friendly name of the device, e.g. "Work laptop". Empty string removes the name.
+	Name string `json:"name" example:"Work laptop"`
+}
+
+func (b *RenameSession) Validate() *Error.Status {
+	if utf8.RuneCountInString(b.Name) > MaxSessionNameLength {
+		return invalidFieldValue("name")
+	}
+	return nil
+}
+
+// swagger:model TrustSessionRequest
+type TrustSession struct {
+	Trusted *bool `json:"trusted" example:"true"`
+}
+
+func (b *TrustSession) Validate() *Error.Status {
+	if b.Trusted == nil {
+		return missingFieldValue("trusted")
+	}
+	return nil
+}
+
+// Max length of the device name (in characters)
+const MaxDeviceNameLength = 64
+
+// swagger:model RenameDeviceRequest
+type RenameDevice struct {
+	// Friendly name of the device, e.g. "Work laptop". Empty string removes the name.
+	Name string `json:"name" example:"Work laptop"`
+}
+
+func (b *RenameDevice) Validate() *Error.Status {
+	if utf8.RuneCountInString(b.Name) > MaxDeviceNameLength {
+		return invalidFieldValue("name")
+	}
+	return nil
+}
+
+// swagger:model TrustDeviceRequest
+type TrustDevice struct {
+	Trusted *bool `json:"trusted" example:"true"`
+}
+
+func (b *TrustDevice) Validate() *Error.Status {
+	if b.Trusted == nil {
+		return missingFieldValue("trusted")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package responsebody
 
 import (
+	"sentinel/packages/core/device/DTO"
 	"sentinel/packages/core/location/DTO"
 	"sentinel/packages/core/session/DTO"
 )
@@ -10,6 +11,8 @@ type Token struct {
 	Message     string `json:"message" example:"hello"`
 	AccessToken string `json:"accessToken" example:"eyJhbGciOi..."`
 	ExpiresIn   int    `json:"expiresIn" example:"600"`
+	// Sessions which were revoked on login due to session limit
+	EvictedSessions []*sessiondto.Public `json:"evictedSessions,omitempty"`
 }
 
 // swagger:model MessageResponse
@@ -31,22 +34,35 @@ type Error struct {
 type (
 	Session  = *sessiondto.Public
 	Location = *locationdto.Public
+	Device   = *devicedto.Full
 )
 
 type UserSession struct {
 	Session  `json:",inline"`
 	Location `json:"location"`
+	// True if this is the session of the current request
+	Current bool `json:"current" example:"true"`
+}
+
+type UserDevice struct {
+	Device `json:",inline"`
+	// True if this is the device of the current request
+	Current bool `json:"current" example:"true"`
+}
+
+type RevokedSessions struct {
+	Sessions []*sessiondto.Public `json:"sessions"`
 }
 
 type Introspection struct {
 	Active    bool     `json:"active" example:"true"`
-	SessionID string   `json:"jti" example:"ade1cdb0-309c-48c5-8251-c3f39ec0d606"`
-	Subject   string   `json:"sub" example:"c9fcc8e3-f4f1-4b85-a65e-29bb889cbccb"`
-	Issuer    string   `json:"iss" example:"3c23ebbd-42af-47c6-9c50-7295b3ac3a62"`
-	Audience  []string `json:"aud" example:"urn:api:auth,urn:api:billing"`
-	ExpiresAt int64    `json:"exp" example:"1753707388"`
-	IssuedAt  int64    `json:"iat" example:"1753706788"`
-	Scope     []string `json:"scope" example:"read,write"`
+	SessionID string   `json:"jti,omitempty" example:"ade1cdb0-309c-48c5-8251-c3f39ec0d606"`
+	Subject   string   `json:"sub,omitempty" example:"c9fcc8e3-f4f1-4b85-a65e-29bb889cbccb"`
+	Issuer    string   `json:"iss,omitempty" example:"3c23ebbd-42af-47c6-9c50-7295b3ac3a62"`
+	Audience  []string `json:"aud,omitempty" example:"urn:api:auth,urn:api:billing"`
+	ExpiresAt int64    `json:"exp,omitempty" example:"1753707388"`
+	IssuedAt  int64    `json:"iat,omitempty" example:"1753706788"`
+	Scope     []string `json:"scope,omitempty" example:"read,write"`
 }
 
 type JSONWebKey struct {
@@ -0,0 +1,102 @@
+// Package client provides everything that downstream services need to verify Sentinel access tokens:
+// JWKS fetcher, token verifier, echo and net/http middlewares and optional hooks
+// for token introspection and the revocation stream.
+//
+// This package is a separate module and must not depend on any of the Sentinel internal packages,
+// so it can be used by any service without pulling in dependencies of Sentinel.
+package client
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Same as user payload of Sentinel (UserDTO.Payload)
+type Payload struct {
+	ID        string   `json:"id"`
+	Login     string   `json:"login"`
+	Roles     []string `json:"roles"`
+	Version   uint32   `json:"version"`
+	SessionID string   `json:"session-id"`
+	Audience  []string `json:"audience"`
+	AuthTime  int64    `json:"auth-time"`
+	AMR       []string `json:"amr"`
+	// ID of the user who impersonates this user, empty if token wasn't issued via impersonation.
+	// Services should forbid destructive operations for impersonated users.
+	ImpersonatorID string `json:"impersonator-id,omitempty"`
+	// ID of the organization to which token is scoped, empty if token isn't scoped to any organization.
+	// Roles of such tokens are roles of the user in this organization, so services must
+	// allow access only to the data of this organization.
+	OrganizationID string `json:"organization-id,omitempty"`
+}
+
+func (p *Payload) IsImpersonated() bool {
+	return p.ImpersonatorID != ""
+}
+
+func (p *Payload) IsOrganizationScoped() bool {
+	return p.OrganizationID != ""
+}
+
+// Reports whether user was authenticated not earlier than maxAge ago.
+// Can be used to require recent authentication (step-up) for sensitive operations.
+func (p *Payload) AuthenticatedWithin(maxAge time.Duration) bool {
+	return time.Since(time.Unix(p.AuthTime, 0)) <= maxAge
+}
+
+func (p *Payload) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	defaultJWKSCacheTTL           = time.Hour
+	defaultJWKSMinRefreshInterval = 30 * time.Second
+	defaultLeeway                 = 5 * time.Second
+	defaultHTTPTimeout            = 10 * time.Second
+)
+
+type Config struct {
+	// Base URL of Sentinel, e.g. "https://auth.example.com"
+	BaseURL string
+	// Audience of the service which uses this client. Required by the verifier.
+	// Token will be rejected if it isn't issued for this audience.
+	Audience string
+	// Expected value of "iss" claim (ID of Sentinel service).
+	// Optional, if empty then only presence of this claim is checked.
+	Issuer string
+	// Default: http.Client with 10s timeout
+	HTTPClient *http.Client
+	// For how long fetched JWKS considered to be fresh.
+	// Default: 1h
+	JWKSCacheTTL time.Duration
+	// Min time gap between JWKS refreshes caused by tokens with unknown "kid".
+	// Prevents flooding Sentinel with requests by sending tokens with random "kid".
+	// Default: 30s
+	JWKSMinRefreshInterval time.Duration
+	// Allowed clock skew for time based claims.
+	// Default: 5s
+	Leeway time.Duration
+}
+
+func (c *Config) setDefaults() {
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	if c.JWKSCacheTTL <= 0 {
+		c.JWKSCacheTTL = defaultJWKSCacheTTL
+	}
+	if c.JWKSMinRefreshInterval <= 0 {
+		c.JWKSMinRefreshInterval = defaultJWKSMinRefreshInterval
+	}
+	if c.Leeway <= 0 {
+		c.Leeway = defaultLeeway
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Error which can be safely returned to the client of the service
+type Error struct {
+	message string
+	status  int
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+// HTTP status code which is appropriate for this error
+func (e *Error) Status() int {
+	return e.status
+}
+
+func newError(message string, status int) *Error {
+	return &Error{message, status}
+}
+
+// Errors are mirroring token errors of Sentinel
+var (
+	ErrTokenMissing = newError(
+		"Token is missing",
+		http.StatusUnauthorized,
+	)
+	ErrTokenMalformed = newError(
+		"Token is malformed or has invalid format",
+		http.StatusUnauthorized,
+	)
+	ErrTokenExpired = newError(
+		"Token expired",
+		http.StatusUnauthorized,
+	)
+	ErrTokenNotValidYet = newError(
+		"Token is not valid yet",
+		http.StatusUnauthorized,
+	)
+	ErrTokenInvalidSignature = newError(
+		"Invalid Token Signature",
+		http.StatusUnauthorized,
+	)
+	ErrTokenInvalidType = newError(
+		"Invalid token type (expected at+jwt)",
+		http.StatusUnauthorized,
+	)
+	ErrTokenMissingRequiredClaims = newError(
+		"At least one of required token claims is missing",
+		http.StatusUnauthorized,
+	)
+	ErrTokenAudienceMismatch = newError(
+		"Token not valid for this audience",
+		http.StatusUnauthorized,
+	)
+	ErrTokenIssuerMismatch = newError(
+		"Token issued by unknown issuer",
+		http.StatusUnauthorized,
+	)
+	ErrTokenUnknownKey = newError(
+		"Token signed with unknown key",
+		http.StatusUnauthorized,
+	)
+	ErrTokenInactive = newError(
+		"Token is not active",
+		http.StatusUnauthorized,
+	)
+	// Same status code as Sentinel uses for revoked sessions
+	ErrSessionRevoked = newError(
+		"Session revoked",
+		491,
+	)
+	// Same status code as Sentinel uses for user data desynchronization.
+	// Token was issued before user data was changed, so it must be refreshed.
+	ErrUserDesync = newError(
+		"User data desynchronization",
+		490,
+	)
+)
+
+// Failed to get JWKS from Sentinel.
+// Tokens can't be verified, so it's a server side error.
+var ErrJWKSUnavailable = errors.New("JWKS is unavailable")
+
+// Config of the verifier has no audience
+var ErrAudienceRequired = errors.New("audience is required")
+
+// Returns true if err is one of the token verification errors (see Error type)
+func IsTokenError(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
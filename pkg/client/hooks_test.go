@@ -0,0 +1,135 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sentinel/pkg/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func staticToken(ctx context.Context) (string, error) {
+	return "service-token", nil
+}
+
+func TestIntrospector(t *testing.T) {
+	const activeToken = "active"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, client.IntrospectionPath, r.URL.Path)
+
+		if r.Header.Get("Authorization") != "Bearer service-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "access", body["type"])
+
+		if body["token"] != activeToken {
+			json.NewEncoder(w).Encode(map[string]any{"active": false})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"active": true, "jti": "session"})
+	}))
+	defer server.Close()
+
+	cfg := client.Config{BaseURL: server.URL}
+	payload := &client.Payload{SessionID: "session"}
+	ctx := context.Background()
+
+	introspector := client.NewIntrospector(cfg, staticToken)
+
+	assert.NoError(t, introspector.Check(ctx, activeToken, payload))
+	assert.Equal(t, client.ErrTokenInactive, introspector.Check(ctx, "revoked", payload))
+
+	introspector = client.NewIntrospector(cfg, func(ctx context.Context) (string, error) {
+		return "invalid", nil
+	})
+
+	err := introspector.Check(ctx, activeToken, payload)
+	assert.Error(t, err)
+	assert.False(t, client.IsTokenError(err))
+}
+
+func TestRevocationListener(t *testing.T) {
+	var connections atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, client.RevocationsPath, r.URL.Path)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		// First connection is closed after sending single event,
+		// so listener must reconnect with Last-Event-ID.
+		if connections.Add(1) == 1 {
+			assert.Empty(t, r.Header.Get("Last-Event-ID"))
+			fmt.Fprint(w, "retry: 3000\n\n")
+			fmt.Fprint(w, "id: 1-0\nevent: session_revoked\n")
+			fmt.Fprint(w, `data: {"id":"1-0","type":"session_revoked","user_id":"user","session_id":"revoked"}`+"\n\n")
+			return
+		}
+
+		assert.Equal(t, "1-0", r.Header.Get("Last-Event-ID"))
+		fmt.Fprint(w, ": heartbeat\n\n")
+		fmt.Fprint(w, "id: 2-0\nevent: user_version_changed\n")
+		fmt.Fprint(w, `data: {"id":"2-0","type":"user_version_changed","user_id":"user","version":5}`+"\n\n")
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	events := make(chan *client.RevocationEvent, 2)
+
+	listener := client.NewRevocationListener(client.Config{BaseURL: server.URL}, staticToken, &client.RevocationListenerOptions{
+		ReconnectDelay: time.Millisecond,
+		OnEvent: func(e *client.RevocationEvent) {
+			events <- e
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go listener.Run(ctx)
+
+	for range 2 {
+		select {
+		case <-events:
+		case <-time.After(5 * time.Second):
+			t.Fatal("revocation events wasn't received")
+		}
+	}
+
+	assert.Equal(t, client.ErrSessionRevoked, listener.Check(ctx, "", &client.Payload{
+		ID:        "user",
+		SessionID: "revoked",
+		Version:   5,
+	}))
+	assert.Equal(t, client.ErrUserDesync, listener.Check(ctx, "", &client.Payload{
+		ID:        "user",
+		SessionID: "active",
+		Version:   4,
+	}))
+	assert.NoError(t, listener.Check(ctx, "", &client.Payload{
+		ID:        "user",
+		SessionID: "active",
+		Version:   5,
+	}))
+	assert.NoError(t, listener.Check(ctx, "", &client.Payload{
+		ID:        "other-user",
+		SessionID: "other-session",
+		Version:   1,
+	}))
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const IntrospectionPath = "/v1/auth/oauth/introspect"
+
+// Returns access token of the service which uses this client.
+// Used to authenticate requests to the Sentinel.
+type TokenSource func(ctx context.Context) (string, error)
+
+// Same as introspection response of Sentinel (RFC 7662)
+type Introspection struct {
+	Active    bool     `json:"active"`
+	SessionID string   `json:"jti"`
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  []string `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	IssuedAt  int64    `json:"iat"`
+	Scope     []string `json:"scope"`
+}
+
+// Hook which asks Sentinel whether token is still active (RFC 7662).
+// Makes request to Sentinel on each check, so use it only when it's really needed.
+// Service token must have permission to introspect tokens.
+type Introspector struct {
+	url         string
+	httpClient  *http.Client
+	tokenSource TokenSource
+}
+
+func NewIntrospector(cfg Config, tokenSource TokenSource) *Introspector {
+	cfg.setDefaults()
+
+	return &Introspector{
+		url:         cfg.BaseURL + IntrospectionPath,
+		httpClient:  cfg.HTTPClient,
+		tokenSource: tokenSource,
+	}
+}
+
+func (i *Introspector) Introspect(ctx context.Context, token string) (*Introspection, error) {
+	serviceToken, err := i.tokenSource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service token: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"token": token,
+		"type":  "access",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+serviceToken)
+
+	res, err := i.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("introspection request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	// Invalid token isn't an error for introspection, in that case Sentinel will respond with {"active": false}.
+	// So any other status means that something is wrong with request itself (e.g. service token is invalid)
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection request failed: unexpected response status: %d", res.StatusCode)
+	}
+
+	introspection := new(Introspection)
+	if err := json.NewDecoder(res.Body).Decode(introspection); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+
+	return introspection, nil
+}
+
+func (i *Introspector) Check(ctx context.Context, token string, payload *Payload) error {
+	introspection, err := i.Introspect(ctx, token)
+	if err != nil {
+		return err
+	}
+	if !introspection.Active || introspection.SessionID != payload.SessionID {
+		return ErrTokenInactive
+	}
+	return nil
+}
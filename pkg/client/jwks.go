@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const JWKSPath = "/v1/.well-known/jwks.json"
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// Fetches JWKS from Sentinel and caches it.
+// Cached keys are refreshed once they're expired or when requested kid wasn't found.
+type JWKS struct {
+	url                string
+	httpClient         *http.Client
+	ttl                time.Duration
+	minRefreshInterval time.Duration
+
+	mut       sync.RWMutex
+	keys      map[string]ed25519.PublicKey
+	fetchedAt time.Time
+	// Time of the last fetch attempt (including failed ones)
+	attemptedAt time.Time
+	// Used to avoid concurrent fetches
+	fetchMut sync.Mutex
+}
+
+func NewJWKS(cfg Config) *JWKS {
+	cfg.setDefaults()
+
+	return &JWKS{
+		url:                cfg.BaseURL + JWKSPath,
+		httpClient:         cfg.HTTPClient,
+		ttl:                cfg.JWKSCacheTTL,
+		minRefreshInterval: cfg.JWKSMinRefreshInterval,
+		keys:               make(map[string]ed25519.PublicKey),
+	}
+}
+
+func (j *JWKS) fetch(ctx context.Context) error {
+	j.mut.Lock()
+	j.attemptedAt = time.Now()
+	j.mut.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrJWKSUnavailable, err.Error())
+	}
+
+	res, err := j.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrJWKSUnavailable, err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected response status: %d", ErrJWKSUnavailable, res.StatusCode)
+	}
+
+	var set jsonWebKeySet
+
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return fmt.Errorf("%w: failed to decode response: %s", ErrJWKSUnavailable, err.Error())
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(set.Keys))
+
+	for _, k := range set.Keys {
+		// Sentinel signs tokens only with Ed25519 keys, so others are just ignored
+		if k.Kty != "OKP" || k.Crv != "Ed25519" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			continue
+		}
+		keys[k.Kid] = ed25519.PublicKey(x)
+	}
+
+	j.mut.Lock()
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	j.mut.Unlock()
+
+	return nil
+}
+
+// Fetches JWKS from Sentinel, even if cached keys are still fresh
+func (j *JWKS) Refresh(ctx context.Context) error {
+	j.fetchMut.Lock()
+	defer j.fetchMut.Unlock()
+
+	return j.fetch(ctx)
+}
+
+func (j *JWKS) lookup(kid string) (key ed25519.PublicKey, found bool, fresh bool) {
+	j.mut.RLock()
+	defer j.mut.RUnlock()
+
+	key, found = j.keys[kid]
+	fresh = !j.fetchedAt.IsZero() && time.Since(j.fetchedAt) < j.ttl
+
+	return key, found, fresh
+}
+
+// Returns public key with specified ID.
+// If keys are expired or key wasn't found, keys will be fetched again
+// (but not more often than once per min refresh interval).
+//
+// Returns ErrTokenUnknownKey if there are no key with such ID.
+func (j *JWKS) Key(ctx context.Context, kid string) (ed25519.PublicKey, error) {
+	if key, found, fresh := j.lookup(kid); found && fresh {
+		return key, nil
+	}
+
+	j.fetchMut.Lock()
+	defer j.fetchMut.Unlock()
+
+	// Keys could be already fetched by another goroutine while waiting for the lock
+	key, found, fresh := j.lookup(kid)
+	if found && fresh {
+		return key, nil
+	}
+
+	j.mut.RLock()
+	canRefresh := time.Since(j.attemptedAt) >= j.minRefreshInterval
+	j.mut.RUnlock()
+
+	if canRefresh {
+		if err := j.fetch(ctx); err != nil {
+			// Better to use stale key rather than fail
+			if found {
+				return key, nil
+			}
+			return nil, err
+		}
+		key, found, _ = j.lookup(kid)
+	}
+
+	if !found {
+		j.mut.RLock()
+		neverFetched := j.fetchedAt.IsZero()
+		j.mut.RUnlock()
+		if neverFetched {
+			return nil, ErrJWKSUnavailable
+		}
+		return nil, ErrTokenUnknownKey
+	}
+
+	return key, nil
+}
+
+// Returns all currently cached keys (fetches them if there are no fresh ones)
+func (j *JWKS) Keys(ctx context.Context) ([]ed25519.PublicKey, error) {
+	if _, _, fresh := j.lookup(""); !fresh {
+		j.fetchMut.Lock()
+		_, _, fresh := j.lookup("")
+		j.mut.RLock()
+		canRefresh := time.Since(j.attemptedAt) >= j.minRefreshInterval
+		j.mut.RUnlock()
+		if !fresh && canRefresh {
+			if err := j.fetch(ctx); err != nil {
+				j.mut.RLock()
+				// Same as in Key(), stale keys are better than nothing
+				hasStaleKeys := len(j.keys) != 0
+				j.mut.RUnlock()
+				if !hasStaleKeys {
+					j.fetchMut.Unlock()
+					return nil, err
+				}
+			}
+		}
+		j.fetchMut.Unlock()
+	}
+
+	j.mut.RLock()
+	defer j.mut.RUnlock()
+
+	if len(j.keys) == 0 {
+		return nil, ErrJWKSUnavailable
+	}
+
+	keys := make([]ed25519.PublicKey, 0, len(j.keys))
+	for _, key := range j.keys {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type payloadContextKey struct{}
+
+// Key under which payload is stored in echo.Context
+const EchoPayloadKey = "user_payload"
+
+// Returns payload of the verified token.
+// Returns nil if request wasn't processed by the verifier middleware.
+func PayloadFromContext(ctx context.Context) *Payload {
+	payload, _ := ctx.Value(payloadContextKey{}).(*Payload)
+	return payload
+}
+
+// Same as PayloadFromContext, but for echo
+func PayloadFromEcho(ctx echo.Context) *Payload {
+	if payload, ok := ctx.Get(EchoPayloadKey).(*Payload); ok {
+		return payload
+	}
+	return PayloadFromContext(ctx.Request().Context())
+}
+
+func WithPayload(ctx context.Context, payload *Payload) context.Context {
+	return context.WithValue(ctx, payloadContextKey{}, payload)
+}
+
+// Extracts token from the "Authorization" header (Bearer scheme).
+// Returns empty string if there are no token.
+func TokenFromRequest(r *http.Request) string {
+	authHeader := r.Header.Get("Authorization")
+
+	token, found := strings.CutPrefix(authHeader, "Bearer ")
+	if !found {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+// Sets WWW-Authenticate header according to RFC 6750.
+// Returns status code and message for the response.
+func handleVerificationError(header http.Header, err error) (int, string) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return http.StatusInternalServerError, "Internal Server Error"
+	}
+
+	switch e {
+	case ErrTokenMissing:
+		header.Set("WWW-Authenticate", `Bearer realm="api", error="invalid_request", error_description="No token provided"`)
+	case ErrTokenExpired:
+		header.Set("WWW-Authenticate", `Bearer realm="api", error="expired_token", error_description="`+e.Error()+`"`)
+	case ErrSessionRevoked:
+		header.Set("X-Session-Revoked", "true")
+	case ErrUserDesync:
+		header.Set("X-Token-Refresh-Required", "true")
+	default:
+		header.Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token", error_description="`+e.Error()+`"`)
+	}
+
+	return e.Status(), e.Error()
+}
+
+// Same as response body of Sentinel errors
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// net/http middleware which allows access only for requests with valid access token.
+// Payload of the token can be retrieved via PayloadFromContext.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := v.Verify(r.Context(), TokenFromRequest(r))
+		if err != nil {
+			status, message := handleVerificationError(w.Header(), err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(errorResponse{
+				Error:   http.StatusText(status),
+				Message: message,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithPayload(r.Context(), payload)))
+	})
+}
+
+// echo middleware which allows access only for requests with valid access token.
+// Payload of the token can be retrieved via PayloadFromEcho.
+func (v *Verifier) EchoMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+
+		payload, err := v.Verify(req.Context(), TokenFromRequest(req))
+		if err != nil {
+			status, message := handleVerificationError(ctx.Response().Header(), err)
+			return echo.NewHTTPError(status, message).SetInternal(err)
+		}
+
+		ctx.Set(EchoPayloadKey, payload)
+		ctx.SetRequest(req.WithContext(WithPayload(req.Context(), payload)))
+
+		return next(ctx)
+	}
+}
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const RevocationsPath = "/v1/auth/revocations"
+
+const (
+	SessionRevokedEvent     = "session_revoked"
+	UserVersionChangedEvent = "user_version_changed"
+)
+
+// Same as revocation event of Sentinel (revocation.Event)
+type RevocationEvent struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id,omitempty"`
+	Version   uint32 `json:"version,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+const (
+	defaultRevocationRetention = time.Hour
+	defaultReconnectDelay      = 3 * time.Second
+)
+
+type RevocationListenerOptions struct {
+	// For how long info about revoked sessions and user versions will be stored.
+	// Must be greater than TTL of access tokens, otherwise revoked tokens may be accepted again.
+	// Default: 1h
+	Retention time.Duration
+	// Default: 3s
+	ReconnectDelay time.Duration
+	// Called on each connection error (including the ones after which listener will reconnect)
+	OnError func(err error)
+	// Called on each received event
+	OnEvent func(e *RevocationEvent)
+}
+
+type revocationEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+// Hook which rejects tokens of revoked sessions and tokens that was issued before user data was changed.
+// Listens to the revocation stream of Sentinel (SSE), so there are no additional requests on each check.
+// Service token must have permission to subscribe to revocations.
+//
+// Listener must be started via Run, otherwise it won't reject anything.
+type RevocationListener struct {
+	url         string
+	httpClient  *http.Client
+	tokenSource TokenSource
+	opt         RevocationListenerOptions
+
+	mut             sync.RWMutex
+	revokedSessions map[string]revocationEntry[struct{}]
+	userVersions    map[string]revocationEntry[uint32]
+	lastEventID     string
+}
+
+func NewRevocationListener(cfg Config, tokenSource TokenSource, opt *RevocationListenerOptions) *RevocationListener {
+	cfg.setDefaults()
+
+	if opt == nil {
+		opt = new(RevocationListenerOptions)
+	}
+	if opt.Retention <= 0 {
+		opt.Retention = defaultRevocationRetention
+	}
+	if opt.ReconnectDelay <= 0 {
+		opt.ReconnectDelay = defaultReconnectDelay
+	}
+
+	// Stream is long-lived, so timeout of the default client can't be used
+	httpClient := *cfg.HTTPClient
+	httpClient.Timeout = 0
+
+	return &RevocationListener{
+		url:             cfg.BaseURL + RevocationsPath,
+		httpClient:      &httpClient,
+		tokenSource:     tokenSource,
+		opt:             *opt,
+		revokedSessions: make(map[string]revocationEntry[struct{}]),
+		userVersions:    make(map[string]revocationEntry[uint32]),
+	}
+}
+
+func (l *RevocationListener) Check(ctx context.Context, token string, payload *Payload) error {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	if _, revoked := l.revokedSessions[payload.SessionID]; revoked {
+		return ErrSessionRevoked
+	}
+	if entry, ok := l.userVersions[payload.ID]; ok && payload.Version < entry.value {
+		return ErrUserDesync
+	}
+
+	return nil
+}
+
+// Applies event to the listener state.
+// Normally events are received from the stream, but they can be also applied manually.
+func (l *RevocationListener) Apply(e *RevocationEvent) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	expiresAt := time.Now().Add(l.opt.Retention)
+
+	switch e.Type {
+	case SessionRevokedEvent:
+		l.revokedSessions[e.SessionID] = revocationEntry[struct{}]{expiresAt: expiresAt}
+	case UserVersionChangedEvent:
+		if entry, ok := l.userVersions[e.UserID]; ok && entry.value > e.Version {
+			break
+		}
+		l.userVersions[e.UserID] = revocationEntry[uint32]{value: e.Version, expiresAt: expiresAt}
+	}
+
+	if e.ID != "" {
+		l.lastEventID = e.ID
+	}
+}
+
+func (l *RevocationListener) removeExpired() {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	now := time.Now()
+
+	for id, entry := range l.revokedSessions {
+		if now.After(entry.expiresAt) {
+			delete(l.revokedSessions, id)
+		}
+	}
+	for id, entry := range l.userVersions {
+		if now.After(entry.expiresAt) {
+			delete(l.userVersions, id)
+		}
+	}
+}
+
+// Listens to the revocation stream until ctx is done, reconnects on any error.
+// Missed events are replayed after reconnect (only if they are still stored by Sentinel).
+// Blocks, so must be called in a separate goroutine.
+func (l *RevocationListener) Run(ctx context.Context) {
+	cleanup := time.NewTicker(l.opt.Retention / 2)
+	defer cleanup.Stop()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-cleanup.C:
+				l.removeExpired()
+			}
+		}
+	}()
+
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && l.opt.OnError != nil {
+			l.opt.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.opt.ReconnectDelay):
+		}
+	}
+}
+
+// Connects to the stream and reads it until connection is closed.
+func (l *RevocationListener) listen(ctx context.Context) error {
+	serviceToken, err := l.tokenSource(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get service token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Authorization", "Bearer "+serviceToken)
+
+	l.mut.RLock()
+	if l.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", l.lastEventID)
+	}
+	l.mut.RUnlock()
+
+	res, err := l.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to revocation stream: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to connect to revocation stream: unexpected response status: %d", res.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+
+	var data strings.Builder
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		// End of event
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			e := new(RevocationEvent)
+			if err := json.Unmarshal([]byte(data.String()), e); err != nil {
+				data.Reset()
+				if l.opt.OnError != nil {
+					l.opt.OnError(fmt.Errorf("failed to decode revocation event: %w", err))
+				}
+				continue
+			}
+			data.Reset()
+			l.Apply(e)
+			if l.opt.OnEvent != nil {
+				l.opt.OnEvent(e)
+			}
+		// Comment (heartbeat)
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() != 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		// Other fields (id, event, retry) are either duplicated in data or aren't needed
+		default:
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("revocation stream read failed: %w", err)
+	}
+
+	return nil
+}
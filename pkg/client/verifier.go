@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Same as token claims of Sentinel (token.Claims)
+type Claims struct {
+	Roles   []string `json:"roles"`
+	Login   string   `json:"login"`
+	Version uint32   `json:"version"`
+	// Time of the last user authentication (not updated on tokens refresh)
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	// Authentication methods references (RFC 8176)
+	AMR []string `json:"amr,omitempty"`
+	// Set only for impersonation tokens (RFC 8693 p4.1)
+	Actor *struct {
+		Subject string `json:"sub"`
+	} `json:"act,omitempty"`
+	// ID of the organization to which token is scoped
+	Organization string `json:"org,omitempty"`
+
+	jwt.RegisteredClaims
+}
+
+func (c *Claims) Payload() *Payload {
+	var authTime int64
+	if c.AuthTime != nil {
+		authTime = c.AuthTime.Unix()
+	}
+
+	var impersonatorID string
+	if c.Actor != nil {
+		impersonatorID = c.Actor.Subject
+	}
+
+	return &Payload{
+		ID:        c.Subject,
+		Login:     c.Login,
+		Roles:     c.Roles,
+		Version:   c.Version,
+		SessionID: c.ID,
+		Audience:  c.Audience,
+		AuthTime:  authTime,
+		AMR:       c.AMR,
+
+		ImpersonatorID: impersonatorID,
+		OrganizationID: c.Organization,
+	}
+}
+
+// Additional check which is performed after token was successfully verified.
+// Returned error should be either *Error (if token must be rejected), either any other error
+// (if check can't be performed).
+type Hook interface {
+	Check(ctx context.Context, token string, payload *Payload) error
+}
+
+// Verifies Sentinel access tokens.
+// Checks are the same as in Sentinel itself (see token.ParseSingedToken), which must match RFC 9068.
+type Verifier struct {
+	cfg    Config
+	jwks   *JWKS
+	hooks  []Hook
+	parser *jwt.Parser
+}
+
+// Returns ErrAudienceRequired if cfg.Audience is empty, since without audience check
+// service would accept tokens issued for any other service (RFC 9068 p4).
+func NewVerifier(cfg Config, hooks ...Hook) (*Verifier, error) {
+	if cfg.Audience == "" {
+		return nil, ErrAudienceRequired
+	}
+
+	cfg.setDefaults()
+
+	opts := []jwt.ParserOption{
+		jwt.WithLeeway(cfg.Leeway),
+		// RFC 9068 p2.1
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithAudience(cfg.Audience),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	return &Verifier{
+		cfg:    cfg,
+		jwks:   NewJWKS(cfg),
+		hooks:  hooks,
+		parser: jwt.NewParser(opts...),
+	}, nil
+}
+
+func (v *Verifier) JWKS() *JWKS {
+	return v.jwks
+}
+
+func (v *Verifier) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		// RFC 9068 p2.1
+		if typ, _ := token.Header["typ"].(string); typ != "at+jwt" && typ != "application/at+jwt" {
+			return nil, ErrTokenInvalidType
+		}
+
+		if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+			return v.jwks.Key(ctx, kid)
+		}
+
+		// Token without "kid", so trying all known keys
+		keys, err := v.jwks.Keys(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		set := jwt.VerificationKeySet{Keys: make([]jwt.VerificationKey, len(keys))}
+		for i, key := range keys {
+			set.Keys[i] = key
+		}
+
+		return set, nil
+	}
+}
+
+// Parses and validates given access token, but doesn't run any hooks
+func (v *Verifier) ParseClaims(ctx context.Context, tokenStr string) (*Claims, error) {
+	if tokenStr == "" {
+		return nil, ErrTokenMissing
+	}
+
+	claims := &Claims{}
+
+	if _, err := v.parser.ParseWithClaims(tokenStr, claims, v.keyFunc(ctx)); err != nil {
+		switch {
+		case errors.Is(err, ErrJWKSUnavailable):
+			return nil, err
+		case errors.Is(err, ErrTokenInvalidType):
+			return nil, ErrTokenInvalidType
+		case errors.Is(err, ErrTokenUnknownKey):
+			return nil, ErrTokenUnknownKey
+		case errors.Is(err, jwt.ErrTokenMalformed):
+			return nil, ErrTokenMalformed
+		case errors.Is(err, jwt.ErrTokenExpired):
+			return nil, ErrTokenExpired
+		case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+			return nil, ErrTokenNotValidYet
+		case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+			return nil, ErrTokenInvalidSignature
+		case errors.Is(err, jwt.ErrTokenInvalidAudience):
+			return nil, ErrTokenAudienceMismatch
+		case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+			return nil, ErrTokenIssuerMismatch
+		case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+			return nil, ErrTokenMissingRequiredClaims
+		case errors.Is(err, jwt.ErrTokenUnverifiable):
+			return nil, ErrTokenInvalidSignature
+		default:
+			return nil, fmt.Errorf("failed to parse token: %w", err)
+		}
+	}
+
+	// RFC 9068 p2.2
+	if claims.Issuer == "" ||
+		claims.Subject == "" ||
+		len(claims.Audience) == 0 ||
+		claims.ExpiresAt == nil ||
+		claims.IssuedAt == nil ||
+		claims.ID == "" {
+		return nil, ErrTokenMissingRequiredClaims
+	}
+
+	return claims, nil
+}
+
+// Parses and validates given access token, then runs all hooks.
+func (v *Verifier) Verify(ctx context.Context, tokenStr string) (*Payload, error) {
+	claims, err := v.ParseClaims(ctx, tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := claims.Payload()
+
+	for _, hook := range v.hooks {
+		if err := hook.Check(ctx, tokenStr, payload); err != nil {
+			return nil, err
+		}
+	}
+
+	return payload, nil
+}
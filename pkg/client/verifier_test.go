@@ -0,0 +1,27 @@
+package client_test
+
+import (
+	"testing"
+
+	"sentinel/pkg/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVerifier(t *testing.T) {
+	t.Run("audience is required", func(t *testing.T) {
+		verifier, err := client.NewVerifier(client.Config{BaseURL: "https://auth.example.com"})
+		assert.Nil(t, verifier)
+		assert.Equal(t, client.ErrAudienceRequired, err)
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		verifier, err := client.NewVerifier(client.Config{
+			BaseURL:  "https://auth.example.com",
+			Audience: "urn:api:test",
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, verifier.JWKS())
+	})
+}
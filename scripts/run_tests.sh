@@ -2,3 +2,5 @@
 
 go test ./... -v --timeout 30s
 
+# Client is a separate module, so it isn't covered by ./...
+(cd pkg/client && go test ./... -v --timeout 30s)